@@ -34,7 +34,20 @@ type Options struct {
 	WorkingDirectory   string
 	HeartbeatInterval  time.Duration
 	AgentSleepInterval time.Duration
-	Cleanup            bool
+	DiskGuardInterval  time.Duration
+	// DebugPollInterval and DebugExchangeInterval override how often the
+	// agent checks for debug sessions and relays an open session's input
+	// and output.
+	DebugPollInterval     time.Duration
+	DebugExchangeInterval time.Duration
+	// PausePollInterval overrides how often the agent checks whether a
+	// task paused on failure has been resumed.
+	PausePollInterval time.Duration
+	Cleanup           bool
+	// TraceCollector is the OTLP/HTTP endpoint to which the agent exports
+	// the spans of the commands it runs. Spans are not exported if it is
+	// empty.
+	TraceCollector string
 }
 
 type taskContext struct {
@@ -48,6 +61,10 @@ type taskContext struct {
 	taskDirectory  string
 	timeout        time.Duration
 	timedOut       bool
+	cgroup         *subprocess.TaskCgroup
+	diskFailure    error
+	pausedAt       time.Time
+	pausedFor      time.Duration
 	sync.RWMutex
 }
 
@@ -220,7 +237,8 @@ func (a *Agent) runTask(ctx context.Context, cancel context.CancelFunc, tc *task
 		return errors.Wrap(err, "problem setting up metrics collection")
 	}
 
-	// Defers are LIFO. We cancel all agent task threads, then any procs started by the agent, then remove the task directory.
+	// Defers are LIFO. We cancel all agent task threads, then any procs started by the agent, then remove the task's cgroup.
+	defer a.closeResourceLimits(tc)
 	defer a.killProcs(tc, false)
 	defer cancel()
 
@@ -321,6 +339,9 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, status string)
 	grip.Infof("Sending final status as: %v", detail.Status)
 	resp, err := a.comm.EndTask(ctx, detail, tc.task)
 	grip.Infof("Sent final status as: %v", detail.Status)
+	// export the task's spans now, since the host may not outlive the
+	// next batch
+	grip.Warning(errors.Wrap(util.GetTracer().Flush(ctx), "problem exporting task spans"))
 	if err != nil {
 		return nil, errors.Wrap(err, "problem marking task complete")
 	}
@@ -329,12 +350,26 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, status string)
 }
 
 func (a *Agent) endTaskResponse(tc *taskContext, status string) *apimodels.TaskEndDetail {
-	return &apimodels.TaskEndDetail{
+	detail := &apimodels.TaskEndDetail{
 		Description: tc.getCurrentCommand().DisplayName(),
 		Type:        tc.getCurrentCommand().Type(),
 		TimedOut:    tc.hadTimedOut(),
 		Status:      status,
 	}
+	// a task that exceeded a resource limit fails, even if the command
+	// that exceeded it was a pre or post command
+	if err := tc.getCgroup().Violation(); err != nil && (status == evergreen.TaskSucceeded || status == evergreen.TaskFailed) {
+		detail.Status = evergreen.TaskFailed
+		detail.Description = err.Error()
+	}
+	// running out of disk is a problem with the host, not the task
+	if err := tc.getDiskFailure(); err != nil && status != evergreen.TaskUndispatched && status != evergreen.TaskConflict {
+		detail.Status = evergreen.TaskFailed
+		detail.Type = evergreen.CommandTypeSystem
+		detail.Description = err.Error()
+		detail.OutOfDisk = true
+	}
+	return detail
 }
 
 func (a *Agent) runPostTaskCommands(ctx context.Context, tc *taskContext) {
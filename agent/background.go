@@ -83,6 +83,9 @@ func (a *Agent) startIdleTimeoutWatch(ctx context.Context, tc *taskContext, canc
 			grip.Info("Idle timeout watch canceled")
 			return
 		case <-ticker.C:
+			if tc.isPaused() {
+				continue
+			}
 			timeout := tc.getCurrentTimeout()
 			timeSinceLastMessage := time.Since(a.comm.LastMessageAt())
 
@@ -108,7 +111,8 @@ func (a *Agent) startMaxExecTimeoutWatch(ctx context.Context, tc *taskContext, c
 			return
 		case <-ticker.C:
 			timeout := tc.getExecTimeout()
-			timeSinceTickerStarted := time.Since(timeTickerStarted)
+			// time spent paused on failure doesn't count
+			timeSinceTickerStarted := time.Since(timeTickerStarted) - tc.getPausedDuration()
 
 			if timeSinceTickerStarted > timeout {
 				tc.logger.Execution().Errorf("Hit exec timeout (%s)", timeout)
@@ -3,10 +3,12 @@ package agent
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/recovery"
 	"github.com/pkg/errors"
@@ -76,6 +78,14 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 			}
 
 			start := time.Now()
+			a.startCommandResources(tc, fullCommandName)
+			cmdCtx, span := util.GetTracer().StartFromTraceParent(ctx, tc.taskConfig.Task.TraceParent,
+				"command "+fullCommandName, map[string]string{
+					"task":      tc.taskConfig.Task.Id,
+					"execution": strconv.Itoa(tc.taskConfig.Task.Execution),
+					"command":   cmd.Name(),
+					"function":  commandInfo.Function,
+				})
 			// We have seen cases where calling exec.*Cmd.Wait() waits for too long if
 			// the process has called subprocesses. It will wait until a subprocess
 			// finishes, instead of returning immediately when the context is canceled.
@@ -88,10 +98,14 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 						fmt.Sprintf("problem running command '%s'", cmd.Name()))
 				}()
 
-				cmdChan <- cmd.Execute(ctx, a.comm, tc.logger, tc.taskConfig)
+				cmdChan <- cmd.Execute(cmdCtx, a.comm, tc.logger, tc.taskConfig)
 			}()
 			select {
 			case err = <-cmdChan:
+				if limitErr := a.endCommandResources(tc, fullCommandName); limitErr != nil {
+					err = limitErr
+				}
+				span.End(err)
 				if err != nil {
 					tc.logger.Task().Errorf("Command failed: %v", err)
 					if isTaskCommands {
@@ -99,6 +113,8 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 					}
 				}
 			case <-ctx.Done():
+				_ = a.endCommandResources(tc, fullCommandName)
+				span.End(ctx.Err())
 				tc.logger.Task().Errorf("Command canceled: %v", err)
 				return errors.Wrap(err, "command canceled")
 			}
@@ -0,0 +1,305 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/recovery"
+	"github.com/pkg/errors"
+)
+
+// maxDebugOutputBuffer bounds the shell output the agent holds while it
+// cannot reach the API server.
+const maxDebugOutputBuffer = 1024 * 1024
+
+var envVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// startDebugSessions polls for the debug sessions that users open into the
+// task and runs them one at a time until the task ends. It does nothing
+// unless the task's project allows debug sessions.
+func (a *Agent) startDebugSessions(ctx context.Context, tc *taskContext) {
+	defer recovery.LogStackTraceAndContinue("debug session poller")
+
+	conf := tc.getTaskConfig()
+	if conf == nil || conf.ProjectRef == nil || !conf.ProjectRef.DebugSessionsEnabled {
+		return
+	}
+
+	interval := defaultDebugPollInterval
+	if a.opts.DebugPollInterval != 0 {
+		interval = a.opts.DebugPollInterval
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			session, err := a.comm.GetDebugSession(ctx, tc.task)
+			if err != nil {
+				grip.Debug(message.WrapError(err, message.Fields{
+					"message": "problem checking for debug sessions",
+					"task_id": tc.task.ID,
+				}))
+			} else if session.ID != "" {
+				a.runDebugSession(ctx, tc, session)
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// runDebugSession runs a shell in the task's working directory and relays
+// its input and output through the API server until the user closes the
+// session, the shell exits or the task ends.
+func (a *Agent) runDebugSession(ctx context.Context, tc *taskContext, session *apimodels.DebugSession) {
+	tc.logger.Execution().Infof("Starting debug session %s for user %s.", session.ID, session.User)
+
+	interval := defaultDebugExchangeInterval
+	if a.opts.DebugExchangeInterval != 0 {
+		interval = a.opts.DebugExchangeInterval
+	}
+
+	output := &debugOutput{}
+	input, err := newDebugInput()
+	if err != nil {
+		a.finishDebugSession(tc, session, output, 0, fmt.Sprintf("could not start shell: %s", err))
+		return
+	}
+	defer input.Close()
+
+	shellCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conf := tc.getTaskConfig()
+	shell := subprocess.NewInteractiveShell(conf.WorkDir, "", debugEnvironment(tc), input.reader)
+	if err = shell.SetOutput(subprocess.OutputOptions{Output: output, SendErrorToOutput: true}); err == nil {
+		err = shell.Start(shellCtx)
+	}
+	input.started()
+	if err != nil {
+		a.finishDebugSession(tc, session, output, 0, fmt.Sprintf("could not start shell: %s", err))
+		return
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- shell.Wait()
+	}()
+
+	ack := 0
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-exited
+			a.finishDebugSession(tc, session, output, ack, "task finished")
+			return
+		case err = <-exited:
+			reason := "shell exited"
+			if err != nil {
+				reason = fmt.Sprintf("shell exited: %s", err)
+			}
+			a.finishDebugSession(tc, session, output, ack, reason)
+			return
+		case <-timer.C:
+			chunks := output.take()
+			var resp *apimodels.DebugExchangeResponse
+			resp, err = a.comm.ExchangeDebugSession(ctx, tc.task, session.ID, &apimodels.DebugExchangeRequest{
+				Output:   chunks,
+				InputAck: ack,
+			})
+			if err != nil {
+				output.restore(chunks)
+				grip.Warning(message.WrapError(err, message.Fields{
+					"message": "problem exchanging debug session",
+					"task_id": tc.task.ID,
+					"session": session.ID,
+				}))
+				timer.Reset(interval)
+				continue
+			}
+
+			if resp.Closed {
+				cancel()
+				<-exited
+				tc.logger.Execution().Infof("Debug session %s was closed by the user.", session.ID)
+				return
+			}
+
+			for _, chunk := range resp.Input {
+				if chunk.Seq <= ack {
+					continue
+				}
+				// input that can't be queued is not acknowledged, so
+				// that it is sent again with the next exchange
+				if err = input.write(chunk.Data); err != nil {
+					grip.Debug(message.WrapError(err, message.Fields{
+						"task_id": tc.task.ID,
+						"session": session.ID,
+					}))
+					break
+				}
+				ack = chunk.Seq
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// finishDebugSession sends the shell's remaining output and closes the
+// session. It does not use the task's context, which may be done.
+func (a *Agent) finishDebugSession(tc *taskContext, session *apimodels.DebugSession, output *debugOutput, ack int, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := a.comm.ExchangeDebugSession(ctx, tc.task, session.ID, &apimodels.DebugExchangeRequest{
+		Output:   output.take(),
+		InputAck: ack,
+		Closed:   true,
+		Reason:   reason,
+	})
+	grip.Warning(message.WrapError(err, message.Fields{
+		"message": "problem closing debug session",
+		"task_id": tc.task.ID,
+		"session": session.ID,
+	}))
+	tc.logger.Execution().Infof("Debug session %s ended: %s.", session.ID, reason)
+}
+
+// debugEnvironment is the agent's environment, marked so that the task's
+// process cleanup finds the shell, with the task's expansions added. Private
+// project variables are left out.
+func debugEnvironment(tc *taskContext) []string {
+	env := append(os.Environ(),
+		fmt.Sprintf("%s=%s", subprocess.MarkerTaskID, tc.task.ID),
+		fmt.Sprintf("%s=%d", subprocess.MarkerAgentPID, os.Getpid()))
+
+	conf := tc.getTaskConfig()
+	if conf == nil || conf.Expansions == nil {
+		return env
+	}
+	for k, v := range conf.Expansions.Map() {
+		if conf.Redacted[k] || !envVarName.MatchString(k) {
+			continue
+		}
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env
+}
+
+// debugInput feeds the shell's stdin through an OS pipe, so that the shell
+// exiting is not held up waiting for more input. Writes happen in the
+// background so that a shell that isn't reading never blocks the session.
+type debugInput struct {
+	reader  *os.File
+	writer  *os.File
+	pending chan string
+	once    sync.Once
+}
+
+func newDebugInput() (*debugInput, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating stdin pipe")
+	}
+
+	in := &debugInput{
+		reader:  r,
+		writer:  w,
+		pending: make(chan string, 100),
+	}
+	go func() {
+		defer recovery.LogStackTraceAndContinue("debug session input")
+		for data := range in.pending {
+			if _, err := io.WriteString(in.writer, data); err != nil {
+				grip.Debug(errors.Wrap(err, "problem writing debug session input"))
+			}
+		}
+		grip.Debug(in.writer.Close())
+	}()
+
+	return in, nil
+}
+
+// started releases the agent's copy of the read end once the shell has its
+// own.
+func (in *debugInput) started() { grip.Debug(in.reader.Close()) }
+
+// write queues input for the shell without blocking, and returns an error
+// if the queue is full because the shell has stopped reading.
+func (in *debugInput) write(data string) error {
+	select {
+	case in.pending <- data:
+		return nil
+	default:
+		return errors.New("debug session input queue is full")
+	}
+}
+
+func (in *debugInput) Close() { in.once.Do(func() { close(in.pending) }) }
+
+// debugOutput collects the shell's combined output until it is sent.
+type debugOutput struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	dropped int
+}
+
+func (o *debugOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if room := maxDebugOutputBuffer - o.buf.Len(); len(p) > room {
+		if room > 0 {
+			o.buf.Write(p[:room])
+		}
+		o.dropped += len(p) - room
+		return len(p), nil
+	}
+	o.buf.Write(p)
+	return len(p), nil
+}
+
+// take removes the buffered output, split into chunks the server accepts.
+func (o *debugOutput) take() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	chunks := []string{}
+	for o.buf.Len() > 0 {
+		chunks = append(chunks, string(o.buf.Next(apimodels.DebugChunkSize)))
+	}
+	if o.dropped > 0 {
+		chunks = append(chunks, fmt.Sprintf("\n[%d bytes of output dropped]\n", o.dropped))
+		o.dropped = 0
+	}
+	return chunks
+}
+
+// restore puts back output that could not be sent.
+func (o *debugOutput) restore(chunks []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	rest := o.buf.String()
+	o.buf.Reset()
+	for _, c := range chunks {
+		o.buf.WriteString(c)
+	}
+	o.buf.WriteString(rest)
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugSession(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test shell commands assume a POSIX shell")
+	}
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "debug-session")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	comm := client.NewMock("url")
+	comm.DebugSession = &apimodels.DebugSession{ID: "session", User: "user"}
+	comm.DebugInput = []apimodels.DebugChunk{
+		{Seq: 1, Data: "pwd\n"},
+		{Seq: 2, Data: "echo $debug_public $debug_secret\n"},
+		{Seq: 3, Data: "exit\n"},
+	}
+
+	a := &Agent{
+		opts: Options{
+			DebugPollInterval:     10 * time.Millisecond,
+			DebugExchangeInterval: 10 * time.Millisecond,
+		},
+		comm: comm,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := &taskContext{
+		task: client.TaskData{ID: "task_id", Secret: "task_secret"},
+		taskConfig: &model.TaskConfig{
+			WorkDir:    dir,
+			ProjectRef: &model.ProjectRef{DebugSessionsEnabled: true},
+			Expansions: util.NewExpansions(map[string]string{
+				"debug_public": "visible",
+				"debug_secret": "hidden",
+			}),
+			Redacted: map[string]bool{"debug_secret": true},
+		},
+	}
+	tc.logger = comm.GetLoggerProducer(ctx, tc.task)
+
+	go a.startDebugSessions(ctx, tc)
+
+	// the mock stops offering the session once the agent closes it
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		session, err := comm.GetDebugSession(ctx, tc.task)
+		require.NoError(err)
+		if session.ID == "" {
+			break
+		}
+		require.True(time.Now().Before(deadline), "debug session did not close")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	output := strings.Join(comm.DebugOutput, "")
+	realDir, err := os.Readlink(dir)
+	if err != nil {
+		realDir = dir
+	}
+	assert.True(strings.Contains(output, dir) || strings.Contains(output, realDir), output)
+	assert.Contains(output, "visible")
+	assert.NotContains(output, "hidden")
+	assert.Equal("shell exited", comm.DebugCloseReason)
+}
+
+func TestDebugSessionsDisabled(t *testing.T) {
+	comm := client.NewMock("url")
+	comm.DebugSession = &apimodels.DebugSession{ID: "session", User: "user"}
+
+	a := &Agent{
+		opts: Options{DebugPollInterval: time.Millisecond},
+		comm: comm,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	tc := &taskContext{
+		task:       client.TaskData{ID: "task_id", Secret: "task_secret"},
+		taskConfig: &model.TaskConfig{ProjectRef: &model.ProjectRef{}},
+	}
+
+	a.startDebugSessions(ctx, tc)
+	assert.Empty(t, comm.DebugOutput)
+	assert.False(t, comm.DebugClosed)
+}
+
+func TestDebugOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &debugOutput{}
+	_, err := out.Write([]byte(strings.Repeat("a", apimodels.DebugChunkSize+1)))
+	assert.NoError(err)
+	chunks := out.take()
+	assert.Len(chunks, 2)
+	assert.Len(chunks[1], 1)
+	assert.Empty(out.take())
+
+	_, err = out.Write([]byte("second"))
+	assert.NoError(err)
+	out.restore([]string{"first "})
+	assert.Equal([]string{"first second"}, out.take())
+
+	_, err = out.Write(make([]byte, maxDebugOutputBuffer+10))
+	assert.NoError(err)
+	chunks = out.take()
+	assert.Contains(chunks[len(chunks)-1], "10 bytes of output dropped")
+}
+
+func TestDebugInputDoesNotBlock(t *testing.T) {
+	// nothing drains the queue, as when the shell stops reading
+	in := &debugInput{pending: make(chan string, 2)}
+	assert.NoError(t, in.write("one"))
+	assert.NoError(t, in.write("two"))
+	assert.Error(t, in.write("three"))
+}
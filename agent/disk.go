@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/recovery"
+	"github.com/pkg/errors"
+)
+
+const mebibyte = 1024 * 1024
+
+// checkDiskSpace returns an error if the disk holding the distro's working
+// directory has less free space than the distro requires to start a task.
+func (a *Agent) checkDiskSpace(tc *taskContext) error {
+	d := tc.taskConfig.Distro
+	if d == nil || d.DiskGuard.MinFreeMB == 0 {
+		return nil
+	}
+
+	free, err := util.DiskFreeBytes(d.WorkDir)
+	if err != nil {
+		tc.logger.Execution().Warningf("Not checking free disk space: %v", err)
+		return nil
+	}
+	if free < uint64(d.DiskGuard.MinFreeMB)*mebibyte {
+		return errors.Errorf("the working directory's disk has %.1f MB free, but the distro requires %d MB to start a task",
+			float64(free)/mebibyte, d.DiskGuard.MinFreeMB)
+	}
+	return nil
+}
+
+// startDiskGuard periodically checks the task's working directory against
+// the distro's disk limits, and aborts the task if it exceeds them.
+func (a *Agent) startDiskGuard(ctx context.Context, tc *taskContext, cancel context.CancelFunc) {
+	defer recovery.LogStackTraceAndContinue("disk guard")
+
+	d := tc.taskConfig.Distro
+	if d == nil || (d.DiskGuard.FreeFloorMB == 0 && d.DiskGuard.WorkDirQuotaMB == 0) {
+		return
+	}
+	dir := tc.taskDirectory
+	if dir == "" {
+		dir = d.WorkDir
+	}
+
+	interval := defaultDiskGuardInterval
+	if a.opts.DiskGuardInterval != 0 {
+		interval = a.opts.DiskGuardInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := checkDiskUsage(dir, d.DiskGuard); err != nil {
+				tc.logger.Task().Errorf("Aborting task: %v", err)
+				tc.setDiskFailure(err)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// checkDiskUsage returns an error if the directory is larger than the
+// quota or its disk has less free space than the floor.
+func checkDiskUsage(dir string, guard distro.DiskGuardSettings) error {
+	if guard.WorkDirQuotaMB != 0 {
+		size, err := util.DirectorySize(dir)
+		if err == nil && size > int64(guard.WorkDirQuotaMB)*mebibyte {
+			return errors.Errorf("the task's working directory uses %.1f MB, which exceeds the distro's quota of %d MB",
+				float64(size)/mebibyte, guard.WorkDirQuotaMB)
+		}
+	}
+	if guard.FreeFloorMB != 0 {
+		free, err := util.DiskFreeBytes(dir)
+		if err == nil && free < uint64(guard.FreeFloorMB)*mebibyte {
+			return errors.Errorf("the working directory's disk has %.1f MB free, which is below the distro's floor of %d MB",
+				float64(free)/mebibyte, guard.FreeFloorMB)
+		}
+	}
+	return nil
+}
+
+func (tc *taskContext) setDiskFailure(err error) {
+	tc.Lock()
+	defer tc.Unlock()
+	tc.diskFailure = err
+}
+
+func (tc *taskContext) getDiskFailure() error {
+	tc.RLock()
+	defer tc.RUnlock()
+	return tc.diskFailure
+}
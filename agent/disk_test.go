@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDiskUsage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "disk-guard")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "big"), make([]byte, 2*mebibyte), 0644))
+
+	assert.NoError(checkDiskUsage(dir, distro.DiskGuardSettings{}))
+	assert.NoError(checkDiskUsage(dir, distro.DiskGuardSettings{WorkDirQuotaMB: 10}))
+	assert.Error(checkDiskUsage(dir, distro.DiskGuardSettings{WorkDirQuotaMB: 1}))
+	assert.NoError(checkDiskUsage(dir, distro.DiskGuardSettings{FreeFloorMB: 1}))
+	assert.Error(checkDiskUsage(dir, distro.DiskGuardSettings{FreeFloorMB: 1 << 40}))
+}
+
+func TestDiskGuardAbortsTask(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "disk-guard")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "big"), make([]byte, 2*mebibyte), 0644))
+
+	a := &Agent{
+		opts: Options{DiskGuardInterval: 10 * time.Millisecond},
+		comm: client.NewMock("url"),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := &taskContext{
+		task:          client.TaskData{ID: "task_id", Secret: "task_secret"},
+		taskDirectory: dir,
+		taskConfig: &model.TaskConfig{
+			Distro: &distro.Distro{WorkDir: dir, DiskGuard: distro.DiskGuardSettings{MinFreeMB: 1, WorkDirQuotaMB: 1}},
+		},
+	}
+	tc.logger = a.comm.GetLoggerProducer(ctx, tc.task)
+	factory, ok := command.GetCommandFactory("setup.initial")
+	require.True(ok)
+	tc.setCurrentCommand(factory())
+
+	assert.NoError(a.checkDiskSpace(tc))
+	tc.taskConfig.Distro.DiskGuard.MinFreeMB = 1 << 40
+	assert.Error(a.checkDiskSpace(tc))
+
+	taskCtx, taskCancel := context.WithCancel(ctx)
+	go a.startDiskGuard(taskCtx, tc, taskCancel)
+	select {
+	case <-taskCtx.Done():
+	case <-time.After(5 * time.Second):
+		require.FailNow("disk guard did not abort the task")
+	}
+	assert.Error(tc.getDiskFailure())
+
+	detail := a.endTaskResponse(tc, evergreen.TaskFailed)
+	assert.Equal(evergreen.TaskFailed, detail.Status)
+	assert.Equal(evergreen.CommandTypeSystem, detail.Type)
+	assert.True(detail.OutOfDisk)
+	assert.Contains(detail.Description, "quota of 1 MB")
+
+	assert.False(a.endTaskResponse(tc, evergreen.TaskUndispatched).OutOfDisk)
+}
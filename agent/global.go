@@ -28,6 +28,22 @@ const (
 	// to API server
 	defaultStatsInterval = time.Minute
 
+	// defaultDiskGuardInterval is the interval after which the agent checks
+	// the size of the task's working directory and the free space on its
+	// disk against the distro's limits.
+	defaultDiskGuardInterval = 30 * time.Second
+
+	// defaultDebugPollInterval is the interval after which the agent checks
+	// whether a user has opened a debug session into the task, and
+	// defaultDebugExchangeInterval is how often it relays an open session's
+	// input and output.
+	defaultDebugPollInterval     = 10 * time.Second
+	defaultDebugExchangeInterval = 500 * time.Millisecond
+
+	// defaultPausePollInterval is the interval after which the agent checks
+	// whether a task paused on failure has been resumed or aborted.
+	defaultPausePollInterval = 30 * time.Second
+
 	// defaultCallbackCmdTimeout specifies the duration after when the "post" or
 	// "timeout" command sets should be shut down.
 	defaultCallbackCmdTimeout = 15 * time.Minute
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/pkg/errors"
+)
+
+// RunLocalTask runs a task's pre, task and post commands, as well as its
+// timeout commands if it hits its exec timeout, in the configuration's
+// working directory. Nothing is reported to an API server beyond what the
+// commands themselves send through the agent's communicator, which is
+// meant to be a client.LocalCommunicator. It returns an error if a task
+// command fails.
+func (a *Agent) RunLocalTask(ctx context.Context, conf *model.TaskConfig) error {
+	if conf == nil || conf.Task == nil || conf.Project == nil {
+		return errors.New("task configuration is incomplete")
+	}
+
+	tc := &taskContext{
+		task: client.TaskData{
+			ID:     conf.Task.Id,
+			Secret: conf.Task.Secret,
+		},
+		taskConfig:    conf,
+		taskDirectory: conf.WorkDir,
+	}
+	tc.logger = a.comm.GetLoggerProducer(ctx, tc.task)
+	defer tc.logger.Close()
+
+	factory, ok := command.GetCommandFactory("setup.initial")
+	if !ok {
+		return errors.New("problem during configuring initial state")
+	}
+	tc.setCurrentCommand(factory())
+
+	execCtx, cancel := context.WithTimeout(ctx, tc.getExecTimeout())
+	defer cancel()
+
+	a.runPreTaskCommands(execCtx, tc)
+	err := a.runTaskCommands(execCtx, tc)
+	if errors.Cause(execCtx.Err()) == context.DeadlineExceeded && ctx.Err() == nil {
+		tc.logger.Execution().Errorf("Hit exec timeout (%s)", tc.getExecTimeout())
+		tc.reachTimeOut()
+		a.runTaskTimeoutCommands(ctx, tc)
+	}
+
+	if ctx.Err() == nil {
+		a.runPostTaskCommands(ctx, tc)
+	}
+	if err != nil {
+		tc.logger.Task().Info("Task completed - FAILURE.")
+		return errors.Wrapf(err, "task '%s' failed", conf.Task.DisplayName)
+	}
+	tc.logger.Task().Info("Task completed - SUCCESS.")
+	return nil
+}
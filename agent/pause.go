@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+// pauseOnFailure asks the API server whether the task, whose command just
+// failed, should be held for inspection. If so, it waits before the task's
+// post commands run until a user resumes or aborts the task, or the pause
+// runs out. Tasks that failed because they were canceled or timed out are
+// not paused.
+func (a *Agent) pauseOnFailure(ctx context.Context, tc *taskContext) {
+	if ctx.Err() != nil || tc.hadTimedOut() {
+		return
+	}
+	conf := tc.getTaskConfig()
+	if conf == nil || conf.Project == nil || conf.Task == nil {
+		return
+	}
+
+	req := &apimodels.TaskPauseRequest{
+		Configured:  conf.Project.ShouldPauseOnFailure(conf.Task.BuildVariant, conf.Task.DisplayName),
+		TimeoutSecs: int(conf.Project.PauseTimeout().Seconds()),
+	}
+	if cmd := tc.getCurrentCommand(); cmd != nil {
+		req.FailedCommand = cmd.DisplayName()
+	}
+
+	resp, err := a.comm.PauseTask(ctx, tc.task, req)
+	if err != nil {
+		tc.logger.Execution().Warningf("Could not check whether to pause task: %v", err)
+		return
+	}
+	if !resp.Paused {
+		return
+	}
+
+	tc.logger.Task().Infof("Command '%s' failed. Pausing task until %s so that the host can be inspected; resume or abort the task to end the pause.",
+		req.FailedCommand, resp.Until.Format(time.RFC3339))
+	tc.setPaused(true)
+	defer func() {
+		tc.setPaused(false)
+		// the task wrote nothing while it was paused
+		a.comm.UpdateLastMessageTime()
+	}()
+
+	interval := defaultPausePollInterval
+	if a.opts.PausePollInterval != 0 {
+		interval = a.opts.PausePollInterval
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	until := resp.Until
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if time.Now().After(until) {
+				tc.logger.Task().Info("Pause ran out, finishing task.")
+				grip.Warning(message.WrapError(a.comm.UnpauseTask(ctx, tc.task), message.Fields{
+					"message": "problem unpausing task",
+					"task_id": tc.task.ID,
+				}))
+				return
+			}
+
+			state, err := a.comm.GetTaskPauseState(ctx, tc.task)
+			if err != nil {
+				grip.Debug(message.WrapError(err, message.Fields{
+					"message": "problem checking whether task is paused",
+					"task_id": tc.task.ID,
+				}))
+			} else if !state.Paused {
+				tc.logger.Task().Info("Task was resumed, finishing task.")
+				return
+			} else {
+				until = state.Until
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// setPaused records when the task starts and stops being paused, so that
+// the time spent paused doesn't count toward its timeouts.
+func (tc *taskContext) setPaused(paused bool) {
+	tc.Lock()
+	defer tc.Unlock()
+
+	if paused {
+		tc.pausedAt = time.Now()
+		return
+	}
+	if !tc.pausedAt.IsZero() {
+		tc.pausedFor += time.Since(tc.pausedAt)
+		tc.pausedAt = time.Time{}
+	}
+}
+
+func (tc *taskContext) isPaused() bool {
+	tc.RLock()
+	defer tc.RUnlock()
+
+	return !tc.pausedAt.IsZero()
+}
+
+// getPausedDuration returns how long the task has been paused in total.
+func (tc *taskContext) getPausedDuration() time.Duration {
+	tc.RLock()
+	defer tc.RUnlock()
+
+	if tc.pausedAt.IsZero() {
+		return tc.pausedFor
+	}
+	return tc.pausedFor + time.Since(tc.pausedAt)
+}
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makePauseTaskContext(ctx context.Context, comm *client.Mock) *taskContext {
+	tc := &taskContext{
+		task: client.TaskData{ID: "task_id", Secret: "task_secret"},
+		taskConfig: &model.TaskConfig{
+			Task: &task.Task{Id: "task_id", BuildVariant: "bv", DisplayName: "compile"},
+			Project: &model.Project{
+				PauseOnFailure:   true,
+				PauseTimeoutSecs: 600,
+			},
+		},
+	}
+	tc.logger = comm.GetLoggerProducer(ctx, tc.task)
+	return tc
+}
+
+func TestPauseOnFailure(t *testing.T) {
+	for name, test := range map[string]func(*testing.T, *Agent, *client.Mock, *taskContext){
+		"ResumedByUser": func(t *testing.T, a *Agent, comm *client.Mock, tc *taskContext) {
+			comm.PauseResponse = &apimodels.TaskPauseResponse{Paused: true, Until: time.Now().Add(time.Hour)}
+
+			done := make(chan struct{})
+			go func() {
+				a.pauseOnFailure(context.Background(), tc)
+				close(done)
+			}()
+
+			deadline := time.Now().Add(5 * time.Second)
+			for !tc.isPaused() {
+				require.True(t, time.Now().Before(deadline), "task did not pause")
+				time.Sleep(time.Millisecond)
+			}
+			comm.ClearTaskPause()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "task was not resumed")
+			}
+			assert.False(t, tc.isPaused())
+			assert.True(t, tc.getPausedDuration() > 0)
+			assert.False(t, comm.Unpaused)
+
+			reqs := comm.GetPauseRequests()
+			require.Len(t, reqs, 1)
+			assert.True(t, reqs[0].Configured)
+			assert.Equal(t, 600, reqs[0].TimeoutSecs)
+		},
+		"PauseRunsOut": func(t *testing.T, a *Agent, comm *client.Mock, tc *taskContext) {
+			comm.PauseResponse = &apimodels.TaskPauseResponse{Paused: true, Until: time.Now().Add(20 * time.Millisecond)}
+
+			a.pauseOnFailure(context.Background(), tc)
+			assert.False(t, tc.isPaused())
+			assert.True(t, comm.Unpaused)
+		},
+		"NotPaused": func(t *testing.T, a *Agent, comm *client.Mock, tc *taskContext) {
+			tc.taskConfig.Project.PauseOnFailure = false
+
+			a.pauseOnFailure(context.Background(), tc)
+			assert.Zero(t, tc.getPausedDuration())
+
+			reqs := comm.GetPauseRequests()
+			require.Len(t, reqs, 1)
+			assert.False(t, reqs[0].Configured)
+		},
+		"TimedOutTasksAreNotPaused": func(t *testing.T, a *Agent, comm *client.Mock, tc *taskContext) {
+			comm.PauseResponse = &apimodels.TaskPauseResponse{Paused: true, Until: time.Now().Add(time.Hour)}
+			tc.reachTimeOut()
+
+			a.pauseOnFailure(context.Background(), tc)
+			assert.Empty(t, comm.GetPauseRequests())
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			comm := client.NewMock("url")
+			a := &Agent{
+				opts: Options{PausePollInterval: time.Millisecond},
+				comm: comm,
+			}
+			test(t, a, comm, makePauseTaskContext(ctx, comm))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/pkg/errors"
+)
+
+// setupResourceLimits places the processes that the task's commands
+// start in a cgroup that enforces the task's resource limits, if the
+// task has any. Hosts that cannot enforce the limits run the task
+// without them.
+func (a *Agent) setupResourceLimits(tc *taskContext) {
+	tc.setCgroup(nil)
+
+	pt := tc.taskConfig.Project.FindProjectTask(tc.taskConfig.Task.DisplayName)
+	if pt == nil || pt.ResourceLimits == nil {
+		return
+	}
+	limits := subprocess.ResourceLimits{
+		MemoryMB:     pt.ResourceLimits.MemoryMB,
+		CPUShares:    pt.ResourceLimits.CPUShares,
+		MaxProcesses: pt.ResourceLimits.MaxProcesses,
+		DiskWriteMB:  pt.ResourceLimits.DiskWriteMB,
+	}
+	if limits.IsZero() {
+		return
+	}
+
+	cg, err := subprocess.NewTaskCgroup(tc.task.ID, limits)
+	if err != nil {
+		tc.logger.Execution().Warningf("Not enforcing resource limits: %v", err)
+		return
+	}
+	tc.setCgroup(cg)
+	tc.logger.Execution().Infof("Enforcing resource limits (memory: %d MB, cpu shares: %d, processes: %d, disk writes: %d MB).",
+		limits.MemoryMB, limits.CPUShares, limits.MaxProcesses, limits.DiskWriteMB)
+}
+
+// startCommandResources starts accounting for the resources that a
+// command uses.
+func (a *Agent) startCommandResources(tc *taskContext, name string) {
+	if err := tc.getCgroup().StartCommand(name); err != nil {
+		tc.logger.Execution().Warningf("Not accounting for resources used by %s: %v", name, err)
+	}
+}
+
+// endCommandResources logs the peak resource usage of a command,
+// returning an error if the task exceeded a resource limit while the
+// command ran.
+func (a *Agent) endCommandResources(tc *taskContext, name string) error {
+	cg := tc.getCgroup()
+	if cg == nil {
+		return nil
+	}
+
+	usage, err := cg.EndCommand()
+	if err == nil {
+		tc.logger.Execution().Infof("Command %s used %s.", name, usage)
+		return nil
+	}
+	if limitErr, ok := errors.Cause(err).(*subprocess.ResourceLimitError); ok {
+		tc.logger.Execution().Infof("Command %s used %s.", name, usage)
+		tc.logger.Task().Errorf("Task exceeded a resource limit: %v", limitErr)
+		return limitErr
+	}
+	tc.logger.Execution().Warningf("Problem accounting for resources used by %s: %v", name, err)
+	return nil
+}
+
+// closeResourceLimits removes the task's cgroup.
+func (a *Agent) closeResourceLimits(tc *taskContext) {
+	if err := tc.getCgroup().Close(); err != nil {
+		tc.logger.Execution().Warningf("Problem removing the task's cgroup: %v", err)
+	}
+}
+
+func (tc *taskContext) setCgroup(cg *subprocess.TaskCgroup) {
+	tc.Lock()
+	defer tc.Unlock()
+	tc.cgroup = cg
+}
+
+func (tc *taskContext) getCgroup() *subprocess.TaskCgroup {
+	tc.RLock()
+	defer tc.RUnlock()
+	return tc.cgroup
+}
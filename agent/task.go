@@ -93,6 +93,14 @@ func (a *Agent) startTask(ctx context.Context, tc *taskContext, complete chan<-
 	taskConfig.Expansions.Update(expVars.Vars)
 	taskConfig.Redacted = expVars.PrivateVars
 	tc.setTaskConfig(taskConfig)
+	a.setupResourceLimits(tc)
+
+	if err = a.checkDiskSpace(tc); err != nil {
+		tc.logger.Task().Errorf("Not starting task: %v", err)
+		tc.setDiskFailure(err)
+		complete <- evergreen.TaskFailed
+		return
+	}
 
 	// set up the system stats collector
 	tc.statsCollector = NewSimpleStatsCollector(
@@ -118,6 +126,7 @@ func (a *Agent) startTask(ctx context.Context, tc *taskContext, complete chan<-
 	}
 	tc.taskConfig.WorkDir = tc.taskDirectory
 	taskConfig.Expansions.Put("workdir", tc.taskConfig.WorkDir)
+	go a.startDiskGuard(innerCtx, tc, cancel)
 
 	// notify API server that the task has been started.
 	tc.logger.Execution().Info("Reporting task started.")
@@ -127,10 +136,13 @@ func (a *Agent) startTask(ctx context.Context, tc *taskContext, complete chan<-
 		return
 	}
 
+	go a.startDebugSessions(innerCtx, tc)
+
 	a.killProcs(tc, false)
 	a.runPreTaskCommands(innerCtx, tc)
 
 	if err = a.runTaskCommands(innerCtx, tc); err != nil {
+		a.pauseOnFailure(innerCtx, tc)
 		complete <- evergreen.TaskFailed
 		return
 	}
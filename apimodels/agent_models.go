@@ -33,6 +33,7 @@ type TaskEndDetail struct {
 	Type        string `bson:"type,omitempty" json:"type,omitempty"`
 	Description string `bson:"desc,omitempty" json:"desc,omitempty"`
 	TimedOut    bool   `bson:"timed_out,omitempty" json:"timed_out,omitempty"`
+	OutOfDisk   bool   `bson:"out_of_disk,omitempty" json:"out_of_disk,omitempty"`
 }
 
 type TaskEndDetails struct {
@@ -0,0 +1,29 @@
+package apimodels
+
+const (
+	// CacheOperationSave and CacheOperationRestore identify which
+	// cache command produced a CacheEvent.
+	CacheOperationSave    = "save"
+	CacheOperationRestore = "restore"
+)
+
+// CacheEvent reports the outcome of a cache.save or cache.restore
+// command to the API server, which uses it to account for hits and
+// misses and to track the objects it may later evict. The server
+// derives the path of an object from the project and key, never from
+// the task.
+type CacheEvent struct {
+	Operation string `json:"operation"`
+	// Bucket is the bucket that the command saved to or restored from.
+	Bucket string `json:"bucket"`
+	// Key is the key requested by the command, and MatchedKey is the
+	// key that was actually saved or restored. On a restore they
+	// differ when a fallback prefix matched, and MatchedKey is empty
+	// on a miss.
+	Key        string `json:"key"`
+	MatchedKey string `json:"matched_key"`
+	SizeBytes  int64  `json:"size_bytes"`
+	// Exists is set on a save that was skipped because the key was
+	// already in the cache.
+	Exists bool `json:"exists"`
+}
@@ -0,0 +1,34 @@
+package apimodels
+
+// DebugChunkSize is the largest piece of shell output the agent sends in a
+// single chunk.
+const DebugChunkSize = 32 * 1024
+
+// DebugSession describes a debug session that a user has requested into the
+// agent's current task. An empty ID means there is no session to start.
+type DebugSession struct {
+	ID   string `json:"id"`
+	User string `json:"user"`
+}
+
+// DebugChunk is one numbered piece of a debug session's input.
+type DebugChunk struct {
+	Seq  int    `json:"seq"`
+	Data string `json:"data"`
+}
+
+// DebugExchangeRequest is sent by the agent to report a debug session's
+// shell output and how much of its input the agent has received.
+type DebugExchangeRequest struct {
+	Output   []string `json:"output"`
+	InputAck int      `json:"input_ack"`
+	Closed   bool     `json:"closed"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// DebugExchangeResponse carries the input the user has typed since the
+// agent's acknowledgment, and whether the user has closed the session.
+type DebugExchangeResponse struct {
+	Input  []DebugChunk `json:"input"`
+	Closed bool         `json:"closed"`
+}
@@ -0,0 +1,18 @@
+package apimodels
+
+import "time"
+
+// TaskPauseRequest is sent by the agent when a command of its task fails.
+// Configured reports whether the task's project configuration pauses it on
+// failure; the server also pauses tasks of patches that ask for it.
+type TaskPauseRequest struct {
+	Configured    bool   `json:"configured"`
+	TimeoutSecs   int    `json:"timeout_secs"`
+	FailedCommand string `json:"failed_command"`
+}
+
+// TaskPauseResponse reports whether the task is paused, and until when.
+type TaskPauseResponse struct {
+	Paused bool      `json:"paused"`
+	Until  time.Time `json:"until"`
+}
@@ -64,7 +64,11 @@ type ContainerManager interface {
 	// CalculateImageSpaceUsage returns the total space taken up by docker images on a specified host
 	CalculateImageSpaceUsage(ctx context.Context, h *host.Host) (int64, error)
 	// BuildContainerImage downloads and builds a container image onto parent specified by URL
+	// or registry reference
 	BuildContainerImage(ctx context.Context, parent *host.Host, url string) error
+	// RemoveContainerImage removes a container image from a parent unless a running
+	// container uses it, and returns whether it was removed
+	RemoveContainerImage(ctx context.Context, h *host.Host, image string) (bool, error)
 }
 
 // CostCalculator is an interface for cloud providers that can estimate what a span of time on a
@@ -19,6 +19,7 @@ type HostOptions struct {
 	HasContainers         bool
 	ParentID              string
 	ContainerPoolSettings *evergreen.ContainerPool
+	ContainerResources    *evergreen.ContainerResources
 	SpawnOptions          host.SpawnOptions
 }
 
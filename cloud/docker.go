@@ -24,23 +24,44 @@ type dockerManager struct {
 type dockerSettings struct {
 	// ImageURL is the url of the Docker image to use when building the container.
 	ImageURL string `mapstructure:"image_url" json:"image_url" bson:"image_url"`
+	// Image is a reference to the Docker image in a registry to use when
+	// building the container, such as "registry.example.com/image:tag". A
+	// reference that includes a digest is verified when it is pulled.
+	Image string `mapstructure:"image" json:"image" bson:"image"`
 }
 
 // nolint
 var (
 	// bson fields for the ProviderSettings struct
 	imageURLKey = bsonutil.MustHaveTag(dockerSettings{}, "ImageURL")
+	imageKey    = bsonutil.MustHaveTag(dockerSettings{}, "Image")
 )
 
 //Validate checks that the settings from the config file are sane.
 func (settings *dockerSettings) Validate() error {
-	if settings.ImageURL == "" {
-		return errors.New("ImageURL must not be blank")
+	if settings.ImageURL == "" && settings.Image == "" {
+		return errors.New("ImageURL or Image must not be blank")
+	}
+	if settings.ImageURL != "" && settings.Image != "" {
+		return errors.New("only one of ImageURL and Image may be set")
+	}
+	if settings.Image != "" {
+		if _, err := parseImageReference(settings.Image); err != nil {
+			return errors.WithStack(err)
+		}
 	}
 
 	return nil
 }
 
+// image returns the URL or registry reference of the container's image.
+func (settings *dockerSettings) image() string {
+	if settings.Image != "" {
+		return settings.Image
+	}
+	return settings.ImageURL
+}
+
 // GetSettings returns an empty ProviderSettings struct.
 func (*dockerManager) GetSettings() ProviderSettings {
 	return &dockerSettings{}
@@ -79,7 +100,7 @@ func (m *dockerManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host
 		"message":   "decoded Docker container settings",
 		"container": h.Id,
 		"host_ip":   hostIP,
-		"image_url": settings.ImageURL,
+		"image":     settings.image(),
 	})
 
 	// Create container
@@ -89,6 +110,13 @@ func (m *dockerManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host
 		return nil, err
 	}
 
+	grip.Warning(message.WrapError(parentHost.SetContainerImageUsed(settings.image(), time.Now()), message.Fields{
+		"message":   "problem recording use of container image",
+		"container": h.Id,
+		"parent":    parentHost.Id,
+		"image":     settings.image(),
+	}))
+
 	if err = h.SetAgentRevision(evergreen.BuildRevision); err != nil {
 		return nil, errors.Wrapf(err, "error setting agent revision on host %s", h.Id)
 	}
@@ -332,14 +360,22 @@ func (m *dockerManager) CostForDuration(ctx context.Context, h *host.Host, start
 }
 
 // BuildContainerImage downloads and buils a container image onto parent specified
-// by URL and returns this URL
+// by URL or registry reference
 func (m *dockerManager) BuildContainerImage(ctx context.Context, parent *host.Host, url string) error {
 	if !parent.HasContainers {
 		return errors.Errorf("Error provisioning image: '%s' is not a parent", parent.Id)
 	}
 
-	// Import correct base image if not already on host.
-	image, err := m.client.EnsureImageDownloaded(ctx, parent, url)
+	// Import or pull correct base image if not already on host.
+	var (
+		image string
+		err   error
+	)
+	if isImageURL(url) {
+		image, err = m.client.EnsureImageDownloaded(ctx, parent, url)
+	} else {
+		image, err = m.client.PullImage(ctx, parent, url)
+	}
 	if err != nil {
 		return errors.Wrapf(err, "Unable to ensure that image '%s' is on host '%s'", url, parent.Id)
 	}
@@ -352,3 +388,50 @@ func (m *dockerManager) BuildContainerImage(ctx context.Context, parent *host.Ho
 
 	return nil
 }
+
+// RemoveContainerImage removes the base and provisioned images built from a
+// container image from a parent, unless a running container uses either,
+// and returns whether they were removed.
+func (m *dockerManager) RemoveContainerImage(ctx context.Context, h *host.Host, image string) (bool, error) {
+	baseImage, err := baseImageName(image)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	names := map[string]bool{
+		baseImage:                       true,
+		provisionedImageName(baseImage): true,
+	}
+
+	images, err := m.client.ListImages(ctx, h)
+	if err != nil {
+		return false, errors.Wrap(err, "Error listing images")
+	}
+
+	ids := []string{}
+	for _, summary := range images {
+		for _, name := range append(summary.RepoTags, summary.RepoDigests...) {
+			if names[name] {
+				ids = append(ids, summary.ID)
+				break
+			}
+		}
+	}
+
+	for _, id := range ids {
+		canBeRemoved, err := m.canImageBeRemoved(ctx, h, id)
+		if err != nil {
+			return false, errors.Wrapf(err, "Error checking whether containers are running on image '%s'", id)
+		}
+		if !canBeRemoved {
+			return false, nil
+		}
+	}
+
+	// remove the provisioned image, which is the newest, before its base
+	for i := len(ids) - 1; i >= 0; i-- {
+		if err = m.client.RemoveImage(ctx, h, ids[i]); err != nil {
+			return false, errors.Wrapf(err, "Error removing image '%s'", ids[i])
+		}
+	}
+	return true, nil
+}
@@ -28,6 +28,7 @@ import (
 type dockerClient interface {
 	Init(string) error
 	EnsureImageDownloaded(context.Context, *host.Host, string) (string, error)
+	PullImage(context.Context, *host.Host, string) (string, error)
 	BuildImageWithAgent(context.Context, *host.Host, string) (string, error)
 	CreateContainer(context.Context, *host.Host, *host.Host, *dockerSettings) error
 	GetContainer(context.Context, *host.Host, string) (*types.ContainerJSON, error)
@@ -174,6 +175,84 @@ func (c *dockerClientImpl) EnsureImageDownloaded(ctx context.Context, h *host.Ho
 	}
 }
 
+// PullImage checks if the image specified by the registry reference already
+// exists, and if not, pulls it from its registry. An image pinned by digest is
+// removed again if the pulled image does not have that digest.
+func (c *dockerClientImpl) PullImage(ctx context.Context, h *host.Host, image string) (string, error) {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	imageName := ref.String()
+
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	// Check if image already exists on host
+	_, _, err = dockerClient.ImageInspectWithRaw(ctx, imageName)
+	if err == nil {
+		return imageName, nil
+	} else if !docker.IsErrNotFound(err) && !strings.Contains(err.Error(), "No such image") {
+		return "", errors.Wrapf(err, "Error inspecting image %s", imageName)
+	}
+
+	auth, err := registryAuth(c.evergreenSettings, ref)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	// Extend http client timeout for ImagePull
+	normalTimeout := c.httpClient.Timeout
+	dockerClient, err = c.changeTimeout(h, imageImportTimeout)
+	if err != nil {
+		return "", errors.Wrap(err, "Error changing http client timeout")
+	}
+
+	msg := makeDockerLogMessage("ImagePull", h.Id, message.Fields{
+		"image_name": imageName,
+		"registry":   ref.Registry,
+	})
+	resp, err := dockerClient.ImagePull(ctx, imageName, types.ImagePullOptions{RegistryAuth: auth})
+	if err != nil {
+		return "", errors.Wrapf(err, "Error pulling image %s", imageName)
+	}
+	grip.Info(msg)
+
+	// Wait until ImagePull finishes
+	_, err = ioutil.ReadAll(resp)
+	grip.Warning(message.WrapError(resp.Close(), message.Fields{
+		"message": "problem closing ImagePull response",
+		"image":   imageName,
+		"host":    h.Id,
+	}))
+	if err != nil {
+		return "", errors.Wrap(err, "Error reading ImagePull response")
+	}
+
+	// Reset http client timeout
+	dockerClient, err = c.changeTimeout(h, normalTimeout)
+	if err != nil {
+		return "", errors.Wrap(err, "Error changing http client timeout")
+	}
+
+	inspect, _, err := dockerClient.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error inspecting pulled image %s", imageName)
+	}
+	if ref.Digest != "" && !hasDigest(inspect.RepoDigests, ref.Digest) {
+		grip.Warning(message.WrapError(c.RemoveImage(ctx, h, inspect.ID), message.Fields{
+			"message": "problem removing image with unexpected digest",
+			"image":   imageName,
+			"host":    h.Id,
+		}))
+		return "", errors.Errorf("image %s pulled from %s does not have digest %s", imageName, ref.Registry, ref.Digest)
+	}
+
+	return imageName, nil
+}
+
 // BuildImageWithAgent takes a base image and builds a new image on the specified
 // host from a Dockfile in the root directory, which adds the Evergreen binary
 func (c *dockerClientImpl) BuildImageWithAgent(ctx context.Context, h *host.Host, baseImage string) (string, error) {
@@ -185,7 +264,7 @@ func (c *dockerClientImpl) BuildImageWithAgent(ctx context.Context, h *host.Host
 	}
 
 	// modify tag for new image
-	provisionedImage := fmt.Sprintf(provisionedImageTag, baseImage)
+	provisionedImage := provisionedImageName(baseImage)
 
 	executableSubPath := h.Distro.ExecutableSubPath()
 	binaryName := h.Distro.BinaryName()
@@ -238,9 +317,12 @@ func (c *dockerClientImpl) CreateContainer(ctx context.Context, parentHost, cont
 		return errors.Wrap(err, "Failed to generate docker client")
 	}
 
-	// Extract image name from url
-	baseName := path.Base(settings.ImageURL)
-	provisionedImage := fmt.Sprintf(provisionedImageTag, strings.TrimSuffix(baseName, filepath.Ext(baseName)))
+	// Extract image name from url or registry reference
+	baseImage, err := baseImageName(settings.image())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	provisionedImage := provisionedImageName(baseImage)
 
 	// Build path to Evergreen executable.
 	pathToExecutable := filepath.Join("root", "evergreen")
@@ -266,6 +348,9 @@ func (c *dockerClientImpl) CreateContainer(ctx context.Context, parentHost, cont
 		fmt.Sprintf("--working_directory=%s", containerHost.Distro.WorkDir),
 		"--cleanup",
 	}
+	if c.evergreenSettings.Tracer.Enabled {
+		agentCmdParts = append(agentCmdParts, fmt.Sprintf("--trace_collector=%s", c.evergreenSettings.Tracer.CollectorEndpoint))
+	}
 
 	// Populate container settings with command and new image.
 	containerConf := &container.Config{
@@ -275,9 +360,13 @@ func (c *dockerClientImpl) CreateContainer(ctx context.Context, parentHost, cont
 	}
 	networkConf := &network.NetworkingConfig{}
 	hostConf := &container.HostConfig{}
+	if containerHost.ContainerResources != nil {
+		hostConf.Resources = containerResourceLimits(*containerHost.ContainerResources)
+	}
 
 	msg := makeDockerLogMessage("ContainerCreate", parentHost.Id, message.Fields{
-		"image": containerConf.Image,
+		"image":     containerConf.Image,
+		"resources": containerHost.ContainerResources,
 	})
 
 	// Build container
@@ -291,6 +380,15 @@ func (c *dockerClientImpl) CreateContainer(ctx context.Context, parentHost, cont
 	return nil
 }
 
+// containerResourceLimits converts the resources that a container requests
+// into the limits that Docker enforces. Unset resources are unlimited.
+func containerResourceLimits(r evergreen.ContainerResources) container.Resources {
+	return container.Resources{
+		NanoCPUs: int64(r.CPUs * 1e9),
+		Memory:   int64(r.MemoryMB) * 1024 * 1024,
+	}
+}
+
 // GetContainer returns low-level information on the Docker container with the
 // specified ID running on the specified host machine.
 func (c *dockerClientImpl) GetContainer(ctx context.Context, h *host.Host, containerID string) (*types.ContainerJSON, error) {
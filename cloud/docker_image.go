@@ -0,0 +1,172 @@
+package cloud
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRegistryHost = "docker.io"
+	defaultImageTag     = "latest"
+	provisionedTagStem  = "provisioned"
+	maxImageTagLength   = 128
+)
+
+var (
+	imageRepositoryRegexp = regexp.MustCompile(`^([a-zA-Z0-9.-]+(:[0-9]+)?/)?[a-z0-9]+([._-]+[a-z0-9]+)*(/[a-z0-9]+([._-]+[a-z0-9]+)*)*$`)
+	imageTagRegexp        = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+	imageDigestRegexp     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// imageReference is a reference to an image in a registry, such as
+// "registry.example.com/team/image:1.0@sha256:<hex>".
+type imageReference struct {
+	// Registry is the host of the registry the image is pulled from.
+	Registry string
+	// Repository is the image's name, including its registry if the
+	// reference named one.
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// isImageURL returns true if a container image is a URL of an image
+// tarball rather than a registry reference.
+func isImageURL(image string) bool {
+	return strings.Contains(image, "://")
+}
+
+// parseImageReference parses a registry reference to an image. A reference
+// without a tag or digest refers to the image's latest tag.
+func parseImageReference(image string) (imageReference, error) {
+	ref := imageReference{}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !imageDigestRegexp.MatchString(ref.Digest) {
+			return ref, errors.Errorf("image '%s' has an invalid digest", image)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+		if !imageTagRegexp.MatchString(ref.Tag) {
+			return ref, errors.Errorf("image '%s' has an invalid tag", image)
+		}
+	}
+	if !imageRepositoryRegexp.MatchString(name) {
+		return ref, errors.Errorf("image '%s' has an invalid name", image)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultImageTag
+	}
+	ref.Repository = name
+
+	ref.Registry = defaultRegistryHost
+	if parts := strings.SplitN(name, "/", 2); len(parts) == 2 &&
+		(strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+	}
+	return ref, nil
+}
+
+// String returns the reference that the image is pulled and inspected by,
+// which is by digest if the reference pins one.
+func (r imageReference) String() string {
+	if r.Digest != "" {
+		return r.Repository + "@" + r.Digest
+	}
+	return r.Repository + ":" + r.Tag
+}
+
+// baseImageName returns the name of the image that a container image is
+// imported or pulled as on a parent.
+func baseImageName(image string) (string, error) {
+	if isImageURL(image) {
+		baseName := path.Base(image)
+		return strings.TrimSuffix(baseName, filepath.Ext(baseName)), nil
+	}
+
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return ref.String(), nil
+}
+
+// provisionedImageName returns the name of the image, built from a base
+// image, that includes the Evergreen binary.
+func provisionedImageName(baseImage string) string {
+	ref, err := parseImageReference(baseImage)
+	if err != nil || (!strings.Contains(baseImage, ":") && !strings.Contains(baseImage, "@")) {
+		return fmt.Sprintf(provisionedImageTag, baseImage)
+	}
+
+	suffix := ref.Tag
+	if ref.Digest != "" {
+		suffix = strings.TrimPrefix(ref.Digest, "sha256:")[:12]
+	}
+	tag := provisionedTagStem + "-" + suffix
+	if len(tag) > maxImageTagLength {
+		tag = tag[:maxImageTagLength]
+	}
+	return ref.Repository + ":" + tag
+}
+
+// hasDigest returns true if one of an image's repository digests is the
+// digest.
+func hasDigest(repoDigests []string, digest string) bool {
+	for _, d := range repoDigests {
+		if strings.HasSuffix(d, "@"+digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// registryAuth returns the encoded credentials for the registry that the
+// image is pulled from, or an empty string if none are configured.
+func registryAuth(settings *evergreen.Settings, ref imageReference) (string, error) {
+	if settings == nil {
+		return "", nil
+	}
+	registry := settings.ContainerPools.GetRegistry(ref.Registry)
+	if registry == nil {
+		return "", nil
+	}
+
+	auth, err := json.Marshal(types.AuthConfig{
+		Username:      registry.Username,
+		Password:      registry.Password,
+		ServerAddress: registry.Host,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "problem encoding credentials for registry '%s'", registry.Host)
+	}
+	return base64.URLEncoding.EncodeToString(auth), nil
+}
+
+// GetContainerImage returns the image that a container distro's hosts are
+// started from: either the URL of an image tarball or a registry reference.
+func GetContainerImage(d *distro.Distro) (string, error) {
+	settings := &dockerSettings{}
+	if d.ProviderSettings != nil {
+		if err := mapstructure.Decode(d.ProviderSettings, settings); err != nil {
+			return "", errors.Wrapf(err, "Error decoding params for distro '%s'", d.Id)
+		}
+	}
+	if err := settings.Validate(); err != nil {
+		return "", errors.Wrapf(err, "Invalid Docker settings for distro '%s'", d.Id)
+	}
+	return settings.image(), nil
+}
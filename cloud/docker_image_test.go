@@ -0,0 +1,131 @@
+package cloud
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParseImageReference(t *testing.T) {
+	assert := assert.New(t)
+
+	ref, err := parseImageReference("ubuntu")
+	assert.NoError(err)
+	assert.Equal(imageReference{Registry: "docker.io", Repository: "ubuntu", Tag: "latest"}, ref)
+	assert.Equal("ubuntu:latest", ref.String())
+
+	ref, err = parseImageReference("registry.example.com:5000/team/image:1.0")
+	assert.NoError(err)
+	assert.Equal("registry.example.com:5000", ref.Registry)
+	assert.Equal("registry.example.com:5000/team/image", ref.Repository)
+	assert.Equal("1.0", ref.Tag)
+
+	ref, err = parseImageReference("localhost/image:1.0@" + testDigest)
+	assert.NoError(err)
+	assert.Equal("localhost", ref.Registry)
+	assert.Equal(testDigest, ref.Digest)
+	assert.Equal("localhost/image@"+testDigest, ref.String())
+
+	for _, image := range []string{
+		"",
+		"Image",
+		"image:",
+		"image:-tag",
+		"image@sha256:abc",
+		"image@" + strings.ToUpper(testDigest),
+	} {
+		_, err = parseImageReference(image)
+		assert.Error(err, image)
+	}
+}
+
+func TestContainerImageNames(t *testing.T) {
+	assert := assert.New(t)
+
+	base, err := baseImageName("http://0.0.0.0:8000/docker_image.tgz")
+	assert.NoError(err)
+	assert.Equal("docker_image", base)
+	assert.Equal("docker_image:provisioned", provisionedImageName(base))
+
+	base, err = baseImageName("registry.example.com/image")
+	assert.NoError(err)
+	assert.Equal("registry.example.com/image:latest", base)
+	assert.Equal("registry.example.com/image:provisioned-latest", provisionedImageName(base))
+
+	base, err = baseImageName("registry.example.com/image:1.0@" + testDigest)
+	assert.NoError(err)
+	assert.Equal("registry.example.com/image@"+testDigest, base)
+	assert.Equal("registry.example.com/image:provisioned-0123456789ab", provisionedImageName(base))
+
+	_, err = baseImageName("Image")
+	assert.Error(err)
+
+	assert.True(hasDigest([]string{"registry.example.com/image@" + testDigest}, testDigest))
+	assert.False(hasDigest([]string{"registry.example.com/image@sha256:abc"}, testDigest))
+	assert.False(hasDigest(nil, testDigest))
+}
+
+func TestRegistryAuth(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	settings := &evergreen.Settings{
+		ContainerPools: evergreen.ContainerPoolsConfig{
+			Registries: []evergreen.ContainerRegistry{
+				{Host: "registry.example.com", Username: "user", Password: "password"},
+			},
+		},
+	}
+
+	ref, err := parseImageReference("registry.example.com/image:1.0")
+	require.NoError(err)
+	auth, err := registryAuth(settings, ref)
+	require.NoError(err)
+	raw, err := base64.URLEncoding.DecodeString(auth)
+	require.NoError(err)
+	config := types.AuthConfig{}
+	require.NoError(json.Unmarshal(raw, &config))
+	assert.Equal("user", config.Username)
+	assert.Equal("password", config.Password)
+	assert.Equal("registry.example.com", config.ServerAddress)
+
+	ref, err = parseImageReference("image:1.0")
+	require.NoError(err)
+	auth, err = registryAuth(settings, ref)
+	assert.NoError(err)
+	assert.Empty(auth)
+
+	auth, err = registryAuth(nil, ref)
+	assert.NoError(err)
+	assert.Empty(auth)
+}
+
+func TestGetContainerImage(t *testing.T) {
+	assert := assert.New(t)
+
+	d := &distro.Distro{
+		Id:               "d",
+		ProviderSettings: &map[string]interface{}{"image": "registry.example.com/image:1.0"},
+	}
+	image, err := GetContainerImage(d)
+	assert.NoError(err)
+	assert.Equal("registry.example.com/image:1.0", image)
+
+	d.ProviderSettings = &map[string]interface{}{"image_url": "http://0.0.0.0:8000/docker_image.tgz"}
+	image, err = GetContainerImage(d)
+	assert.NoError(err)
+	assert.Equal("http://0.0.0.0:8000/docker_image.tgz", image)
+
+	d.ProviderSettings = nil
+	_, err = GetContainerImage(d)
+	assert.Error(err)
+}
@@ -17,6 +17,7 @@ type dockerClientMock struct {
 	// API call options
 	failInit     bool
 	failDownload bool
+	failPull     bool
 	failBuild    bool
 	failCreate   bool
 	failGet      bool
@@ -47,11 +48,18 @@ func (c *dockerClientMock) EnsureImageDownloaded(context.Context, *host.Host, st
 	return c.baseImage, nil
 }
 
+func (c *dockerClientMock) PullImage(context.Context, *host.Host, string) (string, error) {
+	if c.failPull {
+		return "", errors.New("failed to pull image")
+	}
+	return c.baseImage, nil
+}
+
 func (c *dockerClientMock) BuildImageWithAgent(context.Context, *host.Host, string) (string, error) {
 	if c.failBuild {
 		return "", errors.New("failed to build image with agent")
 	}
-	return fmt.Sprintf(provisionedImageTag, c.baseImage), nil
+	return provisionedImageName(c.baseImage), nil
 }
 
 func (c *dockerClientMock) CreateContainer(context.Context, *host.Host, *host.Host, *dockerSettings) error {
@@ -125,10 +133,12 @@ func (c *dockerClientMock) ListImages(context.Context, *host.Host) ([]types.Imag
 		ID:         "image-1",
 		Containers: 2,
 		Created:    now.Unix(),
+		RepoTags:   []string{provisionedImageName(c.baseImage)},
 	}
 	image2 := types.ImageSummary{
 		ID:         "image-2",
 		Containers: 2,
+		RepoTags:   []string{c.baseImage},
 		Created:    now.Add(-10 * time.Minute).Unix(),
 	}
 	return []types.ImageSummary{image1, image2}, nil
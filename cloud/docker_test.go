@@ -72,7 +72,25 @@ func (s *DockerSuite) TestValidateSettings() {
 
 	// error when missing image url
 	settingsNoImageURL := &dockerSettings{}
-	s.EqualError(settingsNoImageURL.Validate(), "ImageURL must not be blank")
+	s.EqualError(settingsNoImageURL.Validate(), "ImageURL or Image must not be blank")
+
+	// registry references are allowed in place of an image url
+	settingsImage := &dockerSettings{
+		Image: "registry.example.com/team/image:1.0",
+	}
+	s.NoError(settingsImage.Validate())
+	s.Equal("registry.example.com/team/image:1.0", settingsImage.image())
+
+	// error when both are set, or the reference is invalid
+	settingsBoth := &dockerSettings{
+		ImageURL: "http://0.0.0.0:8000/docker_image.tgz",
+		Image:    "image:1.0",
+	}
+	s.Error(settingsBoth.Validate())
+	settingsBadImage := &dockerSettings{
+		Image: "Image@sha256:abc",
+	}
+	s.Error(settingsBadImage.Validate())
 }
 
 func (s *DockerSuite) TestConfigureAPICall() {
@@ -352,6 +370,30 @@ func (s *DockerSuite) TestRemoveOldestImage() {
 	s.NoError(err)
 }
 
+func (s *DockerSuite) TestRemoveContainerImage() {
+	mock, ok := s.client.(*dockerClientMock)
+	s.True(ok)
+	mock.baseImage = "image"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parent, err := host.FindOneId("parent")
+	s.NoError(err)
+
+	removed, err := s.manager.RemoveContainerImage(ctx, parent, "http://0.0.0.0:8000/image.tgz")
+	s.NoError(err)
+	s.True(removed)
+
+	mock.failRemove = true
+	removed, err = s.manager.RemoveContainerImage(ctx, parent, "http://0.0.0.0:8000/image.tgz")
+	s.Error(err)
+	s.False(removed)
+
+	_, err = s.manager.RemoveContainerImage(ctx, parent, "Not A Reference")
+	s.Error(err)
+}
+
 func (s *DockerSuite) TestBuildContainerImage() {
 	mock, ok := s.client.(*dockerClientMock)
 	s.True(ok)
@@ -365,8 +407,26 @@ func (s *DockerSuite) TestBuildContainerImage() {
 	s.NoError(err)
 	s.Equal("parent", parent.Id)
 
-	err = s.manager.BuildContainerImage(ctx, parent, "image-url")
+	err = s.manager.BuildContainerImage(ctx, parent, "http://0.0.0.0:8000/image-url.tgz")
+	s.NoError(err)
+}
+
+func (s *DockerSuite) TestBuildContainerImageFromRegistry() {
+	mock, ok := s.client.(*dockerClientMock)
+	s.True(ok)
+	mock.failDownload = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parent, err := host.FindOneId("parent")
 	s.NoError(err)
+
+	s.NoError(s.manager.BuildContainerImage(ctx, parent, "registry.example.com/image:1.0"))
+
+	mock.failPull = true
+	err = s.manager.BuildContainerImage(ctx, parent, "registry.example.com/image:1.0")
+	s.EqualError(err, "Unable to ensure that image 'registry.example.com/image:1.0' is on host 'parent': failed to pull image")
 }
 
 func (s *DockerSuite) TestBuildContainerImageFailedDownload() {
@@ -382,8 +442,8 @@ func (s *DockerSuite) TestBuildContainerImageFailedDownload() {
 	s.NoError(err)
 	s.Equal("parent", parent.Id)
 
-	err = s.manager.BuildContainerImage(ctx, parent, "image-url")
-	s.EqualError(err, "Unable to ensure that image 'image-url' is on host 'parent': failed to download image")
+	err = s.manager.BuildContainerImage(ctx, parent, "http://0.0.0.0:8000/image-url.tgz")
+	s.EqualError(err, "Unable to ensure that image 'http://0.0.0.0:8000/image-url.tgz' is on host 'parent': failed to download image")
 }
 
 func (s *DockerSuite) TestBuildContainerImageFailedBuild() {
@@ -399,6 +459,6 @@ func (s *DockerSuite) TestBuildContainerImageFailedBuild() {
 	s.NoError(err)
 	s.Equal("parent", parent.Id)
 
-	err = s.manager.BuildContainerImage(ctx, parent, "image-url")
-	s.EqualError(err, "Failed to build image 'image-url' with agent on host 'parent': failed to build image with agent")
+	err = s.manager.BuildContainerImage(ctx, parent, "http://0.0.0.0:8000/image-url.tgz")
+	s.EqualError(err, "Failed to build image 'http://0.0.0.0:8000/image-url.tgz' with agent on host 'parent': failed to build image with agent")
 }
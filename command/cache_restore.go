@@ -0,0 +1,158 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/s3"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// cacheRestore extracts a tarball written by cache.save into a
+// directory. If no entry exists for the exact key, the most recently
+// saved entry whose key starts with one of the restore keys is used
+// instead. A miss is not an error.
+type cacheRestore struct {
+	cacheParams `mapstructure:",squash" plugin:"expand"`
+
+	// RestoreKeys are key prefixes that are tried, in order, when
+	// there is no entry for the exact key.
+	RestoreKeys []string `mapstructure:"restore_keys" plugin:"expand"`
+
+	base
+}
+
+func cacheRestoreFactory() Command   { return &cacheRestore{} }
+func (c *cacheRestore) Name() string { return "cache.restore" }
+
+func (c *cacheRestore) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding %s params", c.Name())
+	}
+
+	return errors.Wrapf(c.validate(), "error validating %s params", c.Name())
+}
+
+func (c *cacheRestore) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.Wrap(err, "error expanding params")
+	}
+	if err := c.validate(); err != nil {
+		return errors.Wrap(err, "expanded params are not valid")
+	}
+
+	key, err := c.resolve(conf.WorkDir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	httpClient := util.GetHTTPClient()
+	defer util.PutHTTPClient(httpClient)
+	bucket := c.bucket(httpClient)
+
+	event := &apimodels.CacheEvent{
+		Operation: apimodels.CacheOperationRestore,
+		Bucket:    c.Bucket,
+		Key:       key,
+	}
+
+	remotePath, err := c.findEntry(bucket, conf.Task.Project, key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	if remotePath == "" {
+		logger.Task().Infof("no cache entry found for key '%s'", key)
+		return errors.Wrap(comm.SendCacheEvent(ctx, td, event), "problem reporting cache miss")
+	}
+
+	event.MatchedKey = buildcache.KeyFromRemotePath(conf.Task.Project, remotePath)
+	logger.Task().Infof("restoring cache key '%s' into '%s'", event.MatchedKey, c.Path)
+
+	if err = os.MkdirAll(c.Path, 0755); err != nil {
+		return errors.Wrapf(err, "problem creating directory '%s'", c.Path)
+	}
+
+	reader, err := bucket.GetReader(remotePath)
+	if err != nil {
+		return errors.Wrapf(err, "problem fetching cache key '%s'", event.MatchedKey)
+	}
+	defer reader.Close()
+
+	if err = util.ExtractTarball(ctx, reader, c.Path, []string{}); err != nil {
+		return errors.Wrapf(err, "problem extracting cache key '%s'", event.MatchedKey)
+	}
+
+	return errors.Wrap(comm.SendCacheEvent(ctx, td, event), "problem reporting cache restore")
+}
+
+// findEntry returns the remote path of the entry for the exact key or,
+// failing that, of the newest entry that matches a restore key. It
+// returns an empty string when nothing matches.
+func (c *cacheRestore) findEntry(bucket *s3.Bucket, project, key string) (string, error) {
+	remotePath := buildcache.RemotePath(project, key)
+	exists, err := bucket.Exists(remotePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem checking for cache key '%s'", key)
+	}
+	if exists {
+		return remotePath, nil
+	}
+
+	for _, prefix := range c.RestoreKeys {
+		remotePrefix := path.Join(buildcache.RemotePrefix, project, prefix)
+		newest, err := newestObjectWithPrefix(bucket, remotePrefix)
+		if err != nil {
+			return "", errors.Wrapf(err, "problem listing cache keys with prefix '%s'", prefix)
+		}
+		if newest != "" {
+			return newest, nil
+		}
+	}
+
+	return "", nil
+}
+
+func newestObjectWithPrefix(bucket *s3.Bucket, prefix string) (string, error) {
+	var (
+		newest     string
+		newestTime time.Time
+		marker     string
+	)
+
+	for {
+		resp, err := bucket.List(prefix, "", marker, 1000)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		for _, obj := range resp.Contents {
+			modified, err := time.Parse(time.RFC3339Nano, obj.LastModified)
+			if err != nil {
+				return "", errors.Wrapf(err, "problem parsing modification time of '%s'", obj.Key)
+			}
+			if newest == "" || modified.After(newestTime) {
+				newest = obj.Key
+				newestTime = modified
+			}
+			marker = obj.Key
+		}
+
+		if !resp.IsTruncated || len(resp.Contents) == 0 {
+			break
+		}
+	}
+
+	return newest, nil
+}
@@ -0,0 +1,152 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/s3"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// cacheSave archives a directory and stores it in a bucket under a key
+// derived from expansions and file contents, so that later tasks with
+// identical inputs can restore it with cache.restore instead of
+// rebuilding it.
+type cacheSave struct {
+	cacheParams `mapstructure:",squash" plugin:"expand"`
+
+	// Include and ExcludeFiles select the files under path to
+	// archive, as in archive.targz_pack. Include defaults to every
+	// file.
+	Include      []string `mapstructure:"include" plugin:"expand"`
+	ExcludeFiles []string `mapstructure:"exclude_files" plugin:"expand"`
+
+	base
+}
+
+func cacheSaveFactory() Command   { return &cacheSave{} }
+func (c *cacheSave) Name() string { return "cache.save" }
+
+func (c *cacheSave) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding %s params", c.Name())
+	}
+
+	if len(c.Include) == 0 {
+		c.Include = []string{"**"}
+	}
+
+	return errors.Wrapf(c.validate(), "error validating %s params", c.Name())
+}
+
+func (c *cacheSave) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.Wrap(err, "error expanding params")
+	}
+	if err := c.validate(); err != nil {
+		return errors.Wrap(err, "expanded params are not valid")
+	}
+
+	key, err := c.resolve(conf.WorkDir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	httpClient := util.GetHTTPClient()
+	defer util.PutHTTPClient(httpClient)
+	bucket := c.bucket(httpClient)
+	remotePath := buildcache.RemotePath(conf.Task.Project, key)
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+
+	exists, err := bucket.Exists(remotePath)
+	if err != nil {
+		return errors.Wrapf(err, "problem checking for existing cache key '%s'", key)
+	}
+	if exists {
+		logger.Task().Infof("cache key '%s' already exists, skipping save", key)
+		// the entry is still in use, so it must not be evicted
+		err = comm.SendCacheEvent(ctx, td, &apimodels.CacheEvent{
+			Operation:  apimodels.CacheOperationSave,
+			Bucket:     c.Bucket,
+			Key:        key,
+			MatchedKey: key,
+			Exists:     true,
+		})
+		return errors.Wrap(err, "problem reporting cache save")
+	}
+
+	archive, err := ioutil.TempFile("", "evergreen-cache")
+	if err != nil {
+		return errors.Wrap(err, "problem creating temporary archive")
+	}
+	archivePath := archive.Name()
+	grip.Warning(archive.Close())
+	defer func() {
+		logger.Execution().Warning(os.Remove(archivePath))
+	}()
+
+	numFiles, err := c.makeArchive(ctx, archivePath, logger)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if numFiles == 0 {
+		logger.Task().Warningf("no files in '%s' to cache for key '%s'", c.Path, key)
+		return nil
+	}
+
+	size, err := c.upload(bucket, archivePath, remotePath)
+	if err != nil {
+		return errors.Wrapf(err, "problem uploading cache key '%s'", key)
+	}
+	logger.Task().Infof("saved %d files (%d bytes) to cache key '%s'", numFiles, size, key)
+
+	err = comm.SendCacheEvent(ctx, td, &apimodels.CacheEvent{
+		Operation:  apimodels.CacheOperationSave,
+		Bucket:     c.Bucket,
+		Key:        key,
+		MatchedKey: key,
+		SizeBytes:  size,
+	})
+	return errors.Wrap(err, "problem reporting cache save")
+}
+
+func (c *cacheSave) makeArchive(ctx context.Context, target string, logger client.LoggerProducer) (int, error) {
+	f, gz, tarWriter, err := util.TarGzWriter(target)
+	if err != nil {
+		return -1, errors.Wrapf(err, "error opening target archive file %s", target)
+	}
+	defer func() {
+		logger.Execution().CatchError(tarWriter.Close())
+		logger.Execution().CatchError(gz.Close())
+		logger.Execution().CatchError(f.Close())
+	}()
+
+	out, err := util.BuildArchive(ctx, tarWriter, c.Path, c.Include, c.ExcludeFiles, logger.Execution())
+	return out, errors.WithStack(err)
+}
+
+func (c *cacheSave) upload(bucket *s3.Bucket, archivePath, remotePath string) (int64, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	err = bucket.PutReader(remotePath, f, info.Size(), "application/x-gzip", s3.Private, s3.Options{})
+	return info.Size(), errors.WithStack(err)
+}
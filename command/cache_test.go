@@ -0,0 +1,236 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
+	"github.com/goamz/goamz/s3"
+	"github.com/goamz/goamz/s3/s3test"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	server *s3test.Server
+	comm   *client.Mock
+	conf   *model.TaskConfig
+	logger client.LoggerProducer
+	params map[string]interface{}
+
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) SetupTest() {
+	var err error
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.server, err = s3test.NewServer(&s3test.Config{})
+	s.Require().NoError(err)
+
+	workDir, err := ioutil.TempDir("", "cache-test")
+	s.Require().NoError(err)
+
+	s.comm = client.NewMock("http://localhost.com")
+	s.conf = &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"build_variant": "bv"}),
+		Task:       &task.Task{Id: "task", Project: "proj"},
+		WorkDir:    workDir,
+	}
+	s.logger = s.comm.GetLoggerProducer(s.ctx, client.TaskData{ID: s.conf.Task.Id, Secret: s.conf.Task.Secret})
+	s.params = map[string]interface{}{
+		"aws_key":    "key",
+		"aws_secret": "secret",
+		"bucket":     "cache-bucket",
+		"endpoint":   s.server.URL(),
+		"key":        "deps-${build_variant}",
+		"path":       "deps",
+	}
+
+	// the test server only creates buckets with an explicit location
+	region := aws.Region{Name: "faux-region-1", S3Endpoint: s.server.URL(), S3LocationConstraint: true}
+	s.Require().NoError(s3.New(aws.Auth{}, region).Bucket("cache-bucket").PutBucket(s3.Private))
+}
+
+func (s *CacheSuite) TearDownTest() {
+	s.cancel()
+	s.server.Quit()
+	s.NoError(os.RemoveAll(s.conf.WorkDir))
+}
+
+func (s *CacheSuite) writeFile(name, content string) {
+	fn := filepath.Join(s.conf.WorkDir, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(fn), 0755))
+	s.Require().NoError(ioutil.WriteFile(fn, []byte(content), 0644))
+}
+
+func (s *CacheSuite) bucket() *s3.Bucket {
+	region := aws.Region{Name: "faux-region-1", S3Endpoint: s.server.URL(), S3LocationConstraint: true}
+	return s3.New(aws.Auth{}, region).Bucket("cache-bucket")
+}
+
+func (s *CacheSuite) save(params map[string]interface{}) {
+	cmd := cacheSaveFactory()
+	s.Require().NoError(cmd.ParseParams(params))
+	s.Require().NoError(cmd.Execute(s.ctx, s.comm, s.logger, s.conf))
+}
+
+func (s *CacheSuite) restore(params map[string]interface{}) {
+	cmd := cacheRestoreFactory()
+	s.Require().NoError(cmd.ParseParams(params))
+	s.Require().NoError(cmd.Execute(s.ctx, s.comm, s.logger, s.conf))
+}
+
+func (s *CacheSuite) TestParamsAreValidated() {
+	for _, name := range []string{"aws_key", "aws_secret", "bucket", "key", "path"} {
+		params := map[string]interface{}{}
+		for k, v := range s.params {
+			params[k] = v
+		}
+		delete(params, name)
+
+		s.Error(cacheSaveFactory().ParseParams(params), name)
+		s.Error(cacheRestoreFactory().ParseParams(params), name)
+	}
+	s.NoError(cacheSaveFactory().ParseParams(s.params))
+	s.NoError(cacheRestoreFactory().ParseParams(s.params))
+}
+
+func (s *CacheSuite) TestSaveAndRestoreExactKey() {
+	s.writeFile("deps/lib/a.txt", "a")
+	s.writeFile("deps/b.txt", "b")
+	s.save(s.params)
+
+	s.Require().Len(s.comm.CacheEvents, 1)
+	saved := s.comm.CacheEvents[0]
+	s.Equal(apimodels.CacheOperationSave, saved.Operation)
+	s.Equal("cache-bucket", saved.Bucket)
+	s.Equal("deps-bv", saved.MatchedKey)
+	s.False(saved.Exists)
+	s.True(saved.SizeBytes > 0)
+	exists, err := s.bucket().Exists("build-cache/proj/deps-bv.tgz")
+	s.NoError(err)
+	s.True(exists)
+
+	s.Require().NoError(os.RemoveAll(filepath.Join(s.conf.WorkDir, "deps")))
+	s.restore(s.params)
+
+	content, err := ioutil.ReadFile(filepath.Join(s.conf.WorkDir, "deps", "lib", "a.txt"))
+	s.NoError(err)
+	s.Equal("a", string(content))
+
+	s.Require().Len(s.comm.CacheEvents, 2)
+	restored := s.comm.CacheEvents[1]
+	s.Equal(apimodels.CacheOperationRestore, restored.Operation)
+	s.Equal("cache-bucket", restored.Bucket)
+	s.Equal("deps-bv", restored.Key)
+	s.Equal("deps-bv", restored.MatchedKey)
+}
+
+func (s *CacheSuite) TestSaveSkipsExistingKey() {
+	s.writeFile("deps/a.txt", "a")
+	s.save(s.params)
+	s.save(s.params)
+
+	s.Require().Len(s.comm.CacheEvents, 2)
+	s.False(s.comm.CacheEvents[0].Exists)
+	s.True(s.comm.CacheEvents[1].Exists)
+	s.Equal("deps-bv", s.comm.CacheEvents[1].MatchedKey)
+	s.Zero(s.comm.CacheEvents[1].SizeBytes)
+}
+
+func (s *CacheSuite) TestKeyCannotLeaveProjectPrefix() {
+	s.writeFile("deps/a.txt", "a")
+	s.params["key"] = "../other/deps"
+	cmd := cacheSaveFactory()
+	s.Require().NoError(cmd.ParseParams(s.params))
+	s.Error(cmd.Execute(s.ctx, s.comm, s.logger, s.conf))
+	s.Empty(s.comm.CacheEvents)
+}
+
+func (s *CacheSuite) TestRestoreMiss() {
+	s.restore(s.params)
+
+	s.Require().Len(s.comm.CacheEvents, 1)
+	s.Equal("deps-bv", s.comm.CacheEvents[0].Key)
+	s.Empty(s.comm.CacheEvents[0].MatchedKey)
+	_, err := os.Stat(filepath.Join(s.conf.WorkDir, "deps"))
+	s.True(os.IsNotExist(err))
+}
+
+func (s *CacheSuite) TestHashFilesChangeKeyAndFallBackToPrefix() {
+	s.writeFile("go.sum", "v1")
+	s.writeFile("deps/a.txt", "old")
+	s.params["hash_files"] = []string{"go.sum"}
+	s.save(s.params)
+	s.Require().Len(s.comm.CacheEvents, 1)
+	oldKey := s.comm.CacheEvents[0].MatchedKey
+	s.Contains(oldKey, "deps-bv-")
+
+	// a later save must be strictly newer for the prefix lookup
+	time.Sleep(10 * time.Millisecond)
+	s.writeFile("go.sum", "v2")
+	s.writeFile("deps/a.txt", "new")
+	s.save(s.params)
+	s.Require().Len(s.comm.CacheEvents, 2)
+	newKey := s.comm.CacheEvents[1].MatchedKey
+	s.NotEqual(oldKey, newKey)
+
+	s.writeFile("go.sum", "v3")
+	s.Require().NoError(os.RemoveAll(filepath.Join(s.conf.WorkDir, "deps")))
+	s.params["restore_keys"] = []string{"nothing-", "deps-${build_variant}-"}
+	s.restore(s.params)
+
+	s.Require().Len(s.comm.CacheEvents, 3)
+	restored := s.comm.CacheEvents[2]
+	s.NotEqual(restored.Key, restored.MatchedKey)
+	s.Equal(newKey, restored.MatchedKey)
+	content, err := ioutil.ReadFile(filepath.Join(s.conf.WorkDir, "deps", "a.txt"))
+	s.NoError(err)
+	s.Equal("new", string(content))
+}
+
+func (s *CacheSuite) TestHashFileContentsIsStable() {
+	s.writeFile("a/x.txt", "x")
+	s.writeFile("b/y.txt", "y")
+
+	first, err := hashFileContents(s.conf.WorkDir, []string{"*.txt"})
+	s.NoError(err)
+	second, err := hashFileContents(s.conf.WorkDir, []string{"*.txt"})
+	s.NoError(err)
+	s.Equal(first, second)
+
+	s.writeFile("b/y.txt", "z")
+	third, err := hashFileContents(s.conf.WorkDir, []string{"*.txt"})
+	s.NoError(err)
+	s.NotEqual(first, third)
+
+	_, err = hashFileContents(s.conf.WorkDir, []string{"*.none"})
+	s.Error(err)
+}
+
+func (s *CacheSuite) TestHashFileContentsDelimitsNamesFromContents() {
+	s.writeFile("a/x.txt", "yz")
+	first, err := hashFileContents(s.conf.WorkDir, []string{"a/*"})
+	s.NoError(err)
+
+	s.Require().NoError(os.RemoveAll(filepath.Join(s.conf.WorkDir, "a")))
+	s.writeFile("a/x.txty", "z")
+	second, err := hashFileContents(s.conf.WorkDir, []string{"a/*"})
+	s.NoError(err)
+	s.NotEqual(first, second)
+}
@@ -0,0 +1,139 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
+	"github.com/goamz/goamz/s3"
+	"github.com/pkg/errors"
+)
+
+// cacheParams holds the parameters shared by cache.save and
+// cache.restore.
+type cacheParams struct {
+	// AwsKey and AwsSecret are the credentials for the cache bucket.
+	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
+	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
+
+	// Bucket is the bucket that holds cache tarballs, and Endpoint
+	// optionally points at an S3-compatible service other than AWS.
+	Bucket   string `mapstructure:"bucket" plugin:"expand"`
+	Endpoint string `mapstructure:"endpoint" plugin:"expand"`
+
+	// Key identifies the cached content, e.g. "deps-${build_variant}".
+	// When HashFiles is set, a digest of the content of the matching
+	// files is appended to the key, so that changing any of them
+	// produces a new key.
+	Key       string   `mapstructure:"key" plugin:"expand"`
+	HashFiles []string `mapstructure:"hash_files" plugin:"expand"`
+
+	// Path is the directory that is archived on save and extracted
+	// into on restore.
+	Path string `mapstructure:"path" plugin:"expand"`
+}
+
+func (p *cacheParams) validate() error {
+	if p.AwsKey == "" {
+		return errors.New("aws_key cannot be blank")
+	}
+	if p.AwsSecret == "" {
+		return errors.New("aws_secret cannot be blank")
+	}
+	if p.Key == "" {
+		return errors.New("key cannot be blank")
+	}
+	if p.Path == "" {
+		return errors.New("path cannot be blank")
+	}
+	if err := validateS3BucketName(p.Bucket); err != nil {
+		return errors.Wrapf(err, "%v is an invalid bucket name", p.Bucket)
+	}
+	return nil
+}
+
+// resolve makes the path absolute and computes the final cache key.
+func (p *cacheParams) resolve(workDir string) (string, error) {
+	if !filepath.IsAbs(p.Path) {
+		p.Path = filepath.Join(workDir, p.Path)
+	}
+
+	key := p.Key
+	if len(p.HashFiles) > 0 {
+		digest, err := hashFileContents(workDir, p.HashFiles)
+		if err != nil {
+			return "", errors.Wrap(err, "problem hashing key files")
+		}
+		key = fmt.Sprintf("%s-%s", p.Key, digest)
+	}
+
+	return key, errors.WithStack(buildcache.ValidateKey(key))
+}
+
+func (p *cacheParams) bucket(client *http.Client) *s3.Bucket {
+	region := aws.USEast
+	if p.Endpoint != "" {
+		region = aws.Region{
+			Name:       "custom",
+			S3Endpoint: p.Endpoint,
+		}
+	}
+
+	session := thirdparty.NewS3Session(&aws.Auth{
+		AccessKey: p.AwsKey,
+		SecretKey: p.AwsSecret,
+	}, region, client)
+
+	return session.Bucket(p.Bucket)
+}
+
+// hashFileContents returns a hex digest of the names and contents of
+// every file under root that matches one of the expressions. Each file
+// contributes its name and the digest of its contents, delimited, so
+// that moving bytes between a name and the contents changes the digest.
+// The digest does not depend on the order in which files are found.
+func hashFileContents(root string, expressions []string) (string, error) {
+	files, err := util.BuildFileList(root, expressions...)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if len(files) == 0 {
+		return "", errors.Errorf("no files match %s", strings.Join(expressions, ", "))
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, fn := range files {
+		fileHash := sha256.New()
+		if err = copyFileInto(fileHash, filepath.Join(root, fn)); err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		if _, err = fmt.Fprintf(hash, "%s\x00%x\n", filepath.ToSlash(fn), fileHash.Sum(nil)); err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func copyFileInto(w io.Writer, fn string) error {
+	f, err := os.Open(fn)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening %s", fn)
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return errors.Wrapf(err, "problem reading %s", fn)
+}
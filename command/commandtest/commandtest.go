@@ -0,0 +1,318 @@
+// Package commandtest runs agent commands outside of the agent, against a
+// mock API server and a synthetic task, so that command authors can test
+// their commands without an evergreen deployment.
+//
+// A test runs a registered command with Run and makes assertions about
+// what the command sent to the API server with the Result's helpers:
+//
+//	res, err := commandtest.Run(ctx, "json.send", commandtest.Options{
+//		Params: map[string]interface{}{"name": "perf", "file": "perf.json"},
+//		Files:  map[string]string{"perf.json": `{"ops_per_sec": 100}`},
+//	})
+//	require.NoError(t, err)
+//	defer res.Close()
+//	res.AssertSucceeded(t)
+//	res.AssertJSONData(t, "perf")
+package commandtest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Identifiers of the synthetic task. The ID and secret are those that the
+// mock communicator accepts.
+const (
+	TaskID      = "mock_id"
+	TaskSecret  = "mock_secret"
+	TaskName    = "mock_task"
+	VariantName = "mock_variant"
+	ProjectID   = "mock_project"
+	VersionID   = "mock_version"
+	Revision    = "abcdef0123456789abcdef0123456789abcdef01"
+)
+
+// Options configure a command run.
+type Options struct {
+	// Params are the command's parameters, as they would appear in a
+	// project configuration file.
+	Params map[string]interface{}
+	// Expansions are set in addition to those the agent sets for every
+	// task.
+	Expansions map[string]string
+	// Files are written into the working directory, by path relative to
+	// it, before the command runs.
+	Files map[string]string
+	// WorkDir is the task's working directory. If it is empty, a
+	// temporary directory is created and is removed by Result.Close.
+	WorkDir string
+	// Comm is the communicator the command uses. If it is nil, a new
+	// mock is used.
+	Comm *client.Mock
+	// Timeout bounds the command's execution. It defaults to a minute.
+	Timeout time.Duration
+}
+
+// Result is the outcome of a command run.
+type Result struct {
+	// Err is the error the command returned.
+	Err error
+	// Comm is the communicator the command used, which records what the
+	// command sent.
+	Comm *client.Mock
+	// Config is the task configuration the command ran with.
+	Config *model.TaskConfig
+	// WorkDir is the task's working directory.
+	WorkDir string
+	// ExpansionUpdates are the expansions that the command added or
+	// changed, with their new values.
+	ExpansionUpdates map[string]string
+
+	removeWorkDir bool
+}
+
+// NewTaskConfig returns the configuration of a synthetic task that runs in
+// workDir, with the expansions the agent would set for it.
+func NewTaskConfig(workDir string) (*model.TaskConfig, error) {
+	now := time.Now()
+	t := &task.Task{
+		Id:           TaskID,
+		Secret:       TaskSecret,
+		Project:      ProjectID,
+		Version:      VersionID,
+		BuildId:      VersionID,
+		BuildVariant: VariantName,
+		DisplayName:  TaskName,
+		Revision:     Revision,
+		Requester:    evergreen.RepotrackerVersionRequester,
+		CreateTime:   now,
+		StartTime:    now,
+		Status:       evergreen.TaskStarted,
+	}
+	v := &version.Version{
+		Id:         VersionID,
+		Identifier: ProjectID,
+		Revision:   Revision,
+		Requester:  evergreen.RepotrackerVersionRequester,
+		CreateTime: now,
+	}
+	p := &model.Project{
+		Identifier: ProjectID,
+		BuildVariants: model.BuildVariants{
+			{Name: VariantName, Tasks: []model.BuildVariantTaskUnit{{Name: TaskName}}},
+		},
+		Tasks: []model.ProjectTask{{Name: TaskName}},
+	}
+	d := &distro.Distro{Id: "mock_distro", WorkDir: workDir}
+	ref := &model.ProjectRef{Identifier: ProjectID, Enabled: true}
+
+	conf, err := model.NewTaskConfig(d, v, p, t, ref, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem building task configuration")
+	}
+	conf.Redacted = map[string]bool{}
+	return conf, nil
+}
+
+// Run parses the parameters of the registered command with the given name
+// and executes it as the synthetic task. An error is returned if the
+// command can't be set up; the command's own error is in the Result. The
+// Result must be closed to remove a temporary working directory.
+func Run(ctx context.Context, name string, opts Options) (*Result, error) {
+	cmds, err := command.Render(model.PluginCommandConf{
+		Command:     name,
+		DisplayName: name,
+		Params:      opts.Params,
+	}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem rendering command '%s'", name)
+	}
+
+	res := &Result{
+		Comm:    opts.Comm,
+		WorkDir: opts.WorkDir,
+	}
+	if res.Comm == nil {
+		res.Comm = client.NewMock("http://localhost")
+	}
+	if res.WorkDir == "" {
+		if res.WorkDir, err = ioutil.TempDir("", "commandtest"); err != nil {
+			return nil, errors.Wrap(err, "problem creating working directory")
+		}
+		res.removeWorkDir = true
+	}
+	if err = writeFiles(res.WorkDir, opts.Files); err != nil {
+		return nil, errors.WithStack(res.closeOnError(err))
+	}
+
+	if res.Config, err = NewTaskConfig(res.WorkDir); err != nil {
+		return nil, errors.WithStack(res.closeOnError(err))
+	}
+	res.Config.Expansions.Update(opts.Expansions)
+	before := map[string]string{}
+	for k, v := range res.Config.Expansions.Map() {
+		before[k] = v
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logger := res.Comm.GetLoggerProducer(ctx, client.TaskData{ID: TaskID, Secret: TaskSecret})
+	for _, cmd := range cmds {
+		if res.Err = cmd.Execute(ctx, res.Comm, logger, res.Config); res.Err != nil {
+			break
+		}
+	}
+	if err = logger.Close(); err != nil {
+		return nil, errors.WithStack(res.closeOnError(err))
+	}
+
+	res.ExpansionUpdates = map[string]string{}
+	for k, v := range res.Config.Expansions.Map() {
+		if old, ok := before[k]; !ok || old != v {
+			res.ExpansionUpdates[k] = v
+		}
+	}
+
+	return res, nil
+}
+
+// Close removes the working directory if Run created it.
+func (r *Result) Close() error {
+	if !r.removeWorkDir {
+		return nil
+	}
+	return errors.Wrap(os.RemoveAll(r.WorkDir), "problem removing working directory")
+}
+
+func (r *Result) closeOnError(err error) error {
+	if closeErr := r.Close(); closeErr != nil {
+		return errors.Wrapf(err, "also failed to clean up: %s", closeErr)
+	}
+	return err
+}
+
+// Logs returns the messages the command logged.
+func (r *Result) Logs() []apimodels.LogMessage {
+	return r.Comm.GetMockMessages()[TaskID]
+}
+
+// AssertSucceeded asserts that the command did not return an error.
+func (r *Result) AssertSucceeded(t assert.TestingT) bool {
+	return assert.NoError(t, r.Err, "command failed")
+}
+
+// AssertFailed asserts that the command returned an error containing
+// each of the given strings.
+func (r *Result) AssertFailed(t assert.TestingT, contains ...string) bool {
+	if !assert.Error(t, r.Err, "command succeeded") {
+		return false
+	}
+	ok := true
+	for _, s := range contains {
+		ok = assert.Contains(t, r.Err.Error(), s) && ok
+	}
+	return ok
+}
+
+// AssertLogged asserts that a message the command logged contains the
+// string.
+func (r *Result) AssertLogged(t assert.TestingT, contains string) bool {
+	for _, msg := range r.Logs() {
+		if strings.Contains(msg.Message, contains) {
+			return true
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("no log message contains '%s'", contains))
+}
+
+// AssertTestResult asserts that the command sent a result with the status
+// for the test file.
+func (r *Result) AssertTestResult(t assert.TestingT, testFile, status string) bool {
+	for _, result := range r.Comm.GetTestResults() {
+		if result.TestFile == testFile {
+			return assert.Equal(t, status, result.Status, "status of test '%s'", testFile)
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("no result was sent for test '%s'", testFile))
+}
+
+// AssertTestLog asserts that the command sent a test log with the name.
+func (r *Result) AssertTestLog(t assert.TestingT, name string) bool {
+	for _, log := range r.Comm.GetTestLogs() {
+		if log.Name == name {
+			return true
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("no test log named '%s' was sent", name))
+}
+
+// AssertArtifact asserts that the command attached a file with the name
+// and link.
+func (r *Result) AssertArtifact(t assert.TestingT, name, link string) bool {
+	for _, file := range r.Comm.GetAttachedFiles(TaskID) {
+		if file.Name == name {
+			return assert.Equal(t, link, file.Link, "link of artifact '%s'", name)
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("no artifact named '%s' was attached", name))
+}
+
+// AssertJSONData asserts that the command posted JSON data with the name,
+// and returns the last data posted with it.
+func (r *Result) AssertJSONData(t assert.TestingT, name string) interface{} {
+	data := r.Comm.GetPostedJSONData(name)
+	if len(data) == 0 {
+		assert.Fail(t, fmt.Sprintf("no JSON data named '%s' was posted", name))
+		return nil
+	}
+	return data[len(data)-1]
+}
+
+// AssertExpansion asserts that the command set the expansion to the value.
+func (r *Result) AssertExpansion(t assert.TestingT, key, value string) bool {
+	actual, ok := r.ExpansionUpdates[key]
+	if !ok {
+		return assert.Fail(t, fmt.Sprintf("expansion '%s' was not updated", key))
+	}
+	return assert.Equal(t, value, actual, "value of expansion '%s'", key)
+}
+
+// AssertGeneratedTasks asserts that the command sent the number of
+// generate.tasks configurations.
+func (r *Result) AssertGeneratedTasks(t assert.TestingT, count int) bool {
+	return assert.Len(t, r.Comm.GetGeneratedTasks(), count, "generated task configurations")
+}
+
+func writeFiles(dir string, files map[string]string) error {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.Wrapf(err, "problem creating directory for '%s'", name)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			return errors.Wrapf(err, "problem writing '%s'", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package commandtest
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaskConfig(t *testing.T) {
+	conf, err := NewTaskConfig("/tmp/work")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/work", conf.WorkDir)
+	assert.Equal(t, TaskID, conf.Expansions.Get("task_id"))
+	assert.Equal(t, VariantName, conf.Expansions.Get("build_variant"))
+	assert.Equal(t, Revision, conf.Expansions.Get("revision"))
+}
+
+func TestRun(t *testing.T) {
+	for name, test := range map[string]func(*testing.T, context.Context){
+		"RecordsJSONData": func(t *testing.T, ctx context.Context) {
+			res, err := Run(ctx, "json.send", Options{
+				Params: map[string]interface{}{"name": "perf", "file": "perf.json"},
+				Files:  map[string]string{"perf.json": `{"ops_per_sec": 100}`},
+			})
+			require.NoError(t, err)
+			defer res.Close()
+
+			res.AssertSucceeded(t)
+			data := res.AssertJSONData(t, "perf")
+			assert.Equal(t, map[string]interface{}{"ops_per_sec": float64(100)}, data)
+		},
+		"RecordsExpansionUpdates": func(t *testing.T, ctx context.Context) {
+			res, err := Run(ctx, "expansions.update", Options{
+				Params: map[string]interface{}{
+					"updates": []map[string]interface{}{
+						{"key": "greeting", "value": "hello"},
+						{"key": "target", "value": "${greeting} world"},
+					},
+				},
+				Expansions: map[string]string{"target": "nobody"},
+			})
+			require.NoError(t, err)
+			defer res.Close()
+
+			res.AssertSucceeded(t)
+			res.AssertExpansion(t, "greeting", "hello")
+			res.AssertExpansion(t, "target", "hello world")
+			assert.Len(t, res.ExpansionUpdates, 2)
+		},
+		"RecordsGeneratedTasks": func(t *testing.T, ctx context.Context) {
+			res, err := Run(ctx, "generate.tasks", Options{
+				Params: map[string]interface{}{"files": []string{"generate.json"}},
+				Files:  map[string]string{"generate.json": `{"tasks": [{"name": "generated"}]}`},
+			})
+			require.NoError(t, err)
+			defer res.Close()
+
+			res.AssertSucceeded(t)
+			res.AssertGeneratedTasks(t, 1)
+		},
+		"RecordsFailuresAndLogs": func(t *testing.T, ctx context.Context) {
+			if runtime.GOOS == "windows" {
+				t.Skip("the script assumes a POSIX shell")
+			}
+			res, err := Run(ctx, "shell.exec", Options{
+				Params: map[string]interface{}{"script": "echo 'about to fail'; exit 1"},
+			})
+			require.NoError(t, err)
+			defer res.Close()
+
+			res.AssertFailed(t, "exit status 1")
+			res.AssertLogged(t, "about to fail")
+		},
+		"RemovesTemporaryWorkDir": func(t *testing.T, ctx context.Context) {
+			res, err := Run(ctx, "expansions.update", Options{})
+			require.NoError(t, err)
+			require.NoError(t, res.Close())
+
+			_, err = os.Stat(res.WorkDir)
+			assert.True(t, os.IsNotExist(err))
+		},
+		"UnregisteredCommand": func(t *testing.T, ctx context.Context) {
+			_, err := Run(ctx, "not.a_command", Options{})
+			assert.Error(t, err)
+		},
+		"InvalidParams": func(t *testing.T, ctx context.Context) {
+			_, err := Run(ctx, "json.send", Options{})
+			assert.Error(t, err)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			test(t, ctx)
+		})
+	}
+}
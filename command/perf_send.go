@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// perfSend sends performance results in a typed schema, which, unlike the
+// data that json.send sends, the server can store and query by test,
+// arguments and metric. The file holds the results of one or more tests:
+//
+//	{"results": [{
+//	    "name": "insert",
+//	    "args": {"thread_level": 8},
+//	    "metrics": [{"name": "ops_per_sec", "units": "ops/sec", "direction": "higher", "trials": [99, 101]}]
+//	}]}
+type perfSend struct {
+	File string `mapstructure:"file" plugin:"expand"`
+	base
+}
+
+type perfSendFile struct {
+	Results []perfSendResult `json:"results"`
+}
+
+type perfSendResult struct {
+	Name    string                 `json:"name"`
+	Args    map[string]interface{} `json:"args"`
+	Metrics []perf.Metric          `json:"metrics"`
+}
+
+func perfSendFactory() Command   { return &perfSend{} }
+func (c *perfSend) Name() string { return "perf.send" }
+
+func (c *perfSend) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding '%v' params", c.Name())
+	}
+
+	if c.File == "" {
+		return errors.New("'file' param must not be blank")
+	}
+
+	return nil
+}
+
+func (c *perfSend) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.WithStack(err)
+	}
+
+	results, err := c.readResults(filepath.Join(conf.WorkDir, c.File))
+	if err != nil {
+		logger.Task().Errorf("Reading perf results failed: %v", err)
+		return errors.WithStack(err)
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	errChan := make(chan error)
+	go func() {
+		errChan <- errors.Wrapf(comm.SendPerfResults(ctx, td, results),
+			"problem sending perf results for %s", td.ID)
+	}()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			logger.Task().Errorf("Sending perf results failed: %v", err)
+			return errors.WithStack(err)
+		}
+		logger.Task().Infof("Sent %d perf results from '%s'", len(results), c.File)
+		return nil
+	case <-ctx.Done():
+		logger.Execution().Info("Received abort signal, stopping.")
+		return nil
+	}
+}
+
+func (c *perfSend) readResults(path string) ([]perf.Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't open perf results file")
+	}
+	defer f.Close()
+
+	file := perfSendFile{}
+	if err = util.ReadJSONInto(f, &file); err != nil {
+		return nil, errors.Wrap(err, "file contained invalid json")
+	}
+	if len(file.Results) == 0 {
+		return nil, errors.Errorf("'%s' contains no perf results", c.File)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	results := make([]perf.Result, 0, len(file.Results))
+	for _, r := range file.Results {
+		result := perf.Result{
+			Test:    r.Name,
+			Args:    r.Args,
+			Metrics: r.Metrics,
+		}
+		catcher.Add(result.Validate())
+		results = append(results, result)
+	}
+	if catcher.HasErrors() {
+		return nil, errors.Wrap(catcher.Resolve(), "invalid perf results")
+	}
+	return results, nil
+}
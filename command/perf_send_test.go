@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type PerfSendSuite struct {
+	cancel func()
+	conf   *model.TaskConfig
+	comm   *client.Mock
+	logger client.LoggerProducer
+	ctx    context.Context
+
+	cmd *perfSend
+	suite.Suite
+}
+
+func TestPerfSendSuite(t *testing.T) {
+	suite.Run(t, new(PerfSendSuite))
+}
+
+func (s *PerfSendSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	dir, err := ioutil.TempDir("", "perf-send")
+	s.Require().NoError(err)
+	s.comm = client.NewMock("http://localhost.com")
+	s.conf = &model.TaskConfig{Expansions: &util.Expansions{"file": "perf.json"}, Task: &task.Task{}, Project: &model.Project{}, WorkDir: dir}
+	s.logger = s.comm.GetLoggerProducer(s.ctx, client.TaskData{ID: s.conf.Task.Id, Secret: s.conf.Task.Secret})
+	s.cmd = perfSendFactory().(*perfSend)
+	s.NoError(s.cmd.ParseParams(map[string]interface{}{"file": "${file}"}))
+}
+
+func (s *PerfSendSuite) TearDownTest() {
+	s.cancel()
+	s.NoError(os.RemoveAll(s.conf.WorkDir))
+}
+
+func (s *PerfSendSuite) writeFile(content string) {
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(s.conf.WorkDir, "perf.json"), []byte(content), 0644))
+}
+
+func (s *PerfSendSuite) TestParseParamsRequiresFile() {
+	s.Error(perfSendFactory().ParseParams(map[string]interface{}{}))
+}
+
+func (s *PerfSendSuite) TestSendsResults() {
+	s.writeFile(`{"results": [{
+		"name": "insert",
+		"args": {"thread_level": 8},
+		"metrics": [
+			{"name": "ops_per_sec", "units": "ops/sec", "trials": [99, 101]},
+			{"name": "latency", "units": "ms", "direction": "lower", "value": 4}
+		]
+	}]}`)
+	s.NoError(s.cmd.Execute(s.ctx, s.comm, s.logger, s.conf))
+
+	results := s.comm.GetPerfResults()
+	s.Require().Len(results, 1)
+	s.Equal("insert", results[0].Test)
+	s.Equal(float64(8), results[0].Args["thread_level"])
+	s.Require().Len(results[0].Metrics, 2)
+	s.Equal(perf.DirectionHigher, results[0].Metrics[0].Direction)
+	s.Equal(float64(100), results[0].Metrics[0].Value)
+	s.Equal([]float64{99, 101}, results[0].Metrics[0].Trials)
+	s.Equal(perf.DirectionLower, results[0].Metrics[1].Direction)
+}
+
+func (s *PerfSendSuite) TestInvalidResultsAreNotSent() {
+	for _, content := range []string{
+		`{"results": []}`,
+		`{"results": [{"name": "insert", "metrics": []}]}`,
+		`{"results": [{"name": "insert", "metrics": [{"name": "ops", "direction": "sideways"}]}]}`,
+		`{"results": [{"name": "insert", "metrics": [{"name": "ops"}, {"name": "ops"}]}]}`,
+		`not json`,
+	} {
+		s.writeFile(content)
+		s.Error(s.cmd.Execute(s.ctx, s.comm, s.logger, s.conf), content)
+	}
+	s.Empty(s.comm.GetPerfResults())
+}
+
+func (s *PerfSendSuite) TestMissingFile() {
+	s.Error(s.cmd.Execute(s.ctx, s.comm, s.logger, s.conf))
+}
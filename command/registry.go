@@ -26,6 +26,8 @@ func init() {
 		"attach.results":                attachResultsFactory,
 		"attach.xunit_results":          xunitResultsFactory,
 		"attach.artifacts":              attachArtifactsFactory,
+		"cache.restore":                 cacheRestoreFactory,
+		"cache.save":                    cacheSaveFactory,
 		evergreen.CreateHostCommandName: createHostFactory,
 		"host.list":                     listHostFactory,
 		"expansions.fetch_vars":         fetchVarsFactory,
@@ -41,6 +43,7 @@ func init() {
 		"json.send":                     taskDataSendFactory,
 		"keyval.inc":                    keyValIncFactory,
 		"manifest.load":                 manifestLoadFactory,
+		"perf.send":                     perfSendFactory,
 		"s3.get":                        s3GetFactory,
 		"s3.put":                        s3PutFactory,
 		"s3Copy.copy":                   s3CopyFactory,
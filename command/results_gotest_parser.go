@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,6 +31,9 @@ var (
 
 	// Match the end prefix, save PASS/FAIL/SKIP, save the decimal value for number of seconds
 	gocheckEndRegex = regexp.MustCompile(`(PASS|SKIP|FAIL): .*.go:[0-9]+: (\S+)\s*([0-9\.m]+[ ]*s)?`)
+
+	// Match the indented location that prefixes a test's output, save the file, line number, and message
+	sourceRegex = regexp.MustCompile(`^\s+(\S+\.go):([0-9]+): (.*)$`)
 )
 
 // This test result implementation maps more idiomatically to Go's test output
@@ -56,6 +60,12 @@ type goTestResult struct {
 	// Can be set to mark the id of the server-side log that this
 	// results corresponds to
 	LogId string
+
+	// The file, line number, and message of the first location
+	// that the test's output reports
+	SourceFile string
+	SourceLine int
+	Message    string
 }
 
 // ToModelTestResults converts the implementation of LocalTestResults native
@@ -86,6 +96,11 @@ func ToModelTestResults(results []*goTestResult) task.LocalTestResults {
 			LineNum:   res.StartLine - 1,
 			LogId:     res.LogId,
 		}
+		if status == evergreen.TestFailedStatus {
+			convertedResult.SourceFile = res.SourceFile
+			convertedResult.SourceLine = res.SourceLine
+			convertedResult.Message = res.Message
+		}
 		modelResults = append(modelResults, convertedResult)
 	}
 	return task.LocalTestResults{modelResults}
@@ -101,6 +116,9 @@ type goTestParser struct {
 	// executions of the same test in the same log
 	tests map[string][]*goTestResult
 	order []*goTestResult
+	// the test that most recently started or ended, which owns the
+	// output that follows
+	last *goTestResult
 }
 
 // Logs returns an array of logs captured during test execution.
@@ -145,10 +163,28 @@ func (vp *goTestParser) handleLine(line string) error {
 		return vp.handleEnd(line, endRegex)
 	case gocheckEndRegex.MatchString(line):
 		return vp.handleEnd(line, gocheckEndRegex)
+	case sourceRegex.MatchString(line):
+		vp.handleSource(line)
 	}
 	return nil
 }
 
+// handleSource records the location in the given line of output on
+// the test that owns it, unless that test already has one.
+func (vp *goTestParser) handleSource(line string) {
+	if vp.last == nil || vp.last.SourceFile != "" {
+		return
+	}
+	matches := sourceRegex.FindStringSubmatch(line)
+	lineNum, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return
+	}
+	vp.last.SourceFile = matches[1]
+	vp.last.SourceLine = lineNum
+	vp.last.Message = strings.TrimSpace(matches[3])
+}
+
 // handleEnd gets the end data from an ending line and stores it.
 func (vp *goTestParser) handleEnd(line string, rgx *regexp.Regexp) error {
 	name, status, duration, err := endInfoFromLogLine(line, rgx)
@@ -166,6 +202,7 @@ func (vp *goTestParser) handleEnd(line string, rgx *regexp.Regexp) error {
 	tAry[len(tAry)-1].RunTime = duration
 	tAry[len(tAry)-1].EndLine = len(vp.logs)
 	vp.tests[name] = tAry
+	vp.last = tAry[len(tAry)-1]
 
 	return nil
 }
@@ -194,6 +231,7 @@ func (vp *goTestParser) handleStart(line string, rgx *regexp.Regexp, defaultFail
 	}
 	vp.tests[name] = tAry
 	vp.order = append(vp.order, t)
+	vp.last = t
 
 	return nil
 }
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/testutil"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParserRegex(t *testing.T) {
@@ -190,3 +194,42 @@ func TestParserFunctionality(t *testing.T) {
 	})
 
 }
+
+func TestParserSourceLocation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	parser := &goTestParser{Suite: "test"}
+	require.NoError(parser.Parse(strings.NewReader(strings.Join([]string{
+		"=== RUN   TestOld",
+		"--- FAIL: TestOld (0.00s)",
+		"	old_test.go:12: expected 1, got 2",
+		"	old_test.go:13: expected 3, got 4",
+		"=== RUN   TestNew",
+		"    new_test.go:40: something broke",
+		"--- FAIL: TestNew (0.01s)",
+		"=== RUN   TestPass",
+		"    pass_test.go:5: just logging",
+		"--- PASS: TestPass (0.00s)",
+		"FAIL",
+	}, "\n"))))
+
+	results := parser.Results()
+	require.Len(results, 3)
+	assert.Equal("old_test.go", results[0].SourceFile)
+	assert.Equal(12, results[0].SourceLine)
+	assert.Equal("expected 1, got 2", results[0].Message)
+	assert.Equal("new_test.go", results[1].SourceFile)
+	assert.Equal(40, results[1].SourceLine)
+	assert.Equal("something broke", results[1].Message)
+
+	// only failures carry their location to the model
+	modelResults := ToModelTestResults(results).Results
+	require.Len(modelResults, 3)
+	assert.Equal(evergreen.TestFailedStatus, modelResults[0].Status)
+	assert.Equal("old_test.go", modelResults[0].SourceFile)
+	assert.Equal(12, modelResults[0].SourceLine)
+	assert.Equal(evergreen.TestSucceededStatus, modelResults[2].Status)
+	assert.Empty(modelResults[2].SourceFile)
+	assert.Empty(modelResults[2].Message)
+}
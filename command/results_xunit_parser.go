@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,6 +43,8 @@ type testCase struct {
 	Name      string          `xml:"name,attr"`
 	Time      float64         `xml:"time,attr"`
 	ClassName string          `xml:"classname,attr"`
+	File      string          `xml:"file,attr"`
+	Line      string          `xml:"line,attr"`
 	Failure   *failureDetails `xml:"failure"`
 	Error     *failureDetails `xml:"error"`
 	Skipped   *failureDetails `xml:"skipped"`
@@ -111,6 +114,18 @@ func (tc testCase) toModelTestResultAndLog(t *task.Task) (task.TestResult, *mode
 		res.Status = evergreen.TestSucceededStatus
 	}
 
+	// some xunit writers, like pytest's, report where a test case is
+	// defined, which locates failures for code review annotations
+	if res.Status == evergreen.TestFailedStatus && tc.File != "" {
+		res.SourceFile = tc.File
+		res.SourceLine, _ = strconv.Atoi(tc.Line)
+		if tc.Failure != nil {
+			res.Message = tc.Failure.Message
+		} else {
+			res.Message = tc.Error.Message
+		}
+	}
+
 	if log != nil {
 		log.Name = res.TestFile
 		log.Task = t.Id
@@ -3,6 +3,7 @@ package command
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen"
@@ -10,6 +11,8 @@ import (
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/testutil"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestXMLParsing(t *testing.T) {
@@ -229,3 +232,37 @@ func TestXMLToModelConversion(t *testing.T) {
 		})
 	})
 }
+
+func TestXMLToModelSourceLocation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	res, err := parseXMLResults(strings.NewReader(`<testsuite name="pytest" tests="3">
+  <testcase classname="tests.test_math" name="test_add" file="tests/test_math.py" line="12">
+    <failure message="assert 1 == 2">tests/test_math.py:14: AssertionError</failure>
+  </testcase>
+  <testcase classname="tests.test_math" name="test_sub" file="tests/test_math.py" line="20"/>
+  <testcase classname="tests.test_math" name="test_div">
+    <error message="ZeroDivisionError">trace</error>
+  </testcase>
+</testsuite>`))
+	require.NoError(err)
+	require.Len(res, 1)
+	require.Len(res[0].TestCases, 3)
+
+	testTask := &task.Task{Id: "TEST"}
+	failed, _ := res[0].TestCases[0].toModelTestResultAndLog(testTask)
+	assert.Equal(evergreen.TestFailedStatus, failed.Status)
+	assert.Equal("tests/test_math.py", failed.SourceFile)
+	assert.Equal(12, failed.SourceLine)
+	assert.Equal("assert 1 == 2", failed.Message)
+
+	// passing tests and tests without a location have none
+	passed, _ := res[0].TestCases[1].toModelTestResultAndLog(testTask)
+	assert.Empty(passed.SourceFile)
+	assert.Zero(passed.SourceLine)
+	noFile, _ := res[0].TestCases[2].toModelTestResultAndLog(testTask)
+	assert.Equal(evergreen.TestFailedStatus, noFile.Status)
+	assert.Empty(noFile.SourceFile)
+	assert.Empty(noFile.Message)
+}
@@ -2,9 +2,14 @@ package command
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/model"
@@ -13,6 +18,7 @@ import (
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/goamz/goamz/aws"
 	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -41,6 +47,10 @@ type s3get struct {
 	LocalFile string `mapstructure:"local_file" plugin:"expand"`
 	ExtractTo string `mapstructure:"extract_to" plugin:"expand"`
 
+	// SHA256, if set, is the expected hex encoded SHA-256 digest of the
+	// remote file. The download fails if the contents do not match.
+	SHA256 string `mapstructure:"sha256" plugin:"expand"`
+
 	base
 }
 
@@ -88,6 +98,10 @@ func (c *s3get) validateParams() error {
 	if c.LocalFile == "" && c.ExtractTo == "" {
 		return errors.New("must specify either local_file or extract_to")
 	}
+
+	if c.SHA256 != "" && !util.IsExpandable(c.SHA256) && !sha256Regex.MatchString(c.SHA256) {
+		return errors.Errorf("sha256 '%s' is not a hex encoded SHA-256 digest", c.SHA256)
+	}
 	return nil
 }
 
@@ -214,11 +228,19 @@ func (c *s3get) get(ctx context.Context) error {
 	}
 	defer reader.Close()
 
+	return errors.WithStack(c.write(ctx, reader))
+}
+
+// write saves the contents of the remote file, verifying them against
+// the expected checksum if one is configured.
+func (c *s3get) write(ctx context.Context, reader io.Reader) error {
+	hash := sha256.New()
+	reader = io.TeeReader(reader, hash)
+
 	// either untar the remote, or just write to a file
 	if c.LocalFile != "" {
-		var exists bool
 		// remove the file, if it exists
-		exists, err = util.FileExists(c.LocalFile)
+		exists, err := util.FileExists(c.LocalFile)
 		if err != nil {
 			return errors.Wrapf(err, "error checking existence of local file %v",
 				c.LocalFile)
@@ -234,15 +256,46 @@ func (c *s3get) get(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrapf(err, "error opening local file %v", c.LocalFile)
 		}
-		defer file.Close()
-
 		_, err = io.Copy(file, reader)
-		return errors.WithStack(err)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err = c.verifyChecksum(hash); err != nil {
+			grip.Warning(os.Remove(c.LocalFile))
+			return err
+		}
+		return nil
 	}
 
 	if err := util.ExtractTarball(ctx, reader, c.ExtractTo, []string{}); err != nil {
 		return errors.Wrapf(err, "problem extracting %s from archive", c.RemoteFile)
 	}
 
+	if c.SHA256 == "" {
+		return nil
+	}
+	// the archive may end before the stream does, so read the remainder
+	// to hash the whole file
+	if _, err := io.Copy(ioutil.Discard, reader); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return c.verifyChecksum(hash)
+}
+
+func (c *s3get) verifyChecksum(h hash.Hash) error {
+	if c.SHA256 == "" {
+		return nil
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(sum, c.SHA256) {
+		return errors.Errorf("checksum mismatch for %s: expected sha256 %s, got %s",
+			c.RemoteFile, c.SHA256, sum)
+	}
 	return nil
 }
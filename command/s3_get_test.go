@@ -1,11 +1,19 @@
 package command
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/util"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestS3GetValidateParams(t *testing.T) {
@@ -161,3 +169,57 @@ func TestExpandS3GetParams(t *testing.T) {
 
 	})
 }
+
+func TestS3GetVerifiesChecksum(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "s3-get")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	contents := []byte("hello world")
+	src := filepath.Join(dir, "src")
+	require.NoError(ioutil.WriteFile(src, contents, 0644))
+	sum, err := sha256File(src)
+	require.NoError(err)
+	assert.Equal("b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", sum)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmd := &s3get{
+		RemoteFile: "remote",
+		LocalFile:  filepath.Join(dir, "dst"),
+		SHA256:     strings.ToUpper(sum),
+	}
+	assert.NoError(cmd.write(ctx, bytes.NewReader(contents)))
+	assert.True(util.FileExists(cmd.LocalFile))
+
+	cmd.SHA256 = strings.Repeat("0", 64)
+	assert.Error(cmd.write(ctx, bytes.NewReader(contents)))
+	exists, err := util.FileExists(cmd.LocalFile)
+	assert.NoError(err)
+	assert.False(exists, "files that fail verification should be removed")
+
+	cmd.SHA256 = ""
+	assert.NoError(cmd.write(ctx, bytes.NewReader(contents)))
+}
+
+func TestS3GetValidatesChecksumParam(t *testing.T) {
+	assert := assert.New(t)
+
+	params := map[string]interface{}{
+		"aws_key":     "key",
+		"aws_secret":  "secret",
+		"remote_file": "remote",
+		"bucket":      "bck",
+		"local_file":  "local",
+	}
+	params["sha256"] = "not a digest"
+	assert.Error((&s3get{}).ParseParams(params))
+	params["sha256"] = "${checksum}"
+	assert.NoError((&s3get{}).ParseParams(params))
+	params["sha256"] = strings.Repeat("a", 64)
+	assert.NoError((&s3get{}).ParseParams(params))
+}
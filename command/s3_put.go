@@ -356,10 +356,17 @@ func (s3pc *s3put) attachFiles(ctx context.Context, comm client.Communicator, lo
 			displayName = fmt.Sprintf("%s %s", s3pc.ResourceDisplayName, filepath.Base(fn))
 		}
 
+		checksum, err := sha256File(fn)
+		if err != nil {
+			return errors.Wrapf(err, "problem computing checksum of %s", fn)
+		}
+		logger.Execution().Infof("sha256 of %s is %s", fn, checksum)
+
 		files = append(files, &artifact.File{
 			Name:       displayName,
 			Link:       fileLink,
 			Visibility: s3pc.Visibility,
+			SHA256:     checksum,
 		})
 	}
 
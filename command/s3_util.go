@@ -1,6 +1,8 @@
 package command
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"regexp"
 	"strings"
@@ -21,6 +23,9 @@ const (
 var (
 	// Regular expression for validating S3 bucket names
 	bucketNameRegex = regexp.MustCompile(`^[A-Za-z0-9_\-.]+$`)
+
+	// Regular expression for validating hex encoded SHA-256 digests
+	sha256Regex = regexp.MustCompile(`^[A-Fa-f0-9]{64}$`)
 )
 
 func validateS3BucketName(bucket string) error {
@@ -73,3 +78,12 @@ func getS3OpBackoff() *backoff.Backoff {
 		Jitter: true,
 	}
 }
+
+// sha256File returns the hex encoded SHA-256 digest of a file's contents.
+func sha256File(fn string) (string, error) {
+	hash := sha256.New()
+	if err := copyFileInto(hash, fn); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
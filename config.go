@@ -48,9 +48,12 @@ type Settings struct {
 	Amboy              AmboyConfig               `yaml:"amboy" bson:"amboy" json:"amboy" id:"amboy"`
 	Api                APIConfig                 `yaml:"api" bson:"api" json:"api" id:"api"`
 	ApiUrl             string                    `yaml:"api_url" bson:"api_url" json:"api_url"`
+	ArtifactRetention  ArtifactRetentionConfig   `yaml:"artifact_retention" bson:"artifact_retention" json:"artifact_retention" id:"artifact_retention"`
+	ArtifactSigning    ArtifactSigningConfig     `yaml:"artifact_signing" bson:"artifact_signing" json:"artifact_signing" id:"artifact_signing"`
 	AuthConfig         AuthConfig                `yaml:"auth" bson:"auth" json:"auth" id:"auth"`
 	Banner             string                    `bson:"banner" json:"banner"`
 	BannerTheme        BannerTheme               `bson:"banner_theme" json:"banner_theme"`
+	BuildCache         BuildCacheConfig          `yaml:"build_cache" bson:"build_cache" json:"build_cache" id:"build_cache"`
 	ClientBinariesDir  string                    `yaml:"client_binaries_dir" bson:"client_binaries_dir" json:"client_binaries_dir"`
 	ConfigDir          string                    `yaml:"configdir" bson:"configdir" json:"configdir"`
 	ContainerPools     ContainerPoolsConfig      `yaml:"container_pools" bson:"container_pools" json:"container_pools" id:"container_pools"`
@@ -59,7 +62,9 @@ type Settings struct {
 	Database           DBSettings                `yaml:"database"`
 	Expansions         map[string]string         `yaml:"expansions" bson:"expansions" json:"expansions"`
 	ExpansionsNew      util.KeyValuePairSlice    `yaml:"expansions_new" bson:"expansions_new" json:"expansions_new"`
+	GithubApp          GithubAppConfig           `yaml:"github_app" bson:"github_app" json:"github_app" id:"github_app"`
 	GithubPRCreatorOrg string                    `yaml:"github_pr_creator_org" bson:"github_pr_creator_org" json:"github_pr_creator_org"`
+	HostHealth         HostHealthConfig          `yaml:"host_health" bson:"host_health" json:"host_health" id:"host_health"`
 	HostInit           HostInitConfig            `yaml:"hostinit" bson:"hostinit" json:"hostinit" id:"hostinit"`
 	Jira               JiraConfig                `yaml:"jira" bson:"jira" json:"jira" id:"jira"`
 	JIRANotifications  JIRANotificationsConfig   `yaml:"jira_notifications" json:"jira_notifications" bson:"jira_notifications" id:"jira_notifications"`
@@ -78,6 +83,7 @@ type Settings struct {
 	Slack              SlackConfig               `yaml:"slack" bson:"slack" json:"slack" id:"slack"`
 	Splunk             send.SplunkConnectionInfo `yaml:"splunk" bson:"splunk" json:"splunk"`
 	SuperUsers         []string                  `yaml:"superusers" bson:"superusers" json:"superusers"`
+	Tracer             TracerConfig              `yaml:"tracer" bson:"tracer" json:"tracer" id:"tracer"`
 	Ui                 UIConfig                  `yaml:"ui" bson:"ui" json:"ui" id:"ui"`
 }
 
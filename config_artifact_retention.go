@@ -0,0 +1,46 @@
+package evergreen
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ArtifactRetentionConfig holds the credentials that the artifact
+// retention job uses to delete expired task artifacts. The rules
+// themselves are configured per project.
+type ArtifactRetentionConfig struct {
+	AWSKey    string `bson:"aws_key" json:"aws_key" yaml:"aws_key"`
+	AWSSecret string `bson:"aws_secret" json:"aws_secret" yaml:"aws_secret"`
+	// Buckets are the buckets that evergreen owns. Artifacts linked
+	// anywhere else are never deleted.
+	Buckets []string `bson:"buckets" json:"buckets" yaml:"buckets"`
+	// Endpoint optionally points at an S3-compatible service other
+	// than AWS.
+	Endpoint string `bson:"endpoint" json:"endpoint" yaml:"endpoint"`
+}
+
+func (c *ArtifactRetentionConfig) SectionId() string { return "artifact_retention" }
+
+func (c *ArtifactRetentionConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = ArtifactRetentionConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *ArtifactRetentionConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"aws_key":    c.AWSKey,
+			"aws_secret": c.AWSSecret,
+			"buckets":    c.Buckets,
+			"endpoint":   c.Endpoint,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *ArtifactRetentionConfig) ValidateAndDefault() error { return nil }
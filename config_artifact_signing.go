@@ -0,0 +1,75 @@
+package evergreen
+
+import (
+	"encoding/base64"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ArtifactSigningConfig holds the key that the server signs the manifests
+// of task artifacts with. Signatures are ed25519, so anyone can verify them
+// with the public key that the server publishes, but only the server can
+// produce them.
+type ArtifactSigningConfig struct {
+	// PrivateKey is a base64 encoded ed25519 private key or seed. Manifests
+	// are not signed if it is empty.
+	PrivateKey string `bson:"private_key" json:"private_key" yaml:"private_key"`
+}
+
+func (c *ArtifactSigningConfig) SectionId() string { return "artifact_signing" }
+
+func (c *ArtifactSigningConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = ArtifactSigningConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *ArtifactSigningConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"private_key": c.PrivateKey,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *ArtifactSigningConfig) ValidateAndDefault() error {
+	_, err := c.SigningKey()
+	return errors.WithStack(err)
+}
+
+// SigningKey decodes the private key, returning nil if none is configured.
+func (c *ArtifactSigningConfig) SigningKey() (ed25519.PrivateKey, error) {
+	if c.PrivateKey == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(c.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "artifact signing key is not base64 encoded")
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, errors.Errorf("artifact signing key must be an ed25519 seed or private key, not %d bytes", len(raw))
+	}
+}
+
+// PublicKey returns the base64 encoded public key that verifies the
+// server's signatures, or an empty string if none is configured.
+func (c *ArtifactSigningConfig) PublicKey() (string, error) {
+	key, err := c.SigningKey()
+	if err != nil || key == nil {
+		return "", errors.WithStack(err)
+	}
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), nil
+}
@@ -0,0 +1,76 @@
+package evergreen
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	defaultBuildCacheMaxSizeMB = 10 * 1024
+	defaultBuildCacheTTLDays   = 14
+)
+
+// BuildCacheConfig holds the settings that the server uses to account
+// for and evict the tarballs written by the cache.save command.
+type BuildCacheConfig struct {
+	// Bucket is the bucket that cache.save writes to. The server only
+	// tracks and evicts objects in this bucket.
+	Bucket string `bson:"bucket" json:"bucket" yaml:"bucket"`
+	// AWSKey and AWSSecret are used by the eviction job to delete
+	// expired objects from the bucket.
+	AWSKey    string `bson:"aws_key" json:"aws_key" yaml:"aws_key"`
+	AWSSecret string `bson:"aws_secret" json:"aws_secret" yaml:"aws_secret"`
+	// Endpoint optionally points at an S3-compatible service other
+	// than AWS.
+	Endpoint string `bson:"endpoint" json:"endpoint" yaml:"endpoint"`
+	// MaxSizeMB is the total size of cache objects kept for a single
+	// project; the least recently used entries are evicted first.
+	MaxSizeMB int `bson:"max_size_mb" json:"max_size_mb" yaml:"max_size_mb"`
+	// TTLDays is the number of days an entry may go unused before it
+	// is evicted.
+	TTLDays int `bson:"ttl_days" json:"ttl_days" yaml:"ttl_days"`
+}
+
+func (c *BuildCacheConfig) SectionId() string { return "build_cache" }
+
+func (c *BuildCacheConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = BuildCacheConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *BuildCacheConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"bucket":      c.Bucket,
+			"aws_key":     c.AWSKey,
+			"aws_secret":  c.AWSSecret,
+			"endpoint":    c.Endpoint,
+			"max_size_mb": c.MaxSizeMB,
+			"ttl_days":    c.TTLDays,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *BuildCacheConfig) ValidateAndDefault() error {
+	if c.MaxSizeMB < 0 {
+		return errors.New("build cache max size cannot be negative")
+	}
+	if c.TTLDays < 0 {
+		return errors.New("build cache ttl cannot be negative")
+	}
+
+	if c.MaxSizeMB == 0 {
+		c.MaxSizeMB = defaultBuildCacheMaxSizeMB
+	}
+	if c.TTLDays == 0 {
+		c.TTLDays = defaultBuildCacheTTLDays
+	}
+
+	return nil
+}
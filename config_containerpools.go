@@ -1,11 +1,17 @@
 package evergreen
 
 import (
+	"time"
+
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
 )
 
+// defaultContainerImageTTLHours is how long images stay on parent hosts
+// without being used, unless configured otherwise
+const defaultContainerImageTTLHours = 7 * 24
+
 // ContainerPool holds settings for each container pool
 type ContainerPool struct {
 	// Distro of parent host that hosts containers
@@ -16,10 +22,64 @@ type ContainerPool struct {
 	MaxContainers int `bson:"max_containers" json:"max_containers" yaml:"max_containers"`
 	// Port number to start at for SSH connections
 	Port uint16 `bson:"port" json:"port" yaml:"port"`
+	// Resources of each parent host that containers may use. If set,
+	// containers are packed onto parents by the resources that their
+	// tasks request, as well as by MaxContainers.
+	ParentResources ContainerResources `bson:"parent_resources" json:"parent_resources" yaml:"parent_resources"`
+}
+
+// ContainerResources are the CPUs and memory that a container requests or
+// that a parent host offers to containers. A zero value of either is
+// unbounded.
+type ContainerResources struct {
+	CPUs     float64 `bson:"cpus,omitempty" json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	MemoryMB int     `bson:"memory_mb,omitempty" json:"memory_mb,omitempty" yaml:"memory_mb,omitempty"`
+}
+
+// IsZero returns true if neither CPUs nor memory are set.
+func (r ContainerResources) IsZero() bool {
+	return r.CPUs == 0 && r.MemoryMB == 0
+}
+
+// Add returns the sum of two sets of resources.
+func (r ContainerResources) Add(other ContainerResources) ContainerResources {
+	return ContainerResources{
+		CPUs:     r.CPUs + other.CPUs,
+		MemoryMB: r.MemoryMB + other.MemoryMB,
+	}
+}
+
+// FitsIn returns true if the resources are within the capacity, ignoring
+// the capacity's unbounded resources.
+func (r ContainerResources) FitsIn(capacity ContainerResources) bool {
+	return (capacity.CPUs == 0 || r.CPUs <= capacity.CPUs) &&
+		(capacity.MemoryMB == 0 || r.MemoryMB <= capacity.MemoryMB)
+}
+
+// Validate checks that the resources are not negative.
+func (r ContainerResources) Validate() error {
+	if r.CPUs < 0 || r.MemoryMB < 0 {
+		return errors.New("cpus and memory_mb must not be negative")
+	}
+	return nil
+}
+
+// ContainerRegistry holds the credentials for a Docker registry that
+// container distros pull images from
+type ContainerRegistry struct {
+	// Host of the registry, as it appears in image references, such as
+	// "registry.example.com:5000"
+	Host     string `bson:"host" json:"host" yaml:"host"`
+	Username string `bson:"username" json:"username" yaml:"username"`
+	Password string `bson:"password" json:"password" yaml:"password"`
 }
 
 type ContainerPoolsConfig struct {
-	Pools []ContainerPool `bson:"pools" json:"pools" yaml:"pools"`
+	Pools      []ContainerPool     `bson:"pools" json:"pools" yaml:"pools"`
+	Registries []ContainerRegistry `bson:"registries" json:"registries" yaml:"registries"`
+	// Images that have not been used by a container on a parent host for
+	// this many hours are removed from it. It defaults to a week.
+	ImageTTLHours int `bson:"image_ttl_hours" json:"image_ttl_hours" yaml:"image_ttl_hours"`
 }
 
 func (c *ContainerPoolsConfig) SectionId() string { return "container_pools" }
@@ -36,7 +96,9 @@ func (c *ContainerPoolsConfig) Get() error {
 func (c *ContainerPoolsConfig) Set() error {
 	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
 		"$set": bson.M{
-			poolsKey: c.Pools,
+			poolsKey:         c.Pools,
+			registriesKey:    c.Registries,
+			imageTTLHoursKey: c.ImageTTLHours,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -53,12 +115,50 @@ func (c *ContainerPoolsConfig) GetContainerPool(id string) *ContainerPool {
 	return nil
 }
 
+// GetRegistry retrieves the credentials for the registry with a given host
+// from a ContainerPoolsConfig struct
+func (c *ContainerPoolsConfig) GetRegistry(host string) *ContainerRegistry {
+	for _, registry := range c.Registries {
+		if registry.Host == host {
+			return &registry
+		}
+	}
+	return nil
+}
+
 func (c *ContainerPoolsConfig) ValidateAndDefault() error {
 	// ensure that max_containers is positive
 	for _, pool := range c.Pools {
 		if pool.MaxContainers <= 0 {
 			return errors.Errorf("container pool field max_containers must be positive integer")
 		}
+		if err := pool.ParentResources.Validate(); err != nil {
+			return errors.Wrapf(err, "container pool '%s' has invalid parent_resources", pool.Id)
+		}
+	}
+
+	hosts := map[string]bool{}
+	for _, registry := range c.Registries {
+		if registry.Host == "" {
+			return errors.New("container registry host must not be empty")
+		}
+		if hosts[registry.Host] {
+			return errors.Errorf("container registry '%s' is defined more than once", registry.Host)
+		}
+		hosts[registry.Host] = true
+	}
+
+	if c.ImageTTLHours < 0 {
+		return errors.New("container pool field image_ttl_hours must not be negative")
 	}
 	return nil
 }
+
+// ImageTTL returns how long an image may stay on a parent host without
+// being used
+func (c *ContainerPoolsConfig) ImageTTL() time.Duration {
+	if c.ImageTTLHours <= 0 {
+		return defaultContainerImageTTLHours * time.Hour
+	}
+	return time.Duration(c.ImageTTLHours) * time.Hour
+}
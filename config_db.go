@@ -67,9 +67,12 @@ var (
 	webhookNotificationsDisabledKey = bsonutil.MustHaveTag(ServiceFlags{}, "WebhookNotificationsDisabled")
 	githubStatusAPIDisabledKey      = bsonutil.MustHaveTag(ServiceFlags{}, "GithubStatusAPIDisabled")
 	taskLoggingDisabledKey          = bsonutil.MustHaveTag(ServiceFlags{}, "TaskLoggingDisabled")
+	perfChangePointsDisabledKey     = bsonutil.MustHaveTag(ServiceFlags{}, "PerfChangePointsDisabled")
 
 	// ContainerPoolsConfig keys
-	poolsKey = bsonutil.MustHaveTag(ContainerPoolsConfig{}, "Pools")
+	poolsKey         = bsonutil.MustHaveTag(ContainerPoolsConfig{}, "Pools")
+	registriesKey    = bsonutil.MustHaveTag(ContainerPoolsConfig{}, "Registries")
+	imageTTLHoursKey = bsonutil.MustHaveTag(ContainerPoolsConfig{}, "ImageTTLHours")
 
 	// ContainerPool keys
 	ContainerPoolIdKey = bsonutil.MustHaveTag(ContainerPool{}, "Id")
@@ -0,0 +1,58 @@
+package evergreen
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GithubAppConfig holds the credentials of the Github App that the server
+// reports check runs as. Github only accepts check runs from apps, and
+// sends the webhooks of an app's check runs to the app's webhook rather
+// than to the repository's.
+type GithubAppConfig struct {
+	// AppID is the app's ID. Check runs are not reported if it is zero.
+	AppID int64 `bson:"app_id" json:"app_id" yaml:"app_id"`
+	// PrivateKey is the app's PEM encoded RSA private key, which signs
+	// the requests for the tokens of the app's installations.
+	PrivateKey string `bson:"private_key" json:"private_key" yaml:"private_key"`
+	// WebhookSecret is the secret of the app's webhook.
+	WebhookSecret string `bson:"webhook_secret" json:"webhook_secret" yaml:"webhook_secret"`
+}
+
+func (c *GithubAppConfig) SectionId() string { return "github_app" }
+
+func (c *GithubAppConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = GithubAppConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *GithubAppConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"app_id":         c.AppID,
+			"private_key":    c.PrivateKey,
+			"webhook_secret": c.WebhookSecret,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *GithubAppConfig) ValidateAndDefault() error {
+	if c.AppID < 0 {
+		return errors.New("github app id cannot be negative")
+	}
+	if c.AppID > 0 && c.PrivateKey == "" {
+		return errors.New("github app must have a private key")
+	}
+	return nil
+}
+
+// IsConfigured returns true if the server can act as the app.
+func (c *GithubAppConfig) IsConfigured() bool {
+	return c.AppID > 0 && c.PrivateKey != ""
+}
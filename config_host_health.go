@@ -0,0 +1,65 @@
+package evergreen
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// HostHealthConfig configures the scoring of hosts by the outcomes of the
+// tasks they ran, and the quarantine of hosts with poor scores.
+type HostHealthConfig struct {
+	// QuarantineEnabled allows hosts whose score reaches the threshold to
+	// be quarantined. Scores are computed regardless.
+	QuarantineEnabled bool `bson:"quarantine_enabled" json:"quarantine_enabled" yaml:"quarantine_enabled"`
+	// TerminateQuarantined decommissions quarantined hosts, other than
+	// static hosts, so that they are terminated and replaced.
+	TerminateQuarantined bool `bson:"terminate_quarantined" json:"terminate_quarantined" yaml:"terminate_quarantined"`
+	// Threshold is the score at or above which a host is quarantined.
+	Threshold float64 `bson:"threshold" json:"threshold" yaml:"threshold"`
+	// WindowMinutes is how far back task outcomes count towards a score.
+	WindowMinutes int `bson:"window_minutes" json:"window_minutes" yaml:"window_minutes"`
+	// MinTasks is the number of tasks a host must have run within the
+	// window before it can be quarantined.
+	MinTasks int `bson:"min_tasks" json:"min_tasks" yaml:"min_tasks"`
+}
+
+func (c *HostHealthConfig) SectionId() string { return "host_health" }
+
+func (c *HostHealthConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = HostHealthConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *HostHealthConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"quarantine_enabled":    c.QuarantineEnabled,
+			"terminate_quarantined": c.TerminateQuarantined,
+			"threshold":             c.Threshold,
+			"window_minutes":        c.WindowMinutes,
+			"min_tasks":             c.MinTasks,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *HostHealthConfig) ValidateAndDefault() error {
+	if c.Threshold < 0 || c.WindowMinutes < 0 || c.MinTasks < 0 {
+		return errors.New("host health threshold, window and minimum tasks cannot be negative")
+	}
+	if c.Threshold == 0 {
+		c.Threshold = 0.5
+	}
+	if c.WindowMinutes == 0 {
+		c.WindowMinutes = 24 * 60
+	}
+	if c.MinTasks == 0 {
+		c.MinTasks = 5
+	}
+	return nil
+}
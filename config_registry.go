@@ -34,9 +34,14 @@ func resetRegistry() error {
 		&AlertsConfig{},
 		&AmboyConfig{},
 		&APIConfig{},
+		&ArtifactRetentionConfig{},
+		&ArtifactSigningConfig{},
 		&AuthConfig{},
+		&BuildCacheConfig{},
 		&CloudProviders{},
 		&ContainerPoolsConfig{},
+		&GithubAppConfig{},
+		&HostHealthConfig{},
 		&HostInitConfig{},
 		&JiraConfig{},
 		&LoggerConfig{},
@@ -45,6 +50,7 @@ func resetRegistry() error {
 		&SchedulerConfig{},
 		&ServiceFlags{},
 		&SlackConfig{},
+		&TracerConfig{},
 		&UIConfig{},
 		&Settings{},
 		&JIRANotificationsConfig{},
@@ -20,6 +20,7 @@ type ServiceFlags struct {
 	CLIUpdatesDisabled           bool `bson:"cli_updates_disabled" json:"cli_updates_disabled"`
 	BackgroundStatsDisabled      bool `bson:"background_stats_disabled" json:"background_stats_disabled"`
 	TaskLoggingDisabled          bool `bson:"task_logging_disabled" json:"task_logging_disabled"`
+	PerfChangePointsDisabled     bool `bson:"perf_change_points_disabled" json:"perf_change_points_disabled"`
 
 	// Notification Flags
 	EventProcessingDisabled      bool `bson:"event_processing_disabled" json:"event_processing_disabled"`
@@ -62,6 +63,7 @@ func (c *ServiceFlags) Set() error {
 			webhookNotificationsDisabledKey: c.WebhookNotificationsDisabled,
 			githubStatusAPIDisabledKey:      c.GithubStatusAPIDisabled,
 			taskLoggingDisabledKey:          c.TaskLoggingDisabled,
+			perfChangePointsDisabledKey:     c.PerfChangePointsDisabled,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
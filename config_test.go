@@ -1,15 +1,18 @@
 package evergreen
 
 import (
+	"encoding/base64"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip/send"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ed25519"
 )
 
 const (
@@ -266,6 +269,128 @@ func (s *AdminSuite) TestAuthConfig() {
 	s.Equal(config, settings.AuthConfig)
 }
 
+func (s *AdminSuite) TestArtifactRetentionConfig() {
+	config := ArtifactRetentionConfig{
+		AWSKey:    "key",
+		AWSSecret: "secret",
+		Buckets:   []string{"mciuploads"},
+		Endpoint:  "http://localhost:9000",
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.ArtifactRetention)
+}
+
+func (s *AdminSuite) TestGithubAppConfig() {
+	config := GithubAppConfig{
+		AppID:         1234,
+		PrivateKey:    "key",
+		WebhookSecret: "secret",
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.GithubApp)
+
+	s.NoError(config.ValidateAndDefault())
+	s.True(config.IsConfigured())
+	config.PrivateKey = ""
+	s.Error(config.ValidateAndDefault())
+	s.False(config.IsConfigured())
+}
+
+func (s *AdminSuite) TestArtifactSigningConfig() {
+	seed := make([]byte, ed25519.SeedSize)
+	config := ArtifactSigningConfig{
+		PrivateKey: base64.StdEncoding.EncodeToString(seed),
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.ArtifactSigning)
+
+	s.NoError(config.ValidateAndDefault())
+	key, err := config.SigningKey()
+	s.NoError(err)
+	s.Equal(ed25519.NewKeyFromSeed(seed), key)
+	public, err := config.PublicKey()
+	s.NoError(err)
+	s.Equal(base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), public)
+
+	config.PrivateKey = base64.StdEncoding.EncodeToString([]byte("short"))
+	s.Error(config.ValidateAndDefault())
+	config.PrivateKey = "not base64"
+	s.Error(config.ValidateAndDefault())
+
+	config.PrivateKey = ""
+	s.NoError(config.ValidateAndDefault())
+	key, err = config.SigningKey()
+	s.NoError(err)
+	s.Nil(key)
+}
+
+func (s *AdminSuite) TestBuildCacheConfig() {
+	config := BuildCacheConfig{
+		Bucket:    "cache-bucket",
+		AWSKey:    "key",
+		AWSSecret: "secret",
+		Endpoint:  "http://localhost:9000",
+		MaxSizeMB: 100,
+		TTLDays:   3,
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.BuildCache)
+
+	config = BuildCacheConfig{}
+	s.NoError(config.ValidateAndDefault())
+	s.Equal(defaultBuildCacheMaxSizeMB, config.MaxSizeMB)
+	s.Equal(defaultBuildCacheTTLDays, config.TTLDays)
+
+	config.TTLDays = -1
+	s.Error(config.ValidateAndDefault())
+}
+
+func (s *AdminSuite) TestHostHealthConfig() {
+	config := HostHealthConfig{
+		QuarantineEnabled:    true,
+		TerminateQuarantined: true,
+		Threshold:            0.4,
+		WindowMinutes:        60,
+		MinTasks:             3,
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.HostHealth)
+
+	config = HostHealthConfig{}
+	s.NoError(config.ValidateAndDefault())
+	s.Equal(0.5, config.Threshold)
+	s.Equal(24*60, config.WindowMinutes)
+	s.Equal(5, config.MinTasks)
+
+	config.MinTasks = -1
+	s.Error(config.ValidateAndDefault())
+}
+
 func (s *AdminSuite) TestHostinitConfig() {
 	config := HostInitConfig{
 		SSHTimeoutSeconds: 10,
@@ -381,6 +506,20 @@ func (s *AdminSuite) TestSlackConfig() {
 	s.Equal(config, settings.Slack)
 }
 
+func (s *AdminSuite) TestTracerConfig() {
+	config := TracerConfig{
+		Enabled:           true,
+		CollectorEndpoint: "http://localhost:4318",
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.Tracer)
+}
+
 func (s *AdminSuite) TestUiConfig() {
 	config := UIConfig{
 		Url:            "url",
@@ -555,6 +694,56 @@ func (s *AdminSuite) TestContainerPoolsConfig() {
 
 	lookup = settings.ContainerPools.GetContainerPool("test-pool-3")
 	s.Nil(lookup)
+
+	s.Equal(7*24*time.Hour, validConfig.ImageTTL())
+	validConfig.ImageTTLHours = 12
+	s.Equal(12*time.Hour, validConfig.ImageTTL())
+	validConfig.Registries = []ContainerRegistry{
+		{Host: "registry.example.com", Username: "user", Password: "pass"},
+	}
+	s.NoError(validConfig.ValidateAndDefault())
+	s.NoError(validConfig.Set())
+
+	settings, err = GetConfig()
+	s.NoError(err)
+	s.Equal(validConfig, settings.ContainerPools)
+	registry := settings.ContainerPools.GetRegistry("registry.example.com")
+	s.Require().NotNil(registry)
+	s.Equal("user", registry.Username)
+	s.Nil(settings.ContainerPools.GetRegistry("docker.io"))
+
+	validConfig.Registries = append(validConfig.Registries, ContainerRegistry{Host: "registry.example.com"})
+	s.Error(validConfig.ValidateAndDefault())
+	validConfig.Registries = []ContainerRegistry{{Username: "user"}}
+	s.Error(validConfig.ValidateAndDefault())
+	validConfig.Registries = nil
+	validConfig.ImageTTLHours = -1
+	s.Error(validConfig.ValidateAndDefault())
+	validConfig.ImageTTLHours = 0
+
+	validConfig.Pools[0].ParentResources = ContainerResources{CPUs: 8, MemoryMB: 16 * 1024}
+	s.NoError(validConfig.ValidateAndDefault())
+	s.NoError(validConfig.Set())
+	settings, err = GetConfig()
+	s.NoError(err)
+	s.Equal(validConfig, settings.ContainerPools)
+	validConfig.Pools[0].ParentResources.CPUs = -1
+	s.Error(validConfig.ValidateAndDefault())
+}
+
+func TestContainerResources(t *testing.T) {
+	assert := assert.New(t)
+
+	capacity := ContainerResources{CPUs: 4, MemoryMB: 1024}
+	used := ContainerResources{CPUs: 1.5}.Add(ContainerResources{CPUs: 1, MemoryMB: 512})
+	assert.Equal(ContainerResources{CPUs: 2.5, MemoryMB: 512}, used)
+	assert.True(used.FitsIn(capacity))
+	assert.False(used.Add(ContainerResources{CPUs: 2}).FitsIn(capacity))
+	assert.False(used.Add(ContainerResources{MemoryMB: 1024}).FitsIn(capacity))
+	assert.True(used.Add(ContainerResources{MemoryMB: 1024}).FitsIn(ContainerResources{CPUs: 4}))
+	assert.True(ContainerResources{}.IsZero())
+	assert.False(capacity.IsZero())
+	assert.Error(ContainerResources{MemoryMB: -1}.Validate())
 }
 
 func (s *AdminSuite) TestJIRANotificationsConfig() {
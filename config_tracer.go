@@ -0,0 +1,52 @@
+package evergreen
+
+import (
+	"net/url"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TracerConfig configures the export of trace spans from the app servers
+// and agents to an OpenTelemetry collector.
+type TracerConfig struct {
+	Enabled bool `bson:"enabled" json:"enabled" yaml:"enabled"`
+	// CollectorEndpoint is the base URL of the collector's OTLP/HTTP
+	// receiver, such as "http://localhost:4318".
+	CollectorEndpoint string `bson:"collector_endpoint" json:"collector_endpoint" yaml:"collector_endpoint"`
+}
+
+func (c *TracerConfig) SectionId() string { return "tracer" }
+
+func (c *TracerConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = TracerConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *TracerConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"enabled":            c.Enabled,
+			"collector_endpoint": c.CollectorEndpoint,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *TracerConfig) ValidateAndDefault() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.CollectorEndpoint == "" {
+		return errors.New("tracing requires a collector endpoint")
+	}
+	if _, err := url.ParseRequestURI(c.CollectorEndpoint); err != nil {
+		return errors.Wrapf(err, "collector endpoint '%s' is not a valid url", c.CollectorEndpoint)
+	}
+	return nil
+}
@@ -47,6 +47,11 @@ const (
 	// the agent has not yet told Evergreen that it's running the task
 	TaskDispatched = "dispatched"
 
+	// TaskPaused indicates that a command of a task that pauses on failure
+	// has failed, and the agent is holding the task, before running its
+	// post commands, so that its host can be inspected.
+	TaskPaused = "paused"
+
 	// The task statuses below indicate that a task has finished.
 	TaskSucceeded = "success"
 
@@ -293,7 +298,7 @@ var (
 	}
 
 	// constant arrays for db update logic
-	AbortableStatuses = []string{TaskStarted, TaskDispatched}
+	AbortableStatuses = []string{TaskStarted, TaskDispatched, TaskPaused}
 	CompletedStatuses = []string{TaskSucceeded, TaskFailed}
 
 	ValidCommandTypes = []string{CommandTypeSetup, CommandTypeSystem, CommandTypeTest}
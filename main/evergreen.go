@@ -50,12 +50,16 @@ func buildApp() *cli.App {
 		// Top-level commands.
 		operations.Keys(),
 		operations.Fetch(),
+		operations.Artifacts(),
+		operations.Provenance(),
 		operations.Evaluate(),
+		operations.RunLocal(),
 		operations.Validate(),
 		operations.List(),
 		operations.TestHistory(),
 		operations.LastGreen(),
 		operations.Subscriptions(),
+		operations.Task(),
 
 		// Patch creation and management commands (top-level)
 		operations.Patch(),
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	evgmodel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/mongodb/anser"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const migrationPerfLegacyResults = "perf-legacy-results"
+
+// perfLegacyResultsGenerator converts the performance data that tasks sent
+// with json.send, in the shape the perf plugin displays, into perf.send
+// results. Documents in other shapes are left alone.
+func perfLegacyResultsGenerator(env anser.Environment, args migrationGeneratorFactoryOptions) (anser.Generator, error) {
+	if err := env.RegisterManualMigrationOperation(migrationPerfLegacyResults, makePerfLegacyResultsMigration(args.db)); err != nil {
+		return nil, err
+	}
+
+	opts := model.GeneratorOptions{
+		NS: model.Namespace{
+			DB:         args.db,
+			Collection: jsonCollection,
+		},
+		Limit: args.limit,
+		Query: bson.M{
+			"data.results.0.name":    bson.M{"$exists": true},
+			"data.results.0.results": bson.M{"$exists": true},
+		},
+		JobID: args.id,
+	}
+
+	return anser.NewManualMigrationGenerator(env, opts, migrationPerfLegacyResults), nil
+}
+
+func makePerfLegacyResultsMigration(database string) db.MigrationOperation {
+	return func(session db.Session, rawD bson.RawD) error {
+		defer session.Close()
+
+		raw, err := bson.Marshal(rawD)
+		if err != nil {
+			return errors.Wrap(err, "problem marshaling json document")
+		}
+		doc := evgmodel.TaskJSON{}
+		if err = bson.Unmarshal(raw, &doc); err != nil {
+			return errors.Wrap(err, "problem unmarshaling json document")
+		}
+
+		results, ok := perf.FromLegacyJSON(doc.Data)
+		if !ok {
+			return nil
+		}
+
+		info := perf.ResultInfo{
+			Project:   doc.ProjectId,
+			Variant:   doc.Variant,
+			TaskName:  doc.TaskName,
+			TaskID:    doc.TaskId,
+			VersionID: doc.VersionId,
+			Revision:  doc.Revision,
+			Order:     doc.RevisionOrderNumber,
+			IsPatch:   doc.IsPatch,
+		}
+		for i := range results {
+			results[i].SetInfo(info)
+			results[i].CreatedAt = doc.CreateTime
+			if _, err = session.DB(database).C(perf.ResultsCollection).UpsertId(results[i].ID, results[i]); err != nil {
+				return errors.Wrapf(err, "problem saving perf result '%s'", results[i].ID)
+			}
+		}
+		return nil
+	}
+}
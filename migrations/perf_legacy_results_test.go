@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	evgdb "github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/mongodb/anser"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type perfLegacyResultsMigration struct {
+	migrationSuite
+}
+
+func TestPerfLegacyResultsMigration(t *testing.T) {
+	s := &perfLegacyResultsMigration{}
+	suite.Run(t, s)
+}
+
+func (s *perfLegacyResultsMigration) SetupTest() {
+	s.NoError(evgdb.ClearCollections(jsonCollection, perf.ResultsCollection))
+	data := []bson.M{
+		{
+			"task_id":    "task_1",
+			"task_name":  "perf",
+			"project_id": "a_project",
+			"variant":    "a_variant",
+			"name":       "perf",
+			"order":      5,
+			"data": bson.M{
+				"results": []bson.M{
+					{
+						"name": "insert",
+						"results": bson.M{
+							"1": bson.M{"ops_per_sec": 100.0, "ops_per_sec_values": []float64{99, 101}},
+							"8": bson.M{"ops_per_sec": 400.0},
+						},
+					},
+				},
+			},
+		},
+		{
+			"task_id":    "task_2",
+			"project_id": "a_project",
+			"variant":    "a_variant",
+			"name":       "other",
+			"data":       bson.M{"results": []bson.M{{"name": "free-form", "results": bson.M{"1": "fast"}}}},
+		},
+	}
+	for _, d := range data {
+		s.NoError(evgdb.Insert(jsonCollection, d))
+	}
+}
+
+func (s *perfLegacyResultsMigration) TestMigration() {
+	args := migrationGeneratorFactoryOptions{
+		db:    s.database,
+		limit: 50,
+		id:    migrationPerfLegacyResults,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	generator, err := perfLegacyResultsGenerator(anser.GetEnvironment(), args)
+	s.Require().NoError(err)
+	generator.Run(ctx)
+	s.NoError(generator.Error())
+	for j := range generator.Jobs() {
+		j.Run(ctx)
+		s.NoError(j.Error())
+	}
+
+	results, err := perf.FindResults(perf.ResultsBySeries(perf.ResultsFilter{Project: "a_project"}))
+	s.Require().NoError(err)
+	s.Require().Len(results, 2)
+	for _, r := range results {
+		s.Equal("task_1", r.Info.TaskID)
+		s.Equal("perf", r.Info.TaskName)
+		s.Equal(5, r.Info.Order)
+		s.Equal("insert", r.Test)
+		s.Require().Len(r.Metrics, 1)
+		s.Equal("ops_per_sec", r.Metrics[0].Name)
+	}
+	s.Equal([]float64{99, 101}, results[0].Metrics[0].Trials)
+}
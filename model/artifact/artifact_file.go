@@ -21,6 +21,9 @@ type Entry struct {
 	BuildId         string `json:"build" bson:"build"`
 	Files           []File `json:"files" bson:"files"`
 	Execution       int    `json:"execution" bson:"execution"`
+	// Signature is the ed25519 signature of the entry's manifest, made
+	// with the server's artifact signing key whenever files are attached.
+	Signature string `json:"signature,omitempty" bson:"signature,omitempty"`
 }
 
 // Params stores file entries as key-value pairs, for easy parameter parsing.
@@ -38,6 +41,24 @@ type File struct {
 	Visibility string `json:"visibility" bson:"visibility"`
 	// When true, these artifacts are excluded from reproduction
 	IgnoreForFetch bool `bson:"fetch_ignore,omitempty" json:"ignore_for_fetch"`
+	// Expired is set when a retention rule has deleted the linked file
+	Expired bool `bson:"expired,omitempty" json:"expired,omitempty"`
+	// SHA256 is the hex encoded checksum of the file's contents, when known
+	SHA256 string `bson:"sha256,omitempty" json:"sha256,omitempty"`
+}
+
+// IsVisible returns true if the file may be shown to a user. Files with no
+// visibility are never shown, and private files are only shown to users
+// who are logged in.
+func (f File) IsVisible(loggedIn bool) bool {
+	switch f.Visibility {
+	case None:
+		return false
+	case Private:
+		return loggedIn
+	default:
+		return true
+	}
 }
 
 // Array turns the parameter map into an array of File structs.
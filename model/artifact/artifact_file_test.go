@@ -31,8 +31,8 @@ func (s *TestArtifactFileSuite) SetupTest() {
 			TaskDisplayName: "Task One",
 			BuildId:         "build1",
 			Files: []File{
-				{"cat_pix", "http://placekitten.com/800/600", "", false},
-				{"fast_download", "https://fastdl.mongodb.org", "", false},
+				{"cat_pix", "http://placekitten.com/800/600", "", false, false, ""},
+				{"fast_download", "https://fastdl.mongodb.org", "", false, false, ""},
 			},
 			Execution: 1,
 		},
@@ -41,7 +41,7 @@ func (s *TestArtifactFileSuite) SetupTest() {
 			TaskDisplayName: "Task Two",
 			BuildId:         "build2",
 			Files: []File{
-				{"other", "http://example.com/other", "", false},
+				{"other", "http://example.com/other", "", false, false, ""},
 			},
 			Execution: 5,
 		},
@@ -59,7 +59,7 @@ func (s *TestArtifactFileSuite) SetupTest() {
 		TaskDisplayName: "Task Two",
 		BuildId:         "build2",
 		Files: []File{
-			{"other", "http://example.com/other", "", false},
+			{"other", "http://example.com/other", "", false, false, ""},
 		},
 	}))
 
@@ -89,8 +89,8 @@ func (s *TestArtifactFileSuite) TestArtifactFieldsArePresent() {
 
 func (s *TestArtifactFileSuite) TestArtifactFieldsAfterUpdate() {
 	s.testEntries[0].Files = []File{
-		{"cat_pix", "http://placekitten.com/300/400", "", false},
-		{"the_value_of_four", "4", "", false},
+		{"cat_pix", "http://placekitten.com/300/400", "", false, false, ""},
+		{"the_value_of_four", "4", "", false, false, ""},
 	}
 	s.NoError(s.testEntries[0].Upsert())
 
@@ -166,3 +166,24 @@ func (s *TestArtifactFileSuite) TestFindByIds() {
 	s.NoError(err)
 	s.Len(entries, 3)
 }
+
+func (s *TestArtifactFileSuite) TestMarkFileExpired() {
+	entries, err := FindAll(ByTaskIdsWithUnexpiredFiles([]string{"task1"}))
+	s.NoError(err)
+	s.Require().Len(entries, 1)
+
+	s.NoError(entries[0].MarkFileExpired("http://placekitten.com/800/600"))
+	entry, err := FindOne(ByTaskId("task1"))
+	s.NoError(err)
+	s.True(entry.Files[0].Expired)
+	s.False(entry.Files[1].Expired)
+
+	entries, err = FindAll(ByTaskIdsWithUnexpiredFiles([]string{"task1"}))
+	s.NoError(err)
+	s.Len(entries, 1)
+
+	s.NoError(entries[0].MarkFileExpired("https://fastdl.mongodb.org"))
+	entries, err = FindAll(ByTaskIdsWithUnexpiredFiles([]string{"task1"}))
+	s.NoError(err)
+	s.Len(entries, 0)
+}
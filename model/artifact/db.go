@@ -3,6 +3,7 @@ package artifact
 import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -14,8 +15,10 @@ var (
 	BuildIdKey   = bsonutil.MustHaveTag(Entry{}, "BuildId")
 	FilesKey     = bsonutil.MustHaveTag(Entry{}, "Files")
 	ExecutionKey = bsonutil.MustHaveTag(Entry{}, "Execution")
+	SignatureKey = bsonutil.MustHaveTag(Entry{}, "Signature")
 	NameKey      = bsonutil.MustHaveTag(File{}, "Name")
 	LinkKey      = bsonutil.MustHaveTag(File{}, "Link")
+	ExpiredKey   = bsonutil.MustHaveTag(File{}, "Expired")
 )
 
 type TaskIDAndExecution struct {
@@ -71,6 +74,38 @@ func ByTaskIds(taskIds []string) db.Q {
 	})
 }
 
+// ByTaskIdsWithUnexpiredFiles returns entries for the given tasks that
+// still have at least one file which has not expired.
+func ByTaskIdsWithUnexpiredFiles(taskIds []string) db.Q {
+	return db.Query(bson.M{
+		TaskIdKey: bson.M{
+			"$in": taskIds,
+		},
+		FilesKey: bson.M{
+			"$elemMatch": bson.M{
+				ExpiredKey: bson.M{"$ne": true},
+			},
+		},
+	})
+}
+
+// ByOtherReferencesToLink returns the entries, other than the given
+// execution of a task, that link to the given file and have not expired it.
+func ByOtherReferencesToLink(link, taskId string, execution int) db.Q {
+	return db.Query(bson.M{
+		FilesKey: bson.M{
+			"$elemMatch": bson.M{
+				LinkKey:    link,
+				ExpiredKey: bson.M{"$ne": true},
+			},
+		},
+		"$or": []bson.M{
+			{TaskIdKey: bson.M{"$ne": taskId}},
+			{ExecutionKey: bson.M{"$ne": execution}},
+		},
+	})
+}
+
 // ByBuildId returns all entries with the given Build Id, sorted by Task name
 func ByBuildId(id string) db.Q {
 	return db.Query(bson.D{{BuildIdKey, id}}).Sort([]string{TaskNameKey})
@@ -103,6 +138,45 @@ func (e Entry) Upsert() error {
 	return err
 }
 
+// MarkFileExpired flags the entry's file with the given link as
+// expired.
+func (e Entry) MarkFileExpired(link string) error {
+	return db.Update(
+		Collection,
+		bson.M{
+			TaskIdKey:    e.TaskId,
+			ExecutionKey: e.Execution,
+			bsonutil.GetDottedKeyName(FilesKey, LinkKey): link,
+		},
+		bson.M{
+			"$set": bson.M{
+				bsonutil.GetDottedKeyName(FilesKey, "$", ExpiredKey): true,
+			},
+		},
+	)
+}
+
+// SetSignature stores the signature of the entry's manifest.
+func (e *Entry) SetSignature(signature string) error {
+	err := db.Update(
+		Collection,
+		bson.M{
+			TaskIdKey:    e.TaskId,
+			ExecutionKey: e.Execution,
+		},
+		bson.M{
+			"$set": bson.M{
+				SignatureKey: signature,
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "problem setting artifact signature for task '%s'", e.TaskId)
+	}
+	e.Signature = signature
+	return nil
+}
+
 // FindOne gets one Entry for the given query
 func FindOne(query db.Q) (*Entry, error) {
 	entry := &Entry{}
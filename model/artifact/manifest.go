@@ -0,0 +1,68 @@
+package artifact
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+)
+
+const signaturePrefix = "ed25519="
+
+// Manifest returns a canonical listing of the entry's files. It starts with
+// a line of the form "task <id> execution <n>", so that a signature can't be
+// passed off as another task's, followed by one line per file of the form
+// "<sha256>  <name>  <link>", sorted so that the same set of files always
+// produces the same manifest. Files without a recorded checksum are listed
+// with "-" in its place.
+func (e *Entry) Manifest() []byte {
+	lines := make([]string, 0, len(e.Files))
+	for _, f := range e.Files {
+		sum := f.SHA256
+		if sum == "" {
+			sum = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s  %s\n", sum, f.Name, f.Link))
+	}
+	sort.Strings(lines)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "task %s execution %d\n", e.TaskId, e.Execution)
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+	return buf.Bytes()
+}
+
+// Sign computes the signature of the entry's manifest with the server's
+// ed25519 key. Signatures have the form "ed25519=<base64 signature>", and
+// can be checked by anyone with the public key.
+func (e *Entry) Sign(key ed25519.PrivateKey) (string, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", errors.New("cannot sign artifacts without an ed25519 private key")
+	}
+	return signaturePrefix + base64.StdEncoding.EncodeToString(ed25519.Sign(key, e.Manifest())), nil
+}
+
+// Verify checks that the signature was made for the entry's manifest by the
+// private key matching the public key.
+func (e *Entry) Verify(key ed25519.PublicKey, signature string) error {
+	if len(key) != ed25519.PublicKeySize {
+		return errors.New("cannot verify artifacts without an ed25519 public key")
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return errors.Errorf("signature is not of the form '%s<signature>'", signaturePrefix)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return errors.Wrap(err, "signature is not base64 encoded")
+	}
+	if !ed25519.Verify(key, e.Manifest(), raw) {
+		return errors.New("signature does not match the manifest")
+	}
+	return nil
+}
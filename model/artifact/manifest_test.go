@@ -0,0 +1,74 @@
+package artifact
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestManifest(t *testing.T) {
+	assert := assert.New(t)
+
+	entry := Entry{
+		TaskId: "t1",
+		Files: []File{
+			{Name: "b", Link: "https://s3.amazonaws.com/bucket/b", SHA256: "bbbb"},
+			{Name: "a", Link: "https://s3.amazonaws.com/bucket/a"},
+		},
+	}
+	manifest := string(entry.Manifest())
+	assert.Equal("task t1 execution 0\n-  a  https://s3.amazonaws.com/bucket/a\nbbbb  b  https://s3.amazonaws.com/bucket/b\n", manifest)
+
+	// the order files were attached in does not change the manifest
+	entry.Files[0], entry.Files[1] = entry.Files[1], entry.Files[0]
+	assert.Equal(manifest, string(entry.Manifest()))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	entry := Entry{
+		TaskId: "t1",
+		Files: []File{
+			{Name: "a", Link: "https://s3.amazonaws.com/bucket/a", SHA256: "aaaa"},
+		},
+	}
+
+	_, err := entry.Sign(nil)
+	assert.Error(err)
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	signature, err := entry.Sign(private)
+	require.NoError(err)
+	assert.Contains(signature, "ed25519=")
+	assert.NoError(entry.Verify(public, signature))
+
+	otherPublic, otherPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	other, err := entry.Sign(otherPrivate)
+	require.NoError(err)
+	assert.NotEqual(signature, other)
+	assert.Error(entry.Verify(otherPublic, signature))
+
+	assert.Error(entry.Verify(public, "sha256=abc"))
+	assert.Error(entry.Verify(nil, signature))
+
+	// a signature doesn't carry over to another task or execution
+	replayed := entry
+	replayed.TaskId = "t2"
+	assert.Error(replayed.Verify(public, signature))
+	replayed = entry
+	replayed.Execution = 1
+	assert.Error(replayed.Verify(public, signature))
+
+	entry.Files[0].SHA256 = "bbbb"
+	assert.Error(entry.Verify(public, signature))
+	changed, err := entry.Sign(private)
+	require.NoError(err)
+	assert.NotEqual(signature, changed)
+}
@@ -0,0 +1,83 @@
+package artifact
+
+import (
+	"path"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+const (
+	// values for RetentionRule.Requester
+	RetentionRequesterAny      = ""
+	RetentionRequesterPatch    = "patch"
+	RetentionRequesterMainline = "mainline"
+)
+
+// RetentionRule describes when the artifacts attached by a project's
+// tasks expire. A file is covered by a rule if its name matches the
+// rule's pattern and its task was created by a matching requester;
+// covered files expire once their task finished more than MaxAgeDays
+// ago, unless they belong to one of the KeepGreenVersions most recent
+// successful mainline versions.
+type RetentionRule struct {
+	// NamePattern is a shell glob matched against the file's display
+	// name. An empty pattern matches every file.
+	NamePattern string `bson:"name_pattern" json:"name_pattern" yaml:"name_pattern"`
+	// Requester is one of "patch", "mainline" or empty for both.
+	Requester         string `bson:"requester" json:"requester" yaml:"requester"`
+	MaxAgeDays        int    `bson:"max_age_days" json:"max_age_days" yaml:"max_age_days"`
+	KeepGreenVersions int    `bson:"keep_green_versions" json:"keep_green_versions" yaml:"keep_green_versions"`
+}
+
+// Validate checks that the rule is well formed.
+func (r *RetentionRule) Validate() error {
+	if r.MaxAgeDays <= 0 {
+		return errors.New("retention rules must have a positive max age")
+	}
+	if r.KeepGreenVersions < 0 {
+		return errors.New("number of green versions to keep cannot be negative")
+	}
+	switch r.Requester {
+	case RetentionRequesterAny, RetentionRequesterPatch, RetentionRequesterMainline:
+	default:
+		return errors.Errorf("'%s' is not a valid retention requester", r.Requester)
+	}
+	if _, err := path.Match(r.NamePattern, ""); err != nil {
+		return errors.Wrapf(err, "'%s' is not a valid name pattern", r.NamePattern)
+	}
+	return nil
+}
+
+// Requesters returns the version requesters that the rule applies to.
+func (r *RetentionRule) Requesters() []string {
+	switch r.Requester {
+	case RetentionRequesterPatch:
+		return []string{evergreen.PatchVersionRequester, evergreen.GithubPRRequester}
+	case RetentionRequesterMainline:
+		return []string{evergreen.RepotrackerVersionRequester}
+	default:
+		return []string{
+			evergreen.PatchVersionRequester,
+			evergreen.GithubPRRequester,
+			evergreen.RepotrackerVersionRequester,
+		}
+	}
+}
+
+// Cutoff returns the time before which a task must have finished for
+// its artifacts to expire under the rule.
+func (r *RetentionRule) Cutoff(now time.Time) time.Time {
+	return now.Add(-time.Duration(r.MaxAgeDays) * 24 * time.Hour)
+}
+
+// MatchesName reports whether the rule covers a file with the given
+// display name.
+func (r *RetentionRule) MatchesName(name string) bool {
+	if r.NamePattern == "" {
+		return true
+	}
+	match, err := path.Match(r.NamePattern, name)
+	return err == nil && match
+}
@@ -0,0 +1,40 @@
+package artifact
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionRuleValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&RetentionRule{MaxAgeDays: 1}).Validate())
+	assert.NoError((&RetentionRule{MaxAgeDays: 1, Requester: RetentionRequesterPatch, NamePattern: "*.tgz"}).Validate())
+	assert.Error((&RetentionRule{}).Validate())
+	assert.Error((&RetentionRule{MaxAgeDays: 1, KeepGreenVersions: -1}).Validate())
+	assert.Error((&RetentionRule{MaxAgeDays: 1, Requester: "nightly"}).Validate())
+	assert.Error((&RetentionRule{MaxAgeDays: 1, NamePattern: "["}).Validate())
+}
+
+func TestRetentionRuleMatching(t *testing.T) {
+	assert := assert.New(t)
+
+	rule := RetentionRule{MaxAgeDays: 2}
+	assert.True(rule.MatchesName("anything"))
+	assert.Len(rule.Requesters(), 3)
+
+	rule.NamePattern = "*.tgz"
+	assert.True(rule.MatchesName("binaries.tgz"))
+	assert.False(rule.MatchesName("results.json"))
+
+	rule.Requester = RetentionRequesterMainline
+	assert.Equal([]string{evergreen.RepotrackerVersionRequester}, rule.Requesters())
+	rule.Requester = RetentionRequesterPatch
+	assert.Contains(rule.Requesters(), evergreen.GithubPRRequester)
+
+	now := time.Now()
+	assert.Equal(now.Add(-48*time.Hour), rule.Cutoff(now))
+}
@@ -0,0 +1,227 @@
+package buildcache
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection holds one document per saved cache key.
+	Collection = "build_cache_entries"
+	// StatsCollection holds one document of counters per project.
+	StatsCollection = "build_cache_stats"
+
+	// RemotePrefix is the prefix of every cache object in the bucket.
+	RemotePrefix = "build-cache"
+	remoteSuffix = ".tgz"
+)
+
+// Entry records a tarball written by cache.save so that the server can
+// evict it once it is too old or its project uses too much space.
+type Entry struct {
+	ID           string    `bson:"_id" json:"id"`
+	Project      string    `bson:"project" json:"project"`
+	Key          string    `bson:"key" json:"key"`
+	Bucket       string    `bson:"bucket" json:"bucket"`
+	RemotePath   string    `bson:"remote_path" json:"remote_path"`
+	SizeBytes    int64     `bson:"size_bytes" json:"size_bytes"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+	LastAccessed time.Time `bson:"last_accessed" json:"last_accessed"`
+}
+
+// ProjectStats counts cache activity for a single project.
+type ProjectStats struct {
+	Project      string `bson:"_id" json:"project"`
+	Hits         int64  `bson:"hits" json:"hits"`
+	PartialHits  int64  `bson:"partial_hits" json:"partial_hits"`
+	Misses       int64  `bson:"misses" json:"misses"`
+	Saves        int64  `bson:"saves" json:"saves"`
+	Evictions    int64  `bson:"evictions" json:"evictions"`
+	BytesEvicted int64  `bson:"bytes_evicted" json:"bytes_evicted"`
+}
+
+var (
+	IDKey           = bsonutil.MustHaveTag(Entry{}, "ID")
+	ProjectKey      = bsonutil.MustHaveTag(Entry{}, "Project")
+	KeyKey          = bsonutil.MustHaveTag(Entry{}, "Key")
+	BucketKey       = bsonutil.MustHaveTag(Entry{}, "Bucket")
+	RemotePathKey   = bsonutil.MustHaveTag(Entry{}, "RemotePath")
+	SizeBytesKey    = bsonutil.MustHaveTag(Entry{}, "SizeBytes")
+	CreatedAtKey    = bsonutil.MustHaveTag(Entry{}, "CreatedAt")
+	LastAccessedKey = bsonutil.MustHaveTag(Entry{}, "LastAccessed")
+
+	statsHitsKey         = bsonutil.MustHaveTag(ProjectStats{}, "Hits")
+	statsPartialHitsKey  = bsonutil.MustHaveTag(ProjectStats{}, "PartialHits")
+	statsMissesKey       = bsonutil.MustHaveTag(ProjectStats{}, "Misses")
+	statsSavesKey        = bsonutil.MustHaveTag(ProjectStats{}, "Saves")
+	statsEvictionsKey    = bsonutil.MustHaveTag(ProjectStats{}, "Evictions")
+	statsBytesEvictedKey = bsonutil.MustHaveTag(ProjectStats{}, "BytesEvicted")
+)
+
+// EntryID returns the id of the entry for the given key, which is
+// unique within a project.
+func EntryID(project, key string) string {
+	return fmt.Sprintf("%s/%s", project, key)
+}
+
+// RemotePath returns the location of a cache key's tarball within the
+// bucket. Keys are scoped to a project.
+func RemotePath(project, key string) string {
+	return path.Join(RemotePrefix, project, key) + remoteSuffix
+}
+
+// KeyFromRemotePath is the inverse of RemotePath.
+func KeyFromRemotePath(project, remotePath string) string {
+	key := strings.TrimPrefix(remotePath, path.Join(RemotePrefix, project)+"/")
+	return strings.TrimSuffix(key, remoteSuffix)
+}
+
+// ValidateKey checks that a key cannot address an object outside of its
+// project's prefix.
+func ValidateKey(key string) error {
+	if key == "" {
+		return errors.New("cache key cannot be blank")
+	}
+	if strings.Contains(key, "..") {
+		return errors.Errorf("cache key '%s' cannot contain '..'", key)
+	}
+	return nil
+}
+
+// ByProject returns a query for all entries of a project, least
+// recently used first.
+func ByProject(project string) db.Q {
+	return db.Query(bson.M{ProjectKey: project}).Sort([]string{LastAccessedKey})
+}
+
+// Find returns all entries matching the query.
+func Find(query db.Q) ([]Entry, error) {
+	entries := []Entry{}
+	err := db.FindAllQ(Collection, query, &entries)
+	return entries, errors.WithStack(err)
+}
+
+// FindOne returns a single entry, or nil if none matches.
+func FindOne(query db.Q) (*Entry, error) {
+	entry := &Entry{}
+	err := db.FindOneQ(Collection, query, entry)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return entry, errors.WithStack(err)
+}
+
+// FindProjects returns the identifiers of every project that has at
+// least one cache entry.
+func FindProjects() ([]string, error) {
+	out := []struct {
+		Project string `bson:"_id"`
+	}{}
+	pipeline := []bson.M{
+		{"$group": bson.M{"_id": "$" + ProjectKey}},
+	}
+	if err := db.Aggregate(Collection, pipeline, &out); err != nil {
+		return nil, errors.Wrap(err, "problem finding projects with cache entries")
+	}
+
+	projects := make([]string, 0, len(out))
+	for _, p := range out {
+		projects = append(projects, p.Project)
+	}
+	return projects, nil
+}
+
+// Upsert records a newly saved entry. Saving an existing key replaces
+// its location and size and resets its timestamps.
+func (e *Entry) Upsert() error {
+	e.ID = EntryID(e.Project, e.Key)
+	_, err := db.Upsert(Collection, bson.M{IDKey: e.ID}, e)
+	return errors.Wrapf(err, "problem saving cache entry '%s'", e.ID)
+}
+
+// Touch marks the entry as used at the given time.
+func (e *Entry) Touch(ts time.Time) error {
+	e.LastAccessed = ts
+	err := db.Update(Collection, bson.M{IDKey: e.ID}, bson.M{
+		"$set": bson.M{LastAccessedKey: ts},
+	})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return errors.Wrapf(err, "problem updating cache entry '%s'", e.ID)
+}
+
+// Remove deletes the entry's record. It does not touch the object
+// in the bucket.
+func (e *Entry) Remove() error {
+	return errors.Wrapf(db.Remove(Collection, bson.M{IDKey: e.ID}),
+		"problem removing cache entry '%s'", e.ID)
+}
+
+// RecordHit, RecordPartialHit, RecordMiss and RecordSave increment the
+// corresponding counter for the project.
+func RecordHit(project string) error        { return incStats(project, bson.M{statsHitsKey: 1}) }
+func RecordPartialHit(project string) error { return incStats(project, bson.M{statsPartialHitsKey: 1}) }
+func RecordMiss(project string) error       { return incStats(project, bson.M{statsMissesKey: 1}) }
+func RecordSave(project string) error       { return incStats(project, bson.M{statsSavesKey: 1}) }
+
+// RecordEviction accounts for the removal of one entry of the given
+// size.
+func RecordEviction(project string, size int64) error {
+	return incStats(project, bson.M{statsEvictionsKey: 1, statsBytesEvictedKey: size})
+}
+
+func incStats(project string, inc bson.M) error {
+	_, err := db.Upsert(StatsCollection, bson.M{"_id": project}, bson.M{"$inc": inc})
+	return errors.Wrapf(err, "problem updating cache stats for '%s'", project)
+}
+
+// FindStats returns the counters for a project. A project that has
+// never used the cache has zero counters.
+func FindStats(project string) (*ProjectStats, error) {
+	stats := &ProjectStats{}
+	err := db.FindOne(StatsCollection, bson.M{"_id": project}, db.NoProjection, db.NoSort, stats)
+	if err == mgo.ErrNotFound {
+		return &ProjectStats{Project: project}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding cache stats for '%s'", project)
+	}
+	return stats, nil
+}
+
+// EvictionCandidates selects, from entries ordered least recently used
+// first, those that are older than the ttl and then as many of the
+// remaining ones as needed to bring the total size under maxSize.
+func EvictionCandidates(entries []Entry, now time.Time, ttl time.Duration, maxSize int64) []Entry {
+	out := []Entry{}
+	kept := []Entry{}
+	var total int64
+
+	for _, e := range entries {
+		if ttl > 0 && now.Sub(e.LastAccessed) > ttl {
+			out = append(out, e)
+			continue
+		}
+		kept = append(kept, e)
+		total += e.SizeBytes
+	}
+
+	for _, e := range kept {
+		if maxSize <= 0 || total <= maxSize {
+			break
+		}
+		out = append(out, e)
+		total -= e.SizeBytes
+	}
+
+	return out
+}
@@ -0,0 +1,110 @@
+package buildcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestEntryLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection, StatsCollection))
+
+	now := time.Now().Round(time.Millisecond)
+	e := &Entry{
+		Project:      "proj",
+		Key:          "deps-abc",
+		Bucket:       "bucket",
+		RemotePath:   "build-cache/proj/deps-abc.tgz",
+		SizeBytes:    42,
+		CreatedAt:    now,
+		LastAccessed: now,
+	}
+	require.NoError(e.Upsert())
+	assert.Equal("proj/deps-abc", e.ID)
+
+	// saving the same key again replaces the entry
+	e.SizeBytes = 64
+	require.NoError(e.Upsert())
+	entries, err := Find(ByProject("proj"))
+	require.NoError(err)
+	require.Len(entries, 1)
+	assert.EqualValues(64, entries[0].SizeBytes)
+
+	later := now.Add(time.Hour)
+	require.NoError(e.Touch(later))
+	found, err := FindOne(db.Query(bson.M{IDKey: e.ID}))
+	require.NoError(err)
+	require.NotNil(found)
+	assert.True(later.Equal(found.LastAccessed))
+
+	projects, err := FindProjects()
+	require.NoError(err)
+	assert.Equal([]string{"proj"}, projects)
+
+	require.NoError(e.Remove())
+	found, err = FindOne(db.Query(bson.M{IDKey: e.ID}))
+	assert.NoError(err)
+	assert.Nil(found)
+}
+
+func TestStats(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(StatsCollection))
+
+	stats, err := FindStats("proj")
+	require.NoError(err)
+	assert.Equal("proj", stats.Project)
+	assert.Zero(stats.Hits)
+
+	assert.NoError(RecordHit("proj"))
+	assert.NoError(RecordHit("proj"))
+	assert.NoError(RecordPartialHit("proj"))
+	assert.NoError(RecordMiss("proj"))
+	assert.NoError(RecordSave("proj"))
+	assert.NoError(RecordEviction("proj", 100))
+
+	stats, err = FindStats("proj")
+	require.NoError(err)
+	assert.EqualValues(2, stats.Hits)
+	assert.EqualValues(1, stats.PartialHits)
+	assert.EqualValues(1, stats.Misses)
+	assert.EqualValues(1, stats.Saves)
+	assert.EqualValues(1, stats.Evictions)
+	assert.EqualValues(100, stats.BytesEvicted)
+}
+
+func TestEvictionCandidates(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	entries := []Entry{
+		{ID: "old", SizeBytes: 10, LastAccessed: now.Add(-72 * time.Hour)},
+		{ID: "a", SizeBytes: 30, LastAccessed: now.Add(-3 * time.Hour)},
+		{ID: "b", SizeBytes: 30, LastAccessed: now.Add(-2 * time.Hour)},
+		{ID: "c", SizeBytes: 30, LastAccessed: now.Add(-1 * time.Hour)},
+	}
+
+	out := EvictionCandidates(entries, now, 48*time.Hour, 0)
+	assert.Len(out, 1)
+	assert.Equal("old", out[0].ID)
+
+	out = EvictionCandidates(entries, now, 48*time.Hour, 60)
+	assert.Len(out, 2)
+	assert.Equal("old", out[0].ID)
+	assert.Equal("a", out[1].ID)
+
+	out = EvictionCandidates(entries, now, 0, 1000)
+	assert.Empty(out)
+}
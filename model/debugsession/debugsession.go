@@ -0,0 +1,287 @@
+package debugsession
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection holds one document per debug session.
+	Collection = "debug_sessions"
+	// ChunksCollection holds the input typed into and the output read from
+	// each session's shell. It is kept after the session closes as the
+	// session's transcript.
+	ChunksCollection = "debug_session_chunks"
+)
+
+// Session statuses. A session is requested by a user, started by the agent
+// running the task, and closed by either side or when the task ends.
+const (
+	StatusRequested = "requested"
+	StatusActive    = "active"
+	StatusClosed    = "closed"
+)
+
+// Chunk streams.
+const (
+	StreamInput  = "input"
+	StreamOutput = "output"
+)
+
+// MaxChunkSize bounds a single chunk of input or output.
+const MaxChunkSize = 64 * 1024
+
+// Session is an interactive shell that a user has opened into the
+// environment of a running task.
+type Session struct {
+	ID          string    `bson:"_id" json:"id"`
+	TaskID      string    `bson:"task_id" json:"task_id"`
+	Execution   int       `bson:"execution" json:"execution"`
+	HostID      string    `bson:"host_id" json:"host_id"`
+	User        string    `bson:"user" json:"user"`
+	Status      string    `bson:"status" json:"status"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	StartedAt   time.Time `bson:"started_at,omitempty" json:"started_at"`
+	ClosedAt    time.Time `bson:"closed_at,omitempty" json:"closed_at"`
+	CloseReason string    `bson:"close_reason,omitempty" json:"close_reason,omitempty"`
+	InputSeq    int       `bson:"input_seq" json:"input_seq"`
+	OutputSeq   int       `bson:"output_seq" json:"output_seq"`
+}
+
+// Chunk is one piece of a session's input or output. Chunks of each stream
+// are numbered from 1 in the order they were written.
+type Chunk struct {
+	ID        bson.ObjectId `bson:"_id" json:"-"`
+	SessionID string        `bson:"session_id" json:"session_id"`
+	Stream    string        `bson:"stream" json:"stream"`
+	Seq       int           `bson:"seq" json:"seq"`
+	Data      string        `bson:"data" json:"data"`
+	Timestamp time.Time     `bson:"ts" json:"ts"`
+}
+
+var (
+	IDKey          = bsonutil.MustHaveTag(Session{}, "ID")
+	TaskIDKey      = bsonutil.MustHaveTag(Session{}, "TaskID")
+	ExecutionKey   = bsonutil.MustHaveTag(Session{}, "Execution")
+	HostIDKey      = bsonutil.MustHaveTag(Session{}, "HostID")
+	UserKey        = bsonutil.MustHaveTag(Session{}, "User")
+	StatusKey      = bsonutil.MustHaveTag(Session{}, "Status")
+	CreatedAtKey   = bsonutil.MustHaveTag(Session{}, "CreatedAt")
+	StartedAtKey   = bsonutil.MustHaveTag(Session{}, "StartedAt")
+	ClosedAtKey    = bsonutil.MustHaveTag(Session{}, "ClosedAt")
+	CloseReasonKey = bsonutil.MustHaveTag(Session{}, "CloseReason")
+	InputSeqKey    = bsonutil.MustHaveTag(Session{}, "InputSeq")
+	OutputSeqKey   = bsonutil.MustHaveTag(Session{}, "OutputSeq")
+
+	ChunkSessionIDKey = bsonutil.MustHaveTag(Chunk{}, "SessionID")
+	ChunkStreamKey    = bsonutil.MustHaveTag(Chunk{}, "Stream")
+	ChunkSeqKey       = bsonutil.MustHaveTag(Chunk{}, "Seq")
+)
+
+// IsOpen reports whether the session has not yet been closed.
+func (s *Session) IsOpen() bool { return s.Status != StatusClosed }
+
+// ById returns a query for the session with the given id.
+func ById(id string) db.Q {
+	return db.Query(bson.M{IDKey: id})
+}
+
+// OpenForTask returns a query for the session of a task execution that has
+// not yet been closed.
+func OpenForTask(taskID string, execution int) db.Q {
+	return db.Query(bson.M{
+		TaskIDKey:    taskID,
+		ExecutionKey: execution,
+		StatusKey:    bson.M{"$ne": StatusClosed},
+	})
+}
+
+// ByTask returns a query for every session opened into a task, newest first.
+func ByTask(taskID string) db.Q {
+	return db.Query(bson.M{TaskIDKey: taskID}).Sort([]string{"-" + CreatedAtKey})
+}
+
+// FindOne returns a single session, or nil if none matches.
+func FindOne(query db.Q) (*Session, error) {
+	s := &Session{}
+	err := db.FindOneQ(Collection, query, s)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return s, errors.WithStack(err)
+}
+
+// Find returns all sessions matching the query.
+func Find(query db.Q) ([]Session, error) {
+	sessions := []Session{}
+	err := db.FindAllQ(Collection, query, &sessions)
+	return sessions, errors.WithStack(err)
+}
+
+// Create requests a new session into a task execution. A task may only
+// have one open session at a time.
+func Create(taskID string, execution int, hostID, user string) (*Session, error) {
+	existing, err := FindOne(OpenForTask(taskID, execution))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding debug sessions for task '%s'", taskID)
+	}
+	if existing != nil {
+		return nil, errors.Errorf("task '%s' already has an open debug session '%s' belonging to '%s'",
+			taskID, existing.ID, existing.User)
+	}
+
+	s := &Session{
+		ID:        bson.NewObjectId().Hex(),
+		TaskID:    taskID,
+		Execution: execution,
+		HostID:    hostID,
+		User:      user,
+		Status:    StatusRequested,
+		CreatedAt: time.Now(),
+	}
+	if err = db.Insert(Collection, s); err != nil {
+		return nil, errors.Wrapf(err, "problem creating debug session for task '%s'", taskID)
+	}
+	event.LogTaskDebugSession(taskID, execution, event.TaskDebugSessionOpened, hostID, user, s.ID, "")
+
+	return s, nil
+}
+
+// Start records that the agent has started the session's shell.
+func (s *Session) Start() error {
+	now := time.Now()
+	err := db.Update(Collection, bson.M{
+		IDKey:     s.ID,
+		StatusKey: StatusRequested,
+	}, bson.M{
+		"$set": bson.M{
+			StatusKey:    StatusActive,
+			StartedAtKey: now,
+		},
+	})
+	if err == mgo.ErrNotFound {
+		return errors.Errorf("debug session '%s' is not waiting to start", s.ID)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem starting debug session '%s'", s.ID)
+	}
+
+	s.Status = StatusActive
+	s.StartedAt = now
+	event.LogTaskDebugSession(s.TaskID, s.Execution, event.TaskDebugSessionStarted, s.HostID, s.User, s.ID, "")
+	return nil
+}
+
+// Close ends the session. Closing a closed session is a no-op.
+func (s *Session) Close(reason string) error {
+	now := time.Now()
+	err := db.Update(Collection, bson.M{
+		IDKey:     s.ID,
+		StatusKey: bson.M{"$ne": StatusClosed},
+	}, bson.M{
+		"$set": bson.M{
+			StatusKey:      StatusClosed,
+			ClosedAtKey:    now,
+			CloseReasonKey: reason,
+		},
+	})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem closing debug session '%s'", s.ID)
+	}
+
+	s.Status = StatusClosed
+	s.ClosedAt = now
+	s.CloseReason = reason
+	event.LogTaskDebugSession(s.TaskID, s.Execution, event.TaskDebugSessionClosed, s.HostID, s.User, s.ID, reason)
+	return nil
+}
+
+// Append adds a chunk to one of the session's streams and returns its
+// sequence number. Only open sessions accept chunks.
+func (s *Session) Append(stream, data string) (int, error) {
+	if stream != StreamInput && stream != StreamOutput {
+		return 0, errors.Errorf("invalid debug session stream '%s'", stream)
+	}
+	if len(data) > MaxChunkSize {
+		return 0, errors.Errorf("chunk of %d bytes exceeds the limit of %d", len(data), MaxChunkSize)
+	}
+
+	seqKey := InputSeqKey
+	if stream == StreamOutput {
+		seqKey = OutputSeqKey
+	}
+
+	updated := &Session{}
+	_, err := db.FindAndModify(Collection, bson.M{
+		IDKey:     s.ID,
+		StatusKey: bson.M{"$ne": StatusClosed},
+	}, nil, mgo.Change{
+		Update:    bson.M{"$inc": bson.M{seqKey: 1}},
+		ReturnNew: true,
+	}, updated)
+	if err == mgo.ErrNotFound {
+		return 0, errors.Errorf("debug session '%s' is closed", s.ID)
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem appending to debug session '%s'", s.ID)
+	}
+
+	seq := updated.InputSeq
+	if stream == StreamOutput {
+		seq = updated.OutputSeq
+	}
+	chunk := &Chunk{
+		ID:        bson.NewObjectId(),
+		SessionID: s.ID,
+		Stream:    stream,
+		Seq:       seq,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+	if err = db.Insert(ChunksCollection, chunk); err != nil {
+		return 0, errors.Wrapf(err, "problem saving chunk %d of debug session '%s'", seq, s.ID)
+	}
+
+	*s = *updated
+	return seq, nil
+}
+
+// FindChunks returns up to limit chunks of a session's stream with sequence
+// numbers after the given one, in order.
+func FindChunks(sessionID, stream string, after, limit int) ([]Chunk, error) {
+	chunks := []Chunk{}
+	q := db.Query(bson.M{
+		ChunkSessionIDKey: sessionID,
+		ChunkStreamKey:    stream,
+		ChunkSeqKey:       bson.M{"$gt": after},
+	}).Sort([]string{ChunkSeqKey}).Limit(limit)
+	err := db.FindAllQ(ChunksCollection, q, &chunks)
+	return chunks, errors.WithStack(err)
+}
+
+// CloseForTask closes any open session of the task execution, for use when
+// the task finishes.
+func CloseForTask(taskID string, execution int, reason string) error {
+	s, err := FindOne(OpenForTask(taskID, execution))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding debug session for task '%s'", taskID)
+	}
+	if s == nil {
+		return nil
+	}
+	return s.Close(reason)
+}
+
+func (s *Session) String() string {
+	return fmt.Sprintf("%s (task %s, execution %d, user %s, %s)", s.ID, s.TaskID, s.Execution, s.User, s.Status)
+}
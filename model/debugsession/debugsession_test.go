@@ -0,0 +1,71 @@
+package debugsession
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection, ChunksCollection, event.AllLogCollection))
+
+	s, err := Create("t1", 0, "h1", "user")
+	require.NoError(err)
+	assert.Equal(StatusRequested, s.Status)
+
+	_, err = Create("t1", 0, "h1", "other")
+	assert.Error(err, "a task has one open session")
+
+	require.NoError(s.Start())
+	assert.Error(s.Start())
+
+	for _, data := range []string{"ls\n", "pwd\n", "exit\n"} {
+		_, err = s.Append(StreamInput, data)
+		require.NoError(err)
+	}
+	seq, err := s.Append(StreamOutput, "out")
+	require.NoError(err)
+	assert.Equal(1, seq)
+	_, err = s.Append("other", "x")
+	assert.Error(err)
+	_, err = s.Append(StreamInput, strings.Repeat("x", MaxChunkSize+1))
+	assert.Error(err)
+
+	chunks, err := FindChunks(s.ID, StreamInput, 1, 1)
+	require.NoError(err)
+	require.Len(chunks, 1)
+	assert.Equal(2, chunks[0].Seq)
+	assert.Equal("pwd\n", chunks[0].Data)
+
+	require.NoError(CloseForTask("t1", 0, "task finished"))
+	found, err := FindOne(ById(s.ID))
+	require.NoError(err)
+	require.NotNil(found)
+	assert.Equal(StatusClosed, found.Status)
+	assert.Equal("task finished", found.CloseReason)
+	assert.NoError(found.Close("again"), "closing twice is a no-op")
+	_, err = found.Append(StreamInput, "late\n")
+	assert.Error(err)
+
+	events, err := event.Find(event.AllLogCollection, event.TaskEventsInOrder("t1"))
+	require.NoError(err)
+	assert.Len(events, 3)
+
+	// the transcript outlives the session, and a new one may be opened
+	_, err = Create("t1", 0, "h1", "other")
+	assert.NoError(err)
+	sessions, err := Find(ByTask("t1"))
+	require.NoError(err)
+	assert.Len(sessions, 2)
+}
@@ -73,6 +73,12 @@ func BySpawnAllowed() db.Q {
 	return db.Query(bson.D{{SpawnAllowedKey, true}})
 }
 
+// ByContainerPool returns a query that selects the distros whose hosts are
+// containers in the pool.
+func ByContainerPool(poolID string) db.Q {
+	return db.Query(bson.M{ContainerPoolKey: poolID})
+}
+
 // ByActive returns a query that selects only active distros
 func ByActive() db.Q {
 	return db.Query(bson.M{DisabledKey: bson.M{"$exists": false}})
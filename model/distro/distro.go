@@ -34,6 +34,74 @@ type Distro struct {
 	Disabled     bool        `bson:"disabled,omitempty" json:"disabled,omitempty" mapstructure:"disabled,omitempty"`
 
 	ContainerPool string `bson:"container_pool,omitempty" json:"container_pool,omitempty" mapstructure:"container_pool,omitempty"`
+
+	DiskGuard DiskGuardSettings `bson:"disk_guard,omitempty" json:"disk_guard,omitempty" mapstructure:"disk_guard,omitempty"`
+
+	TaskPrioritizer string            `bson:"task_prioritizer,omitempty" json:"task_prioritizer,omitempty" mapstructure:"task_prioritizer,omitempty"`
+	FairShare       FairShareSettings `bson:"fair_share,omitempty" json:"fair_share,omitempty" mapstructure:"fair_share,omitempty"`
+}
+
+const (
+	// TaskPrioritizerCmp orders a distro's queue by comparing tasks'
+	// priority, dependents, age, runtime and failure history.
+	TaskPrioritizerCmp = "cmp"
+	// TaskPrioritizerFairShare allocates a distro's queue positions between
+	// projects by their weights and how much they have recently used it.
+	TaskPrioritizerFairShare = "fair-share"
+
+	defaultFairShareHalfLifeHours = 6
+)
+
+// ValidTaskPrioritizers are the task prioritizers that a distro may use. An
+// empty prioritizer is the cmp prioritizer.
+var ValidTaskPrioritizers = []string{"", TaskPrioritizerCmp, TaskPrioritizerFairShare}
+
+// FairShareSettings configure the fair-share task prioritizer.
+type FairShareSettings struct {
+	// Weights are the projects' relative shares of the distro. Projects
+	// without a weight have a weight of 1.
+	Weights []ProjectWeight `bson:"weights,omitempty" json:"weights,omitempty" mapstructure:"weights,omitempty"`
+	// UsageHalfLifeHours is how long it takes for the weight of a project's
+	// past use of the distro to halve. It defaults to 6 hours.
+	UsageHalfLifeHours int `bson:"usage_half_life_hours,omitempty" json:"usage_half_life_hours,omitempty" mapstructure:"usage_half_life_hours,omitempty"`
+}
+
+// ProjectWeight is a project's relative share of a distro.
+type ProjectWeight struct {
+	Project string  `bson:"project" json:"project" mapstructure:"project"`
+	Weight  float64 `bson:"weight" json:"weight" mapstructure:"weight"`
+}
+
+// Weight returns a project's relative share of the distro.
+func (s *FairShareSettings) Weight(project string) float64 {
+	for _, w := range s.Weights {
+		if w.Project == project {
+			return w.Weight
+		}
+	}
+	return 1
+}
+
+// UsageHalfLife returns how long it takes for the weight of past use of the
+// distro to halve.
+func (s *FairShareSettings) UsageHalfLife() time.Duration {
+	if s.UsageHalfLifeHours <= 0 {
+		return defaultFairShareHalfLifeHours * time.Hour
+	}
+	return time.Duration(s.UsageHalfLifeHours) * time.Hour
+}
+
+// DiskGuardSettings bound the disk space that tasks on a distro's hosts may
+// use. A zero value means no limit.
+type DiskGuardSettings struct {
+	// MinFreeMB is the free space the working directory's disk must have
+	// before a task starts.
+	MinFreeMB int `bson:"min_free_mb,omitempty" json:"min_free_mb,omitempty" mapstructure:"min_free_mb,omitempty"`
+	// FreeFloorMB is the free space below which a running task is aborted.
+	FreeFloorMB int `bson:"free_floor_mb,omitempty" json:"free_floor_mb,omitempty" mapstructure:"free_floor_mb,omitempty"`
+	// WorkDirQuotaMB is the size above which a task's working directory
+	// causes the task to be aborted.
+	WorkDirQuotaMB int `bson:"work_dir_quota_mb,omitempty" json:"work_dir_quota_mb,omitempty" mapstructure:"work_dir_quota_mb,omitempty"`
 }
 
 type DistroGroup []Distro
@@ -130,6 +130,15 @@ func TaskEventsForId(id string) db.Q {
 	return db.Query(filter)
 }
 
+// TaskPriorityEventsForIds finds the priority changes of the given tasks.
+func TaskPriorityEventsForIds(ids []string) db.Q {
+	filter := resourceTypeKeyIs(ResourceTypeTask)
+	filter[ResourceIdKey] = bson.M{"$in": ids}
+	filter[TypeKey] = TaskPriorityChanged
+
+	return db.Query(filter)
+}
+
 func MostRecentTaskEvents(id string, n int) db.Q {
 	return TaskEventsForId(id).Sort([]string{"-" + TimestampKey}).Limit(n)
 }
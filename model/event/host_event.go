@@ -17,6 +17,7 @@ func init() {
 	registry.AllowSubscription(ResourceTypeHost, EventHostExpirationWarningSent)
 	registry.AllowSubscription(ResourceTypeHost, EventHostProvisioned)
 	registry.AllowSubscription(ResourceTypeHost, EventHostProvisionFailed)
+	registry.AllowSubscription(ResourceTypeHost, EventHostQuarantined)
 }
 
 const (
@@ -41,6 +42,7 @@ const (
 	EventHostTeardown              = "HOST_TEARDOWN"
 	EventHostTerminatedExternally  = "HOST_TERMINATED_EXTERNALLY"
 	EventHostExpirationWarningSent = "HOST_EXPIRATION_WARNING_SENT"
+	EventHostQuarantined           = "HOST_QUARANTINED"
 )
 
 // implements EventData
@@ -109,6 +111,12 @@ func LogHostTerminatedExternally(hostId string) {
 	LogHostEvent(hostId, EventHostStatusChanged, HostEventData{NewStatus: EventHostTerminatedExternally})
 }
 
+// LogHostQuarantined is used when a host is taken out of service because
+// of its health score, or by an admin.
+func LogHostQuarantined(hostId, user, reason string) {
+	LogHostEvent(hostId, EventHostQuarantined, HostEventData{User: user, Logs: reason})
+}
+
 func LogHostStatusChanged(hostId, oldStatus, newStatus, user string, logs string) {
 	if oldStatus == newStatus {
 		return
@@ -30,6 +30,11 @@ const (
 	TaskPriorityChanged         = "TASK_PRIORITY_CHANGED"
 	TaskJiraAlertCreated        = "TASK_JIRA_ALERT_CREATED"
 	TaskDepdendenciesOverridden = "TASK_DEPENDENCIES_OVERRIDDEN"
+	TaskDebugSessionOpened      = "TASK_DEBUG_SESSION_OPENED"
+	TaskDebugSessionStarted     = "TASK_DEBUG_SESSION_STARTED"
+	TaskDebugSessionClosed      = "TASK_DEBUG_SESSION_CLOSED"
+	TaskPaused                  = "TASK_PAUSED"
+	TaskResumed                 = "TASK_RESUMED"
 )
 
 // implements Data
@@ -40,8 +45,12 @@ type TaskEventData struct {
 	Status    string `bson:"s,omitempty" json:"status,omitempty"`
 	JiraIssue string `bson:"jira,omitempty" json:"jira,omitempty"`
 
-	Timestamp time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
-	Priority  int64     `bson:"pri,omitempty" json:"priority,omitempty"`
+	DebugSession string `bson:"debug_session,omitempty" json:"debug_session,omitempty"`
+	Reason       string `bson:"reason,omitempty" json:"reason,omitempty"`
+
+	Timestamp   time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
+	PausedUntil time.Time `bson:"paused_until,omitempty" json:"paused_until,omitempty"`
+	Priority    int64     `bson:"pri,omitempty" json:"priority,omitempty"`
 }
 
 func logTaskEvent(taskId string, eventType string, eventData TaskEventData) {
@@ -154,3 +163,35 @@ func LogTaskDependenciesOverridden(taskId string, execution int, userID string)
 	logTaskEvent(taskId, TaskDepdendenciesOverridden,
 		TaskEventData{Execution: execution, UserId: userID})
 }
+
+// LogTaskPaused records that the agent is holding the task after a failed
+// command until the given time.
+func LogTaskPaused(taskId string, execution int, hostId, reason string, until time.Time) {
+	logTaskEvent(taskId, TaskPaused, TaskEventData{
+		Execution:   execution,
+		HostId:      hostId,
+		Reason:      reason,
+		PausedUntil: until,
+	})
+}
+
+// LogTaskResumed records that a paused task was resumed, by a user or when
+// its pause ran out.
+func LogTaskResumed(taskId string, execution int, userId string) {
+	logTaskEvent(taskId, TaskResumed, TaskEventData{
+		Execution: execution,
+		UserId:    userId,
+	})
+}
+
+// LogTaskDebugSession records a change to a debug session opened into the
+// task; the reason is only used when the session closes.
+func LogTaskDebugSession(taskId string, execution int, eventType, hostId, userId, sessionId, reason string) {
+	logTaskEvent(taskId, eventType, TaskEventData{
+		Execution:    execution,
+		HostId:       hostId,
+		UserId:       userId,
+		DebugSession: sessionId,
+		Reason:       reason,
+	})
+}
@@ -10,6 +10,7 @@ import (
 func init() {
 	registry.AddType(ResourceTypeVersion, versionEventDataFactory)
 	registry.AllowSubscription(ResourceTypeVersion, VersionStateChange)
+	registry.AllowSubscription(ResourceTypeVersion, VersionPerfChangePoint)
 }
 
 func versionEventDataFactory() interface{} {
@@ -17,12 +18,14 @@ func versionEventDataFactory() interface{} {
 }
 
 const (
-	ResourceTypeVersion = "VERSION"
-	VersionStateChange  = "STATE_CHANGE"
+	ResourceTypeVersion    = "VERSION"
+	VersionStateChange     = "STATE_CHANGE"
+	VersionPerfChangePoint = "PERF_CHANGE_POINT"
 )
 
 type VersionEventData struct {
-	Status string `bson:"status,omitempty" json:"status,omitempty"`
+	Status         string   `bson:"status,omitempty" json:"status,omitempty"`
+	ChangePointIDs []string `bson:"change_point_ids,omitempty" json:"change_point_ids,omitempty"`
 }
 
 func LogVersionStateChangeEvent(id, newStatus string) {
@@ -45,3 +48,26 @@ func LogVersionStateChangeEvent(id, newStatus string) {
 		}))
 	}
 }
+
+// LogVersionPerfChangePointEvent records that change points were detected
+// in the performance data of the version's tasks.
+func LogVersionPerfChangePointEvent(id string, changePointIDs []string) {
+	event := EventLogEntry{
+		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
+		ResourceId:   id,
+		ResourceType: ResourceTypeVersion,
+		EventType:    VersionPerfChangePoint,
+		Data: &VersionEventData{
+			ChangePointIDs: changePointIDs,
+		},
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeVersion,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+	}
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// fairShareUsageHalfLives is how many half-lives of a distro's history count
+// towards projects' usage. Older use would weigh less than a sixteenth.
+const fairShareUsageHalfLives = 4
+
+// ProjectShare is a project's share of a distro under the fair-share task
+// prioritizer.
+type ProjectShare struct {
+	Project string `json:"project"`
+	// Weight is the project's configured relative share of the distro.
+	Weight float64 `json:"weight"`
+	// Share is the fraction of the distro that the project is entitled to
+	// among the projects with queued tasks.
+	Share float64 `json:"share"`
+	// Usage is the project's recent use of the distro in host-seconds,
+	// decayed by how long ago the project used them.
+	Usage float64 `json:"usage"`
+	// UsageFraction is the project's fraction of all recent use of the
+	// distro.
+	UsageFraction float64 `json:"usage_fraction"`
+	// Deficit is how far the project's use falls short of its share. It is
+	// negative if the project has used more than its share.
+	Deficit     float64 `json:"deficit"`
+	QueuedTasks int     `json:"queued_tasks"`
+}
+
+// DecayedUsage sums the time that tasks ran by project, in seconds, halving
+// the weight of a task's time for every half-life since it finished.
+func DecayedUsage(tasks []task.Task, now time.Time, halfLife time.Duration) map[string]float64 {
+	usage := map[string]float64{}
+	for _, t := range tasks {
+		age := now.Sub(t.FinishTime)
+		if age < 0 {
+			age = 0
+		}
+		usage[t.Project] += t.TimeTaken.Seconds() * math.Pow(0.5, float64(age)/float64(halfLife))
+	}
+	return usage
+}
+
+// FindDecayedUsage returns the projects' decayed recent use of a distro, in
+// host-seconds.
+func FindDecayedUsage(d *distro.Distro, now time.Time) (map[string]float64, error) {
+	halfLife := d.FairShare.UsageHalfLife()
+	tasks, err := task.Find(task.ByFinishedOnDistroSince(d.Id, now.Add(-fairShareUsageHalfLives*halfLife)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding recently finished tasks on distro '%s'", d.Id)
+	}
+	return DecayedUsage(tasks, now, halfLife), nil
+}
+
+// ComputeProjectShares returns the shares of the projects that have queued
+// tasks on a distro or have recently used it, ordered from the largest
+// deficit to the smallest.
+func ComputeProjectShares(d *distro.Distro, queued map[string]int, usage map[string]float64) []ProjectShare {
+	var totalWeight, totalUsage float64
+	for project, n := range queued {
+		if n > 0 {
+			totalWeight += d.FairShare.Weight(project)
+		}
+	}
+	for _, u := range usage {
+		totalUsage += u
+	}
+
+	projects := map[string]bool{}
+	for project := range queued {
+		projects[project] = true
+	}
+	for project := range usage {
+		projects[project] = true
+	}
+
+	shares := make([]ProjectShare, 0, len(projects))
+	for project := range projects {
+		s := ProjectShare{
+			Project:     project,
+			Weight:      d.FairShare.Weight(project),
+			Usage:       usage[project],
+			QueuedTasks: queued[project],
+		}
+		if s.QueuedTasks > 0 && totalWeight > 0 {
+			s.Share = s.Weight / totalWeight
+		}
+		if totalUsage > 0 {
+			s.UsageFraction = s.Usage / totalUsage
+		}
+		s.Deficit = s.Share - s.UsageFraction
+		shares = append(shares, s)
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Deficit != shares[j].Deficit {
+			return shares[i].Deficit > shares[j].Deficit
+		}
+		return shares[i].Project < shares[j].Project
+	})
+	return shares
+}
+
+// FindProjectShares returns the current shares of the projects that have
+// tasks in a distro's queue or have recently used it.
+func FindProjectShares(distroID string) ([]ProjectShare, error) {
+	d, err := distro.FindOne(distro.ById(distroID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding distro '%s'", distroID)
+	}
+
+	queue, err := LoadTaskQueue(distroID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem loading task queue for distro '%s'", distroID)
+	}
+	queued := map[string]int{}
+	if queue != nil {
+		for _, item := range queue.Queue {
+			queued[item.Project]++
+		}
+	}
+
+	usage, err := FindDecayedUsage(&d, time.Now())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return ComputeProjectShares(&d, queued, usage), nil
+}
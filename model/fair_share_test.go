@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecayedUsage(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	usage := DecayedUsage([]task.Task{
+		{Project: "a", TimeTaken: time.Hour, FinishTime: now},
+		{Project: "a", TimeTaken: time.Hour, FinishTime: now.Add(-time.Hour)},
+		{Project: "b", TimeTaken: time.Hour, FinishTime: now.Add(-2 * time.Hour)},
+		{Project: "c", TimeTaken: time.Hour, FinishTime: now.Add(time.Minute)},
+	}, now, time.Hour)
+
+	assert.InDelta(3600+1800, usage["a"], 0.001)
+	assert.InDelta(900, usage["b"], 0.001)
+	assert.InDelta(3600, usage["c"], 0.001)
+}
+
+func TestComputeProjectShares(t *testing.T) {
+	assert := assert.New(t)
+
+	d := &distro.Distro{
+		Id: "d",
+		FairShare: distro.FairShareSettings{
+			Weights: []distro.ProjectWeight{{Project: "a", Weight: 3}},
+		},
+	}
+	shares := ComputeProjectShares(d,
+		map[string]int{"a": 4, "b": 2},
+		map[string]float64{"b": 100, "c": 100},
+	)
+
+	assert.Len(shares, 3)
+	assert.Equal("a", shares[0].Project)
+	assert.Equal(3.0, shares[0].Weight)
+	assert.Equal(0.75, shares[0].Share)
+	assert.Equal(0.75, shares[0].Deficit)
+	assert.Equal(4, shares[0].QueuedTasks)
+
+	assert.Equal("b", shares[1].Project)
+	assert.Equal(0.25, shares[1].Share)
+	assert.Equal(0.5, shares[1].UsageFraction)
+	assert.Equal(-0.25, shares[1].Deficit)
+
+	// projects without queued tasks have no share
+	assert.Equal("c", shares[2].Project)
+	assert.Zero(shares[2].Share)
+	assert.Equal(-0.5, shares[2].Deficit)
+
+	assert.Empty(ComputeProjectShares(d, nil, nil))
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+const (
+	// GithubCheckRunCaller is the user that restarts requested from Github
+	// check runs record in task events.
+	GithubCheckRunCaller = "github-checks"
+
+	// GithubCheckRunRerunFailed identifies the check run action that
+	// restarts the check run's failed tasks.
+	GithubCheckRunRerunFailed = "rerun_failed"
+
+	githubCheckRunBuildPrefix       = "build:"
+	githubCheckRunDisplayTaskPrefix = "display-task:"
+)
+
+// GithubCheckRunTasks groups a build's tasks by the check run that reports
+// them. The build variant's check run reports the tasks that are not part of
+// a display task, and each display task has a check run of its own that
+// reports its execution tasks.
+type GithubCheckRunTasks struct {
+	Variant        []task.Task
+	DisplayTasks   []task.Task
+	ExecutionTasks map[string][]task.Task
+}
+
+// FindGithubCheckRunTasks finds a build's tasks, grouped by check run.
+func FindGithubCheckRunTasks(buildID string) (*GithubCheckRunTasks, error) {
+	tasks, err := task.Find(task.ByBuildId(buildID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding tasks of build '%s'", buildID)
+	}
+	return groupGithubCheckRunTasks(tasks), nil
+}
+
+func groupGithubCheckRunTasks(tasks []task.Task) *GithubCheckRunTasks {
+	displayTaskOf := map[string]string{}
+	for _, t := range tasks {
+		if !t.DisplayOnly {
+			continue
+		}
+		for _, et := range t.ExecutionTasks {
+			displayTaskOf[et] = t.Id
+		}
+	}
+
+	grouped := &GithubCheckRunTasks{ExecutionTasks: map[string][]task.Task{}}
+	for _, t := range tasks {
+		if dt, ok := displayTaskOf[t.Id]; ok {
+			grouped.ExecutionTasks[dt] = append(grouped.ExecutionTasks[dt], t)
+			continue
+		}
+		grouped.Variant = append(grouped.Variant, t)
+		if t.DisplayOnly {
+			grouped.DisplayTasks = append(grouped.DisplayTasks, t)
+		}
+	}
+
+	return grouped
+}
+
+// GithubCheckRunExternalID returns the external ID of the check run of a
+// build variant or, if displayTaskID is not empty, of a display task.
+func GithubCheckRunExternalID(buildID, displayTaskID string) string {
+	if displayTaskID != "" {
+		return githubCheckRunDisplayTaskPrefix + displayTaskID
+	}
+	return githubCheckRunBuildPrefix + buildID
+}
+
+// GithubCheckRunProject returns the identifier of the project of the build
+// or display task that the check run with the given external ID reports.
+func GithubCheckRunProject(externalID string) (string, error) {
+	switch {
+	case strings.HasPrefix(externalID, githubCheckRunBuildPrefix):
+		buildID := strings.TrimPrefix(externalID, githubCheckRunBuildPrefix)
+		b, err := build.FindOne(build.ById(buildID))
+		if err != nil {
+			return "", errors.Wrapf(err, "problem finding build '%s'", buildID)
+		}
+		if b == nil {
+			return "", errors.Errorf("build '%s' not found", buildID)
+		}
+		return b.Project, nil
+
+	case strings.HasPrefix(externalID, githubCheckRunDisplayTaskPrefix):
+		taskID := strings.TrimPrefix(externalID, githubCheckRunDisplayTaskPrefix)
+		t, err := task.FindOneId(taskID)
+		if err != nil {
+			return "", errors.Wrapf(err, "problem finding display task '%s'", taskID)
+		}
+		if t == nil {
+			return "", errors.Errorf("display task '%s' not found", taskID)
+		}
+		return t.Project, nil
+	}
+
+	return "", errors.Errorf("'%s' is not a check run external ID", externalID)
+}
+
+// RestartFailedGithubCheckTasks restarts the failed tasks that the check run
+// with the given external ID reports.
+func RestartFailedGithubCheckTasks(externalID, caller string) error {
+	switch {
+	case strings.HasPrefix(externalID, githubCheckRunBuildPrefix):
+		buildID := strings.TrimPrefix(externalID, githubCheckRunBuildPrefix)
+		grouped, err := FindGithubCheckRunTasks(buildID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		failed := []string{}
+		for _, t := range grouped.Variant {
+			if t.Status == evergreen.TaskFailed {
+				failed = append(failed, t.Id)
+			}
+		}
+		if len(failed) == 0 {
+			return nil
+		}
+		return errors.Wrapf(RestartBuild(buildID, failed, false, caller), "problem restarting failed tasks of build '%s'", buildID)
+
+	case strings.HasPrefix(externalID, githubCheckRunDisplayTaskPrefix):
+		taskID := strings.TrimPrefix(externalID, githubCheckRunDisplayTaskPrefix)
+		t, err := task.FindOneId(taskID)
+		if err != nil {
+			return errors.Wrapf(err, "problem finding display task '%s'", taskID)
+		}
+		if t == nil || !t.DisplayOnly {
+			return errors.Errorf("display task '%s' not found", taskID)
+		}
+		if t.Status != evergreen.TaskFailed {
+			return nil
+		}
+		// execution tasks are only restarted with their display task
+		return errors.Wrapf(RestartBuild(t.BuildId, []string{t.Id}, false, caller), "problem restarting display task '%s'", taskID)
+	}
+
+	return errors.Errorf("'%s' is not a check run external ID", externalID)
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupGithubCheckRunTasks(t *testing.T) {
+	assert := assert.New(t)
+
+	grouped := groupGithubCheckRunTasks([]task.Task{
+		{Id: "t1"},
+		{Id: "exec1"},
+		{Id: "display", DisplayOnly: true, ExecutionTasks: []string{"exec1", "exec2"}},
+		{Id: "exec2"},
+	})
+
+	ids := func(tasks []task.Task) []string {
+		out := []string{}
+		for _, t := range tasks {
+			out = append(out, t.Id)
+		}
+		return out
+	}
+	assert.Equal([]string{"t1", "display"}, ids(grouped.Variant))
+	assert.Equal([]string{"display"}, ids(grouped.DisplayTasks))
+	assert.Len(grouped.ExecutionTasks, 1)
+	assert.Equal([]string{"exec1", "exec2"}, ids(grouped.ExecutionTasks["display"]))
+}
+
+func TestRestartFailedGithubCheckTasks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(task.Collection, task.OldCollection, build.Collection))
+
+	b := build.Build{Id: "b", Tasks: []build.TaskCache{{Id: "failed"}, {Id: "passed"}, {Id: "display"}}}
+	require.NoError(b.Insert())
+	dispatched := time.Now().Add(-time.Hour)
+	for _, tsk := range []task.Task{
+		{Id: "failed", BuildId: "b", Status: evergreen.TaskFailed, DispatchTime: dispatched},
+		{Id: "passed", BuildId: "b", Status: evergreen.TaskSucceeded, DispatchTime: dispatched},
+		{Id: "display", BuildId: "b", Status: evergreen.TaskFailed, DisplayOnly: true, ExecutionTasks: []string{"exec"}, DispatchTime: dispatched},
+		{Id: "exec", BuildId: "b", Status: evergreen.TaskFailed, DispatchTime: dispatched},
+	} {
+		require.NoError(tsk.Insert())
+	}
+
+	assert.Error(RestartFailedGithubCheckTasks("b", GithubCheckRunCaller))
+	assert.Error(RestartFailedGithubCheckTasks(GithubCheckRunExternalID("", "failed"), GithubCheckRunCaller))
+
+	require.NoError(RestartFailedGithubCheckTasks(GithubCheckRunExternalID("b", ""), GithubCheckRunCaller))
+	for id, status := range map[string]string{
+		"failed":  evergreen.TaskUndispatched,
+		"passed":  evergreen.TaskSucceeded,
+		"display": evergreen.TaskUndispatched,
+		"exec":    evergreen.TaskUndispatched,
+	} {
+		dbTask, err := task.FindOneId(id)
+		require.NoError(err)
+		require.NotNil(dbTask)
+		assert.Equal(status, dbTask.Status, id)
+	}
+}
+
+func TestGithubCheckRunProject(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(task.Collection, build.Collection))
+
+	require.NoError((&build.Build{Id: "b", Project: "proj"}).Insert())
+	require.NoError((&task.Task{Id: "display", BuildId: "b", Project: "proj", DisplayOnly: true}).Insert())
+
+	project, err := GithubCheckRunProject(GithubCheckRunExternalID("b", ""))
+	require.NoError(err)
+	assert.Equal("proj", project)
+	project, err = GithubCheckRunProject(GithubCheckRunExternalID("b", "display"))
+	require.NoError(err)
+	assert.Equal("proj", project)
+
+	_, err = GithubCheckRunProject(GithubCheckRunExternalID("missing", ""))
+	assert.Error(err)
+	_, err = GithubCheckRunProject("b")
+	assert.Error(err)
+}
@@ -0,0 +1,99 @@
+package host
+
+import (
+	"time"
+
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ContainerImageUse records when a container image on a parent was last
+// used to build the image or to start a container.
+type ContainerImageUse struct {
+	Image    string    `bson:"image" json:"image"`
+	LastUsed time.Time `bson:"last_used" json:"last_used"`
+}
+
+var (
+	ContainerImageUseImageKey    = bsonutil.MustHaveTag(ContainerImageUse{}, "Image")
+	ContainerImageUseLastUsedKey = bsonutil.MustHaveTag(ContainerImageUse{}, "LastUsed")
+)
+
+// ContainerImageLastUsed returns when the parent last used the image, and
+// whether its use has been recorded.
+func (h *Host) ContainerImageLastUsed(image string) (time.Time, bool) {
+	for _, use := range h.ContainerImageUses {
+		if use.Image == image {
+			return use.LastUsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SetContainerImageUsed records that the parent used the image at the
+// given time.
+func (h *Host) SetContainerImageUsed(image string, t time.Time) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey: h.Id,
+			bsonutil.GetDottedKeyName(ContainerImageUsesKey, ContainerImageUseImageKey): image,
+		},
+		bson.M{
+			"$set": bson.M{
+				bsonutil.GetDottedKeyName(ContainerImageUsesKey, "$", ContainerImageUseLastUsedKey): t,
+			},
+		},
+	)
+	if err == mgo.ErrNotFound {
+		err = UpdateOne(
+			bson.M{IdKey: h.Id},
+			bson.M{"$push": bson.M{ContainerImageUsesKey: ContainerImageUse{Image: image, LastUsed: t}}},
+		)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem recording use of image '%s' on host '%s'", image, h.Id)
+	}
+
+	for i := range h.ContainerImageUses {
+		if h.ContainerImageUses[i].Image == image {
+			h.ContainerImageUses[i].LastUsed = t
+			return nil
+		}
+	}
+	h.ContainerImageUses = append(h.ContainerImageUses, ContainerImageUse{Image: image, LastUsed: t})
+	return nil
+}
+
+// RemoveContainerImage records that the image is no longer on the parent,
+// so that it is built again before a container uses it.
+func (h *Host) RemoveContainerImage(image string) error {
+	images := map[string]bool{}
+	for k, v := range h.ContainerImages {
+		if k != image {
+			images[k] = v
+		}
+	}
+
+	err := UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{
+			"$set":  bson.M{ContainerImagesKey: images},
+			"$pull": bson.M{ContainerImageUsesKey: bson.M{ContainerImageUseImageKey: image}},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "problem removing image '%s' from host '%s'", image, h.Id)
+	}
+
+	h.ContainerImages = images
+	uses := []ContainerImageUse{}
+	for _, use := range h.ContainerImageUses {
+		if use.Image != image {
+			uses = append(uses, use)
+		}
+	}
+	h.ContainerImageUses = uses
+	return nil
+}
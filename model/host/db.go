@@ -52,6 +52,7 @@ var (
 	AgentRevisionKey             = bsonutil.MustHaveTag(Host{}, "AgentRevision")
 	AgentDeployAttemptKey        = bsonutil.MustHaveTag(Host{}, "AgentDeployAttempt")
 	NeedsNewAgentKey             = bsonutil.MustHaveTag(Host{}, "NeedsNewAgent")
+	HealthKey                    = bsonutil.MustHaveTag(Host{}, "Health")
 	StartedByKey                 = bsonutil.MustHaveTag(Host{}, "StartedBy")
 	InstanceTypeKey              = bsonutil.MustHaveTag(Host{}, "InstanceType")
 	VolumeSizeKey                = bsonutil.MustHaveTag(Host{}, "VolumeTotalSize")
@@ -69,6 +70,7 @@ var (
 	HasContainersKey             = bsonutil.MustHaveTag(Host{}, "HasContainers")
 	ParentIDKey                  = bsonutil.MustHaveTag(Host{}, "ParentID")
 	ContainerImagesKey           = bsonutil.MustHaveTag(Host{}, "ContainerImages")
+	ContainerImageUsesKey        = bsonutil.MustHaveTag(Host{}, "ContainerImageUses")
 	ContainerBuildAttempt        = bsonutil.MustHaveTag(Host{}, "ContainerBuildAttempt")
 	LastContainerFinishTimeKey   = bsonutil.MustHaveTag(Host{}, "LastContainerFinishTime")
 	SpawnOptionsKey              = bsonutil.MustHaveTag(Host{}, "SpawnOptions")
@@ -0,0 +1,160 @@
+package host
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// HealthStats are the outcomes of the tasks that a host ran recently, and
+// the score computed from them. A higher score means a less healthy host.
+type HealthStats struct {
+	Tasks             int `bson:"tasks" json:"tasks"`
+	SystemFailures    int `bson:"system_failures" json:"system_failures"`
+	SetupFailures     int `bson:"setup_failures" json:"setup_failures"`
+	HeartbeatTimeouts int `bson:"heartbeat_timeouts" json:"heartbeat_timeouts"`
+	TestFailures      int `bson:"test_failures" json:"test_failures"`
+	// DistroTestFailureRate is the fraction of the tasks on the host's
+	// distro that failed for reasons other than the host.
+	DistroTestFailureRate float64   `bson:"distro_test_failure_rate" json:"distro_test_failure_rate"`
+	Score                 float64   `bson:"score" json:"score"`
+	UpdatedAt             time.Time `bson:"updated_at" json:"updated_at"`
+	// RestoredAt is when the host was last restored from quarantine. Tasks
+	// that finished before then do not count towards its score.
+	RestoredAt time.Time `bson:"restored_at,omitempty" json:"restored_at,omitempty"`
+}
+
+var (
+	HealthScoreKey      = bsonutil.MustHaveTag(HealthStats{}, "Score")
+	HealthUpdatedAtKey  = bsonutil.MustHaveTag(HealthStats{}, "UpdatedAt")
+	HealthRestoredAtKey = bsonutil.MustHaveTag(HealthStats{}, "RestoredAt")
+)
+
+// IsHostFailure reports whether a finished task failed because of the host
+// it ran on, rather than because of its own commands.
+func IsHostFailure(t *task.Task) bool {
+	return t.Status == evergreen.TaskFailed && (isHeartbeatTimeout(t) ||
+		t.Details.Type == evergreen.CommandTypeSystem || t.Details.Type == evergreen.CommandTypeSetup)
+}
+
+// isHeartbeatTimeout reports whether a task failed because its agent
+// stopped sending heartbeats. The task monitor records these failures
+// without a command type.
+func isHeartbeatTimeout(t *task.Task) bool {
+	return t.Details.TimedOut && t.Details.Description == task.AgentHeartbeat
+}
+
+// AddTask counts the outcome of a finished task.
+func (s *HealthStats) AddTask(t *task.Task) {
+	s.Tasks++
+	if t.Status != evergreen.TaskFailed {
+		return
+	}
+	switch {
+	case isHeartbeatTimeout(t):
+		s.HeartbeatTimeouts++
+	case t.Details.Type == evergreen.CommandTypeSystem:
+		s.SystemFailures++
+	case t.Details.Type == evergreen.CommandTypeSetup:
+		s.SetupFailures++
+	default:
+		s.TestFailures++
+	}
+}
+
+// ComputeScore sets the score to the fraction of tasks that failed
+// because of the host, plus half of the amount by which the host's rate of
+// other failures exceeds its distro's.
+func (s *HealthStats) ComputeScore() {
+	if s.Tasks == 0 {
+		s.Score = 0
+		return
+	}
+
+	tasks := float64(s.Tasks)
+	score := float64(s.SystemFailures+s.SetupFailures+s.HeartbeatTimeouts) / tasks
+	if excess := float64(s.TestFailures)/tasks - s.DistroTestFailureRate; excess > 0 {
+		score += excess / 2
+	}
+	s.Score = score
+}
+
+// ExceedsThreshold reports whether the stats are based on enough tasks,
+// and have a high enough score, for the host to be quarantined.
+func (s *HealthStats) ExceedsThreshold(conf evergreen.HostHealthConfig) bool {
+	return s.Tasks >= conf.MinTasks && s.Score >= conf.Threshold
+}
+
+// Reason describes the stats, for the logs of a quarantine.
+func (s *HealthStats) Reason() string {
+	return fmt.Sprintf("health score %.2f from %d tasks (%d system failures, %d setup failures, %d heartbeat timeouts, %d other failures against a distro rate of %.0f%%)",
+		s.Score, s.Tasks, s.SystemFailures, s.SetupFailures, s.HeartbeatTimeouts, s.TestFailures, 100*s.DistroTestFailureRate)
+}
+
+// SetHealth records the host's health stats, keeping the time at which it
+// was last restored.
+func (h *Host) SetHealth(stats HealthStats) error {
+	stats.RestoredAt = h.Health.RestoredAt
+	if err := UpdateOne(bson.M{IdKey: h.Id}, bson.M{"$set": bson.M{HealthKey: stats}}); err != nil {
+		return errors.Wrapf(err, "problem setting health of host %s", h.Id)
+	}
+	h.Health = stats
+	return nil
+}
+
+// Quarantine stops tasks from being dispatched to the host, and logs an
+// event to which users may subscribe.
+func (h *Host) Quarantine(user, reason string) error {
+	if h.Status == evergreen.HostQuarantined {
+		return nil
+	}
+	if err := h.SetQuarantined(user, reason); err != nil {
+		return errors.Wrapf(err, "problem quarantining host %s", h.Id)
+	}
+	event.LogHostQuarantined(h.Id, user, reason)
+	return nil
+}
+
+// Restore returns a quarantined host to service. Tasks that the host ran
+// before it was restored no longer count towards its health score.
+func (h *Host) Restore(user string) error {
+	if h.Status != evergreen.HostQuarantined {
+		return errors.Errorf("host %s is %s, not %s", h.Id, h.Status, evergreen.HostQuarantined)
+	}
+	if err := h.SetStatus(evergreen.HostRunning, user, "restored from quarantine"); err != nil {
+		return errors.Wrapf(err, "problem restoring host %s", h.Id)
+	}
+
+	now := time.Now()
+	err := UpdateOne(bson.M{IdKey: h.Id}, bson.M{"$set": bson.M{
+		bsonutil.GetDottedKeyName(HealthKey, HealthScoreKey):      0.0,
+		bsonutil.GetDottedKeyName(HealthKey, HealthRestoredAtKey): now,
+	}})
+	if err != nil {
+		return errors.Wrapf(err, "problem resetting health of host %s", h.Id)
+	}
+	h.Health.Score = 0
+	h.Health.RestoredAt = now
+	return nil
+}
+
+// WithHealthScores produces a query that returns the hosts, optionally of
+// a single distro, that have health scores, from least to most healthy.
+func WithHealthScores(distroID string) db.Q {
+	q := bson.M{
+		StatusKey: bson.M{"$ne": evergreen.HostTerminated},
+		bsonutil.GetDottedKeyName(HealthKey, HealthUpdatedAtKey): bson.M{"$exists": true},
+	}
+	if distroID != "" {
+		q[bsonutil.GetDottedKeyName(DistroKey, distro.IdKey)] = distroID
+	}
+	return db.Query(q).Sort([]string{"-" + bsonutil.GetDottedKeyName(HealthKey, HealthScoreKey)})
+}
@@ -0,0 +1,105 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthStatsScore(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := HealthStats{DistroTestFailureRate: 0.1}
+	stats.ComputeScore()
+	assert.Zero(stats.Score)
+
+	for _, tsk := range []task.Task{
+		{Status: evergreen.TaskSucceeded},
+		{Status: evergreen.TaskSucceeded},
+		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeTest}},
+		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeTest}},
+		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSystem}},
+		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSetup}},
+		// the task monitor records heartbeat timeouts without a type
+		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Status: evergreen.TaskFailed, TimedOut: true, Description: task.AgentHeartbeat}},
+		{Status: evergreen.TaskSucceeded},
+		{Status: evergreen.TaskSucceeded},
+		{Status: evergreen.TaskSucceeded},
+	} {
+		tsk := tsk
+		stats.AddTask(&tsk)
+	}
+	assert.Equal(10, stats.Tasks)
+	assert.Equal(1, stats.SystemFailures)
+	assert.Equal(1, stats.SetupFailures)
+	assert.Equal(1, stats.HeartbeatTimeouts)
+	assert.Equal(2, stats.TestFailures)
+
+	// 3 of 10 tasks failed because of the host, and the host's other
+	// failures exceed the distro's rate by 10%
+	stats.ComputeScore()
+	assert.InDelta(0.35, stats.Score, 0.0001)
+	assert.Contains(stats.Reason(), "score 0.35 from 10 tasks")
+
+	conf := evergreen.HostHealthConfig{Threshold: 0.3, MinTasks: 10}
+	assert.True(stats.ExceedsThreshold(conf))
+	conf.MinTasks = 11
+	assert.False(stats.ExceedsThreshold(conf))
+	conf = evergreen.HostHealthConfig{Threshold: 0.5, MinTasks: 1}
+	assert.False(stats.ExceedsThreshold(conf))
+
+	// failing no more often than the distro does not count against a host
+	stats = HealthStats{DistroTestFailureRate: 0.5}
+	stats.AddTask(&task.Task{Status: evergreen.TaskFailed})
+	stats.AddTask(&task.Task{Status: evergreen.TaskSucceeded})
+	stats.ComputeScore()
+	assert.Zero(stats.Score)
+
+	assert.True(IsHostFailure(&task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSetup}}))
+	assert.True(IsHostFailure(&task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{TimedOut: true, Description: task.AgentHeartbeat}}))
+	assert.False(IsHostFailure(&task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeTest}}))
+	assert.False(IsHostFailure(&task.Task{Status: evergreen.TaskSucceeded, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSystem}}))
+}
+
+func TestHostQuarantineAndRestore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection, event.AllLogCollection))
+
+	h := &Host{Id: "h1", Status: evergreen.HostRunning, Distro: distro.Distro{Id: "d1"}}
+	require.NoError(h.Insert())
+	require.NoError(h.SetHealth(HealthStats{Tasks: 5, Score: 0.8, UpdatedAt: time.Now()}))
+
+	hosts, err := Find(WithHealthScores("d1"))
+	require.NoError(err)
+	require.Len(hosts, 1)
+	assert.Equal(0.8, hosts[0].Health.Score)
+	hosts, err = Find(WithHealthScores("d2"))
+	require.NoError(err)
+	assert.Empty(hosts)
+
+	assert.Error(h.Restore("admin"))
+	require.NoError(h.Quarantine("admin", "bad disk"))
+	dbHost, err := FindOneId("h1")
+	require.NoError(err)
+	assert.Equal(evergreen.HostQuarantined, dbHost.Status)
+
+	require.NoError(h.Restore("admin"))
+	dbHost, err = FindOneId("h1")
+	require.NoError(err)
+	assert.Equal(evergreen.HostRunning, dbHost.Status)
+	assert.Zero(dbHost.Health.Score)
+	assert.False(dbHost.Health.RestoredAt.IsZero())
+
+	// later scores keep the time of the restore
+	require.NoError(dbHost.SetHealth(HealthStats{Tasks: 1, UpdatedAt: time.Now()}))
+	assert.False(dbHost.Health.RestoredAt.IsZero())
+}
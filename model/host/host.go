@@ -79,6 +79,9 @@ type Host struct {
 	NeedsNewAgent      bool   `bson:"needs_agent" json:"needs_agent"`
 	AgentDeployAttempt int    `bson:"agent_deploy_attempt" json:"agent_deploy_attempt"`
 
+	// Health scores the host by the outcomes of the tasks it ran recently
+	Health HealthStats `bson:"health,omitempty" json:"health,omitempty"`
+
 	// for ec2 dynamic hosts, the instance type requested
 	InstanceType string `bson:"instance_type" json:"instance_type,omitempty"`
 	// for ec2 dynamic hosts, the total size of the volumes requested, in GiB
@@ -100,6 +103,8 @@ type Host struct {
 	HasContainers bool `bson:"has_containers,omitempty" json:"has_containers,omitempty"`
 	// stores URLs of container images already downloaded on a parent
 	ContainerImages map[string]bool `bson:"container_images,omitempty" json:"container_images,omitempty"`
+	// stores when each container image on a parent was last used
+	ContainerImageUses []ContainerImageUse `bson:"container_image_uses,omitempty" json:"container_image_uses,omitempty"`
 	// stores the ID of the host a container is on
 	ParentID string `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
 	// stores last expected finish time among all containers on the host
@@ -107,6 +112,9 @@ type Host struct {
 	// ContainerPoolSettings
 	ContainerPoolSettings *evergreen.ContainerPool `bson:"container_pool_settings,omitempty" json:"container_pool_settings,omitempty"`
 	ContainerBuildAttempt int                      `bson:"container_build_attempt" json:"container_build_attempt"`
+	// ContainerResources are the CPUs and memory that a container is
+	// limited to, and that it uses of its parent's capacity
+	ContainerResources *evergreen.ContainerResources `bson:"container_resources,omitempty" json:"container_resources,omitempty"`
 
 	// SpawnOptions holds data which the monitor uses to determine when to terminate hosts spawned by tasks.
 	SpawnOptions SpawnOptions `bson:"spawn_options,omitempty" json:"spawn_options,omitempty"`
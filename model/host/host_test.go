@@ -2565,3 +2565,48 @@ func TestRemoveStaleInitializing(t *testing.T) {
 	assert.Equal(4, numHosts)
 
 }
+
+func TestContainerImageUses(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection))
+
+	parent := &Host{
+		Id:              "parent",
+		Status:          evergreen.HostRunning,
+		HasContainers:   true,
+		ContainerImages: map[string]bool{"image1": true, "image2": true},
+	}
+	require.NoError(parent.Insert())
+
+	_, ok := parent.ContainerImageLastUsed("image1")
+	assert.False(ok)
+
+	first := time.Now().Add(-time.Hour).Round(time.Millisecond)
+	second := time.Now().Round(time.Millisecond)
+	require.NoError(parent.SetContainerImageUsed("image1", first))
+	require.NoError(parent.SetContainerImageUsed("image2", first))
+	require.NoError(parent.SetContainerImageUsed("image1", second))
+
+	lastUsed, ok := parent.ContainerImageLastUsed("image1")
+	assert.True(ok)
+	assert.True(second.Equal(lastUsed))
+
+	dbParent, err := FindOneId("parent")
+	require.NoError(err)
+	require.Len(dbParent.ContainerImageUses, 2)
+	lastUsed, ok = dbParent.ContainerImageLastUsed("image1")
+	assert.True(ok)
+	assert.True(second.Equal(lastUsed))
+
+	require.NoError(parent.RemoveContainerImage("image1"))
+	assert.Equal(map[string]bool{"image2": true}, parent.ContainerImages)
+	_, ok = parent.ContainerImageLastUsed("image1")
+	assert.False(ok)
+
+	dbParent, err = FindOneId("parent")
+	require.NoError(err)
+	assert.Equal(map[string]bool{"image2": true}, dbParent.ContainerImages)
+	require.Len(dbParent.ContainerImageUses, 1)
+	assert.Equal("image2", dbParent.ContainerImageUses[0].Image)
+}
@@ -830,11 +830,14 @@ func TryMarkPatchBuildFinished(b *build.Build, finishTime time.Time, updates *St
 func createOneTask(id string, buildVarTask BuildVariantTaskUnit, project *Project,
 	buildVariant *BuildVariant, b *build.Build, v *version.Version) *task.Task {
 	var distroID string
+	var secondaryDistros []string
 
 	if len(buildVarTask.Distros) > 0 {
 		distroID = buildVarTask.Distros[0]
+		secondaryDistros = buildVarTask.Distros[1:]
 	} else if len(buildVariant.RunOn) > 0 {
 		distroID = buildVariant.RunOn[0]
+		secondaryDistros = buildVariant.RunOn[1:]
 	} else {
 		grip.Warning(message.Fields{
 			"task_id":   id,
@@ -869,6 +872,18 @@ func createOneTask(id string, buildVarTask BuildVariantTaskUnit, project *Projec
 		Project:             project.Identifier,
 		Priority:            buildVarTask.Priority,
 		GenerateTask:        project.IsGenerateTask(buildVarTask.Name),
+		TraceParent:         v.TraceParent,
+	}
+	// tasks in a task group share hosts, so they only run on their distro
+	if !buildVarTask.IsGroup {
+		for _, d := range secondaryDistros {
+			if d != distroID && !util.StringSliceContains(t.SecondaryDistros, d) {
+				t.SecondaryDistros = append(t.SecondaryDistros, d)
+			}
+		}
+	}
+	if pt := project.FindProjectTask(buildVarTask.Name); pt != nil {
+		t.ResourceRequests = pt.ResourceRequests
 	}
 	if buildVarTask.IsGroup {
 		t.TaskGroup = buildVarTask.GroupName
@@ -914,6 +929,7 @@ func createDisplayTask(id string, displayName string, execTasks []string,
 		Activated:           b.Activated,
 		DispatchTime:        util.ZeroTime,
 		ScheduledTime:       util.ZeroTime,
+		TraceParent:         v.TraceParent,
 	}
 }
 
@@ -34,7 +34,8 @@ var (
 	PatchesKey         = bsonutil.MustHaveTag(Patch{}, "Patches")
 	ActivatedKey       = bsonutil.MustHaveTag(Patch{}, "Activated")
 	PatchedConfigKey   = bsonutil.MustHaveTag(Patch{}, "PatchedConfig")
-	githubPatchDataKey = bsonutil.MustHaveTag(Patch{}, "GithubPatchData")
+	GithubPatchDataKey = bsonutil.MustHaveTag(Patch{}, "GithubPatchData")
+	PauseOnFailureKey  = bsonutil.MustHaveTag(Patch{}, "PauseOnFailure")
 
 	// BSON fields for the module patch struct
 	ModulePatchNameKey    = bsonutil.MustHaveTag(ModulePatch{}, "ModuleName")
@@ -109,6 +110,15 @@ func ByUserProjectAndGitspec(user string, project string, gitspec string) db.Q {
 	})
 }
 
+// ByUserAndProject produces a query that returns patches by the given
+// patch author for the given project, newest first.
+func ByUserAndProject(user string, project string) db.Q {
+	return db.Query(bson.M{
+		AuthorKey:  user,
+		ProjectKey: project,
+	}).Sort([]string{"-" + CreateTimeKey})
+}
+
 // ByVersion produces a query that returns the patch for a given version.
 func ByVersion(version string) db.Q {
 	return db.Query(bson.M{VersionKey: version})
@@ -175,13 +185,24 @@ func PatchesByProject(projectId string, ts time.Time, limit int) db.Q {
 	}).Sort([]string{"-" + CreateTimeKey}).Limit(limit)
 }
 
+// ByProjectAndGithubPR finds the patches of a project's pull request, newest
+// first.
+func ByProjectAndGithubPR(project, owner, repo string, prNumber int) db.Q {
+	return db.Query(bson.M{
+		ProjectKey: project,
+		bsonutil.GetDottedKeyName(GithubPatchDataKey, githubPatchBaseOwnerKey): owner,
+		bsonutil.GetDottedKeyName(GithubPatchDataKey, githubPatchBaseRepoKey):  repo,
+		bsonutil.GetDottedKeyName(GithubPatchDataKey, githubPatchPRNumberKey):  prNumber,
+	}).Sort([]string{"-" + CreateTimeKey})
+}
+
 func ByGithubPRAndCreatedBefore(t time.Time, owner, repo string, prNumber int) db.Q {
 	return db.Query(bson.M{
 		CreateTimeKey: bson.M{
 			"$lt": t,
 		},
-		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchBaseOwnerKey): owner,
-		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchBaseRepoKey):  repo,
-		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchPRNumberKey):  prNumber,
+		bsonutil.GetDottedKeyName(GithubPatchDataKey, githubPatchBaseOwnerKey): owner,
+		bsonutil.GetDottedKeyName(GithubPatchDataKey, githubPatchBaseRepoKey):  repo,
+		bsonutil.GetDottedKeyName(GithubPatchDataKey, githubPatchPRNumberKey):  prNumber,
 	})
 }
@@ -46,6 +46,12 @@ type Patch struct {
 	PatchedConfig   string         `bson:"patched_config"`
 	Alias           string         `bson:"alias"`
 	GithubPatchData GithubPatch    `bson:"github_patch_data,omitempty"`
+	// PauseOnFailure, when set, decides whether the patch's failed tasks
+	// are held for inspection, whatever the project's configuration says.
+	PauseOnFailure *bool `bson:"pause_on_failure,omitempty"`
+	// TraceParent is the W3C trace context of the patch's trace, which
+	// continues into its version once the patch is finalized.
+	TraceParent string `bson:"trace_parent,omitempty"`
 }
 
 // GithubPatch stores patch data for patches create from GitHub pull requests
@@ -283,6 +289,20 @@ func (p *Patch) SetActivation(activated bool) error {
 	)
 }
 
+// SetPauseOnFailure sets whether the patch's failed tasks are held for
+// inspection.
+func (p *Patch) SetPauseOnFailure(pause bool) error {
+	p.PauseOnFailure = &pause
+	return UpdateOne(
+		bson.M{IdKey: p.Id},
+		bson.M{
+			"$set": bson.M{
+				PauseOnFailureKey: pause,
+			},
+		},
+	)
+}
+
 // UpdateModulePatch adds or updates a module within a patch.
 func (p *Patch) UpdateModulePatch(modulePatch ModulePatch) error {
 	// check that a patch for this module exists
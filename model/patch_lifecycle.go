@@ -222,6 +222,13 @@ func MakePatchedConfig(ctx context.Context, p *patch.Patch, remoteConfigPath, pr
 // Creates a version for this patch and links it.
 // Creates builds based on the version.
 func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, githubOauthToken string) (*version.Version, error) {
+	// the version's trace continues the patch's, if it has one
+	_, span := util.GetTracer().StartFromTraceParent(ctx, p.TraceParent, "finalize patch", map[string]string{
+		"patch":   p.Id.Hex(),
+		"project": p.Project,
+	})
+	defer span.End(nil)
+
 	// unmarshal the project YAML for storage
 	project := &Project{}
 	err := LoadProjectInto([]byte(p.PatchedConfig), p.Project, project)
@@ -259,6 +266,7 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		Branch:              projectRef.Branch,
 		RevisionOrderNumber: p.PatchNumber,
 		AuthorID:            p.Author,
+		TraceParent:         span.TraceParent(),
 	}
 
 	tasks := TaskVariantPairs{}
@@ -330,11 +338,12 @@ func CancelPatch(p *patch.Patch, caller string) error {
 // AbortPatchesWithGithubPatchData runs CancelPatch on patches created before
 // the given time, with the same pr number, and base repository. Tasks which
 // are abortable (see model/task.IsAbortable()) will be aborted, while
-// dispatched/running/completed tasks will not be affected
-func AbortPatchesWithGithubPatchData(createdBefore time.Time, owner, repo string, prNumber int) error {
+// dispatched/running/completed tasks will not be affected. It returns the
+// patches that were unfinished when they were canceled.
+func AbortPatchesWithGithubPatchData(createdBefore time.Time, owner, repo string, prNumber int) ([]patch.Patch, error) {
 	patches, err := patch.Find(patch.ByGithubPRAndCreatedBefore(createdBefore, owner, repo, prNumber))
 	if err != nil {
-		return errors.Wrap(err, "initial patch fetch failed")
+		return nil, errors.Wrap(err, "initial patch fetch failed")
 	}
 	grip.Info(message.Fields{
 		"source":         "github hook",
@@ -346,6 +355,7 @@ func AbortPatchesWithGithubPatchData(createdBefore time.Time, owner, repo string
 	})
 
 	catcher := grip.NewSimpleCatcher()
+	canceled := []patch.Patch{}
 	for i, _ := range patches {
 		if patches[i].Version != "" {
 			if err = CancelPatch(&patches[i], evergreen.GithubPRRequester); err != nil {
@@ -360,9 +370,13 @@ func AbortPatchesWithGithubPatchData(createdBefore time.Time, owner, repo string
 				}))
 
 				catcher.Add(err)
+				continue
+			}
+			if patches[i].Status == evergreen.PatchCreated || patches[i].Status == evergreen.PatchStarted {
+				canceled = append(canceled, patches[i])
 			}
 		}
 	}
 
-	return errors.Wrap(catcher.Resolve(), "error aborting patches")
+	return canceled, errors.Wrap(catcher.Resolve(), "error aborting patches")
 }
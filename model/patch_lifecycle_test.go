@@ -19,6 +19,8 @@ import (
 	"github.com/mongodb/grip"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
 )
 
 func TestMakePatchedConfig(t *testing.T) {
@@ -405,3 +407,46 @@ func TestAddNewPatch(t *testing.T) {
 	assert.Equal(dbTasks[2].DisplayName, "task2")
 	assert.Equal(dbTasks[3].DisplayName, "task3")
 }
+
+func TestAbortPatchesWithGithubPatchData(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(patch.Collection, build.Collection, task.Collection))
+
+	now := time.Now()
+	githubData := patch.GithubPatch{BaseOwner: "evergreen-ci", BaseRepo: "evergreen", PRNumber: 1}
+	patches := []patch.Patch{
+		{Id: bson.NewObjectId(), Version: "running", Status: evergreen.PatchStarted, CreateTime: now.Add(-2 * time.Hour), GithubPatchData: githubData},
+		{Id: bson.NewObjectId(), Version: "finished", Status: evergreen.PatchFailed, CreateTime: now.Add(-time.Hour), GithubPatchData: githubData},
+		{Id: bson.NewObjectId(), Status: evergreen.PatchCreated, CreateTime: now.Add(-time.Hour), GithubPatchData: githubData},
+		{Id: bson.NewObjectId(), Version: "other-pr", Status: evergreen.PatchStarted, CreateTime: now.Add(-time.Hour),
+			GithubPatchData: patch.GithubPatch{BaseOwner: "evergreen-ci", BaseRepo: "evergreen", PRNumber: 2}},
+		{Id: bson.NewObjectId(), Version: "newest", Status: evergreen.PatchCreated, CreateTime: now, GithubPatchData: githubData},
+	}
+	for _, p := range patches {
+		require.NoError(p.Insert())
+	}
+	for _, tsk := range []task.Task{
+		{Id: "running-task", Version: "running", Status: evergreen.TaskStarted, Activated: true},
+		{Id: "finished-task", Version: "finished", Status: evergreen.TaskStarted, Activated: true},
+		{Id: "other-task", Version: "other-pr", Status: evergreen.TaskStarted, Activated: true},
+	} {
+		require.NoError(tsk.Insert())
+	}
+
+	newest := patches[4]
+	canceled, err := AbortPatchesWithGithubPatchData(newest.CreateTime, newest.GithubPatchData.BaseOwner,
+		newest.GithubPatchData.BaseRepo, newest.GithubPatchData.PRNumber)
+	require.NoError(err)
+	require.Len(canceled, 1)
+	assert.Equal("running", canceled[0].Version)
+
+	for _, id := range []string{"running-task", "finished-task"} {
+		dbTask, err := task.FindOneId(id)
+		require.NoError(err)
+		assert.True(dbTask.Aborted, id)
+	}
+	dbTask, err := task.FindOneId("other-task")
+	require.NoError(err)
+	assert.False(dbTask.Aborted)
+}
@@ -0,0 +1,269 @@
+package perf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Collection holds the change points detected in the performance data
+// that tasks send with json.send.
+const Collection = "perf_change_points"
+
+// ChangePoint is a significant change in a measurement of a task's
+// performance data, at the first mainline version after the change.
+type ChangePoint struct {
+	ID            string    `bson:"_id" json:"id"`
+	Project       string    `bson:"project" json:"project"`
+	Variant       string    `bson:"variant" json:"variant"`
+	Task          string    `bson:"task" json:"task"`
+	Name          string    `bson:"name" json:"name"`
+	Measurement   string    `bson:"measurement" json:"measurement"`
+	VersionID     string    `bson:"version_id" json:"version_id"`
+	TaskID        string    `bson:"task_id" json:"task_id"`
+	Revision      string    `bson:"revision" json:"revision"`
+	Order         int       `bson:"order" json:"order"`
+	MeanBefore    float64   `bson:"mean_before" json:"mean_before"`
+	MeanAfter     float64   `bson:"mean_after" json:"mean_after"`
+	PercentChange float64   `bson:"percent_change" json:"percent_change"`
+	Direction     string    `bson:"direction,omitempty" json:"direction,omitempty"`
+	Probability   float64   `bson:"probability" json:"probability"`
+	DetectedAt    time.Time `bson:"detected_at" json:"detected_at"`
+}
+
+var (
+	IDKey            = bsonutil.MustHaveTag(ChangePoint{}, "ID")
+	ProjectKey       = bsonutil.MustHaveTag(ChangePoint{}, "Project")
+	VariantKey       = bsonutil.MustHaveTag(ChangePoint{}, "Variant")
+	TaskKey          = bsonutil.MustHaveTag(ChangePoint{}, "Task")
+	NameKey          = bsonutil.MustHaveTag(ChangePoint{}, "Name")
+	MeasurementKey   = bsonutil.MustHaveTag(ChangePoint{}, "Measurement")
+	VersionIDKey     = bsonutil.MustHaveTag(ChangePoint{}, "VersionID")
+	TaskIDKey        = bsonutil.MustHaveTag(ChangePoint{}, "TaskID")
+	RevisionKey      = bsonutil.MustHaveTag(ChangePoint{}, "Revision")
+	OrderKey         = bsonutil.MustHaveTag(ChangePoint{}, "Order")
+	MeanBeforeKey    = bsonutil.MustHaveTag(ChangePoint{}, "MeanBefore")
+	MeanAfterKey     = bsonutil.MustHaveTag(ChangePoint{}, "MeanAfter")
+	PercentChangeKey = bsonutil.MustHaveTag(ChangePoint{}, "PercentChange")
+	DirectionKey     = bsonutil.MustHaveTag(ChangePoint{}, "Direction")
+	ProbabilityKey   = bsonutil.MustHaveTag(ChangePoint{}, "Probability")
+	DetectedAtKey    = bsonutil.MustHaveTag(ChangePoint{}, "DetectedAt")
+)
+
+// Series identifies a measurement of the data sent under a name by a task
+// on a build variant.
+type Series struct {
+	Project     string
+	Variant     string
+	Task        string
+	Name        string
+	Measurement string
+}
+
+// Point is a measurement's value in one mainline version.
+type Point struct {
+	Order     int
+	Revision  string
+	VersionID string
+	TaskID    string
+	Value     float64
+}
+
+// Detect returns the change points in the points, which must be in
+// revision order. A change point's mean before and after are those of the
+// values between it and its neighboring change points.
+func Detect(s Series, points []Point, opts EDivisiveOptions) []ChangePoint {
+	values := make([]float64, len(points))
+	for i := range points {
+		values[i] = points[i].Value
+	}
+
+	detections := EDivisive(values, opts)
+	now := time.Now()
+	out := make([]ChangePoint, 0, len(detections))
+	for i, d := range detections {
+		start, end := 0, len(values)
+		if i > 0 {
+			start = detections[i-1].Index
+		}
+		if i+1 < len(detections) {
+			end = detections[i+1].Index
+		}
+
+		p := points[d.Index]
+		cp := ChangePoint{
+			Project:     s.Project,
+			Variant:     s.Variant,
+			Task:        s.Task,
+			Name:        s.Name,
+			Measurement: s.Measurement,
+			VersionID:   p.VersionID,
+			TaskID:      p.TaskID,
+			Revision:    p.Revision,
+			Order:       p.Order,
+			MeanBefore:  mean(values[start:d.Index]),
+			MeanAfter:   mean(values[d.Index:end]),
+			Probability: d.Probability,
+			Direction:   MeasurementDirection(s.Measurement),
+			DetectedAt:  now,
+		}
+		if cp.MeanBefore != 0 {
+			cp.PercentChange = 100 * (cp.MeanAfter - cp.MeanBefore) / cp.MeanBefore
+		}
+		cp.ID = changePointID(s, p.VersionID)
+		out = append(out, cp)
+	}
+	return out
+}
+
+// IsRegression reports whether the change point is a change for the worse.
+// Change points detected before their direction was recorded improve
+// upward, as most measurements do.
+func (cp *ChangePoint) IsRegression() bool {
+	if cp.Direction == DirectionLower {
+		return cp.PercentChange > 0
+	}
+	return cp.PercentChange < 0
+}
+
+// lowerIsBetterWords are the words in a measurement's name that mark it as
+// a latency or a duration, rather than a throughput.
+var lowerIsBetterWords = map[string]bool{
+	"latency":  true,
+	"duration": true,
+	"elapsed":  true,
+	"time":     true,
+	"seconds":  true,
+	"secs":     true,
+	"ms":       true,
+	"us":       true,
+	"ns":       true,
+}
+
+// MeasurementDirection returns the direction of improvement of a
+// measurement of json.send data, which doesn't record one. Measurements
+// whose last name component is a latency or a duration, such as
+// "results.insert.latency_ms", improve downward, and all others, such as
+// "ops_per_sec", upward.
+func MeasurementDirection(measurement string) string {
+	name := measurement[strings.LastIndex(measurement, ".")+1:]
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '_' || r == '-'
+	})
+	for _, word := range words {
+		if lowerIsBetterWords[word] {
+			return DirectionLower
+		}
+	}
+	return DirectionHigher
+}
+
+func changePointID(s Series, versionID string) string {
+	return strings.Join([]string{s.Project, s.Variant, s.Task, s.Name, s.Measurement, versionID}, "|")
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Measurements flattens the numeric values in data sent with json.send into
+// measurements named by their dotted paths, such as "results.insert.ops".
+// Array elements are named by their index.
+func Measurements(data map[string]interface{}) map[string]float64 {
+	out := map[string]float64{}
+	flatten("", data, out)
+	return out
+}
+
+func flatten(prefix string, value interface{}, out map[string]float64) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			flatten(join(key), val, out)
+		}
+	case bson.M:
+		flatten(prefix, map[string]interface{}(v), out)
+	case []interface{}:
+		for i, val := range v {
+			flatten(join(strconv.Itoa(i)), val, out)
+		}
+	case float64:
+		out[prefix] = v
+	case float32:
+		out[prefix] = float64(v)
+	case int:
+		out[prefix] = float64(v)
+	case int32:
+		out[prefix] = float64(v)
+	case int64:
+		out[prefix] = float64(v)
+	}
+}
+
+// Insert stores the change point unless it has already been detected,
+// and reports whether it was new.
+func (cp *ChangePoint) Insert() (bool, error) {
+	info, err := db.Upsert(Collection, bson.M{IDKey: cp.ID}, bson.M{"$setOnInsert": cp})
+	if err != nil {
+		return false, errors.Wrapf(err, "problem inserting change point '%s'", cp.ID)
+	}
+	return info.UpsertedId != nil, nil
+}
+
+// ByIDs finds the change points with the ids.
+func ByIDs(ids []string) db.Q {
+	return db.Query(bson.M{IDKey: bson.M{"$in": ids}})
+}
+
+// ByVersion finds the change points at a version.
+func ByVersion(versionID string) db.Q {
+	return db.Query(bson.M{VersionIDKey: versionID}).Sort([]string{VariantKey, TaskKey, NameKey, MeasurementKey})
+}
+
+// BySeries finds the change points of a project, most recent first. Empty
+// variants, tasks and measurements match all.
+func BySeries(project, variant, task, measurement string) db.Q {
+	q := bson.M{ProjectKey: project}
+	if variant != "" {
+		q[VariantKey] = variant
+	}
+	if task != "" {
+		q[TaskKey] = task
+	}
+	if measurement != "" {
+		q[MeasurementKey] = measurement
+	}
+	return db.Query(q).Sort([]string{"-" + OrderKey, VariantKey, TaskKey, NameKey, MeasurementKey})
+}
+
+// Find returns the change points matching the query.
+func Find(q db.Q) ([]ChangePoint, error) {
+	out := []ChangePoint{}
+	err := db.FindAllQ(Collection, q, &out)
+	return out, errors.Wrap(err, "problem finding change points")
+}
+
+// SortByOrder sorts change points from most to least recent.
+func SortByOrder(cps []ChangePoint) {
+	sort.SliceStable(cps, func(i, j int) bool { return cps[i].Order > cps[j].Order })
+}
@@ -0,0 +1,178 @@
+package perf
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// EDivisiveOptions tune change point detection.
+type EDivisiveOptions struct {
+	// MinSize is the fewest points allowed on either side of a change
+	// point. It must be at least 2.
+	MinSize int
+	// Permutations is the number of random permutations of the series
+	// used to test whether a candidate change point is significant.
+	Permutations int
+	// PValue is the significance level a candidate change point must
+	// reach to be accepted.
+	PValue float64
+	// Seed seeds the permutations, so that a series always produces the
+	// same change points.
+	Seed int64
+}
+
+// DefaultEDivisiveOptions are used for options left unset.
+var DefaultEDivisiveOptions = EDivisiveOptions{
+	MinSize:      3,
+	Permutations: 199,
+	PValue:       0.05,
+	Seed:         1,
+}
+
+func (o EDivisiveOptions) withDefaults() EDivisiveOptions {
+	if o.MinSize < 2 {
+		o.MinSize = DefaultEDivisiveOptions.MinSize
+	}
+	if o.Permutations <= 0 {
+		o.Permutations = DefaultEDivisiveOptions.Permutations
+	}
+	if o.PValue <= 0 {
+		o.PValue = DefaultEDivisiveOptions.PValue
+	}
+	if o.Seed == 0 {
+		o.Seed = DefaultEDivisiveOptions.Seed
+	}
+	return o
+}
+
+// Detection is a change point found in a series.
+type Detection struct {
+	// Index is the index of the first value after the change.
+	Index int
+	// Probability is the confidence that the change is real, as one
+	// minus the p-value of the permutation test that accepted it.
+	Probability float64
+}
+
+// EDivisive finds change points in the series with the E-Divisive method
+// of Matteson and James: it repeatedly splits the segment whose best split
+// most increases the energy distance between the two sides, for as long as
+// a permutation test finds that split significant. The detections are
+// returned in index order.
+func EDivisive(series []float64, opts EDivisiveOptions) []Detection {
+	opts = opts.withDefaults()
+	if len(series) < 2*opts.MinSize {
+		return nil
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	bounds := []int{0, len(series)}
+	detections := []Detection{}
+	permuted := make([]float64, len(series))
+	for {
+		index, q := bestSplit(series, bounds, opts.MinSize)
+		if index < 0 {
+			break
+		}
+
+		exceeded := 0
+		for i := 0; i < opts.Permutations; i++ {
+			copy(permuted, series)
+			for s := 0; s+1 < len(bounds); s++ {
+				shuffle(rng, permuted[bounds[s]:bounds[s+1]])
+			}
+			if _, permutedQ := bestSplit(permuted, bounds, opts.MinSize); permutedQ >= q {
+				exceeded++
+			}
+		}
+		p := float64(exceeded+1) / float64(opts.Permutations+1)
+		if p > opts.PValue {
+			break
+		}
+
+		detections = append(detections, Detection{Index: index, Probability: 1 - p})
+		bounds = append(bounds, index)
+		sort.Ints(bounds)
+	}
+
+	sort.Slice(detections, func(i, j int) bool { return detections[i].Index < detections[j].Index })
+	return detections
+}
+
+func shuffle(rng *rand.Rand, values []float64) {
+	for i := len(values) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
+// bestSplit returns the index, across all of the segments between the
+// bounds, that best divides its segment, and the divergence of that split.
+// The index is -1 if no segment is large enough to split.
+func bestSplit(series []float64, bounds []int, minSize int) (int, float64) {
+	best, bestQ := -1, math.Inf(-1)
+	for s := 0; s+1 < len(bounds); s++ {
+		index, q := bestSegmentSplit(series[bounds[s]:bounds[s+1]], minSize)
+		if index >= 0 && q > bestQ {
+			best, bestQ = bounds[s]+index, q
+		}
+	}
+	return best, bestQ
+}
+
+// bestSegmentSplit returns the index that maximizes the scaled energy
+// distance between the values before and after it. The sums of distances
+// within and between the two sides are updated as each value moves from
+// the right side to the left, so that each segment takes quadratic time.
+func bestSegmentSplit(x []float64, minSize int) (int, float64) {
+	n := len(x)
+	if n < 2*minSize {
+		return -1, 0
+	}
+
+	var withinLeft, withinRight, between float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := math.Abs(x[i] - x[j])
+			switch {
+			case j < minSize:
+				withinLeft += d
+			case i >= minSize:
+				withinRight += d
+			default:
+				between += d
+			}
+		}
+	}
+
+	best, bestQ := -1, math.Inf(-1)
+	for tau := minSize; tau <= n-minSize; tau++ {
+		if q := divergence(between, withinLeft, withinRight, tau, n-tau); q > bestQ {
+			best, bestQ = tau, q
+		}
+		if tau == n-minSize {
+			break
+		}
+
+		var toLeft, toRight float64
+		for i := 0; i < tau; i++ {
+			toLeft += math.Abs(x[i] - x[tau])
+		}
+		for j := tau + 1; j < n; j++ {
+			toRight += math.Abs(x[tau] - x[j])
+		}
+		withinLeft += toLeft
+		withinRight -= toRight
+		between += toRight - toLeft
+	}
+	return best, bestQ
+}
+
+// divergence is the E-Divisive statistic for a split into m values on the
+// left and k on the right, given the sums of the distances between and
+// within the two sides.
+func divergence(between, withinLeft, withinRight float64, m, k int) float64 {
+	fm, fk := float64(m), float64(k)
+	return fm * fk / (fm + fk) * (2*between/(fm*fk) - withinLeft/(fm*(fm-1)/2) - withinRight/(fk*(fk-1)/2))
+}
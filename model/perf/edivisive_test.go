@@ -0,0 +1,109 @@
+package perf
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noisy returns n values around mean with a little deterministic noise.
+func noisy(rng *rand.Rand, n int, mean float64) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = mean + rng.Float64() - 0.5
+	}
+	return out
+}
+
+func TestEDivisive(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	t.Run("FlatSeriesHasNoChangePoints", func(t *testing.T) {
+		assert.Empty(t, EDivisive(noisy(rng, 50, 100), EDivisiveOptions{}))
+	})
+	t.Run("ShortSeriesHasNoChangePoints", func(t *testing.T) {
+		assert.Empty(t, EDivisive([]float64{1, 100, 1, 100, 1}, EDivisiveOptions{}))
+	})
+	t.Run("FindsSingleStep", func(t *testing.T) {
+		series := append(noisy(rng, 30, 100), noisy(rng, 20, 80)...)
+		detections := EDivisive(series, EDivisiveOptions{})
+		require.Len(t, detections, 1)
+		assert.Equal(t, 30, detections[0].Index)
+		assert.True(t, detections[0].Probability >= 0.95)
+	})
+	t.Run("FindsMultipleStepsInOrder", func(t *testing.T) {
+		series := append(noisy(rng, 20, 100), noisy(rng, 20, 50)...)
+		series = append(series, noisy(rng, 20, 150)...)
+		detections := EDivisive(series, EDivisiveOptions{})
+		require.Len(t, detections, 2)
+		assert.Equal(t, 20, detections[0].Index)
+		assert.Equal(t, 40, detections[1].Index)
+	})
+	t.Run("IsDeterministic", func(t *testing.T) {
+		series := append(noisy(rng, 10, 10), noisy(rng, 10, 12)...)
+		assert.Equal(t, EDivisive(series, EDivisiveOptions{}), EDivisive(series, EDivisiveOptions{}))
+	})
+}
+
+func TestDetect(t *testing.T) {
+	points := []Point{}
+	for i := 0; i < 20; i++ {
+		value := 100.0
+		if i >= 12 {
+			value = 50
+		}
+		points = append(points, Point{Order: i + 1, VersionID: fmt.Sprintf("v%d", i), Value: value})
+	}
+	s := Series{Project: "p", Variant: "bv", Task: "t", Name: "perf", Measurement: "ops"}
+
+	cps := Detect(s, points, EDivisiveOptions{})
+	require.Len(t, cps, 1)
+	cp := cps[0]
+	assert.Equal(t, 13, cp.Order)
+	assert.Equal(t, points[12].VersionID, cp.VersionID)
+	assert.Equal(t, 100.0, cp.MeanBefore)
+	assert.Equal(t, 50.0, cp.MeanAfter)
+	assert.Equal(t, -50.0, cp.PercentChange)
+	assert.Equal(t, DirectionHigher, cp.Direction)
+	assert.Equal(t, "p|bv|t|perf|ops|"+cp.VersionID, cp.ID)
+}
+
+func TestMeasurements(t *testing.T) {
+	data := map[string]interface{}{
+		"ops_per_sec": 100.5,
+		"threads":     4,
+		"label":       "ignored",
+		"results": map[string]interface{}{
+			"insert": map[string]interface{}{"latency": 2.5},
+		},
+		"runs": []interface{}{1.0, 2.0},
+	}
+	assert.Equal(t, map[string]float64{
+		"ops_per_sec":            100.5,
+		"threads":                4,
+		"results.insert.latency": 2.5,
+		"runs.0":                 1,
+		"runs.1":                 2,
+	}, Measurements(data))
+}
+
+func TestChangePointIsRegression(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DirectionHigher, MeasurementDirection("ops_per_sec"))
+	assert.Equal(DirectionHigher, MeasurementDirection("results.insert.ops"))
+	assert.Equal(DirectionHigher, MeasurementDirection("runs.0"))
+	assert.Equal(DirectionLower, MeasurementDirection("results.insert.latency"))
+	assert.Equal(DirectionLower, MeasurementDirection("results.insert.Latency_ms"))
+	assert.Equal(DirectionLower, MeasurementDirection("build-time"))
+	assert.Equal(DirectionHigher, MeasurementDirection("time.ops"), "only the last component is used")
+
+	assert.True((&ChangePoint{Direction: DirectionHigher, PercentChange: -10}).IsRegression())
+	assert.False((&ChangePoint{Direction: DirectionHigher, PercentChange: 10}).IsRegression())
+	assert.True((&ChangePoint{Direction: DirectionLower, PercentChange: 10}).IsRegression())
+	assert.False((&ChangePoint{Direction: DirectionLower, PercentChange: -10}).IsRegression())
+	assert.True((&ChangePoint{PercentChange: -10}).IsRegression(), "change points without a direction improve upward")
+}
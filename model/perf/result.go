@@ -0,0 +1,325 @@
+package perf
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ResultsCollection holds the performance results that tasks send with
+// perf.send.
+const ResultsCollection = "perf_results"
+
+// Improvement directions of a metric.
+const (
+	DirectionHigher = "higher"
+	DirectionLower  = "lower"
+)
+
+// Result is the outcome of one performance test, run with one set of
+// arguments, in a task.
+type Result struct {
+	ID        string                 `bson:"_id" json:"id"`
+	Info      ResultInfo             `bson:"info" json:"info"`
+	Test      string                 `bson:"test" json:"test"`
+	Args      map[string]interface{} `bson:"args,omitempty" json:"args,omitempty"`
+	Metrics   []Metric               `bson:"metrics" json:"metrics"`
+	CreatedAt time.Time              `bson:"created_at" json:"created_at"`
+}
+
+// ResultInfo identifies the task execution a result was sent by.
+type ResultInfo struct {
+	Project   string `bson:"project" json:"project"`
+	Variant   string `bson:"variant" json:"variant"`
+	TaskName  string `bson:"task_name" json:"task_name"`
+	TaskID    string `bson:"task_id" json:"task_id"`
+	Execution int    `bson:"execution" json:"execution"`
+	VersionID string `bson:"version_id" json:"version_id"`
+	Revision  string `bson:"revision" json:"revision"`
+	Order     int    `bson:"order" json:"order"`
+	IsPatch   bool   `bson:"is_patch" json:"is_patch"`
+}
+
+// Metric is a measurement of a test. Value summarizes the raw values of
+// the test's trials, if it ran more than one.
+type Metric struct {
+	Name      string    `bson:"name" json:"name"`
+	Units     string    `bson:"units,omitempty" json:"units,omitempty"`
+	Direction string    `bson:"direction" json:"direction"`
+	Value     float64   `bson:"value" json:"value"`
+	Trials    []float64 `bson:"trials,omitempty" json:"trials,omitempty"`
+}
+
+var (
+	ResultIDKey        = bsonutil.MustHaveTag(Result{}, "ID")
+	ResultInfoKey      = bsonutil.MustHaveTag(Result{}, "Info")
+	ResultTestKey      = bsonutil.MustHaveTag(Result{}, "Test")
+	ResultArgsKey      = bsonutil.MustHaveTag(Result{}, "Args")
+	ResultMetricsKey   = bsonutil.MustHaveTag(Result{}, "Metrics")
+	ResultCreatedAtKey = bsonutil.MustHaveTag(Result{}, "CreatedAt")
+
+	ResultInfoProjectKey   = bsonutil.MustHaveTag(ResultInfo{}, "Project")
+	ResultInfoVariantKey   = bsonutil.MustHaveTag(ResultInfo{}, "Variant")
+	ResultInfoTaskNameKey  = bsonutil.MustHaveTag(ResultInfo{}, "TaskName")
+	ResultInfoTaskIDKey    = bsonutil.MustHaveTag(ResultInfo{}, "TaskID")
+	ResultInfoExecutionKey = bsonutil.MustHaveTag(ResultInfo{}, "Execution")
+	ResultInfoOrderKey     = bsonutil.MustHaveTag(ResultInfo{}, "Order")
+	ResultInfoIsPatchKey   = bsonutil.MustHaveTag(ResultInfo{}, "IsPatch")
+)
+
+func infoKey(key string) string {
+	return bsonutil.GetDottedKeyName(ResultInfoKey, key)
+}
+
+// Validate checks that the result names its test and has well-formed,
+// uniquely named metrics. Metrics without a direction are assumed to
+// improve when they are higher.
+func (r *Result) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if r.Test == "" {
+		catcher.Add(errors.New("result must name its test"))
+	}
+	if len(r.Metrics) == 0 {
+		catcher.Add(errors.Errorf("result for test '%s' has no metrics", r.Test))
+	}
+
+	seen := map[string]bool{}
+	for i := range r.Metrics {
+		m := &r.Metrics[i]
+		if m.Name == "" {
+			catcher.Add(errors.Errorf("metric %d of test '%s' has no name", i, r.Test))
+			continue
+		}
+		if seen[m.Name] {
+			catcher.Add(errors.Errorf("test '%s' has more than one metric named '%s'", r.Test, m.Name))
+		}
+		seen[m.Name] = true
+
+		switch m.Direction {
+		case "":
+			m.Direction = DirectionHigher
+		case DirectionHigher, DirectionLower:
+		default:
+			catcher.Add(errors.Errorf("metric '%s' of test '%s' has invalid direction '%s'", m.Name, r.Test, m.Direction))
+		}
+		if len(m.Trials) > 0 && m.Value == 0 {
+			m.Value = mean(m.Trials)
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+// SetInfo sets the task execution the result was sent by, and the result's
+// id, which is the same each time the execution sends the test with the
+// same arguments.
+func (r *Result) SetInfo(info ResultInfo) {
+	r.Info = info
+	r.ID = strings.Join([]string{info.TaskID, strconv.Itoa(info.Execution), r.Test, argsString(r.Args)}, "|")
+}
+
+func argsString(args map[string]interface{}) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, args[k]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Save stores the result, replacing any the task execution sent before for
+// the same test and arguments.
+func (r *Result) Save() error {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	_, err := db.Upsert(ResultsCollection, bson.M{ResultIDKey: r.ID}, r)
+	return errors.Wrapf(err, "problem saving perf result '%s'", r.ID)
+}
+
+// ResultsByTask finds the results of a task execution.
+func ResultsByTask(taskID string, execution int) db.Q {
+	return db.Query(bson.M{
+		infoKey(ResultInfoTaskIDKey):    taskID,
+		infoKey(ResultInfoExecutionKey): execution,
+	}).Sort([]string{ResultTestKey})
+}
+
+// ResultsFilter selects results of a project's mainline versions.
+// Empty fields match all results.
+type ResultsFilter struct {
+	Project  string
+	Variant  string
+	TaskName string
+	Test     string
+	// Start and End bound the time the results were sent.
+	Start time.Time
+	End   time.Time
+	// MinOrder and MaxOrder bound the revision order numbers of the
+	// results' versions, inclusively.
+	MinOrder int
+	MaxOrder int
+}
+
+// ResultsBySeries finds the results matching the filter, oldest first.
+func ResultsBySeries(f ResultsFilter) db.Q {
+	q := bson.M{
+		infoKey(ResultInfoProjectKey): f.Project,
+		infoKey(ResultInfoIsPatchKey): false,
+	}
+	if f.Variant != "" {
+		q[infoKey(ResultInfoVariantKey)] = f.Variant
+	}
+	if f.TaskName != "" {
+		q[infoKey(ResultInfoTaskNameKey)] = f.TaskName
+	}
+	if f.Test != "" {
+		q[ResultTestKey] = f.Test
+	}
+
+	created := bson.M{}
+	if !f.Start.IsZero() {
+		created["$gte"] = f.Start
+	}
+	if !f.End.IsZero() {
+		created["$lt"] = f.End
+	}
+	if len(created) > 0 {
+		q[ResultCreatedAtKey] = created
+	}
+
+	order := bson.M{}
+	if f.MinOrder > 0 {
+		order["$gte"] = f.MinOrder
+	}
+	if f.MaxOrder > 0 {
+		order["$lte"] = f.MaxOrder
+	}
+	if len(order) > 0 {
+		q[infoKey(ResultInfoOrderKey)] = order
+	}
+
+	return db.Query(q).Sort([]string{infoKey(ResultInfoOrderKey), ResultCreatedAtKey})
+}
+
+// FindResults returns the results matching the query.
+func FindResults(q db.Q) ([]Result, error) {
+	out := []Result{}
+	err := db.FindAllQ(ResultsCollection, q, &out)
+	return out, errors.Wrap(err, "problem finding perf results")
+}
+
+// FromLegacyJSON converts data sent with json.send in the shape the perf
+// plugin displays, in which each test's results are keyed by thread level:
+//
+//	{"results": [{"name": "insert", "results": {"8": {"ops_per_sec": 100, "ops_per_sec_values": [99, 101]}}}]}
+//
+// into a result for each test and thread level. It reports false if the
+// data isn't in that shape.
+func FromLegacyJSON(data map[string]interface{}) ([]Result, bool) {
+	tests, ok := toSlice(data["results"])
+	if !ok || len(tests) == 0 {
+		return nil, false
+	}
+
+	out := []Result{}
+	for _, t := range tests {
+		test, ok := toMap(t)
+		if !ok {
+			return nil, false
+		}
+		name, ok := test["name"].(string)
+		if !ok || name == "" {
+			return nil, false
+		}
+		levels, ok := toMap(test["results"])
+		if !ok {
+			return nil, false
+		}
+
+		threads := make([]string, 0, len(levels))
+		for k := range levels {
+			threads = append(threads, k)
+		}
+		sort.Strings(threads)
+
+		for _, thread := range threads {
+			level, ok := toMap(levels[thread])
+			if !ok {
+				return nil, false
+			}
+			value, ok := toFloat(level["ops_per_sec"])
+			if !ok {
+				return nil, false
+			}
+
+			r := Result{
+				Test: name,
+				Metrics: []Metric{{
+					Name:      "ops_per_sec",
+					Units:     "ops/sec",
+					Direction: DirectionHigher,
+					Value:     value,
+				}},
+			}
+			if n, err := strconv.Atoi(thread); err == nil {
+				r.Args = map[string]interface{}{"thread_level": n}
+			} else {
+				r.Args = map[string]interface{}{"thread_level": thread}
+			}
+			if values, ok := toSlice(level["ops_per_sec_values"]); ok {
+				for _, v := range values {
+					if f, ok := toFloat(v); ok {
+						r.Metrics[0].Trials = append(r.Metrics[0].Trials, f)
+					}
+				}
+			}
+			out = append(out, r)
+		}
+	}
+	return out, true
+}
+
+func toMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case bson.M:
+		return map[string]interface{}(v), true
+	}
+	return nil, false
+}
+
+func toSlice(value interface{}) ([]interface{}, bool) {
+	v, ok := value.([]interface{})
+	return v, ok
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,90 @@
+package perf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultValidate(t *testing.T) {
+	t.Run("FillsDefaults", func(t *testing.T) {
+		r := Result{Test: "insert", Metrics: []Metric{{Name: "ops", Trials: []float64{1, 3}}}}
+		require.NoError(t, r.Validate())
+		assert.Equal(t, DirectionHigher, r.Metrics[0].Direction)
+		assert.Equal(t, 2.0, r.Metrics[0].Value)
+	})
+	t.Run("KeepsExplicitValue", func(t *testing.T) {
+		r := Result{Test: "insert", Metrics: []Metric{{Name: "ops", Value: 5, Trials: []float64{1, 3}}}}
+		require.NoError(t, r.Validate())
+		assert.Equal(t, 5.0, r.Metrics[0].Value)
+	})
+	for name, r := range map[string]Result{
+		"MissingTest":      {Metrics: []Metric{{Name: "ops"}}},
+		"MissingMetrics":   {Test: "insert"},
+		"UnnamedMetric":    {Test: "insert", Metrics: []Metric{{Value: 1}}},
+		"DuplicateMetric":  {Test: "insert", Metrics: []Metric{{Name: "ops"}, {Name: "ops"}}},
+		"InvalidDirection": {Test: "insert", Metrics: []Metric{{Name: "ops", Direction: "up"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, r.Validate())
+		})
+	}
+}
+
+func TestResultSetInfo(t *testing.T) {
+	info := ResultInfo{TaskID: "t1", Execution: 2}
+	a := Result{Test: "insert", Args: map[string]interface{}{"threads": 8, "docs": 10}}
+	b := Result{Test: "insert", Args: map[string]interface{}{"docs": 10, "threads": 8}}
+	c := Result{Test: "insert", Args: map[string]interface{}{"threads": 16, "docs": 10}}
+	a.SetInfo(info)
+	b.SetInfo(info)
+	c.SetInfo(info)
+
+	assert.Equal(t, info, a.Info)
+	assert.Equal(t, "t1|2|insert|docs=10,threads=8", a.ID)
+	assert.Equal(t, a.ID, b.ID)
+	assert.NotEqual(t, a.ID, c.ID)
+}
+
+func TestFromLegacyJSON(t *testing.T) {
+	parse := func(s string) map[string]interface{} {
+		data := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(s), &data))
+		return data
+	}
+
+	t.Run("ConvertsEachThreadLevel", func(t *testing.T) {
+		results, ok := FromLegacyJSON(parse(`{"results": [
+			{"name": "insert", "results": {"8": {"ops_per_sec": 400}, "1": {"ops_per_sec": 100, "ops_per_sec_values": [99, 101]}}},
+			{"name": "update", "results": {"1": {"ops_per_sec": 50}}}
+		]}`))
+		require.True(t, ok)
+		require.Len(t, results, 3)
+
+		assert.Equal(t, "insert", results[0].Test)
+		assert.Equal(t, map[string]interface{}{"thread_level": 1}, results[0].Args)
+		require.Len(t, results[0].Metrics, 1)
+		assert.Equal(t, Metric{Name: "ops_per_sec", Units: "ops/sec", Direction: DirectionHigher, Value: 100, Trials: []float64{99, 101}}, results[0].Metrics[0])
+		assert.Equal(t, map[string]interface{}{"thread_level": 8}, results[1].Args)
+		assert.Equal(t, 400.0, results[1].Metrics[0].Value)
+		assert.Equal(t, "update", results[2].Test)
+		for _, r := range results {
+			assert.NoError(t, r.Validate())
+		}
+	})
+	for name, data := range map[string]string{
+		"NoResults":       `{"ops_per_sec": 100}`,
+		"EmptyResults":    `{"results": []}`,
+		"UnnamedTest":     `{"results": [{"results": {"1": {"ops_per_sec": 100}}}]}`,
+		"MissingOps":      `{"results": [{"name": "insert", "results": {"1": {"latency": 4}}}]}`,
+		"NonNumericOps":   `{"results": [{"name": "insert", "results": {"1": {"ops_per_sec": "fast"}}}]}`,
+		"ResultsNotAList": `{"results": {"insert": 100}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, ok := FromLegacyJSON(parse(data))
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// PriorityPolicyCaller is the user that priority policies record in the
+// task events of the changes that they make.
+const PriorityPolicyCaller = "priority-policy"
+
+const (
+	// values for PriorityPolicyChange.Reason
+	priorityPolicyReleaseBranch   = "release-branch"
+	priorityPolicySupersededPatch = "superseded-patch"
+	priorityPolicyStalePatch      = "stale-patch"
+)
+
+// PriorityPolicy describes how a project's undispatched tasks are
+// reprioritized without anyone setting their priorities by hand.
+type PriorityPolicy struct {
+	// ReleaseBranchPattern is a regular expression for release branches.
+	// If the project's branch matches it, mainline tasks are boosted to
+	// ReleaseBranchPriority.
+	ReleaseBranchPattern  string `bson:"release_branch_pattern,omitempty" json:"release_branch_pattern" yaml:"release_branch_pattern"`
+	ReleaseBranchPriority int64  `bson:"release_branch_priority,omitempty" json:"release_branch_priority" yaml:"release_branch_priority"`
+
+	// DeprioritizeSupersededPatches drops the tasks of a pull request's
+	// patch to SupersededPatchPriority once a newer patch is created for
+	// the same pull request. Other patches don't record the branch they
+	// were made from, so they are never superseded.
+	DeprioritizeSupersededPatches bool  `bson:"deprioritize_superseded_patches,omitempty" json:"deprioritize_superseded_patches" yaml:"deprioritize_superseded_patches"`
+	SupersededPatchPriority       int64 `bson:"superseded_patch_priority,omitempty" json:"superseded_patch_priority" yaml:"superseded_patch_priority"`
+
+	// StalePatchHours, if positive, deactivates patch tasks that have not
+	// started this many hours after they were activated.
+	StalePatchHours int `bson:"stale_patch_hours,omitempty" json:"stale_patch_hours" yaml:"stale_patch_hours"`
+}
+
+// IsZero returns true if the policy changes nothing.
+func (p *PriorityPolicy) IsZero() bool {
+	return p.ReleaseBranchPattern == "" && !p.DeprioritizeSupersededPatches && p.StalePatchHours <= 0
+}
+
+// Validate checks that the policy is well formed.
+func (p *PriorityPolicy) Validate() error {
+	if p.ReleaseBranchPattern != "" {
+		if _, err := regexp.Compile(p.ReleaseBranchPattern); err != nil {
+			return errors.Wrapf(err, "'%s' is not a valid release branch pattern", p.ReleaseBranchPattern)
+		}
+		if p.ReleaseBranchPriority <= 0 {
+			return errors.New("release branch priority must be positive")
+		}
+	}
+	if p.SupersededPatchPriority < 0 {
+		return errors.New("superseded patch priority cannot be negative")
+	}
+	if p.StalePatchHours < 0 {
+		return errors.New("stale patch hours cannot be negative")
+	}
+	return nil
+}
+
+// PriorityPolicyChange is a change that a priority policy makes to a task.
+type PriorityPolicyChange struct {
+	TaskId     string
+	Priority   int64
+	Deactivate bool
+	Reason     string
+}
+
+// changes returns what the policy changes about a project's undispatched
+// tasks, where superseded holds the versions of patches that newer patches
+// have superseded and userPrioritized holds the tasks whose priorities users
+// have set, which the policy doesn't reprioritize.
+func (p *PriorityPolicy) changes(ref *ProjectRef, tasks []task.Task, superseded, userPrioritized map[string]bool, now time.Time) []PriorityPolicyChange {
+	releaseBranch := false
+	if p.ReleaseBranchPattern != "" {
+		// the pattern is validated when the policy is saved
+		releaseBranch, _ = regexp.MatchString(p.ReleaseBranchPattern, ref.Branch)
+	}
+
+	changes := []PriorityPolicyChange{}
+	for _, t := range tasks {
+		if !t.IsDispatchable() || t.Priority < 0 {
+			continue
+		}
+
+		if evergreen.IsPatchRequester(t.Requester) {
+			activated := t.ActivatedTime
+			if util.IsZeroTime(activated) {
+				activated = t.CreateTime
+			}
+			if p.StalePatchHours > 0 && !util.IsZeroTime(activated) &&
+				now.Sub(activated) > time.Duration(p.StalePatchHours)*time.Hour {
+				changes = append(changes, PriorityPolicyChange{
+					TaskId:     t.Id,
+					Priority:   t.Priority,
+					Deactivate: true,
+					Reason:     priorityPolicyStalePatch,
+				})
+				continue
+			}
+			if p.DeprioritizeSupersededPatches && superseded[t.Version] && !userPrioritized[t.Id] && t.Priority > p.SupersededPatchPriority {
+				changes = append(changes, PriorityPolicyChange{
+					TaskId:   t.Id,
+					Priority: p.SupersededPatchPriority,
+					Reason:   priorityPolicySupersededPatch,
+				})
+			}
+			continue
+		}
+
+		if releaseBranch && t.Requester == evergreen.RepotrackerVersionRequester && !userPrioritized[t.Id] && t.Priority < p.ReleaseBranchPriority {
+			changes = append(changes, PriorityPolicyChange{
+				TaskId:   t.Id,
+				Priority: p.ReleaseBranchPriority,
+				Reason:   priorityPolicyReleaseBranch,
+			})
+		}
+	}
+
+	return changes
+}
+
+// findSupersededPatchVersions returns the versions of the given patch
+// versions whose pull requests have since had a newer patch created.
+func findSupersededPatchVersions(versions []string) (map[string]bool, error) {
+	superseded := map[string]bool{}
+	if len(versions) == 0 {
+		return superseded, nil
+	}
+
+	patches, err := patch.Find(patch.ByVersions(versions).WithFields(
+		patch.ProjectKey, patch.VersionKey, patch.CreateTimeKey, patch.GithubPatchDataKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding patches")
+	}
+
+	newest := map[string]time.Time{}
+	for _, p := range patches {
+		pr := p.GithubPatchData
+		if pr.PRNumber == 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s#%d", p.Project, pr.BaseOwner, pr.BaseRepo, pr.PRNumber)
+		latest, ok := newest[key]
+		if !ok {
+			var newestPatch *patch.Patch
+			newestPatch, err = patch.FindOne(patch.ByProjectAndGithubPR(p.Project, pr.BaseOwner, pr.BaseRepo, pr.PRNumber).
+				WithFields(patch.CreateTimeKey).Limit(1))
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem finding newest patch of pull request '%s'", key)
+			}
+			if newestPatch != nil {
+				latest = newestPatch.CreateTime
+			}
+			newest[key] = latest
+		}
+		if latest.After(p.CreateTime) {
+			superseded[p.Version] = true
+		}
+	}
+
+	return superseded, nil
+}
+
+// findUserPrioritizedTasks returns the tasks whose priorities have been set
+// by anyone other than a priority policy.
+func findUserPrioritizedTasks(ids []string) (map[string]bool, error) {
+	prioritized := map[string]bool{}
+	if len(ids) == 0 {
+		return prioritized, nil
+	}
+
+	events, err := event.Find(event.AllLogCollection, event.TaskPriorityEventsForIds(ids))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding task priority events")
+	}
+	for _, e := range events {
+		data, ok := e.Data.(*event.TaskEventData)
+		if ok && data.UserId != PriorityPolicyCaller {
+			prioritized[e.ResourceId] = true
+		}
+	}
+
+	return prioritized, nil
+}
+
+// ApplyPriorityPolicies applies the priority policies of the tasks'
+// projects to the undispatched tasks, recording each change as a task
+// event. The tasks are updated in place.
+func ApplyPriorityPolicies(tasks []task.Task, now time.Time) ([]PriorityPolicyChange, error) {
+	byProject := map[string][]int{}
+	for i, t := range tasks {
+		byProject[t.Project] = append(byProject[t.Project], i)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	applied := []PriorityPolicyChange{}
+	for project, indexes := range byProject {
+		ref, err := FindOneProjectRef(project)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem finding project '%s'", project))
+			continue
+		}
+		if ref == nil || ref.PriorityPolicy.IsZero() {
+			continue
+		}
+
+		projectTasks := make([]task.Task, 0, len(indexes))
+		byID := map[string]int{}
+		ids := make([]string, 0, len(indexes))
+		patchVersions := []string{}
+		for _, i := range indexes {
+			projectTasks = append(projectTasks, tasks[i])
+			byID[tasks[i].Id] = i
+			ids = append(ids, tasks[i].Id)
+			if evergreen.IsPatchRequester(tasks[i].Requester) && !util.StringSliceContains(patchVersions, tasks[i].Version) {
+				patchVersions = append(patchVersions, tasks[i].Version)
+			}
+		}
+
+		superseded := map[string]bool{}
+		if ref.PriorityPolicy.DeprioritizeSupersededPatches {
+			superseded, err = findSupersededPatchVersions(patchVersions)
+			if err != nil {
+				catcher.Add(errors.Wrapf(err, "problem finding superseded patches of project '%s'", project))
+				continue
+			}
+		}
+
+		userPrioritized, err := findUserPrioritizedTasks(ids)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem finding prioritized tasks of project '%s'", project))
+			continue
+		}
+
+		for _, change := range ref.PriorityPolicy.changes(ref, projectTasks, superseded, userPrioritized, now) {
+			t := &tasks[byID[change.TaskId]]
+			if err = applyPriorityPolicyChange(t, change); err != nil {
+				catcher.Add(errors.Wrapf(err, "problem applying %s policy to task '%s'", change.Reason, t.Id))
+				continue
+			}
+			applied = append(applied, change)
+
+			grip.Info(message.Fields{
+				"message":    "applied priority policy",
+				"policy":     change.Reason,
+				"task_id":    t.Id,
+				"project":    project,
+				"priority":   change.Priority,
+				"deactivate": change.Deactivate,
+			})
+		}
+	}
+
+	return applied, catcher.Resolve()
+}
+
+// applyPriorityPolicyChange changes a task, which records the change as a
+// task event.
+func applyPriorityPolicyChange(t *task.Task, change PriorityPolicyChange) error {
+	if change.Deactivate {
+		if err := SetActiveState(t.Id, PriorityPolicyCaller, false); err != nil {
+			return errors.WithStack(err)
+		}
+		t.Activated = false
+		t.ActivatedBy = PriorityPolicyCaller
+		return nil
+	}
+	return errors.WithStack(t.SetPriority(change.Priority, PriorityPolicyCaller))
+}
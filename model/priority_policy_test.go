@@ -0,0 +1,138 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestPriorityPolicyValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := PriorityPolicy{}
+	assert.True(policy.IsZero())
+	assert.NoError(policy.Validate())
+
+	policy = PriorityPolicy{ReleaseBranchPattern: `^v\d+\.\d+$`, ReleaseBranchPriority: 50, StalePatchHours: 24}
+	assert.False(policy.IsZero())
+	assert.NoError(policy.Validate())
+
+	for _, policy := range []PriorityPolicy{
+		{ReleaseBranchPattern: "("},
+		{ReleaseBranchPattern: "v1"},
+		{DeprioritizeSupersededPatches: true, SupersededPatchPriority: -1},
+		{StalePatchHours: -1},
+	} {
+		assert.Error(policy.Validate())
+	}
+}
+
+func TestPriorityPolicyChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	ref := &ProjectRef{Identifier: "proj", Branch: "v4.0"}
+	policy := PriorityPolicy{
+		ReleaseBranchPattern:          `^v\d+\.\d+$`,
+		ReleaseBranchPriority:         50,
+		DeprioritizeSupersededPatches: true,
+		StalePatchHours:               12,
+	}
+	tasks := []task.Task{
+		{Id: "mainline", Requester: evergreen.RepotrackerVersionRequester, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "mainline-high", Requester: evergreen.RepotrackerVersionRequester, Priority: 100, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "mainline-started", Requester: evergreen.RepotrackerVersionRequester, Activated: true, Status: evergreen.TaskStarted},
+		{Id: "stale", Requester: evergreen.PatchVersionRequester, Version: "p1", ActivatedTime: now.Add(-13 * time.Hour), Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "superseded", Requester: evergreen.GithubPRRequester, Version: "p1", Priority: 10, ActivatedTime: now.Add(-time.Hour), Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "newest", Requester: evergreen.PatchVersionRequester, Version: "p2", Priority: 10, ActivatedTime: now, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "superseded-set", Requester: evergreen.GithubPRRequester, Version: "p1", Priority: 10, ActivatedTime: now.Add(-time.Hour), Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "mainline-set", Requester: evergreen.RepotrackerVersionRequester, Priority: 5, Activated: true, Status: evergreen.TaskUndispatched},
+	}
+
+	userPrioritized := map[string]bool{"superseded-set": true, "mainline-set": true}
+	changes := policy.changes(ref, tasks, map[string]bool{"p1": true}, userPrioritized, now)
+	assert.Equal([]PriorityPolicyChange{
+		{TaskId: "mainline", Priority: 50, Reason: priorityPolicyReleaseBranch},
+		{TaskId: "stale", Deactivate: true, Reason: priorityPolicyStalePatch},
+		{TaskId: "superseded", Priority: 0, Reason: priorityPolicySupersededPatch},
+	}, changes)
+
+	// mainline tasks are only boosted on release branches
+	ref.Branch = "master"
+	changes = policy.changes(ref, tasks[:3], nil, nil, now)
+	assert.Empty(changes)
+}
+
+func TestApplyPriorityPolicies(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(ProjectRefCollection, task.Collection, patch.Collection, event.AllLogCollection))
+
+	ref := ProjectRef{
+		Identifier: "proj",
+		Branch:     "master",
+		Enabled:    true,
+		PriorityPolicy: PriorityPolicy{
+			DeprioritizeSupersededPatches: true,
+			SupersededPatchPriority:       1,
+		},
+	}
+	require.NoError(ref.Insert())
+	refs, err := FindProjectRefsWithPriorityPolicy()
+	require.NoError(err)
+	assert.Len(refs, 1)
+
+	now := time.Now()
+	pr := func(number int) patch.GithubPatch {
+		return patch.GithubPatch{BaseOwner: "evergreen-ci", BaseRepo: "evergreen", PRNumber: number}
+	}
+	for i, p := range []patch.Patch{
+		{Id: bson.NewObjectId(), Version: "p1", Author: "me", Project: "proj", CreateTime: now.Add(-time.Hour), GithubPatchData: pr(1)},
+		{Id: bson.NewObjectId(), Version: "p2", Author: "me", Project: "proj", CreateTime: now, GithubPatchData: pr(1)},
+		{Id: bson.NewObjectId(), Version: "p3", Author: "me", Project: "proj", CreateTime: now.Add(-time.Hour), GithubPatchData: pr(2)},
+		{Id: bson.NewObjectId(), Version: "p5", Author: "me", Project: "proj", CreateTime: now.Add(-time.Hour)},
+		{Id: bson.NewObjectId(), Version: "p6", Author: "me", Project: "proj", CreateTime: now},
+	} {
+		require.NoError(p.Insert(), "patch %d", i)
+	}
+
+	tasks := []task.Task{
+		{Id: "t1", Project: "proj", Version: "p1", Requester: evergreen.GithubPRRequester, Priority: 10, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "t2", Project: "proj", Version: "p2", Requester: evergreen.GithubPRRequester, Priority: 10, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "t3", Project: "proj", Version: "p3", Requester: evergreen.GithubPRRequester, Priority: 10, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "t4", Project: "other", Version: "p4", Requester: evergreen.PatchVersionRequester, Priority: 10, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "t5", Project: "proj", Version: "p5", Requester: evergreen.PatchVersionRequester, Priority: 10, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "t6", Project: "proj", Version: "p1", Requester: evergreen.GithubPRRequester, Priority: 10, Activated: true, Status: evergreen.TaskUndispatched},
+	}
+	for _, tsk := range tasks {
+		require.NoError(tsk.Insert())
+	}
+	event.LogTaskPriority("t6", 0, "me", 10)
+
+	changes, err := ApplyPriorityPolicies(tasks, now)
+	require.NoError(err)
+	require.Len(changes, 1)
+	assert.Equal("t1", changes[0].TaskId)
+	assert.EqualValues(1, tasks[0].Priority)
+	assert.EqualValues(10, tasks[1].Priority)
+
+	dbTask, err := task.FindOneId("t1")
+	require.NoError(err)
+	assert.EqualValues(1, dbTask.Priority)
+
+	events, err := event.Find(event.AllLogCollection, event.TaskEventsInOrder("t1"))
+	require.NoError(err)
+	require.Len(events, 1)
+	assert.Equal(event.TaskPriorityChanged, events[0].EventType)
+	data, ok := events[0].Data.(*event.TaskEventData)
+	require.True(ok)
+	assert.Equal(PriorityPolicyCaller, data.UserId)
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/build"
@@ -24,6 +25,11 @@ const (
 	// DefaultCommandType is a system configuration option that is used to
 	// differentiate between setup related commands and actual testing commands.
 	DefaultCommandType = evergreen.CommandTypeTest
+
+	// DefaultPauseTimeout and MaxPauseTimeout bound how long a task that
+	// pauses on failure is held.
+	DefaultPauseTimeout = 30 * time.Minute
+	MaxPauseTimeout     = 4 * time.Hour
 )
 
 type Project struct {
@@ -50,6 +56,12 @@ type Project struct {
 	Tasks           []ProjectTask              `yaml:"tasks,omitempty" bson:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
 
+	// PauseOnFailure holds a task whose command fails, before its post
+	// commands run, for PauseTimeoutSecs, so that its host can be
+	// inspected. Variants and tasks may override it.
+	PauseOnFailure   bool `yaml:"pause_on_failure,omitempty" bson:"pause_on_failure"`
+	PauseTimeoutSecs int  `yaml:"pause_timeout_secs,omitempty" bson:"pause_timeout_secs"`
+
 	// Flag that indicates a project as requiring user authentication
 	Private bool `yaml:"private,omitempty" bson:"private"`
 }
@@ -174,6 +186,9 @@ type BuildVariant struct {
 	//   3. false = overriding the project setting with false
 	Stepback *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
 
+	// PauseOnFailure overrides the project setting when non-nil.
+	PauseOnFailure *bool `yaml:"pause_on_failure,omitempty" bson:"pause_on_failure,omitempty"`
+
 	// the default distros.  will be used to run a task if no distro field is
 	// provided for the task
 	RunOn []string `yaml:"run_on,omitempty" bson:"run_on"`
@@ -329,8 +344,24 @@ type ProjectTask struct {
 	//   1. nil   = not overriding the project setting (default)
 	//   2. true  = overriding the project setting with true
 	//   3. false = overriding the project setting with false
-	Patchable *bool `yaml:"patchable,omitempty" bson:"patchable,omitempty"`
-	Stepback  *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+	Patchable      *bool `yaml:"patchable,omitempty" bson:"patchable,omitempty"`
+	Stepback       *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+	PauseOnFailure *bool `yaml:"pause_on_failure,omitempty" bson:"pause_on_failure,omitempty"`
+
+	ResourceLimits *TaskResourceLimits `yaml:"resource_limits,omitempty" bson:"resource_limits,omitempty"`
+	// ResourceRequests are the CPUs and memory that the task needs from a
+	// container. The scheduler packs containers onto parent hosts by them.
+	ResourceRequests *evergreen.ContainerResources `yaml:"resource_requests,omitempty" bson:"resource_requests,omitempty"`
+}
+
+// TaskResourceLimits bounds the resources that the processes a task's
+// commands start may use. The agent enforces the limits on Linux hosts
+// that support cgroups v2; a zero value means no limit.
+type TaskResourceLimits struct {
+	MemoryMB     int `yaml:"memory_mb,omitempty" bson:"memory_mb,omitempty"`
+	CPUShares    int `yaml:"cpu_shares,omitempty" bson:"cpu_shares,omitempty"`
+	MaxProcesses int `yaml:"max_processes,omitempty" bson:"max_processes,omitempty"`
+	DiskWriteMB  int `yaml:"disk_write_mb,omitempty" bson:"disk_write_mb,omitempty"`
 }
 
 // TaskIdTable is a map of [variant, task display name]->[task id].
@@ -922,6 +953,31 @@ func (p *Project) FindProjectTask(name string) *ProjectTask {
 	return nil
 }
 
+// ShouldPauseOnFailure reports whether a failed command of the task in the
+// variant holds the task for inspection. The task's setting takes precedence
+// over the variant's, which takes precedence over the project's.
+func (p *Project) ShouldPauseOnFailure(variant, taskName string) bool {
+	if pt := p.FindProjectTask(taskName); pt != nil && pt.PauseOnFailure != nil {
+		return *pt.PauseOnFailure
+	}
+	if bv := p.FindBuildVariant(variant); bv != nil && bv.PauseOnFailure != nil {
+		return *bv.PauseOnFailure
+	}
+	return p.PauseOnFailure
+}
+
+// PauseTimeout returns how long a failed task is held for inspection.
+func (p *Project) PauseTimeout() time.Duration {
+	if p.PauseTimeoutSecs <= 0 {
+		return DefaultPauseTimeout
+	}
+	timeout := time.Duration(p.PauseTimeoutSecs) * time.Second
+	if timeout > MaxPauseTimeout {
+		return MaxPauseTimeout
+	}
+	return timeout
+}
+
 func (p *Project) GetModuleByName(name string) (*Module, error) {
 	for _, v := range p.Modules {
 		if v.Name == name {
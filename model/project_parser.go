@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -37,28 +38,30 @@ import (
 // configuration YAML. It implements the Unmarshaler interface
 // to allow for flexible handling.
 type parserProject struct {
-	Enabled         bool                       `yaml:"enabled,omitempty"`
-	Stepback        bool                       `yaml:"stepback,omitempty"`
-	BatchTime       int                        `yaml:"batchtime,omitempty"`
-	Owner           string                     `yaml:"owner,omitempty"`
-	Repo            string                     `yaml:"repo,omitempty"`
-	RemotePath      string                     `yaml:"remote_path,omitempty"`
-	RepoKind        string                     `yaml:"repokind,omitempty"`
-	Branch          string                     `yaml:"branch,omitempty"`
-	Identifier      string                     `yaml:"identifier,omitempty"`
-	DisplayName     string                     `yaml:"display_name,omitempty"`
-	CommandType     string                     `yaml:"command_type,omitempty"`
-	Ignore          parserStringSlice          `yaml:"ignore,omitempty"`
-	Pre             *YAMLCommandSet            `yaml:"pre,omitempty"`
-	Post            *YAMLCommandSet            `yaml:"post,omitempty"`
-	Timeout         *YAMLCommandSet            `yaml:"timeout,omitempty"`
-	CallbackTimeout int                        `yaml:"callback_timeout_secs,omitempty"`
-	Modules         []Module                   `yaml:"modules,omitempty"`
-	BuildVariants   []parserBV                 `yaml:"buildvariants,omitempty"`
-	Functions       map[string]*YAMLCommandSet `yaml:"functions,omitempty"`
-	TaskGroups      []parserTaskGroup          `yaml:"task_groups,omitempty"`
-	Tasks           []parserTask               `yaml:"tasks,omitempty"`
-	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty"`
+	Enabled          bool                       `yaml:"enabled,omitempty"`
+	Stepback         bool                       `yaml:"stepback,omitempty"`
+	BatchTime        int                        `yaml:"batchtime,omitempty"`
+	Owner            string                     `yaml:"owner,omitempty"`
+	Repo             string                     `yaml:"repo,omitempty"`
+	RemotePath       string                     `yaml:"remote_path,omitempty"`
+	RepoKind         string                     `yaml:"repokind,omitempty"`
+	Branch           string                     `yaml:"branch,omitempty"`
+	Identifier       string                     `yaml:"identifier,omitempty"`
+	DisplayName      string                     `yaml:"display_name,omitempty"`
+	CommandType      string                     `yaml:"command_type,omitempty"`
+	Ignore           parserStringSlice          `yaml:"ignore,omitempty"`
+	Pre              *YAMLCommandSet            `yaml:"pre,omitempty"`
+	Post             *YAMLCommandSet            `yaml:"post,omitempty"`
+	Timeout          *YAMLCommandSet            `yaml:"timeout,omitempty"`
+	CallbackTimeout  int                        `yaml:"callback_timeout_secs,omitempty"`
+	Modules          []Module                   `yaml:"modules,omitempty"`
+	BuildVariants    []parserBV                 `yaml:"buildvariants,omitempty"`
+	Functions        map[string]*YAMLCommandSet `yaml:"functions,omitempty"`
+	TaskGroups       []parserTaskGroup          `yaml:"task_groups,omitempty"`
+	Tasks            []parserTask               `yaml:"tasks,omitempty"`
+	ExecTimeoutSecs  int                        `yaml:"exec_timeout_secs,omitempty"`
+	PauseOnFailure   bool                       `yaml:"pause_on_failure,omitempty"`
+	PauseTimeoutSecs int                        `yaml:"pause_timeout_secs,omitempty"`
 
 	// Matrix code
 	Axes []matrixAxis `yaml:"axes,omitempty"`
@@ -97,6 +100,10 @@ type parserTask struct {
 	Tags            parserStringSlice   `yaml:"tags,omitempty"`
 	Patchable       *bool               `yaml:"patchable,omitempty"`
 	Stepback        *bool               `yaml:"stepback,omitempty"`
+	PauseOnFailure  *bool               `yaml:"pause_on_failure,omitempty"`
+	ResourceLimits  *TaskResourceLimits `yaml:"resource_limits,omitempty"`
+
+	ResourceRequests *evergreen.ContainerResources `yaml:"resource_requests,omitempty"`
 }
 
 type displayTask struct {
@@ -248,20 +255,21 @@ func (ts *taskSelector) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // parserBV is a helper type storing intermediary variant definitions.
 type parserBV struct {
-	Name         string             `yaml:"name,omitempty"`
-	DisplayName  string             `yaml:"display_name,omitempty"`
-	Expansions   util.Expansions    `yaml:"expansions,omitempty"`
-	Tags         parserStringSlice  `yaml:"tags,omitempty,omitempty"`
-	Modules      parserStringSlice  `yaml:"modules,omitempty"`
-	Disabled     bool               `yaml:"disabled,omitempty"`
-	Push         bool               `yaml:"push,omitempty"`
-	BatchTime    *int               `yaml:"batchtime,omitempty"`
-	Stepback     *bool              `yaml:"stepback,omitempty"`
-	RunOn        parserStringSlice  `yaml:"run_on,omitempty"`
-	Tasks        parserBVTaskUnits  `yaml:"tasks,omitempty"`
-	DisplayTasks []displayTask      `yaml:"display_tasks,omitempty"`
-	DependsOn    parserDependencies `yaml:"depends_on,omitempty"`
-	Requires     taskSelectors      `yaml:"requires,omitempty"`
+	Name           string             `yaml:"name,omitempty"`
+	DisplayName    string             `yaml:"display_name,omitempty"`
+	Expansions     util.Expansions    `yaml:"expansions,omitempty"`
+	Tags           parserStringSlice  `yaml:"tags,omitempty,omitempty"`
+	Modules        parserStringSlice  `yaml:"modules,omitempty"`
+	Disabled       bool               `yaml:"disabled,omitempty"`
+	Push           bool               `yaml:"push,omitempty"`
+	BatchTime      *int               `yaml:"batchtime,omitempty"`
+	Stepback       *bool              `yaml:"stepback,omitempty"`
+	PauseOnFailure *bool              `yaml:"pause_on_failure,omitempty"`
+	RunOn          parserStringSlice  `yaml:"run_on,omitempty"`
+	Tasks          parserBVTaskUnits  `yaml:"tasks,omitempty"`
+	DisplayTasks   []displayTask      `yaml:"display_tasks,omitempty"`
+	DependsOn      parserDependencies `yaml:"depends_on,omitempty"`
+	Requires       taskSelectors      `yaml:"requires,omitempty"`
 
 	// internal matrix stuff
 	matrixId  string
@@ -463,6 +471,9 @@ func translateProject(pp *parserProject) (*Project, []error) {
 		Modules:         pp.Modules,
 		Functions:       pp.Functions,
 		ExecTimeoutSecs: pp.ExecTimeoutSecs,
+
+		PauseOnFailure:   pp.PauseOnFailure,
+		PauseTimeoutSecs: pp.PauseTimeoutSecs,
 	}
 	tse := NewParserTaskSelectorEvaluator(pp.Tasks)
 	tgse := newTaskGroupSelectorEvaluator(pp.TaskGroups)
@@ -509,6 +520,10 @@ func evaluateTaskUnits(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, v
 			Tags:            pt.Tags,
 			Patchable:       pt.Patchable,
 			Stepback:        pt.Stepback,
+			PauseOnFailure:  pt.PauseOnFailure,
+			ResourceLimits:  pt.ResourceLimits,
+
+			ResourceRequests: pt.ResourceRequests,
 		}
 		t.DependsOn, errs = evaluateDependsOn(tse.tagEval, tgse, vse, pt.DependsOn)
 		evalErrs = append(evalErrs, errs...)
@@ -564,6 +579,8 @@ func evaluateBuildVariants(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluato
 			Stepback:    pbv.Stepback,
 			RunOn:       pbv.RunOn,
 			Tags:        pbv.Tags,
+
+			PauseOnFailure: pbv.PauseOnFailure,
 		}
 		bv.Tasks, errs = evaluateBVTasks(tse, tgse, vse, pbv)
 		// evaluate any rules passed in during matrix construction
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
@@ -1115,3 +1116,40 @@ buildvariants:
 	assert.Equal("task_3", proj.BuildVariants[2].Tasks[0].Requires[0].Name)
 	assert.Equal("task_3", proj.BuildVariants[2].Tasks[1].Requires[0].Name)
 }
+
+func TestPauseOnFailure(t *testing.T) {
+	assert := assert.New(t)
+	yml := `
+pause_on_failure: true
+pause_timeout_secs: 600
+tasks:
+- name: compile
+- name: lint
+  pause_on_failure: false
+- name: test
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+  - name: lint
+  - name: test
+- name: windows
+  pause_on_failure: false
+  tasks:
+  - name: compile
+  - name: test
+`
+	proj, errs := projectFromYAML([]byte(yml))
+	assert.NotNil(proj)
+	assert.Empty(errs)
+
+	assert.True(proj.ShouldPauseOnFailure("linux", "compile"))
+	assert.False(proj.ShouldPauseOnFailure("linux", "lint"), "the task setting overrides the project")
+	assert.False(proj.ShouldPauseOnFailure("windows", "test"), "the variant setting overrides the project")
+	assert.Equal(10*time.Minute, proj.PauseTimeout())
+
+	proj.PauseTimeoutSecs = 0
+	assert.Equal(DefaultPauseTimeout, proj.PauseTimeout())
+	proj.PauseTimeoutSecs = int((24 * time.Hour).Seconds())
+	assert.Equal(MaxPauseTimeout, proj.PauseTimeout())
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
@@ -36,6 +37,15 @@ type ProjectRef struct {
 
 	PRTestingEnabled bool `bson:"pr_testing_enabled" json:"pr_testing_enabled" yaml:"pr_testing_enabled"`
 
+	// AbortSupersededPRPatches, if true, aborts the unfinished tasks of a
+	// pull request's earlier patches when a new push creates a patch, and
+	// marks their head commits as superseded.
+	AbortSupersededPRPatches bool `bson:"abort_superseded_pr_patches" json:"abort_superseded_pr_patches" yaml:"abort_superseded_pr_patches"`
+
+	// GithubChecksEnabled, if true, reports finished builds of pull request
+	// patches and mainline commits as Github check runs.
+	GithubChecksEnabled bool `bson:"github_checks_enabled" json:"github_checks_enabled" yaml:"github_checks_enabled"`
+
 	//Tracked determines whether or not the project is discoverable in the UI
 	Tracked          bool `bson:"tracked" json:"tracked"`
 	PatchingDisabled bool `bson:"patching_disabled" json:"patching_disabled"`
@@ -43,9 +53,21 @@ type ProjectRef struct {
 	// Admins contain a list of users who are able to access the projects page.
 	Admins []string `bson:"admins" json:"admins"`
 
+	// DebugSessionsEnabled lets the project's admins open interactive
+	// debug sessions into its running tasks.
+	DebugSessionsEnabled bool `bson:"debug_sessions_enabled" json:"debug_sessions_enabled"`
+
 	// TODO: remove the alerts field above
 	NotifyOnBuildFailure bool `bson:"notify_on_failure" json:"notify_on_failure"`
 
+	// ArtifactRetention contains the rules that determine when the files
+	// attached by this project's tasks are deleted.
+	ArtifactRetention []artifact.RetentionRule `bson:"artifact_retention" json:"artifact_retention"`
+
+	// PriorityPolicy adjusts the priorities of the project's undispatched
+	// tasks and deactivates stale patch tasks.
+	PriorityPolicy PriorityPolicy `bson:"priority_policy,omitempty" json:"priority_policy"`
+
 	// RepoDetails contain the details of the status of the consistency
 	// between what is in GitHub and what is in Evergreen
 	RepotrackerError *RepositoryErrorDetails `bson:"repotracker_error" json:"repotracker_error"`
@@ -98,8 +120,13 @@ var (
 	ProjectRefAdminsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
 	projectRefTracksPushEventsKey   = bsonutil.MustHaveTag(ProjectRef{}, "TracksPushEvents")
 	projectRefPRTestingEnabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PRTestingEnabled")
+	projectRefAbortSupersededKey    = bsonutil.MustHaveTag(ProjectRef{}, "AbortSupersededPRPatches")
+	projectRefGithubChecksKey       = bsonutil.MustHaveTag(ProjectRef{}, "GithubChecksEnabled")
+	projectRefDebugSessionsKey      = bsonutil.MustHaveTag(ProjectRef{}, "DebugSessionsEnabled")
 	projectRefPatchingDisabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PatchingDisabled")
 	projectRefNotifyOnFailureKey    = bsonutil.MustHaveTag(ProjectRef{}, "NotifyOnBuildFailure")
+	ProjectRefArtifactRetentionKey  = bsonutil.MustHaveTag(ProjectRef{}, "ArtifactRetention")
+	ProjectRefPriorityPolicyKey     = bsonutil.MustHaveTag(ProjectRef{}, "PriorityPolicy")
 )
 
 const (
@@ -175,6 +202,48 @@ func FindAllProjectRefs() ([]ProjectRef, error) {
 	return projectRefs, err
 }
 
+// FindProjectRefsWithArtifactRetention returns all project refs that have
+// at least one artifact retention rule.
+func FindProjectRefsWithArtifactRetention() ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAll(
+		ProjectRefCollection,
+		bson.M{
+			bsonutil.GetDottedKeyName(ProjectRefArtifactRetentionKey, "0"): bson.M{
+				"$exists": true,
+			},
+		},
+		db.NoProjection,
+		db.NoSort,
+		db.NoSkip,
+		db.NoLimit,
+		&projectRefs,
+	)
+	return projectRefs, err
+}
+
+// FindProjectRefsWithPriorityPolicy returns all enabled project refs that
+// have a priority policy.
+func FindProjectRefsWithPriorityPolicy() ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAll(
+		ProjectRefCollection,
+		bson.M{
+			ProjectRefEnabledKey: true,
+			ProjectRefPriorityPolicyKey: bson.M{
+				"$exists": true,
+				"$ne":     bson.M{},
+			},
+		},
+		db.NoProjection,
+		db.NoSort,
+		db.NoSkip,
+		db.NoLimit,
+		&projectRefs,
+	)
+	return projectRefs, err
+}
+
 // FindProjectRefsByRepoAndBranch finds ProjectRefs with matching repo/branch
 // that are enabled and setup for PR testing
 func FindProjectRefsByRepoAndBranch(owner, repoName, branch string) ([]ProjectRef, error) {
@@ -308,8 +377,13 @@ func (projectRef *ProjectRef) Upsert() error {
 				ProjectRefAdminsKey:             projectRef.Admins,
 				projectRefTracksPushEventsKey:   projectRef.TracksPushEvents,
 				projectRefPRTestingEnabledKey:   projectRef.PRTestingEnabled,
+				projectRefAbortSupersededKey:    projectRef.AbortSupersededPRPatches,
+				projectRefGithubChecksKey:       projectRef.GithubChecksEnabled,
 				projectRefPatchingDisabledKey:   projectRef.PatchingDisabled,
+				projectRefDebugSessionsKey:      projectRef.DebugSessionsEnabled,
 				projectRefNotifyOnFailureKey:    projectRef.NotifyOnBuildFailure,
+				ProjectRefArtifactRetentionKey:  projectRef.ArtifactRetention,
+				ProjectRefPriorityPolicyKey:     projectRef.PriorityPolicy,
 			},
 		},
 	)
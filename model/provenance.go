@@ -0,0 +1,337 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/provenance"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// distroImageSettings are the provider settings that identify the image
+// a host was started from, in order of preference.
+var distroImageSettings = []string{"ami", "image", "image_url", "image_name", "image_id"}
+
+// BuildVersionProvenance assembles the provenance document of a version
+// from its finished tasks. uiURL identifies the evergreen instance that
+// built the version.
+func BuildVersionProvenance(versionId, uiURL string) (*provenance.Provenance, error) {
+	v, err := version.FindOne(version.ById(versionId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding version '%s'", versionId)
+	}
+	if v == nil {
+		return nil, errors.Errorf("version '%s' not found", versionId)
+	}
+
+	proj := &Project{}
+	if err = LoadProjectInto([]byte(v.Config), v.Identifier, proj); err != nil {
+		return nil, errors.Wrap(err, "problem loading project")
+	}
+	projRef, err := FindOneProjectRef(v.Identifier)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding project ref")
+	}
+	if projRef == nil {
+		return nil, errors.Errorf("project '%s' not found", v.Identifier)
+	}
+
+	var p *patch.Patch
+	if evergreen.IsPatchRequester(v.Requester) {
+		p, err = patch.FindOne(patch.ByVersion(v.Id))
+		if err != nil {
+			return nil, errors.Wrap(err, "problem finding patch")
+		}
+		if p != nil {
+			if err = p.FetchPatchFiles(); err != nil {
+				return nil, errors.Wrap(err, "problem fetching patch diffs")
+			}
+		}
+	}
+
+	vars, err := FindOneProjectVars(v.Identifier)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding project variables")
+	}
+
+	m, err := manifest.FindOne(manifest.ById(v.Id))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding manifest")
+	}
+
+	tasks, err := task.Find(task.ByVersion(v.Id))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding tasks")
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Id < tasks[j].Id })
+
+	doc := &provenance.Provenance{
+		Id:            v.Id,
+		Type:          provenance.StatementType,
+		PredicateType: provenance.PredicateType,
+		Subject:       []provenance.Subject{},
+		Predicate: provenance.Predicate{
+			Builder:   provenance.Builder{Id: uiURL},
+			BuildType: provenance.BuildType,
+			Invocation: provenance.Invocation{
+				ConfigSource: provenance.ConfigSource{
+					URI:        gitMaterialURI(v.Owner, v.Repo, v.Branch),
+					Digest:     map[string]string{"sha1": v.Revision},
+					EntryPoint: projRef.RemotePath,
+				},
+				Parameters: invocationParameters(v, vars),
+			},
+			BuildConfig: provenance.BuildConfig{
+				Version:   v.Id,
+				Project:   v.Identifier,
+				Requester: v.Requester,
+				Patches:   patchDiffs(p),
+				Tasks:     []provenance.Task{},
+			},
+			Metadata: provenance.Metadata{
+				BuildInvocationId: fmt.Sprintf("%s/version/%s", uiURL, v.Id),
+				BuildStartedOn:    v.StartTime,
+				BuildFinishedOn:   v.FinishTime,
+			},
+			Materials: versionMaterials(v, m),
+		},
+	}
+
+	distros := map[string]distro.Distro{}
+	for i := range tasks {
+		t := &tasks[i]
+		if t.DisplayOnly || !t.IsFinished() {
+			continue
+		}
+
+		record := provenance.Task{
+			Id:           t.Id,
+			DisplayName:  t.DisplayName,
+			BuildVariant: t.BuildVariant,
+			Execution:    t.Execution,
+			Status:       t.Status,
+			Distro:       t.DistroId,
+			Host:         t.HostId,
+		}
+
+		d, ok := distros[t.DistroId]
+		if t.HostId != "" {
+			var h *host.Host
+			h, err = host.FindOneId(t.HostId)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem finding host for task '%s'", t.Id)
+			}
+			if h != nil {
+				// the host records the distro as it was when the host started
+				d, ok = h.Distro, true
+				record.AgentRevision = h.AgentRevision
+			}
+		}
+		if !ok {
+			d, err = distro.FindOne(distro.ById(t.DistroId))
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem finding distro '%s' for task '%s'", t.DistroId, t.Id)
+			}
+			distros[t.DistroId] = d
+		}
+		record.Image = distroImage(d)
+		record.Commands = taskCommands(proj, t.TaskGroup, t.DisplayName, t.BuildVariant)
+
+		var entry *artifact.Entry
+		entry, err = artifact.FindOne(artifact.ByTaskIdAndExecution(t.Id, t.Execution))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding artifacts for task '%s'", t.Id)
+		}
+		if entry != nil {
+			for _, f := range entry.Files {
+				record.Artifacts = append(record.Artifacts, provenance.Artifact{
+					Name:   f.Name,
+					Link:   f.Link,
+					SHA256: f.SHA256,
+				})
+				if f.SHA256 != "" && !f.Expired {
+					doc.Subject = append(doc.Subject, provenance.Subject{
+						Name:   f.Link,
+						Digest: map[string]string{"sha256": f.SHA256},
+					})
+				}
+			}
+		}
+
+		doc.Predicate.BuildConfig.Tasks = append(doc.Predicate.BuildConfig.Tasks, record)
+	}
+
+	return doc, nil
+}
+
+// invocationParameters records who requested a version and the project's
+// expansions, with the values of private expansions redacted.
+func invocationParameters(v *version.Version, vars *ProjectVars) map[string]string {
+	params := map[string]string{
+		"requester": v.Requester,
+		"author":    v.Author,
+	}
+	if vars == nil {
+		return params
+	}
+	for k, val := range vars.Vars {
+		if vars.PrivateVars[k] {
+			val = provenance.RedactedParameter
+		}
+		params[provenance.ExpansionParameterPrefix+k] = val
+	}
+	return params
+}
+
+func gitMaterialURI(owner, repo, branch string) string {
+	uri := fmt.Sprintf("git+https://github.com/%s/%s", owner, repo)
+	if branch != "" {
+		uri += "@" + branch
+	}
+	return uri
+}
+
+func versionMaterials(v *version.Version, m *manifest.Manifest) []provenance.Material {
+	materials := []provenance.Material{{
+		URI:    gitMaterialURI(v.Owner, v.Repo, v.Branch),
+		Digest: map[string]string{"sha1": v.Revision},
+	}}
+	if m == nil {
+		return materials
+	}
+
+	names := make([]string, 0, len(m.Modules))
+	for name := range m.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		module := m.Modules[name]
+		if module == nil {
+			continue
+		}
+		materials = append(materials, provenance.Material{
+			URI:    gitMaterialURI(module.Owner, module.Repo, module.Branch),
+			Digest: map[string]string{"sha1": module.Revision},
+		})
+	}
+	return materials
+}
+
+func patchDiffs(p *patch.Patch) []provenance.Patch {
+	if p == nil {
+		return nil
+	}
+	diffs := make([]provenance.Patch, 0, len(p.Patches))
+	for _, part := range p.Patches {
+		diffs = append(diffs, provenance.Patch{
+			Module:  part.ModuleName,
+			Githash: part.Githash,
+			Diff:    part.PatchSet.Patch,
+		})
+	}
+	return diffs
+}
+
+func distroImage(d distro.Distro) string {
+	if d.ProviderSettings == nil {
+		return ""
+	}
+	for _, key := range distroImageSettings {
+		if image, ok := (*d.ProviderSettings)[key].(string); ok && image != "" {
+			return image
+		}
+	}
+	return ""
+}
+
+// taskCommands lists the commands a task runs on a build variant, in the
+// order the agent runs them, including the commands of any functions it
+// calls. Tasks in a task group run the group's setup and teardown commands;
+// other tasks run the project's pre and post commands. The setup_group and
+// teardown_group commands only run for the group's first and last tasks,
+// and the timeout commands only run if the task times out, but all are
+// listed.
+func taskCommands(p *Project, taskGroup, taskName, variant string) []provenance.Command {
+	tg := &TaskGroup{
+		SetupTask:    p.Pre,
+		TeardownTask: p.Post,
+		Timeout:      p.Timeout,
+	}
+	if taskGroup != "" {
+		if found := p.FindTaskGroup(taskGroup); found != nil {
+			tg = found
+		}
+	}
+
+	taskCmds := []PluginCommandConf{}
+	if pt := p.FindProjectTask(taskName); pt != nil {
+		taskCmds = pt.Commands
+	}
+
+	blocks := []struct {
+		name string
+		cmds []PluginCommandConf
+	}{
+		{name: provenance.BlockSetupGroup, cmds: commandSetList(tg.SetupGroup)},
+		{name: provenance.BlockSetupTask, cmds: commandSetList(tg.SetupTask)},
+		{name: provenance.BlockTask, cmds: taskCmds},
+		{name: provenance.BlockTeardownTask, cmds: commandSetList(tg.TeardownTask)},
+		{name: provenance.BlockTeardownGroup, cmds: commandSetList(tg.TeardownGroup)},
+		{name: provenance.BlockTimeout, cmds: commandSetList(tg.Timeout)},
+	}
+
+	runsOn := func(cmd PluginCommandConf) bool {
+		return len(cmd.Variants) == 0 || util.StringSliceContains(cmd.Variants, variant)
+	}
+
+	out := []provenance.Command{}
+	for _, block := range blocks {
+		for _, cmd := range block.cmds {
+			if !runsOn(cmd) {
+				continue
+			}
+			if cmd.Function == "" {
+				out = append(out, provenance.Command{
+					Block:       block.name,
+					Command:     cmd.Command,
+					DisplayName: cmd.DisplayName,
+				})
+				continue
+			}
+
+			fn, ok := p.Functions[cmd.Function]
+			if !ok || fn == nil {
+				continue
+			}
+			for _, fnCmd := range fn.List() {
+				if !runsOn(fnCmd) {
+					continue
+				}
+				out = append(out, provenance.Command{
+					Block:       block.name,
+					Command:     fnCmd.Command,
+					Function:    cmd.Function,
+					DisplayName: fnCmd.DisplayName,
+				})
+			}
+		}
+	}
+	return out
+}
+
+func commandSetList(set *YAMLCommandSet) []PluginCommandConf {
+	if set == nil {
+		return nil
+	}
+	return set.List()
+}
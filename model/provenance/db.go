@@ -0,0 +1,34 @@
+package provenance
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var (
+	IdKey = bsonutil.MustHaveTag(Provenance{}, "Id")
+)
+
+// FindOne gets one Provenance for the given query.
+func FindOne(query db.Q) (*Provenance, error) {
+	p := &Provenance{}
+	err := db.FindOneQ(Collection, query, p)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return p, err
+}
+
+// ById returns a query that contains an Id selector on the string, id.
+func ById(id string) db.Q {
+	return db.Query(bson.D{{Name: IdKey, Value: id}})
+}
+
+// Upsert writes the provenance to the database, replacing the provenance
+// of an earlier run of the version.
+func (p *Provenance) Upsert() error {
+	_, err := db.Upsert(Collection, bson.M{IdKey: p.Id}, p)
+	return err
+}
@@ -0,0 +1,139 @@
+package provenance
+
+import "time"
+
+const (
+	Collection = "provenance"
+
+	// StatementType and PredicateType identify the document as an in-toto
+	// statement carrying a SLSA provenance predicate.
+	StatementType = "https://in-toto.io/Statement/v0.1"
+	PredicateType = "https://slsa.dev/provenance/v0.2"
+
+	// BuildType identifies the schema of the evergreen specific build
+	// configuration recorded in the predicate.
+	BuildType = "https://github.com/evergreen-ci/evergreen/provenance/version/v1"
+
+	// ExpansionParameterPrefix prefixes the names of the invocation
+	// parameters that record the project's expansions, and
+	// RedactedParameter replaces the values of private expansions.
+	ExpansionParameterPrefix = "expansions."
+	RedactedParameter        = "[redacted]"
+)
+
+// The blocks of a task's configuration that its commands come from, in the
+// order that the agent runs them. Timeout commands only run if the task
+// times out.
+const (
+	BlockSetupGroup    = "setup_group"
+	BlockSetupTask     = "setup_task"
+	BlockTask          = "task"
+	BlockTeardownTask  = "teardown_task"
+	BlockTeardownGroup = "teardown_group"
+	BlockTimeout       = "timeout"
+)
+
+// Provenance records what built a version: the source it was built
+// from, and for every task the image and agent it ran on, the commands it
+// ran and the checksums of the files it uploaded. Id is the version id.
+type Provenance struct {
+	Id            string    `bson:"_id" json:"-"`
+	Type          string    `bson:"type" json:"_type"`
+	Subject       []Subject `bson:"subject" json:"subject"`
+	PredicateType string    `bson:"predicate_type" json:"predicateType"`
+	Predicate     Predicate `bson:"predicate" json:"predicate"`
+}
+
+// Subject is an artifact described by the provenance, identified by its
+// link and digests.
+type Subject struct {
+	Name   string            `bson:"name" json:"name"`
+	Digest map[string]string `bson:"digest" json:"digest"`
+}
+
+type Predicate struct {
+	Builder     Builder     `bson:"builder" json:"builder"`
+	BuildType   string      `bson:"build_type" json:"buildType"`
+	Invocation  Invocation  `bson:"invocation" json:"invocation"`
+	BuildConfig BuildConfig `bson:"build_config" json:"buildConfig"`
+	Metadata    Metadata    `bson:"metadata" json:"metadata"`
+	Materials   []Material  `bson:"materials" json:"materials"`
+}
+
+// Builder identifies the evergreen instance that built the version.
+type Builder struct {
+	Id string `bson:"id" json:"id"`
+}
+
+type Invocation struct {
+	ConfigSource ConfigSource      `bson:"config_source" json:"configSource"`
+	Parameters   map[string]string `bson:"parameters" json:"parameters"`
+}
+
+// ConfigSource is the project configuration file the version was
+// created from.
+type ConfigSource struct {
+	URI        string            `bson:"uri" json:"uri"`
+	Digest     map[string]string `bson:"digest" json:"digest"`
+	EntryPoint string            `bson:"entry_point" json:"entryPoint"`
+}
+
+type Metadata struct {
+	BuildInvocationId string    `bson:"build_invocation_id" json:"buildInvocationId"`
+	BuildStartedOn    time.Time `bson:"build_started_on" json:"buildStartedOn"`
+	BuildFinishedOn   time.Time `bson:"build_finished_on" json:"buildFinishedOn"`
+}
+
+// Material is a source repository, or a module, checked out by the
+// version's tasks.
+type Material struct {
+	URI    string            `bson:"uri" json:"uri"`
+	Digest map[string]string `bson:"digest" json:"digest"`
+}
+
+type BuildConfig struct {
+	Version   string  `bson:"version" json:"version"`
+	Project   string  `bson:"project" json:"project"`
+	Requester string  `bson:"requester" json:"requester"`
+	Patches   []Patch `bson:"patches,omitempty" json:"patches,omitempty"`
+	Tasks     []Task  `bson:"tasks" json:"tasks"`
+}
+
+// Patch is the diff applied to the repository, or to one of its
+// modules, by a patch build.
+type Patch struct {
+	Module  string `bson:"module,omitempty" json:"module,omitempty"`
+	Githash string `bson:"githash" json:"githash"`
+	Diff    string `bson:"diff" json:"diff"`
+}
+
+// Task records how a single task execution ran.
+type Task struct {
+	Id            string     `bson:"id" json:"id"`
+	DisplayName   string     `bson:"display_name" json:"display_name"`
+	BuildVariant  string     `bson:"build_variant" json:"build_variant"`
+	Execution     int        `bson:"execution" json:"execution"`
+	Status        string     `bson:"status" json:"status"`
+	Distro        string     `bson:"distro" json:"distro"`
+	Image         string     `bson:"image,omitempty" json:"image,omitempty"`
+	Host          string     `bson:"host,omitempty" json:"host,omitempty"`
+	AgentRevision string     `bson:"agent_revision,omitempty" json:"agent_revision,omitempty"`
+	Commands      []Command  `bson:"commands" json:"commands"`
+	Artifacts     []Artifact `bson:"artifacts,omitempty" json:"artifacts,omitempty"`
+}
+
+// Command is a command run by a task. Block is the part of the task's
+// configuration the command comes from, and Function is set for commands
+// that were run as part of a function.
+type Command struct {
+	Block       string `bson:"block" json:"block"`
+	Command     string `bson:"command" json:"command"`
+	Function    string `bson:"function,omitempty" json:"function,omitempty"`
+	DisplayName string `bson:"display_name,omitempty" json:"display_name,omitempty"`
+}
+
+type Artifact struct {
+	Name   string `bson:"name" json:"name"`
+	Link   string `bson:"link" json:"link"`
+	SHA256 string `bson:"sha256,omitempty" json:"sha256,omitempty"`
+}
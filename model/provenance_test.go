@@ -0,0 +1,145 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/provenance"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenanceTaskCommands(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	proj := &Project{}
+	require.NoError(LoadProjectInto([]byte(`
+pre:
+  - command: git.get_project
+functions:
+  build:
+    - command: shell.exec
+      display_name: compile
+    - command: shell.exec
+      display_name: windows only
+      variants: ["windows"]
+tasks:
+  - name: compile
+    commands:
+      - func: build
+      - command: s3.put
+      - command: attach.results
+        variants: ["windows"]
+  - name: test
+    commands:
+      - command: shell.exec
+        display_name: test
+post:
+  - command: attach.xunit_results
+timeout:
+  - command: shell.exec
+    display_name: dump stacks
+task_groups:
+  - name: tg
+    setup_group:
+      - command: git.get_project
+    setup_task:
+      - func: build
+    tasks: ["test"]
+    teardown_task:
+      - command: attach.results
+    teardown_group:
+      - command: shell.exec
+        display_name: clean up
+    timeout:
+      - command: shell.exec
+        display_name: group timeout
+`), "proj", proj))
+
+	cmds := taskCommands(proj, "", "compile", "linux")
+	assert.Equal([]provenance.Command{
+		{Block: provenance.BlockSetupTask, Command: "git.get_project"},
+		{Block: provenance.BlockTask, Command: "shell.exec", Function: "build", DisplayName: "compile"},
+		{Block: provenance.BlockTask, Command: "s3.put"},
+		{Block: provenance.BlockTeardownTask, Command: "attach.xunit_results"},
+		{Block: provenance.BlockTimeout, Command: "shell.exec", DisplayName: "dump stacks"},
+	}, cmds)
+
+	cmds = taskCommands(proj, "", "compile", "windows")
+	assert.Len(cmds, 7)
+
+	cmds = taskCommands(proj, "", "missing", "linux")
+	assert.Len(cmds, 3)
+
+	cmds = taskCommands(proj, "tg", "test", "linux")
+	assert.Equal([]provenance.Command{
+		{Block: provenance.BlockSetupGroup, Command: "git.get_project"},
+		{Block: provenance.BlockSetupTask, Command: "shell.exec", Function: "build", DisplayName: "compile"},
+		{Block: provenance.BlockTask, Command: "shell.exec", DisplayName: "test"},
+		{Block: provenance.BlockTeardownTask, Command: "attach.results"},
+		{Block: provenance.BlockTeardownGroup, Command: "shell.exec", DisplayName: "clean up"},
+		{Block: provenance.BlockTimeout, Command: "shell.exec", DisplayName: "group timeout"},
+	}, cmds)
+}
+
+func TestProvenanceDistroImage(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(distroImage(distro.Distro{Id: "d"}))
+
+	settings := map[string]interface{}{"ami": "ami-123", "instance_type": "m4.large"}
+	assert.Equal("ami-123", distroImage(distro.Distro{ProviderSettings: &settings}))
+
+	settings = map[string]interface{}{"image_url": "https://example.com/image.tgz"}
+	assert.Equal("https://example.com/image.tgz", distroImage(distro.Distro{ProviderSettings: &settings}))
+}
+
+func TestProvenanceMaterials(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &version.Version{Owner: "evergreen-ci", Repo: "evergreen", Branch: "master", Revision: "abc"}
+	materials := versionMaterials(v, nil)
+	assert.Equal([]provenance.Material{
+		{URI: "git+https://github.com/evergreen-ci/evergreen@master", Digest: map[string]string{"sha1": "abc"}},
+	}, materials)
+
+	m := &manifest.Manifest{Modules: map[string]*manifest.Module{
+		"b": {Owner: "o", Repo: "b", Branch: "master", Revision: "bbb"},
+		"a": {Owner: "o", Repo: "a", Branch: "master", Revision: "aaa"},
+	}}
+	materials = versionMaterials(v, m)
+	assert.Len(materials, 3)
+	assert.Equal("git+https://github.com/o/a@master", materials[1].URI)
+	assert.Equal("bbb", materials[2].Digest["sha1"])
+
+	assert.Nil(patchDiffs(nil))
+	diffs := patchDiffs(&patch.Patch{Patches: []patch.ModulePatch{
+		{Githash: "abc", PatchSet: patch.PatchSet{Patch: "diff --git"}},
+	}})
+	assert.Equal([]provenance.Patch{{Githash: "abc", Diff: "diff --git"}}, diffs)
+}
+
+func TestProvenanceInvocationParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &version.Version{Requester: "gitter_request", Author: "octocat"}
+	assert.Equal(map[string]string{"requester": "gitter_request", "author": "octocat"},
+		invocationParameters(v, nil))
+
+	vars := &ProjectVars{
+		Vars:        map[string]string{"region": "us-east-1", "aws_secret": "hunter2"},
+		PrivateVars: map[string]bool{"aws_secret": true},
+	}
+	params := invocationParameters(v, vars)
+	assert.Equal("octocat", params["author"])
+	assert.Equal("us-east-1", params["expansions.region"])
+	assert.Equal(provenance.RedactedParameter, params["expansions.aws_secret"])
+	for _, val := range params {
+		assert.NotContains(val, "hunter2")
+	}
+	assert.Equal("hunter2", vars.Vars["aws_secret"])
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	StatsMetricsCollection = "stats_metrics"
+
+	// statsMetricsMaxAge is how long the metrics published by a collector
+	// are served for, so that the metrics of app servers that have gone
+	// away stop being served.
+	statsMetricsMaxAge = time.Hour
+)
+
+// StatsMetrics are the metrics that a stats collector last published. The
+// stats collectors run on whichever app server picks them up, so their
+// metrics are stored, rather than kept in memory, for every app server to
+// serve. Id identifies the collector.
+type StatsMetrics struct {
+	Id          string              `bson:"_id" json:"id"`
+	CollectedAt time.Time           `bson:"collected_at" json:"collected_at"`
+	Families    []util.MetricFamily `bson:"families" json:"families"`
+}
+
+var (
+	StatsMetricsIdKey          = bsonutil.MustHaveTag(StatsMetrics{}, "Id")
+	StatsMetricsCollectedAtKey = bsonutil.MustHaveTag(StatsMetrics{}, "CollectedAt")
+	StatsMetricsFamiliesKey    = bsonutil.MustHaveTag(StatsMetrics{}, "Families")
+)
+
+// SaveStatsMetrics replaces the metrics that a collector published.
+func SaveStatsMetrics(collector string, families []util.MetricFamily, collectedAt time.Time) error {
+	_, err := db.Upsert(StatsMetricsCollection,
+		bson.M{StatsMetricsIdKey: collector},
+		bson.M{"$set": bson.M{
+			StatsMetricsCollectedAtKey: collectedAt,
+			StatsMetricsFamiliesKey:    families,
+		}},
+	)
+	return errors.Wrapf(err, "problem saving metrics of '%s'", collector)
+}
+
+// FindStatsMetrics returns a registry holding the metrics that every
+// collector published within the last hour.
+func FindStatsMetrics(now time.Time) (*util.MetricsRegistry, error) {
+	stored := []StatsMetrics{}
+	err := db.FindAll(StatsMetricsCollection,
+		bson.M{StatsMetricsCollectedAtKey: bson.M{"$gte": now.Add(-statsMetricsMaxAge)}},
+		db.NoProjection,
+		[]string{StatsMetricsIdKey},
+		db.NoSkip,
+		db.NoLimit,
+		&stored,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding stats metrics")
+	}
+
+	metrics := util.NewMetricsRegistry()
+	for _, s := range stored {
+		for _, f := range s.Families {
+			metrics.MergeFamily(f)
+		}
+	}
+	return metrics, nil
+}
@@ -34,9 +34,12 @@ var (
 	ProjectKey              = bsonutil.MustHaveTag(Task{}, "Project")
 	RevisionKey             = bsonutil.MustHaveTag(Task{}, "Revision")
 	LastHeartbeatKey        = bsonutil.MustHaveTag(Task{}, "LastHeartbeat")
+	PausedUntilKey          = bsonutil.MustHaveTag(Task{}, "PausedUntil")
 	ActivatedKey            = bsonutil.MustHaveTag(Task{}, "Activated")
 	BuildIdKey              = bsonutil.MustHaveTag(Task{}, "BuildId")
 	DistroIdKey             = bsonutil.MustHaveTag(Task{}, "DistroId")
+	SecondaryDistrosKey     = bsonutil.MustHaveTag(Task{}, "SecondaryDistros")
+	PrimaryDistroIdKey      = bsonutil.MustHaveTag(Task{}, "PrimaryDistroId")
 	BuildVariantKey         = bsonutil.MustHaveTag(Task{}, "BuildVariant")
 	DependsOnKey            = bsonutil.MustHaveTag(Task{}, "DependsOn")
 	OverrideDependenciesKey = bsonutil.MustHaveTag(Task{}, "OverrideDependencies")
@@ -93,7 +96,7 @@ var All = db.Query(nil)
 
 var (
 	SelectorTaskInProgress = bson.M{
-		"$in": []string{evergreen.TaskStarted, evergreen.TaskDispatched},
+		"$in": []string{evergreen.TaskStarted, evergreen.TaskDispatched, evergreen.TaskPaused},
 	}
 
 	FinishedOpts = []bson.M{{
@@ -402,6 +405,43 @@ func ByRecentlyFinished(finishTime time.Time, project string, requester string)
 	return db.Query(query)
 }
 
+// ByFinishedBefore finds a project's completed tasks with one of the given
+// requesters that finished before the cutoff, in order of id. If afterID is
+// not empty, only tasks with greater ids are found, so that the tasks can be
+// paged through in batches.
+func ByFinishedBefore(cutoff time.Time, project string, requesters []string, afterID string) db.Q {
+	query := bson.M{
+		ProjectKey: project,
+		RequesterKey: bson.M{
+			"$in": requesters,
+		},
+		StatusKey: bson.M{
+			"$in": evergreen.CompletedStatuses,
+		},
+		FinishTimeKey: bson.M{
+			"$lt": cutoff,
+		},
+	}
+	if afterID != "" {
+		query[IdKey] = bson.M{"$gt": afterID}
+	}
+	return db.Query(query).Sort([]string{IdKey})
+}
+
+// ByFinishedOnDistroSince finds the completed tasks that finished on a distro
+// since the given time, with the fields needed to account for its use.
+func ByFinishedOnDistroSince(distroID string, since time.Time) db.Q {
+	return db.Query(bson.M{
+		DistroIdKey: distroID,
+		StatusKey: bson.M{
+			"$in": evergreen.CompletedStatuses,
+		},
+		FinishTimeKey: bson.M{
+			"$gte": since,
+		},
+	}).WithFields(ProjectKey, TimeTakenKey, FinishTimeKey)
+}
+
 func ByDispatchedWithIdsVersionAndStatus(taskIds []string, versionId string, statuses []string) db.Q {
 	return db.Query(bson.M{
 		IdKey: bson.M{
@@ -421,7 +461,7 @@ func ByExecutionTask(taskId string) db.Q {
 
 var (
 	IsDispatchedOrStarted = db.Query(bson.M{
-		StatusKey: bson.M{"$in": []string{evergreen.TaskStarted, evergreen.TaskDispatched}},
+		StatusKey: bson.M{"$in": []string{evergreen.TaskStarted, evergreen.TaskDispatched, evergreen.TaskPaused}},
 	})
 )
 
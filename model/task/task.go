@@ -16,6 +16,7 @@ import (
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	"github.com/tychoish/tarjan"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -75,15 +76,25 @@ type Task struct {
 	TaskGroup         string `bson:"task_group" json:"task_group"`
 	TaskGroupMaxHosts int    `bson:"task_group_max_hosts,omitempty" json:"task_group_max_hosts,omitempty"`
 
+	// ResourceRequests are the CPUs and memory that the task needs from a
+	// container, as declared in the project
+	ResourceRequests *evergreen.ContainerResources `bson:"resource_requests,omitempty" json:"resource_requests,omitempty"`
+
 	// only relevant if the task is runnin.  the time of the last heartbeat
 	// sent back by the agent
 	LastHeartbeat time.Time `bson:"last_heartbeat"`
 
+	// only relevant if the task is paused. the time after which the agent
+	// stops holding the task and runs its post commands
+	PausedUntil time.Time `bson:"paused_until,omitempty" json:"paused_until,omitempty"`
+
 	// used to indicate whether task should be scheduled to run
 	Activated            bool         `bson:"activated" json:"activated"`
 	ActivatedBy          string       `bson:"activated_by" json:"activated_by"`
 	BuildId              string       `bson:"build_id" json:"build_id"`
 	DistroId             string       `bson:"distro" json:"distro"`
+	SecondaryDistros     []string     `bson:"secondary_distros,omitempty" json:"secondary_distros,omitempty"`
+	PrimaryDistroId      string       `bson:"primary_distro,omitempty" json:"primary_distro,omitempty"`
 	BuildVariant         string       `bson:"build_variant" json:"build_variant"`
 	DependsOn            []Dependency `bson:"depends_on" json:"depends_on"`
 	NumDependents        int          `bson:"num_dependents,omitempty" json:"num_dependents,omitempty"`
@@ -146,6 +157,10 @@ type Task struct {
 	GenerateTask bool `bson:"generate_task,omitempty" json:"generate_task,omitempty"`
 	// GeneratedBy, if present, is the ID of the task that generated this task.
 	GeneratedBy string `bson:"generated_by,omitempty" json:"generated_by,omitempty"`
+
+	// TraceParent is the W3C trace context of the version that created
+	// the task, which spans of the task's execution descend from.
+	TraceParent string `bson:"trace_parent,omitempty" json:"trace_parent,omitempty"`
 }
 
 // Dependency represents a task that must be completed before the owning
@@ -220,6 +235,12 @@ type TestResult struct {
 	StartTime float64 `json:"start" bson:"start"`
 	EndTime   float64 `json:"end" bson:"end"`
 
+	// SourceFile and SourceLine locate a failure in the code under test,
+	// and Message describes it, when the results format reports them.
+	SourceFile string `json:"source_file,omitempty" bson:"source_file,omitempty"`
+	SourceLine int    `json:"source_line,omitempty" bson:"source_line,omitempty"`
+	Message    string `json:"message,omitempty" bson:"message,omitempty"`
+
 	// LogRaw is not saved in the task
 	LogRaw string `json:"log_raw" bson:"log_raw,omitempty"`
 }
@@ -231,7 +252,8 @@ var (
 // Abortable returns true if the task can be aborted.
 func IsAbortable(t Task) bool {
 	return t.Status == evergreen.TaskStarted ||
-		t.Status == evergreen.TaskDispatched
+		t.Status == evergreen.TaskDispatched ||
+		t.Status == evergreen.TaskPaused
 }
 
 // IsFinished returns true if the project is no longer running
@@ -244,6 +266,19 @@ func (t *Task) IsDispatchable() bool {
 	return t.Status == evergreen.TaskUndispatched && t.Activated
 }
 
+// Distros returns every distro that the task can run on: its primary distro
+// followed by its secondary distros. A task with secondary distros is queued
+// on each of them and runs on whichever distro dispatches it first.
+func (t *Task) Distros() []string {
+	distros := []string{}
+	for _, d := range append([]string{t.PrimaryDistroId, t.DistroId}, t.SecondaryDistros...) {
+		if d != "" && !util.StringSliceContains(distros, d) {
+			distros = append(distros, d)
+		}
+	}
+	return distros
+}
+
 func (t *Task) GetTaskCreatedTime() time.Time {
 	if t.IngestTime.IsZero() {
 		return t.CreateTime
@@ -463,8 +498,22 @@ func (t *Task) cacheExpectedDuration() error {
 
 // Mark that the task has been dispatched onto a particular host. Sets the
 // running task field on the host and the host id field on the task.
+// A task dispatched on one of its secondary distros keeps its original
+// distro as its primary distro, so that it is queued there if restarted.
 // Returns an error if any of the database updates fail.
 func (t *Task) MarkAsDispatched(hostId string, distroId string, dispatchTime time.Time) error {
+	update := bson.M{
+		DispatchTimeKey:  dispatchTime,
+		StatusKey:        evergreen.TaskDispatched,
+		HostIdKey:        hostId,
+		LastHeartbeatKey: dispatchTime,
+		DistroIdKey:      distroId,
+	}
+	if t.PrimaryDistroId == "" && t.DistroId != "" && t.DistroId != distroId && len(t.SecondaryDistros) > 0 {
+		t.PrimaryDistroId = t.DistroId
+		update[PrimaryDistroIdKey] = t.PrimaryDistroId
+	}
+
 	t.DispatchTime = dispatchTime
 	t.Status = evergreen.TaskDispatched
 	t.HostId = hostId
@@ -475,13 +524,7 @@ func (t *Task) MarkAsDispatched(hostId string, distroId string, dispatchTime tim
 			IdKey: t.Id,
 		},
 		bson.M{
-			"$set": bson.M{
-				DispatchTimeKey:  dispatchTime,
-				StatusKey:        evergreen.TaskDispatched,
-				HostIdKey:        hostId,
-				LastHeartbeatKey: dispatchTime,
-				DistroIdKey:      distroId,
-			},
+			"$set": update,
 			"$unset": bson.M{
 				AbortedKey: "",
 				DetailsKey: "",
@@ -757,7 +800,7 @@ func (t *Task) MarkEnd(finishTime time.Time, detail *apimodels.TaskEndDetail) er
 
 func displayTaskPriority(status string) int {
 	switch status {
-	case evergreen.TaskStarted:
+	case evergreen.TaskStarted, evergreen.TaskPaused:
 		return 10
 	case evergreen.TaskUndispatched:
 		return 40
@@ -879,6 +922,56 @@ func (t *Task) UpdateHeartbeat() error {
 	)
 }
 
+// Pause holds the started task until the given time, or until it is resumed.
+func (t *Task) Pause(until time.Time) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey:     t.Id,
+			StatusKey: evergreen.TaskStarted,
+		},
+		bson.M{
+			"$set": bson.M{
+				StatusKey:      evergreen.TaskPaused,
+				PausedUntilKey: until,
+			},
+		},
+	)
+	if err == mgo.ErrNotFound {
+		return errors.Errorf("task '%s' is %s, not %s", t.Id, t.Status, evergreen.TaskStarted)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem pausing task '%s'", t.Id)
+	}
+
+	t.Status = evergreen.TaskPaused
+	t.PausedUntil = until
+	return nil
+}
+
+// Resume lets the agent finish a paused task.
+func (t *Task) Resume() error {
+	err := UpdateOne(
+		bson.M{
+			IdKey:     t.Id,
+			StatusKey: evergreen.TaskPaused,
+		},
+		bson.M{
+			"$set":   bson.M{StatusKey: evergreen.TaskStarted},
+			"$unset": bson.M{PausedUntilKey: ""},
+		},
+	)
+	if err == mgo.ErrNotFound {
+		return errors.Errorf("task '%s' is not paused", t.Id)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem resuming task '%s'", t.Id)
+	}
+
+	t.Status = evergreen.TaskStarted
+	t.PausedUntil = time.Time{}
+	return nil
+}
+
 // SetPriority sets the priority of the tasks and the tasks that they depend on
 func (t *Task) SetPriority(priority int64, user string) error {
 	t.Priority = priority
@@ -978,6 +1071,10 @@ func (t TestResult) convertToNewStyleTestResult(id string, execution int) testre
 		ExitCode:  t.ExitCode,
 		StartTime: t.StartTime,
 		EndTime:   t.EndTime,
+
+		SourceFile: t.SourceFile,
+		SourceLine: t.SourceLine,
+		Message:    t.Message,
 	}
 }
 
@@ -993,6 +1090,10 @@ func ConvertToOld(in *testresult.TestResult) TestResult {
 		StartTime: in.StartTime,
 		EndTime:   in.EndTime,
 		LogRaw:    in.LogRaw,
+
+		SourceFile: in.SourceFile,
+		SourceLine: in.SourceLine,
+		Message:    in.Message,
 	}
 }
 
@@ -1286,6 +1387,17 @@ func MergeTestResultsBulk(tasks []Task, query *db.Q) ([]Task, error) {
 	return out, nil
 }
 
+// byDistroOrSecondaryDistro matches the tasks that can run on a distro,
+// whether it is their distro, their primary distro or one of their secondary
+// distros.
+func byDistroOrSecondaryDistro(distroID string) []bson.M {
+	return []bson.M{
+		{DistroIdKey: distroID},
+		{PrimaryDistroIdKey: distroID},
+		{SecondaryDistrosKey: distroID},
+	}
+}
+
 func FindSchedulable(distroID string) ([]Task, error) {
 	query := scheduleableTasksQuery()
 
@@ -1293,7 +1405,15 @@ func FindSchedulable(distroID string) ([]Task, error) {
 		return Find(db.Query(query))
 	}
 
-	query[DistroIdKey] = distroID
+	query["$or"] = byDistroOrSecondaryDistro(distroID)
+	return Find(db.Query(query))
+}
+
+// FindSchedulableForProject returns the activated, undispatched tasks of a
+// project.
+func FindSchedulableForProject(projectID string) ([]Task, error) {
+	query := scheduleableTasksQuery()
+	query[ProjectKey] = projectID
 	return Find(db.Query(query))
 }
 
@@ -1302,8 +1422,7 @@ func FindRunnable(distroID string) ([]Task, error) {
 
 	match := scheduleableTasksQuery()
 	if distroID != "" {
-		match[DistroIdKey] = distroID
-
+		match["$or"] = byDistroOrSecondaryDistro(distroID)
 	}
 
 	matchActivatedUndispatchedTasks := bson.M{
@@ -1532,7 +1651,7 @@ func (tsc *TaskStatusCount) IncrementStatus(status string, statusDetails apimode
 		} else {
 			tsc.Failed++
 		}
-	case evergreen.TaskStarted, evergreen.TaskDispatched:
+	case evergreen.TaskStarted, evergreen.TaskDispatched, evergreen.TaskPaused:
 		tsc.Started++
 	case evergreen.TaskUndispatched:
 		tsc.Undispatched++
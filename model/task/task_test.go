@@ -1037,3 +1037,98 @@ func TestSiblingDependency(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal("pending", state)
 }
+
+func TestPauseAndResume(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection))
+
+	t1 := &Task{Id: "t1", Status: evergreen.TaskStarted}
+	require.NoError(t1.Insert())
+	t2 := &Task{Id: "t2", Status: evergreen.TaskSucceeded}
+	require.NoError(t2.Insert())
+
+	until := time.Now().Add(time.Hour).Round(time.Second)
+	require.NoError(t1.Pause(until))
+	assert.Equal(evergreen.TaskPaused, t1.Status)
+	assert.Error(t2.Pause(until), "only started tasks can pause")
+
+	dbTask, err := FindOneId(t1.Id)
+	require.NoError(err)
+	assert.Equal(evergreen.TaskPaused, dbTask.Status)
+	assert.True(until.Equal(dbTask.PausedUntil))
+	assert.True(IsAbortable(*dbTask))
+
+	require.NoError(t1.Resume())
+	assert.Equal(evergreen.TaskStarted, t1.Status)
+	assert.Error(t1.Resume(), "a resumed task is no longer paused")
+
+	dbTask, err = FindOneId(t1.Id)
+	require.NoError(err)
+	assert.Equal(evergreen.TaskStarted, dbTask.Status)
+	assert.True(dbTask.PausedUntil.IsZero())
+}
+
+func TestFindSchedulableOnSecondaryDistros(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection))
+
+	tasks := []Task{
+		{Id: "t1", DistroId: "d1", Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "t2", DistroId: "d1", SecondaryDistros: []string{"d2", "d1"}, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "t3", DistroId: "d2", Activated: true, Status: evergreen.TaskUndispatched},
+	}
+	for _, task := range tasks {
+		require.NoError(task.Insert())
+	}
+	assert.Equal([]string{"d1"}, tasks[0].Distros())
+	assert.Equal([]string{"d1", "d2"}, tasks[1].Distros())
+	assert.Empty((&Task{}).Distros())
+
+	found, err := FindSchedulable("d2")
+	require.NoError(err)
+	ids := []string{}
+	for _, task := range found {
+		ids = append(ids, task.Id)
+	}
+	assert.Len(ids, 2)
+	assert.Contains(ids, "t2")
+	assert.Contains(ids, "t3")
+
+	found, err = FindSchedulable("d1")
+	require.NoError(err)
+	assert.Len(found, 2)
+}
+
+func TestMarkAsDispatchedOnSecondaryDistroKeepsPrimary(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection))
+
+	t1 := &Task{Id: "t1", DistroId: "d1", SecondaryDistros: []string{"d2"}, Activated: true, Status: evergreen.TaskUndispatched}
+	require.NoError(t1.Insert())
+
+	require.NoError(t1.MarkAsDispatched("h1", "d2", time.Now()))
+	assert.Equal("d2", t1.DistroId)
+	assert.Equal("d1", t1.PrimaryDistroId)
+	assert.Equal([]string{"d1", "d2"}, t1.Distros())
+
+	dbTask, err := FindOneId(t1.Id)
+	require.NoError(err)
+	assert.Equal("d2", dbTask.DistroId)
+	assert.Equal("d1", dbTask.PrimaryDistroId)
+
+	// a restarted task is queued on its primary distro again
+	require.NoError(dbTask.Reset())
+	found, err := FindSchedulable("d1")
+	require.NoError(err)
+	require.Len(found, 1)
+	assert.Equal([]string{"d1", "d2"}, found[0].Distros())
+
+	// dispatching on its distro leaves a task without a primary distro
+	t2 := &Task{Id: "t2", DistroId: "d1", SecondaryDistros: []string{"d2"}, Activated: true, Status: evergreen.TaskUndispatched}
+	require.NoError(t2.Insert())
+	require.NoError(t2.MarkAsDispatched("h2", "d1", time.Now()))
+	assert.Empty(t2.PrimaryDistroId)
+}
@@ -355,3 +355,37 @@ func fixPatchInHistory(taskId string, base *task.Task, history []TaskJSON) ([]Ta
 	}
 	return history, nil
 }
+
+// GetTaskJSONForVersion returns the data that a mainline version's tasks
+// sent.
+func GetTaskJSONForVersion(versionId string) ([]TaskJSON, error) {
+	out := []TaskJSON{}
+	err := db.FindAllQ(TaskJSONCollection, db.Query(bson.M{
+		TaskJSONVersionIdKey: versionId,
+		TaskJSONIsPatchKey:   false,
+	}), &out)
+	return out, errors.Wrapf(err, "problem finding task data for version '%s'", versionId)
+}
+
+// GetTaskJSONSeries returns the data sent under the name by a task on a
+// build variant in up to limit mainline versions, ending with the version
+// with the given revision order number, in revision order.
+func GetTaskJSONSeries(projectId, variant, taskName, name string, maxOrder, limit int) ([]TaskJSON, error) {
+	out := []TaskJSON{}
+	err := db.FindAllQ(TaskJSONCollection, db.Query(bson.M{
+		TaskJSONProjectIdKey:           projectId,
+		TaskJSONVariantKey:             variant,
+		TaskJSONTaskNameKey:            taskName,
+		TaskJSONNameKey:                name,
+		TaskJSONIsPatchKey:             false,
+		TaskJSONRevisionOrderNumberKey: bson.M{"$lte": maxOrder},
+	}).Sort([]string{"-" + TaskJSONRevisionOrderNumberKey}).Limit(limit), &out)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding '%s' data for task '%s' on '%s'", name, taskName, variant)
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -154,6 +155,69 @@ func FindTaskLogsBeforeTime(taskId string, execution int, ts time.Time, limit in
 	return result, err
 }
 
+// FindTaskLogsAfterId returns the log documents for a task execution that
+// were inserted after the document with the given id, in insertion order. An
+// empty id returns every document.
+func FindTaskLogsAfterId(taskId string, execution int, id bson.ObjectId) ([]TaskLog, error) {
+	session, db, err := getSessionAndDB()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	query := bson.M{
+		TaskLogTaskIdKey:    taskId,
+		TaskLogExecutionKey: execution,
+	}
+	if id != "" {
+		query[TaskLogIdKey] = bson.M{"$gt": id}
+	}
+
+	result := []TaskLog{}
+	err = db.C(TaskLogCollection).Find(query).Sort(TaskLogIdKey).All(&result)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return result, err
+}
+
+// FindTaskLogIdBeforeLine returns the id of the last log document for a
+// task execution that ends before the given line, and the number of lines
+// up to the end of that document, so that reading can resume at that line
+// without reading the messages before it. An empty id means the line is in
+// the first document.
+func FindTaskLogIdBeforeLine(taskId string, execution, line int) (bson.ObjectId, int, error) {
+	session, db, err := getSessionAndDB()
+	if err != nil {
+		return "", 0, err
+	}
+	defer session.Close()
+
+	query := bson.M{
+		TaskLogTaskIdKey:    taskId,
+		TaskLogExecutionKey: execution,
+	}
+	iter := db.C(TaskLogCollection).Find(query).
+		Select(bson.M{TaskLogIdKey: 1, TaskLogMessageCountKey: 1}).
+		Sort(TaskLogIdKey).Iter()
+
+	lastId := bson.ObjectId("")
+	count := 0
+	l := TaskLog{}
+	for iter.Next(&l) {
+		if count+l.MessageCount > line {
+			break
+		}
+		lastId = l.Id
+		count += l.MessageCount
+	}
+	if err = iter.Close(); err != nil {
+		return "", 0, errors.Wrap(err, "problem finding task log position")
+	}
+
+	return lastId, count, nil
+}
+
 func GetRawTaskLogChannel(taskId string, execution int, severities []string,
 	msgTypes []string) (chan apimodels.LogMessage, error) {
 	session, db, err := getSessionAndDB()
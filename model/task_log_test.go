@@ -8,6 +8,8 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -65,6 +67,72 @@ func TestFindMostRecentTaskLogs(t *testing.T) {
 
 }
 
+func TestFindTaskLogsAfterId(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(cleanUpLogDB())
+
+	for i := 0; i < 3; i++ {
+		taskLog := &TaskLog{
+			Id:           bson.NewObjectId(),
+			TaskId:       "task_id",
+			MessageCount: i,
+			Timestamp:    time.Now(),
+		}
+		require.NoError(taskLog.Insert())
+	}
+	require.NoError((&TaskLog{Id: bson.NewObjectId(), TaskId: "task_id", Execution: 1}).Insert())
+
+	all, err := FindTaskLogsAfterId("task_id", 0, "")
+	require.NoError(err)
+	require.Len(all, 3)
+	for i := range all {
+		assert.Equal(i, all[i].MessageCount)
+	}
+
+	rest, err := FindTaskLogsAfterId("task_id", 0, all[0].Id)
+	require.NoError(err)
+	require.Len(rest, 2)
+	assert.Equal(all[1].Id, rest[0].Id)
+
+	none, err := FindTaskLogsAfterId("task_id", 0, all[2].Id)
+	assert.NoError(err)
+	assert.Empty(none)
+}
+
+func TestFindTaskLogIdBeforeLine(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(cleanUpLogDB())
+
+	ids := []bson.ObjectId{}
+	for i := 0; i < 3; i++ {
+		taskLog := &TaskLog{
+			Id:           bson.NewObjectId(),
+			TaskId:       "task_id",
+			MessageCount: 2,
+			Timestamp:    time.Now(),
+		}
+		require.NoError(taskLog.Insert())
+		ids = append(ids, taskLog.Id)
+	}
+
+	id, count, err := FindTaskLogIdBeforeLine("task_id", 0, 0)
+	require.NoError(err)
+	assert.Empty(id)
+	assert.Zero(count)
+
+	id, count, err = FindTaskLogIdBeforeLine("task_id", 0, 3)
+	require.NoError(err)
+	assert.Equal(ids[0], id)
+	assert.Equal(2, count)
+
+	id, count, err = FindTaskLogIdBeforeLine("task_id", 0, 10)
+	require.NoError(err)
+	assert.Equal(ids[2], id)
+	assert.Equal(6, count)
+}
+
 func TestFindTaskLogsBeforeTime(t *testing.T) {
 
 	Convey("When finding task logs before a specified time", t, func() {
@@ -46,6 +46,11 @@ type TaskQueueItem struct {
 	Project             string        `bson:"project" json:"project"`
 	ExpectedDuration    time.Duration `bson:"exp_dur" json:"exp_dur"`
 	Priority            int64         `bson:"priority" json:"priority"`
+	// Distros are all of the distros whose queues hold the task, if the task
+	// can run on more than one distro.
+	Distros []string `bson:"distros,omitempty" json:"distros,omitempty"`
+
+	ResourceRequests *evergreen.ContainerResources `bson:"resource_requests,omitempty" json:"resource_requests,omitempty"`
 }
 
 // nolint
@@ -66,8 +71,19 @@ var (
 	taskQueueItemProjectKey      = bsonutil.MustHaveTag(TaskQueueItem{}, "Project")
 	taskQueueItemExpDurationKey  = bsonutil.MustHaveTag(TaskQueueItem{}, "ExpectedDuration")
 	taskQueuePriorityKey         = bsonutil.MustHaveTag(TaskQueueItem{}, "Priority")
+	taskQueueItemDistrosKey      = bsonutil.MustHaveTag(TaskQueueItem{}, "Distros")
 )
 
+// DemandShare is the fraction of the task that a single distro's queue
+// accounts for. A task that can run on several distros is queued on each of
+// them, so each queue accounts for an equal share of it.
+func (it TaskQueueItem) DemandShare() float64 {
+	if len(it.Distros) < 2 {
+		return 1
+	}
+	return 1 / float64(len(it.Distros))
+}
+
 // TaskSpec is an argument structure to formalize the way that callers
 // may query/select a task from an existing task queue to support
 // out-of-order task execution for the purpose of task-groups.
@@ -77,6 +93,9 @@ type TaskSpec struct {
 	ProjectID     string
 	Version       string
 	GroupMaxHosts int
+	// Resources, if set, are the resources of the container that the task
+	// is for, and only tasks that request no more than them are found.
+	Resources *evergreen.ContainerResources
 }
 
 func NewTaskQueue(distro string, queue []TaskQueueItem) *TaskQueue {
@@ -198,6 +217,15 @@ func (self *TaskQueue) BlockTaskGroupTasks(spec TaskSpec, taskID string) error {
 	return catcher.Resolve()
 }
 
+// fitsIn returns true if the task requests no more than a container's
+// resources. Tasks fit in hosts that are not limited.
+func (it TaskQueueItem) fitsIn(resources *evergreen.ContainerResources) bool {
+	if resources == nil || it.ResourceRequests == nil {
+		return true
+	}
+	return it.ResourceRequests.FitsIn(*resources)
+}
+
 func (self *TaskQueue) Save() error {
 	return updateTaskQueue(self.Distro, self.Queue)
 }
@@ -206,9 +234,14 @@ func (self *TaskQueue) FindNextTask(spec TaskSpec) *TaskQueueItem {
 	if self.Length() == 0 {
 		return nil
 	}
+	resources := spec.Resources
 	// With a spec, find a matching task.
 	if spec.Group != "" && spec.ProjectID != "" && spec.BuildVariant != "" && spec.Version != "" {
 		for _, it := range self.Queue {
+			if !it.fitsIn(resources) {
+				continue
+			}
+
 			if it.Project != spec.ProjectID {
 				continue
 			}
@@ -230,6 +263,9 @@ func (self *TaskQueue) FindNextTask(spec TaskSpec) *TaskQueueItem {
 
 	// Otherwise, find the next dispatchable task.
 	for _, it := range self.Queue {
+		if !it.fitsIn(resources) {
+			continue
+		}
 		// Always return a task if the task group is empty.
 		if it.Group == "" {
 			return &it
@@ -412,3 +448,27 @@ func (self *TaskQueue) DequeueTask(taskId string) error {
 		},
 	))
 }
+
+// DequeueTaskFromDistros removes a task from the queues of the given distros.
+// A task that can run on several distros is removed from the queues of the
+// other distros once one of them dispatches it.
+func DequeueTaskFromDistros(taskId string, distros []string) error {
+	if len(distros) == 0 {
+		return nil
+	}
+
+	_, err := db.UpdateAll(
+		TaskQueuesCollection,
+		bson.M{
+			taskQueueDistroKey: bson.M{"$in": distros},
+		},
+		bson.M{
+			"$pull": bson.M{
+				taskQueueQueueKey: bson.M{
+					taskQueueItemIdKey: taskId,
+				},
+			},
+		},
+	)
+	return errors.WithStack(err)
+}
@@ -121,6 +121,26 @@ func TestFindTask(t *testing.T) {
 	assert.Equal("two", q.FindNextTask(TaskSpec{Group: "bar", ProjectID: "a", Version: "b", BuildVariant: "a"}).Id)
 }
 
+func TestFindTaskFittingResources(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &TaskQueue{
+		Queue: []TaskQueueItem{
+			{Id: "big", ResourceRequests: &evergreen.ContainerResources{CPUs: 4, MemoryMB: 8192}},
+			{Id: "small", ResourceRequests: &evergreen.ContainerResources{CPUs: 1, MemoryMB: 1024}},
+			{Id: "any"},
+		},
+	}
+
+	assert.Equal("big", q.FindNextTask(TaskSpec{}).Id)
+	assert.Equal("big", q.FindNextTask(TaskSpec{Resources: &evergreen.ContainerResources{CPUs: 4}}).Id)
+	assert.Equal("small", q.FindNextTask(TaskSpec{Resources: &evergreen.ContainerResources{CPUs: 2, MemoryMB: 2048}}).Id)
+	assert.Equal("any", q.FindNextTask(TaskSpec{Resources: &evergreen.ContainerResources{CPUs: 0.5}}).Id)
+
+	q.Queue = q.Queue[:2]
+	assert.Nil(q.FindNextTask(TaskSpec{Resources: &evergreen.ContainerResources{CPUs: 0.5}}))
+}
+
 func TestBlockTaskGroupTasks(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -493,3 +513,30 @@ func TestClearTaskQueue(t *testing.T) {
 	assert.NoError(err)
 	assert.Len(otherQueueFromDb.Queue, 3)
 }
+
+func TestDequeueTaskFromDistros(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(TaskQueuesCollection))
+
+	shared := TaskQueueItem{Id: "shared", Distros: []string{"d1", "d2", "d3"}}
+	assert.Equal(1.0/3, shared.DemandShare())
+	assert.Equal(1.0, TaskQueueItem{Id: "t1"}.DemandShare())
+
+	for _, distro := range []string{"d1", "d2", "d3"} {
+		require.NoError(NewTaskQueue(distro, []TaskQueueItem{shared, {Id: "t1"}}).Save())
+	}
+
+	assert.NoError(DequeueTaskFromDistros("shared", nil))
+	assert.NoError(DequeueTaskFromDistros("shared", []string{"d2", "d3"}))
+
+	queue, err := LoadTaskQueue("d1")
+	require.NoError(err)
+	assert.Len(queue.Queue, 2)
+	for _, distro := range []string{"d2", "d3"} {
+		queue, err = LoadTaskQueue(distro)
+		require.NoError(err)
+		require.Len(queue.Queue, 1)
+		assert.Equal("t1", queue.Queue[0].Id)
+	}
+}
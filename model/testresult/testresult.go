@@ -26,6 +26,12 @@ type TestResult struct {
 	StartTime float64       `json:"start" bson:"start"`
 	EndTime   float64       `json:"end" bson:"end"`
 
+	// SourceFile and SourceLine locate a failure in the code under test,
+	// and Message describes it, when the results format reports them.
+	SourceFile string `json:"source_file,omitempty" bson:"source_file,omitempty"`
+	SourceLine int    `json:"source_line,omitempty" bson:"source_line,omitempty"`
+	Message    string `json:"message,omitempty" bson:"message,omitempty"`
+
 	// Together, TaskID and Execution identify the task which created this TestResult
 	TaskID    string `bson:"task_id" json:"task_id"`
 	Execution int    `bson:"task_execution" json:"task_execution"`
@@ -36,18 +42,21 @@ type TestResult struct {
 
 var (
 	// BSON fields for the task struct
-	IDKey        = bsonutil.MustHaveTag(TestResult{}, "ID")
-	StatusKey    = bsonutil.MustHaveTag(TestResult{}, "Status")
-	LineNumKey   = bsonutil.MustHaveTag(TestResult{}, "LineNum")
-	TestFileKey  = bsonutil.MustHaveTag(TestResult{}, "TestFile")
-	URLKey       = bsonutil.MustHaveTag(TestResult{}, "URL")
-	LogIDKey     = bsonutil.MustHaveTag(TestResult{}, "LogID")
-	URLRawKey    = bsonutil.MustHaveTag(TestResult{}, "URLRaw")
-	ExitCodeKey  = bsonutil.MustHaveTag(TestResult{}, "ExitCode")
-	StartTimeKey = bsonutil.MustHaveTag(TestResult{}, "StartTime")
-	EndTimeKey   = bsonutil.MustHaveTag(TestResult{}, "EndTime")
-	TaskIDKey    = bsonutil.MustHaveTag(TestResult{}, "TaskID")
-	ExecutionKey = bsonutil.MustHaveTag(TestResult{}, "Execution")
+	IDKey         = bsonutil.MustHaveTag(TestResult{}, "ID")
+	StatusKey     = bsonutil.MustHaveTag(TestResult{}, "Status")
+	LineNumKey    = bsonutil.MustHaveTag(TestResult{}, "LineNum")
+	TestFileKey   = bsonutil.MustHaveTag(TestResult{}, "TestFile")
+	URLKey        = bsonutil.MustHaveTag(TestResult{}, "URL")
+	LogIDKey      = bsonutil.MustHaveTag(TestResult{}, "LogID")
+	URLRawKey     = bsonutil.MustHaveTag(TestResult{}, "URLRaw")
+	ExitCodeKey   = bsonutil.MustHaveTag(TestResult{}, "ExitCode")
+	StartTimeKey  = bsonutil.MustHaveTag(TestResult{}, "StartTime")
+	EndTimeKey    = bsonutil.MustHaveTag(TestResult{}, "EndTime")
+	TaskIDKey     = bsonutil.MustHaveTag(TestResult{}, "TaskID")
+	ExecutionKey  = bsonutil.MustHaveTag(TestResult{}, "Execution")
+	SourceFileKey = bsonutil.MustHaveTag(TestResult{}, "SourceFile")
+	SourceLineKey = bsonutil.MustHaveTag(TestResult{}, "SourceLine")
+	MessageKey    = bsonutil.MustHaveTag(TestResult{}, "Message")
 )
 
 // FindByTaskIDAndExecution returns test results from the testresults collection for a given task.
@@ -2,6 +2,7 @@ package version
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
@@ -150,6 +151,32 @@ func ByMostRecentNonIgnored(projectId string) db.Q {
 	).Sort([]string{"-" + RevisionOrderNumberKey})
 }
 
+// ByMostRecentSucceeded finds the successful mainline versions of a
+// project, ordered by most recent first.
+func ByMostRecentSucceeded(projectId string) db.Q {
+	return db.Query(
+		bson.M{
+			RequesterKey:  evergreen.RepotrackerVersionRequester,
+			IdentifierKey: projectId,
+			StatusKey:     evergreen.VersionSucceeded,
+		},
+	).Sort([]string{"-" + RevisionOrderNumberKey})
+}
+
+// ByMainlineFinishedSince finds the mainline versions that finished at or
+// after the given time.
+func ByMainlineFinishedSince(since time.Time) db.Q {
+	return db.Query(
+		bson.M{
+			RequesterKey: evergreen.RepotrackerVersionRequester,
+			StatusKey: bson.M{
+				"$in": []string{evergreen.VersionSucceeded, evergreen.VersionFailed},
+			},
+			FinishTimeKey: bson.M{"$gte": since},
+		},
+	).WithFields(IdKey)
+}
+
 func BySuccessfulBeforeRevision(project string, beforeRevision int) db.Q {
 	return db.Query(
 		bson.M{
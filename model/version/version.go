@@ -51,6 +51,10 @@ type Version struct {
 	// AuthorID is an optional reference to the Evergreen user that authored
 	// this comment, if they can be identified
 	AuthorID string `bson:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// TraceParent is the W3C trace context of the trace that follows the
+	// version from its creation through its tasks' execution.
+	TraceParent string `bson:"trace_parent,omitempty" json:"trace_parent,omitempty"`
 }
 
 func (v *Version) LastSuccessful() (*Version, error) {
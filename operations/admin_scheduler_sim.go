@@ -0,0 +1,179 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/scheduler"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func adminSchedulerSim() cli.Command {
+	const (
+		dumpFlagName             = "dump"
+		distroFlagName           = "distro"
+		scratchDBFlagName        = "scratch-db"
+		hostAllocatorFlagName    = "host-allocator"
+		taskPrioritizerFlagName  = "task-prioritizer"
+		poolSizeFlagName         = "pool-size"
+		freeHostFractionFlagName = "free-host-fraction"
+		startFlagName            = "start"
+		endFlagName              = "end"
+		intervalFlagName         = "interval"
+		hostStartupFlagName      = "host-startup"
+		idleTimeoutFlagName      = "idle-timeout"
+		maxDrainFlagName         = "max-drain"
+		costFlagName             = "cost-per-host-hour"
+	)
+
+	return cli.Command{
+		Name:  "scheduler-sim",
+		Usage: "replay a distro's recorded tasks through the scheduler with different settings",
+		Description: `Reads the distro, tasks, hosts and versions collections that mongodump
+wrote to a directory, replays the distro's tasks through the task prioritizer
+and host allocator, and reports task wait times, host hours and cost next to
+what was recorded. The simulator writes tasks to the scratch database, which
+must be empty, and empties it again when it is done.`,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  dumpFlagName,
+				Usage: "path to the directory of collections written by mongodump",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(distroFlagName, "d"),
+				Usage: "the distro to simulate",
+			},
+			cli.StringFlag{
+				Name:  dbUrlFlagName,
+				Usage: "URL of the mongod that holds the scratch database",
+				Value: evergreen.DefaultDatabaseUrl,
+			},
+			cli.StringFlag{
+				Name:  scratchDBFlagName,
+				Usage: "name of an empty database to replay tasks in",
+			},
+			cli.StringFlag{
+				Name:  hostAllocatorFlagName,
+				Usage: "the host allocator to use (deficit, duration or utilization)",
+				Value: "utilization",
+			},
+			cli.StringFlag{
+				Name:  taskPrioritizerFlagName,
+				Usage: "override the distro's task prioritizer (cmp or fair-share)",
+			},
+			cli.IntFlag{
+				Name:  poolSizeFlagName,
+				Usage: "override the distro's pool size",
+			},
+			cli.Float64Flag{
+				Name:  freeHostFractionFlagName,
+				Usage: "the fraction of soon to be free hosts that the utilization allocator counts as free",
+			},
+			cli.StringFlag{
+				Name:  startFlagName,
+				Usage: "replay tasks activated at or after this time (RFC 3339)",
+			},
+			cli.StringFlag{
+				Name:  endFlagName,
+				Usage: "replay tasks activated at or before this time (RFC 3339)",
+			},
+			cli.DurationFlag{
+				Name:  intervalFlagName,
+				Usage: "how often the scheduler runs",
+			},
+			cli.DurationFlag{
+				Name:  hostStartupFlagName,
+				Usage: "how long new hosts take to be ready for tasks",
+			},
+			cli.DurationFlag{
+				Name:  idleTimeoutFlagName,
+				Usage: "how long idle hosts run before they are terminated",
+			},
+			cli.DurationFlag{
+				Name:  maxDrainFlagName,
+				Usage: "how long to keep running after the last task is activated",
+			},
+			cli.Float64Flag{
+				Name:  costFlagName,
+				Usage: "what running a host for an hour costs",
+			},
+		},
+		Before: mergeBeforeFuncs(
+			requireStringFlag(dumpFlagName),
+			requireStringFlag(distroFlagName),
+			requireStringFlag(scratchDBFlagName),
+			func(c *cli.Context) error {
+				if c.String(scratchDBFlagName) == evergreen.DefaultDatabaseName {
+					return errors.Errorf("cannot simulate in '%s', which is evergreen's database", evergreen.DefaultDatabaseName)
+				}
+				return nil
+			},
+			func(c *cli.Context) error {
+				sender := send.MakePlainLogger()
+				grip.Warning(sender.SetLevel(send.LevelInfo{Default: level.Info, Threshold: level.Warning}))
+				return errors.WithStack(grip.SetSender(sender))
+			},
+		),
+		Action: func(c *cli.Context) error {
+			opts := scheduler.SimulationOptions{
+				HostAllocator:    c.String(hostAllocatorFlagName),
+				TaskPrioritizer:  c.String(taskPrioritizerFlagName),
+				PoolSize:         c.Int(poolSizeFlagName),
+				FreeHostFraction: c.Float64(freeHostFractionFlagName),
+				Interval:         c.Duration(intervalFlagName),
+				HostStartupTime:  c.Duration(hostStartupFlagName),
+				IdleTimeout:      c.Duration(idleTimeoutFlagName),
+				MaxDrainTime:     c.Duration(maxDrainFlagName),
+				CostPerHostHour:  c.Float64(costFlagName),
+			}
+			var err error
+			if start := c.String(startFlagName); start != "" {
+				if opts.Start, err = time.Parse(time.RFC3339, start); err != nil {
+					return errors.Wrapf(err, "problem parsing start time '%s'", start)
+				}
+			}
+			if end := c.String(endFlagName); end != "" {
+				if opts.End, err = time.Parse(time.RFC3339, end); err != nil {
+					return errors.Wrapf(err, "problem parsing end time '%s'", end)
+				}
+			}
+			if err = opts.Validate(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			input, err := scheduler.LoadSimulationInput(c.String(dumpFlagName), c.String(distroFlagName))
+			if err != nil {
+				return errors.Wrap(err, "problem loading recorded history")
+			}
+
+			db.SetGlobalSessionProvider(evergreen.CreateSession(evergreen.DBSettings{
+				Url: c.String(dbUrlFlagName),
+				DB:  c.String(scratchDBFlagName),
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			report, err := scheduler.Simulate(ctx, input, opts)
+			if err != nil {
+				return errors.Wrap(err, "problem simulating scheduler")
+			}
+
+			out, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return errors.Wrap(err, "problem marshalling simulation report")
+			}
+			fmt.Println(string(out))
+
+			return nil
+		},
+	}
+}
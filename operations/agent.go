@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen/agent"
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/mongodb/grip/recovery"
@@ -25,6 +26,7 @@ func Agent() cli.Command {
 		logPrefixFlagName        = "log_prefix"
 		statusPortFlagName       = "status_port"
 		cleanupFlagName          = "cleanup"
+		traceCollectorFlagName   = "trace_collector"
 	)
 
 	return cli.Command{
@@ -61,6 +63,10 @@ func Agent() cli.Command {
 				Name:  cleanupFlagName,
 				Usage: "clean up working directory and processes (do not set for smoke tests)",
 			},
+			cli.StringFlag{
+				Name:  traceCollectorFlagName,
+				Usage: "OTLP/HTTP endpoint of the collector to export command spans to",
+			},
 		},
 		Before: mergeBeforeFuncs(
 			func(c *cli.Context) error {
@@ -80,6 +86,7 @@ func Agent() cli.Command {
 				LogPrefix:        c.String(logPrefixFlagName),
 				WorkingDirectory: c.String(workingDirectoryFlagName),
 				Cleanup:          c.Bool(cleanupFlagName),
+				TraceCollector:   c.String(traceCollectorFlagName),
 			}
 
 			if err := os.MkdirAll(opts.WorkingDirectory, 0777); err != nil {
@@ -96,6 +103,14 @@ func Agent() cli.Command {
 			comm := client.NewCommunicator(c.String("api_server"))
 			defer comm.Close()
 
+			if opts.TraceCollector != "" {
+				tracer := util.NewTracer(util.NewOTLPSpanExporter(opts.TraceCollector, "evergreen-agent"))
+				util.SetTracer(tracer)
+				defer func() {
+					grip.Warning(errors.Wrap(tracer.Close(context.Background()), "problem closing tracer"))
+				}()
+			}
+
 			agt := agent.New(opts, comm)
 
 			ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,358 @@
+package operations
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func Artifacts() cli.Command {
+	return cli.Command{
+		Name:   "artifacts",
+		Usage:  "list and download the files attached by tasks",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			artifactsDownload(),
+		},
+	}
+}
+
+func artifactsDownload() cli.Command {
+	const (
+		versionFlagName = "version"
+		buildFlagName   = "build"
+		taskFlagName    = "task"
+		variantFlagName = "variant"
+		nameFlagName    = "name"
+		dirFlagName     = "dir"
+		workersFlagName = "workers"
+	)
+
+	return cli.Command{
+		Name:  "download",
+		Usage: "download the artifacts of a task, build, or version in parallel",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(versionFlagName, "v"),
+				Usage: "download the artifacts of every task in a version",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(buildFlagName, "b"),
+				Usage: "download the artifacts of every task in a build",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(taskFlagName, "t"),
+				Usage: "download the artifacts of a single task",
+			},
+			cli.StringFlag{
+				Name:  variantFlagName,
+				Usage: "with --version, only download artifacts from this build variant",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(nameFlagName, "n"),
+				Usage: "only download artifacts whose name matches this glob",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(dirFlagName, "d"),
+				Usage: "root directory to download artifacts into. defaults to current working directory",
+			},
+			cli.IntFlag{
+				Name:  workersFlagName,
+				Usage: "number of artifacts to download at once",
+				Value: 4,
+			},
+		},
+		Before: mergeBeforeFuncs(
+			requireIntValueBetween(workersFlagName, 1, 64),
+			func(c *cli.Context) error {
+				count := 0
+				for _, name := range []string{versionFlagName, buildFlagName, taskFlagName} {
+					if c.String(name) != "" {
+						count++
+					}
+				}
+				if count != 1 {
+					return errors.Errorf("must specify one and only one of: --%s, --%s, --%s",
+						versionFlagName, buildFlagName, taskFlagName)
+				}
+				if c.String(variantFlagName) != "" && c.String(versionFlagName) == "" {
+					return errors.Errorf("--%s may only be used with --%s", variantFlagName, versionFlagName)
+				}
+				return nil
+			},
+			func(c *cli.Context) error {
+				if c.String(dirFlagName) != "" {
+					return nil
+				}
+				wd, err := os.Getwd()
+				if err != nil {
+					return errors.Wrap(err, "cannot find working directory")
+				}
+				return c.Set(dirFlagName, wd)
+			}),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			name := c.String(nameFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			var artifacts []model.APIArtifact
+			switch {
+			case c.String(taskFlagName) != "":
+				artifacts, err = client.GetTaskArtifacts(ctx, c.String(taskFlagName), name)
+			case c.String(buildFlagName) != "":
+				artifacts, err = client.GetBuildArtifacts(ctx, c.String(buildFlagName), name)
+			default:
+				artifacts, err = client.GetVersionArtifacts(ctx, c.String(versionFlagName), c.String(variantFlagName), name)
+			}
+			if err != nil {
+				return errors.Wrap(err, "problem listing artifacts")
+			}
+
+			downloads := planArtifactDownloads(c.String(dirFlagName), artifacts)
+			if len(downloads) == 0 {
+				grip.Info("no artifacts to download")
+				return nil
+			}
+			grip.Infof("downloading %d artifacts", len(downloads))
+
+			return downloadArtifacts(ctx, downloads, c.Int(workersFlagName))
+		},
+	}
+}
+
+type artifactFileDownload struct {
+	url  string
+	path string
+	// sha256 is the checksum recorded when the file was uploaded, if any
+	sha256 string
+}
+
+// planArtifactDownloads assigns each artifact a local path of the form
+// <root>/<build>/<task>/<file>. Paths are deterministic, so that an
+// interrupted download can be resumed by running the same command again.
+func planArtifactDownloads(root string, artifacts []model.APIArtifact) []artifactFileDownload {
+	downloads := []artifactFileDownload{}
+	used := map[string]int{}
+	for _, a := range artifacts {
+		link := model.FromAPIString(a.Link)
+		if a.Expired || a.IgnoreForFetch || link == "" {
+			continue
+		}
+
+		fileName := util.CleanForPath(model.FromAPIString(a.Name))
+		if u, err := url.Parse(link); err == nil && path.Base(u.Path) != "" && path.Base(u.Path) != "/" {
+			fileName = util.CleanForPath(path.Base(u.Path))
+		}
+
+		dir := filepath.Join(root,
+			util.CleanForPath(model.FromAPIString(a.BuildId)),
+			util.CleanForPath(model.FromAPIString(a.TaskDisplayName)))
+		key := filepath.Join(dir, fileName)
+		used[key]++
+		downloads = append(downloads, artifactFileDownload{
+			url:    link,
+			path:   filepath.Join(dir, fileNameWithIndex(fileName, used[key])),
+			sha256: strings.ToLower(model.FromAPIString(a.SHA256)),
+		})
+	}
+
+	return downloads
+}
+
+func downloadArtifacts(ctx context.Context, downloads []artifactFileDownload, workers int) error {
+	work := make(chan artifactFileDownload, len(downloads))
+	for _, d := range downloads {
+		work <- d
+	}
+	close(work)
+
+	httpClient := util.GetHTTPClient()
+	defer util.PutHTTPClient(httpClient)
+
+	catcher := grip.NewBasicCatcher()
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range work {
+				if ctx.Err() != nil {
+					return
+				}
+				catcher.Add(errors.Wrapf(downloadArtifact(ctx, httpClient, d), "problem downloading '%s'", d.url))
+			}
+		}()
+	}
+	wg.Wait()
+
+	return catcher.Resolve()
+}
+
+var md5ETag = regexp.MustCompile("^[0-9a-f]{32}$")
+
+// downloadArtifact fetches a single file. Completed files are skipped,
+// and a partial file left by an earlier run is resumed with a range
+// request. The finished file is verified against the sha256 checksum
+// recorded when it was uploaded or, for files without one, against the
+// md5 etag that s3 reports for objects not uploaded in parts.
+func downloadArtifact(ctx context.Context, client *http.Client, d artifactFileDownload) error {
+	if _, err := os.Stat(d.path); err == nil {
+		grip.Infof("skipping '%s', which was already downloaded", d.path)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+
+	partial := d.path + ".part"
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, d.url, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// the server ignored the range, so start over
+		flags |= os.O_TRUNC
+		offset = 0
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file may already be complete, but it is only
+		// kept if its checksum shows that it is, since it could also be
+		// stale or corrupt
+		etag := resp.Header.Get("ETag")
+		if canVerifyArtifactDownload(d.sha256, etag) && verifyArtifactDownload(partial, d.sha256, etag) == nil {
+			return errors.WithStack(os.Rename(partial, d.path))
+		}
+		grip.Infof("discarding partial download of '%s'", d.path)
+		if err = os.Remove(partial); err != nil {
+			return errors.WithStack(err)
+		}
+		return downloadArtifact(ctx, client, d)
+	default:
+		return errors.Errorf("unexpected response '%s'", resp.Status)
+	}
+
+	out, err := os.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	sizeLog := ""
+	if resp.ContentLength > 0 {
+		sizeLog = fmt.Sprintf(" (%s)", humanize.Bytes(uint64(resp.ContentLength)))
+	}
+	if offset > 0 {
+		grip.Infof("resuming '%s' at %s%s", d.path, humanize.Bytes(uint64(offset)), sizeLog)
+	} else {
+		grip.Infof("downloading '%s'%s", d.path, sizeLog)
+	}
+
+	_, err = io.Copy(out, resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "problem writing file")
+	}
+
+	if err = verifyArtifactDownload(partial, d.sha256, resp.Header.Get("ETag")); err != nil {
+		grip.Warning(os.Remove(partial))
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(os.Rename(partial, d.path))
+}
+
+// canVerifyArtifactDownload returns true if a download has a recorded
+// sha256 checksum or an md5 etag to check it against.
+func canVerifyArtifactDownload(sha256Sum, etag string) bool {
+	return sha256Sum != "" || md5ETag.MatchString(strings.Trim(etag, `"`))
+}
+
+// verifyArtifactDownload checks a downloaded file against its recorded
+// sha256 checksum or, if it has none, against an md5 etag.
+func verifyArtifactDownload(fn, sha256Sum, etag string) error {
+	if sha256Sum != "" {
+		sum, err := hashFile(fn, sha256.New())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if sum != sha256Sum {
+			return errors.Errorf("checksum mismatch: expected sha256 %s, got %s", sha256Sum, sum)
+		}
+		return nil
+	}
+
+	etag = strings.Trim(etag, `"`)
+	if !md5ETag.MatchString(etag) {
+		return nil
+	}
+	sum, err := hashFile(fn, md5.New())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if sum != etag {
+		return errors.Errorf("checksum mismatch: expected md5 %s, got %s", etag, sum)
+	}
+	return nil
+}
+
+func hashFile(fn string, hash hash.Hash) (string, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
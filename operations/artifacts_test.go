@@ -0,0 +1,133 @@
+package operations
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanArtifactDownloads(t *testing.T) {
+	assert := assert.New(t)
+
+	artifact := func(task, name, link string) model.APIArtifact {
+		a := model.APIArtifact{
+			TaskDisplayName: model.ToAPIString(task),
+			BuildId:         model.ToAPIString("build"),
+		}
+		a.Name = model.ToAPIString(name)
+		a.Link = model.ToAPIString(link)
+		return a
+	}
+	expired := artifact("compile", "old", "https://example.com/old.tgz")
+	expired.Expired = true
+
+	checked := artifact("compile", "Binaries", "https://example.com/a/bin.tgz")
+	checked.SHA256 = model.ToAPIString("ABCDEF")
+
+	downloads := planArtifactDownloads("root", []model.APIArtifact{
+		checked,
+		artifact("compile", "Other Binaries", "https://example.com/b/bin.tgz"),
+		artifact("test", "Logs", "https://example.com/"),
+		expired,
+	})
+
+	assert.Len(downloads, 3)
+	assert.Equal(filepath.Join("root", "build", "compile", "bin.tgz"), downloads[0].path)
+	assert.Equal("abcdef", downloads[0].sha256)
+	assert.Equal(filepath.Join("root", "build", "compile", "bin_(1).tgz"), downloads[1].path)
+	assert.Equal(filepath.Join("root", "build", "test", "Logs"), downloads[2].path)
+}
+
+func TestDownloadArtifact(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	content := strings.Repeat("evergreen", 100)
+	sum := md5.Sum([]byte(content))
+	etag := hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/corrupt" {
+			w.Header().Set("ETag", `"00000000000000000000000000000000"`)
+		} else {
+			w.Header().Set("ETag", `"`+etag+`"`)
+		}
+		http.ServeContent(w, r, "file", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "artifacts-download")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	d := artifactFileDownload{url: server.URL + "/file", path: filepath.Join(dir, "build", "file")}
+
+	// resume from a partial download
+	require.NoError(os.MkdirAll(filepath.Dir(d.path), 0755))
+	require.NoError(ioutil.WriteFile(d.path+".part", []byte(content[:100]), 0644))
+	require.NoError(downloadArtifact(ctx, http.DefaultClient, d))
+	out, err := ioutil.ReadFile(d.path)
+	require.NoError(err)
+	assert.Equal(content, string(out))
+	_, err = os.Stat(d.path + ".part")
+	assert.True(os.IsNotExist(err))
+
+	// a complete partial download is kept if its checksum matches
+	complete := artifactFileDownload{url: server.URL + "/file", path: filepath.Join(dir, "complete")}
+	require.NoError(ioutil.WriteFile(complete.path+".part", []byte(content), 0644))
+	require.NoError(downloadArtifact(ctx, http.DefaultClient, complete))
+	out, err = ioutil.ReadFile(complete.path)
+	require.NoError(err)
+	assert.Equal(content, string(out))
+
+	// and is downloaded again if it doesn't
+	stale := artifactFileDownload{url: server.URL + "/file", path: filepath.Join(dir, "stale")}
+	require.NoError(ioutil.WriteFile(stale.path+".part", []byte(strings.Repeat("x", len(content)+10)), 0644))
+	require.NoError(downloadArtifact(ctx, http.DefaultClient, stale))
+	out, err = ioutil.ReadFile(stale.path)
+	require.NoError(err)
+	assert.Equal(content, string(out))
+	_, err = os.Stat(stale.path + ".part")
+	assert.True(os.IsNotExist(err))
+
+	// completed files are not downloaded again
+	require.NoError(ioutil.WriteFile(d.path, []byte("done"), 0644))
+	require.NoError(downloadArtifact(ctx, http.DefaultClient, d))
+	out, err = ioutil.ReadFile(d.path)
+	require.NoError(err)
+	assert.Equal("done", string(out))
+
+	// checksum mismatches are errors and discard the download
+	bad := artifactFileDownload{url: server.URL + "/corrupt", path: filepath.Join(dir, "corrupt")}
+	assert.Error(downloadArtifact(ctx, http.DefaultClient, bad))
+	_, err = os.Stat(bad.path)
+	assert.True(os.IsNotExist(err))
+	_, err = os.Stat(bad.path + ".part")
+	assert.True(os.IsNotExist(err))
+
+	// recorded sha256 checksums take precedence over the etag
+	shaSum := sha256.Sum256([]byte(content))
+	checked := artifactFileDownload{url: server.URL + "/file", path: filepath.Join(dir, "checked"), sha256: hex.EncodeToString(shaSum[:])}
+	require.NoError(downloadArtifact(ctx, http.DefaultClient, checked))
+	out, err = ioutil.ReadFile(checked.path)
+	require.NoError(err)
+	assert.Equal(content, string(out))
+
+	tampered := artifactFileDownload{url: server.URL + "/file", path: filepath.Join(dir, "tampered"), sha256: strings.Repeat("0", 64)}
+	assert.Error(downloadArtifact(ctx, http.DefaultClient, tampered))
+	_, err = os.Stat(tampered.path)
+	assert.True(os.IsNotExist(err))
+}
@@ -366,6 +366,8 @@ func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, e
 		Tasks       []string `json:"tasks"`
 		Finalize    bool     `json:"finalize"`
 		Alias       string   `json:"alias"`
+		// PauseOnFailure holds the patch's failed tasks for inspection
+		PauseOnFailure bool `json:"pause_on_failure"`
 	}{
 		incomingPatch.description,
 		incomingPatch.projectId,
@@ -375,6 +377,7 @@ func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, e
 		incomingPatch.tasks,
 		incomingPatch.finalize,
 		incomingPatch.alias,
+		incomingPatch.pauseOnFailure,
 	}
 
 	rPipe, wPipe := io.Pipe()
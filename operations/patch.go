@@ -14,6 +14,7 @@ const (
 	patchVerboseFlagName     = "verbose"
 	patchAliasFlagName       = "alias"
 	patchBrowseFlagName      = "browse"
+	patchPauseFlagName       = "pause-on-failure"
 )
 
 func getPatchFlags(flags ...cli.Flag) []cli.Flag {
@@ -37,6 +38,10 @@ func getPatchFlags(flags ...cli.Flag) []cli.Flag {
 		cli.BoolFlag{
 			Name:  patchVerboseFlagName,
 			Usage: "show patch summary",
+		},
+		cli.BoolFlag{
+			Name:  patchPauseFlagName,
+			Usage: "hold failed tasks for inspection before running their post commands",
 		}))
 }
 
@@ -61,6 +66,8 @@ func Patch() cli.Command {
 				ShowSummary: c.Bool(patchVerboseFlagName),
 				Large:       c.Bool(largeFlagName),
 				Alias:       c.String(patchAliasFlagName),
+
+				PauseOnFailure: c.Bool(patchPauseFlagName),
 			}
 
 			ctx, cancel := context.WithCancel(context.Background())
@@ -124,6 +131,8 @@ func PatchFile() cli.Command {
 				Finalize:    c.Bool(patchFinalizeFlagName),
 				ShowSummary: c.Bool(patchVerboseFlagName),
 				Large:       c.Bool(largeFlagName),
+
+				PauseOnFailure: c.Bool(patchPauseFlagName),
 			}
 			diffPath := c.String(diffPathFlagName)
 			base := c.String(baseFlagName)
@@ -57,6 +57,8 @@ type patchParams struct {
 	Browse      bool
 	Large       bool
 	ShowSummary bool
+	// PauseOnFailure holds the patch's failed tasks for inspection.
+	PauseOnFailure bool
 }
 
 type patchSubmission struct {
@@ -68,6 +70,8 @@ type patchSubmission struct {
 	variants    string
 	tasks       []string
 	finalize    bool
+
+	pauseOnFailure bool
 }
 
 func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffData *localDiff) error {
@@ -99,6 +103,8 @@ func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffDa
 		tasks:       p.Tasks,
 		finalize:    p.Finalize,
 		alias:       p.Alias,
+
+		pauseOnFailure: p.PauseOnFailure,
 	}
 
 	newPatch, err := ac.PutPatch(patchSub)
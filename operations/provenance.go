@@ -0,0 +1,73 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func Provenance() cli.Command {
+	const (
+		versionFlagName = "version"
+		outputFlagName  = "output"
+	)
+
+	return cli.Command{
+		Name:  "provenance",
+		Usage: "export the build provenance document of a finished version",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(versionFlagName, "v"),
+				Usage: "the version to export provenance for",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(outputFlagName, "o"),
+				Usage: "write the document to this file instead of standard output",
+			},
+		},
+		Before: mergeBeforeFuncs(setPlainLogger, requireStringFlag(versionFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			versionID := c.String(versionFlagName)
+			output := c.String(outputFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			doc, err := client.GetVersionProvenance(ctx, versionID)
+			if err != nil {
+				return errors.Wrapf(err, "problem fetching provenance for version '%s'", versionID)
+			}
+
+			out, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				return errors.Wrap(err, "problem encoding provenance")
+			}
+			out = append(out, '\n')
+
+			if output == "" {
+				fmt.Print(string(out))
+				return nil
+			}
+
+			if err = ioutil.WriteFile(output, out, 0644); err != nil {
+				return errors.Wrapf(err, "problem writing provenance to '%s'", output)
+			}
+			grip.Infof("wrote provenance for version '%s' to '%s'", versionID, output)
+			return nil
+		},
+	}
+}
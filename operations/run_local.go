@@ -0,0 +1,217 @@
+package operations
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/agent"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// localTaskID is the ID of a task run with run-local, which the agent also
+// uses to find the task's processes.
+const localTaskID = "local"
+
+// localTaskOptions describe a task to run on a developer's machine.
+type localTaskOptions struct {
+	Project    []byte
+	ProjectID  string
+	Variant    string
+	Task       string
+	Revision   string
+	WorkDir    string
+	Expansions map[string]string
+}
+
+func RunLocal() cli.Command {
+	const (
+		taskFlagName           = "task"
+		variantFlagName        = "variant"
+		dirFlagName            = "dir"
+		outputFlagName         = "output"
+		expansionFlagName      = "expansion"
+		expansionsFileFlagName = "expansions-file"
+		revisionFlagName       = "revision"
+	)
+
+	return cli.Command{
+		Name:  "run-local",
+		Usage: "run a task's commands on this machine, without an evergreen server",
+		Flags: addPathFlag(addProjectFlag(
+			cli.StringFlag{
+				Name:  joinFlagNames(taskFlagName, "t"),
+				Usage: "name of the task to run",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(variantFlagName, "v"),
+				Usage: "name of the build variant to run the task as",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(dirFlagName, "d"),
+				Usage: "working directory of the task. defaults to the current working directory",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(outputFlagName, "o"),
+				Usage: "directory to write test results, artifacts and JSON data into. defaults to 'evergreen-local' in the working directory",
+			},
+			cli.StringSliceFlag{
+				Name:  joinFlagNames(expansionFlagName, "e"),
+				Usage: "an expansion to set, as key=value (may be specified more than once)",
+			},
+			cli.StringFlag{
+				Name:  expansionsFileFlagName,
+				Usage: "path to a YAML file of expansions to set, such as the project's private variables",
+			},
+			cli.StringFlag{
+				Name:  revisionFlagName,
+				Usage: "value of the revision expansion",
+			},
+		)...),
+		Before: mergeBeforeFuncs(setPlainLogger, requirePathFlag, requireStringFlag(taskFlagName), requireStringFlag(variantFlagName)),
+		Action: func(c *cli.Context) error {
+			opts := localTaskOptions{
+				ProjectID:  c.String(projectFlagName),
+				Variant:    c.String(variantFlagName),
+				Task:       c.String(taskFlagName),
+				Revision:   c.String(revisionFlagName),
+				WorkDir:    c.String(dirFlagName),
+				Expansions: map[string]string{},
+			}
+
+			var err error
+			opts.Project, err = ioutil.ReadFile(c.String(pathFlagName))
+			if err != nil {
+				return errors.Wrap(err, "error reading project config")
+			}
+			if opts.WorkDir == "" {
+				if opts.WorkDir, err = os.Getwd(); err != nil {
+					return errors.Wrap(err, "problem finding working directory")
+				}
+			}
+			if opts.WorkDir, err = filepath.Abs(opts.WorkDir); err != nil {
+				return errors.Wrap(err, "problem finding working directory")
+			}
+
+			if path := c.String(expansionsFileFlagName); path != "" {
+				var data []byte
+				data, err = ioutil.ReadFile(path)
+				if err != nil {
+					return errors.Wrap(err, "error reading expansions file")
+				}
+				if err = yaml.Unmarshal(data, &opts.Expansions); err != nil {
+					return errors.Wrap(err, "error parsing expansions file")
+				}
+			}
+			for _, kv := range c.StringSlice(expansionFlagName) {
+				parts := strings.SplitN(kv, "=", 2)
+				if len(parts) != 2 || parts[0] == "" {
+					return errors.Errorf("expansion '%s' is not of the form key=value", kv)
+				}
+				opts.Expansions[parts[0]] = parts[1]
+			}
+
+			conf, err := makeLocalTaskConfig(opts)
+			if err != nil {
+				return err
+			}
+
+			output := c.String(outputFlagName)
+			if output == "" {
+				output = filepath.Join(opts.WorkDir, "evergreen-local")
+			}
+			comm, err := client.NewLocalCommunicator(output)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				sigs := make(chan os.Signal, 1)
+				signal.Notify(sigs, os.Interrupt)
+				defer signal.Stop(sigs)
+				select {
+				case <-sigs:
+					grip.Info("interrupted, stopping task")
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			grip.Infof("Running task '%s' on build variant '%s' in %s", opts.Task, opts.Variant, opts.WorkDir)
+			a := agent.New(agent.Options{Cleanup: true, WorkingDirectory: opts.WorkDir}, comm)
+			err = a.RunLocalTask(ctx, conf)
+			grip.Infof("Task output was written to %s", output)
+			return err
+		},
+	}
+}
+
+// makeLocalTaskConfig builds the configuration of a task run outside of
+// evergreen, with the expansions that the agent would set for it and those
+// given in the options.
+func makeLocalTaskConfig(opts localTaskOptions) (*model.TaskConfig, error) {
+	p := &model.Project{}
+	if err := model.LoadProjectInto(opts.Project, opts.ProjectID, p); err != nil {
+		return nil, errors.Wrap(err, "error loading project")
+	}
+	if p.FindBuildVariant(opts.Variant) == nil {
+		return nil, errors.Errorf("build variant '%s' is not defined", opts.Variant)
+	}
+	if p.FindTaskForVariant(opts.Task, opts.Variant) == nil {
+		return nil, errors.Errorf("task '%s' does not run on build variant '%s'", opts.Task, opts.Variant)
+	}
+
+	now := time.Now()
+	t := &task.Task{
+		Id:           localTaskID,
+		Project:      opts.ProjectID,
+		Version:      localTaskID,
+		BuildId:      localTaskID,
+		BuildVariant: opts.Variant,
+		DisplayName:  opts.Task,
+		Revision:     opts.Revision,
+		Requester:    evergreen.RepotrackerVersionRequester,
+		CreateTime:   now,
+		StartTime:    now,
+		Status:       evergreen.TaskStarted,
+	}
+	v := &version.Version{
+		Id:         localTaskID,
+		Identifier: opts.ProjectID,
+		Revision:   opts.Revision,
+		Requester:  evergreen.RepotrackerVersionRequester,
+		CreateTime: now,
+		Config:     string(opts.Project),
+	}
+	d := &distro.Distro{
+		Id:      localTaskID,
+		WorkDir: opts.WorkDir,
+	}
+	ref := &model.ProjectRef{
+		Identifier: opts.ProjectID,
+		Enabled:    true,
+	}
+
+	conf, err := model.NewTaskConfig(d, v, p, t, ref, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem building task configuration")
+	}
+	conf.Expansions.Update(opts.Expansions)
+	conf.Redacted = map[string]bool{}
+	return conf, nil
+}
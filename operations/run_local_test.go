@@ -0,0 +1,129 @@
+package operations
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const localProject = `
+pre:
+- command: shell.exec
+  params:
+    script: echo "${greeting}" > pre.txt
+tasks:
+- name: pass
+  commands:
+  - command: shell.exec
+    params:
+      script: |
+        echo '{"ops_per_sec": 100}' > perf.json
+  - command: json.send
+    params:
+      name: perf
+      file: perf.json
+- name: fail
+  commands:
+  - command: shell.exec
+    params:
+      script: exit 1
+- name: unscheduled
+buildvariants:
+- name: linux
+  expansions:
+    greeting: hello
+  tasks:
+  - name: pass
+  - name: fail
+`
+
+func TestMakeLocalTaskConfig(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	opts := localTaskOptions{
+		Project:    []byte(localProject),
+		ProjectID:  "proj",
+		Variant:    "linux",
+		Task:       "pass",
+		Revision:   "abcdef",
+		WorkDir:    "/tmp/work",
+		Expansions: map[string]string{"greeting": "hi", "secret": "shh"},
+	}
+	conf, err := makeLocalTaskConfig(opts)
+	require.NoError(err)
+	assert.Equal("pass", conf.Task.DisplayName)
+	assert.Equal("linux", conf.BuildVariant.Name)
+	assert.Equal("/tmp/work", conf.WorkDir)
+	assert.Equal("/tmp/work", conf.Expansions.Get("workdir"))
+	assert.Equal("abcdef", conf.Expansions.Get("revision"))
+	assert.Equal("hi", conf.Expansions.Get("greeting"), "given expansions override the variant's")
+	assert.Equal("shh", conf.Expansions.Get("secret"))
+
+	opts.Variant = "windows"
+	_, err = makeLocalTaskConfig(opts)
+	assert.Error(err)
+
+	opts.Variant = "linux"
+	opts.Task = "unscheduled"
+	_, err = makeLocalTaskConfig(opts)
+	assert.Error(err)
+}
+
+func TestRunLocalTask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test shell commands assume a POSIX shell")
+	}
+
+	for name, test := range map[string]func(*testing.T, string, string){
+		"Succeeds": func(t *testing.T, workDir, output string) {
+			require.NoError(t, runLocalTestTask(workDir, output, "pass"))
+
+			pre, err := ioutil.ReadFile(filepath.Join(workDir, "pre.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "hello\n", string(pre))
+
+			perf, err := ioutil.ReadFile(filepath.Join(output, client.LocalJSONDataDir, "perf.json"))
+			require.NoError(t, err)
+			assert.Contains(t, string(perf), `"ops_per_sec": 100`)
+		},
+		"Fails": func(t *testing.T, workDir, output string) {
+			err := runLocalTestTask(workDir, output, "fail")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "task 'fail' failed")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			workDir, err := ioutil.TempDir("", "run-local")
+			require.NoError(t, err)
+			defer os.RemoveAll(workDir)
+
+			test(t, workDir, filepath.Join(workDir, "output"))
+		})
+	}
+}
+
+func runLocalTestTask(workDir, output, taskName string) error {
+	conf, err := makeLocalTaskConfig(localTaskOptions{
+		Project: []byte(localProject),
+		Variant: "linux",
+		Task:    taskName,
+		WorkDir: workDir,
+	})
+	if err != nil {
+		return err
+	}
+	comm, err := client.NewLocalCommunicator(output)
+	if err != nil {
+		return err
+	}
+	return agent.New(agent.Options{}, comm).RunLocalTask(context.Background(), conf)
+}
@@ -95,11 +95,16 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), 150*time.Second, time.Now(), opts, amboy.GroupQueueOperationFactory(
 		units.PopulateActivationJobs(6),
-		units.PopulateRepotrackerPollingJobs(5)))
+		units.PopulateRepotrackerPollingJobs(5),
+		units.PopulatePriorityPolicyJobs(5)))
 
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), 15*time.Minute, time.Now(), opts, amboy.GroupQueueOperationFactory(
 		units.PopulateCatchupJobs(30),
-		units.PopulateHostAlertJobs(20)))
+		units.PopulateHostAlertJobs(20),
+		units.PopulateHostHealthJobs(env, 30),
+		units.PopulateBuildCacheEvictionJobs(30),
+		units.PopulateArtifactRetentionJobs(30),
+		units.PopulatePerfChangePointJobs(30)))
 
 	////////////////////////////////////////////////////////////////////////
 	//
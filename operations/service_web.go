@@ -54,6 +54,12 @@ func startWebService() cli.Command {
 			grip.SetName("evergreen.service")
 			grip.Notice(message.Fields{"build": evergreen.BuildRevision, "process": grip.Name()})
 
+			var tracer *util.Tracer
+			if settings.Tracer.Enabled {
+				tracer = util.NewTracer(util.NewOTLPSpanExporter(settings.Tracer.CollectorEndpoint, "evergreen"))
+				util.SetTracer(tracer)
+			}
+
 			startSystemCronJobs(ctx, env)
 
 			var (
@@ -114,6 +120,7 @@ func startWebService() cli.Command {
 			ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 			catcher.Add(env.Close(ctx))
+			catcher.Add(tracer.Close(ctx))
 
 			return catcher.Resolve()
 		},
@@ -0,0 +1,247 @@
+package operations
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/debugsession"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func Task() cli.Command {
+	return cli.Command{
+		Name:   "task",
+		Usage:  "inspect evergreen tasks",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			taskLogs(),
+			taskDebug(),
+		},
+	}
+}
+
+func taskLogs() cli.Command {
+	const (
+		taskFlagName      = "task"
+		executionFlagName = "execution"
+		typeFlagName      = "type"
+		offsetFlagName    = "offset"
+		followFlagName    = "follow"
+	)
+
+	return cli.Command{
+		Name:  "logs",
+		Usage: "print a task's logs, optionally following them until the task finishes",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(taskFlagName, "t"),
+				Usage: "the id of the task",
+			},
+			cli.IntFlag{
+				Name:  executionFlagName,
+				Value: -1,
+				Usage: "the task execution (defaults to the latest)",
+			},
+			cli.StringFlag{
+				Name: typeFlagName,
+				Usage: fmt.Sprintf("only print one log channel: %s (task), %s (system) or %s (agent)",
+					apimodels.TaskLogPrefix, apimodels.SystemLogPrefix, apimodels.AgentLogPrefix),
+			},
+			cli.IntFlag{
+				Name:  offsetFlagName,
+				Usage: "skip this many lines, counting lines of every channel",
+			},
+			cli.BoolFlag{
+				Name:  joinFlagNames(followFlagName, "f"),
+				Usage: "keep printing new lines until the task finishes",
+			},
+		},
+		Before: mergeBeforeFuncs(
+			requireStringFlag(taskFlagName),
+			func(c *cli.Context) error {
+				switch c.String(typeFlagName) {
+				case "", apimodels.TaskLogPrefix, apimodels.SystemLogPrefix, apimodels.AgentLogPrefix:
+					return nil
+				default:
+					return errors.Errorf("--%s must be one of %s, %s or %s", typeFlagName,
+						apimodels.TaskLogPrefix, apimodels.SystemLogPrefix, apimodels.AgentLogPrefix)
+				}
+			},
+			func(c *cli.Context) error {
+				if c.Int(offsetFlagName) < 0 {
+					return errors.Errorf("--%s must not be negative", offsetFlagName)
+				}
+				return nil
+			}),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			comm := conf.GetRestCommunicator(ctx)
+			defer comm.Close()
+
+			opts := client.TaskLogStreamOptions{
+				TaskID: c.String(taskFlagName),
+				Type:   c.String(typeFlagName),
+				Offset: c.Int(offsetFlagName),
+				Follow: c.Bool(followFlagName),
+			}
+			if execution := c.Int(executionFlagName); execution >= 0 {
+				opts.Execution = &execution
+			}
+
+			err = comm.StreamTaskLogs(ctx, opts, func(msg apimodels.LogMessage) error {
+				_, err := fmt.Fprintln(os.Stdout, formatLogMessage(msg))
+				return err
+			})
+			return errors.Wrap(err, "problem reading task logs")
+		},
+	}
+}
+
+// debugOutputPollInterval is how often the debug command checks for shell
+// output.
+const debugOutputPollInterval = 500 * time.Millisecond
+
+func taskDebug() cli.Command {
+	const taskFlagName = "task"
+
+	return cli.Command{
+		Name: "debug",
+		Usage: "open an interactive shell into a running task's working directory; " +
+			"commands are relayed through the evergreen server, so output may lag",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(taskFlagName, "t"),
+				Usage: "the id of the running task",
+			},
+		},
+		Before: requireStringFlag(taskFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			comm := conf.GetRestCommunicator(ctx)
+			defer comm.Close()
+
+			session, err := comm.CreateDebugSession(ctx, c.String(taskFlagName))
+			if err != nil {
+				return errors.Wrap(err, "problem opening debug session")
+			}
+			sessionID := model.FromAPIString(session.ID)
+			grip.Infof("opened debug session %s; waiting for the agent to start the shell (exit or ctrl-d to end)", sessionID)
+
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(sigChan)
+			go func() {
+				select {
+				case <-sigChan:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			err = runDebugSession(ctx, comm, sessionID, os.Stdin, os.Stdout, debugOutputPollInterval)
+			if ctx.Err() != nil {
+				closeCtx, closeCancel := context.WithTimeout(context.Background(), time.Minute)
+				defer closeCancel()
+				return errors.Wrap(comm.CloseDebugSession(closeCtx, sessionID), "problem closing debug session")
+			}
+			return err
+		},
+	}
+}
+
+// runDebugSession relays lines read from in to the session's shell and
+// copies the shell's output to out until the session closes. The end of the
+// input exits the shell, which closes the session once its output is sent.
+func runDebugSession(ctx context.Context, comm client.Communicator, sessionID string, in io.Reader, out io.Writer, interval time.Duration) error {
+	inputErr := make(chan error, 1)
+	go func() {
+		inputErr <- sendDebugInput(ctx, comm, sessionID, in)
+	}()
+
+	after := 0
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-inputErr:
+			// the session may have closed; its output is still worth reading
+			grip.Warning(err)
+		case <-timer.C:
+			session, err := comm.GetDebugSessionOutput(ctx, sessionID, after)
+			if err != nil {
+				return errors.Wrap(err, "problem reading debug session output")
+			}
+			for _, chunk := range session.Output {
+				if _, err = io.WriteString(out, chunk.Data); err != nil {
+					return errors.Wrap(err, "problem writing debug session output")
+				}
+				after = chunk.Seq
+			}
+			// keep reading until the closed session's output is drained
+			if model.FromAPIString(session.Status) == debugsession.StatusClosed && len(session.Output) == 0 {
+				if reason := model.FromAPIString(session.CloseReason); reason != "" {
+					grip.Infof("debug session %s closed: %s", sessionID, reason)
+				}
+				return nil
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+func sendDebugInput(ctx context.Context, comm client.Communicator, sessionID string, in io.Reader) error {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if sendErr := comm.SendDebugSessionInput(ctx, sessionID, line); sendErr != nil {
+				return errors.Wrap(sendErr, "problem sending input")
+			}
+		}
+		if err == io.EOF {
+			return errors.Wrap(comm.SendDebugSessionInput(ctx, sessionID, "exit\n"), "problem ending shell")
+		}
+		if err != nil {
+			return errors.Wrap(err, "problem reading input")
+		}
+	}
+}
+
+// formatLogMessage renders a log line the same way as the raw log page.
+func formatLogMessage(msg apimodels.LogMessage) string {
+	if msg.Timestamp.IsZero() {
+		return msg.Message
+	}
+	return msg.Timestamp.Local().Format("[2006/01/02 15:04:05.000] ") + msg.Message
+}
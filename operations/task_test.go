@@ -0,0 +1,48 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDebugSession(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	comm := client.NewMock("url")
+	session, err := comm.CreateDebugSession(ctx, "task")
+	require.NoError(err)
+	id := model.FromAPIString(session.ID)
+
+	// play the agent: wait for the input, answer it, and close the session
+	go func() {
+		for ctx.Err() == nil {
+			resp, err := comm.ExchangeDebugSession(ctx, client.TaskData{}, id, &apimodels.DebugExchangeRequest{})
+			if err == nil && len(resp.Input) == 2 {
+				_, _ = comm.ExchangeDebugSession(ctx, client.TaskData{}, id, &apimodels.DebugExchangeRequest{
+					Output: []string{"you said " + resp.Input[0].Data, "and " + resp.Input[1].Data},
+					Closed: true,
+					Reason: "shell exited",
+				})
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	out := &bytes.Buffer{}
+	require.NoError(runDebugSession(ctx, comm, id, strings.NewReader("hello\n"), out, time.Millisecond))
+	assert.Equal("you said hello\nand exit\n", out.String())
+}
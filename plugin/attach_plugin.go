@@ -43,12 +43,7 @@ func (self *AttachPlugin) Configure(map[string]interface{}) error { return nil }
 func stripHiddenFiles(files []artifact.File, pluginUser gimlet.User) []artifact.File {
 	publicFiles := []artifact.File{}
 	for _, file := range files {
-		switch {
-		case file.Visibility == artifact.None:
-			continue
-		case file.Visibility == artifact.Private && pluginUser == nil:
-			continue
-		default:
+		if file.IsVisible(pluginUser != nil) {
 			publicFiles = append(publicFiles, file)
 		}
 	}
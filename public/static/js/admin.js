@@ -155,7 +155,8 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
     slack_notifications_disabled: "slack_notifications",
     email_notifications_disabled: "email_notifications",
     webhook_notifications_disabled: "webhook_notifications",
-    github_status_api_disabled: "github_status_api"
+    github_status_api_disabled: "github_status_api",
+    perf_change_points_disabled: "perf change points"
   }
 
   timestamp = function(ts) {
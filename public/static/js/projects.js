@@ -280,13 +280,18 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
           enabled: $scope.projectRef.enabled,
           private: $scope.projectRef.private,
           patching_disabled: $scope.projectRef.patching_disabled,
+          debug_sessions_enabled: $scope.projectRef.debug_sessions_enabled || false,
           alert_config: $scope.projectRef.alert_config || {},
           repotracker_error: $scope.projectRef.repotracker_error || {},
           admins : $scope.projectRef.admins || [],
           setup_github_hook: $scope.githubHookID != 0,
           tracks_push_events: data.ProjectRef.tracks_push_events || false,
           pr_testing_enabled: data.ProjectRef.pr_testing_enabled || false,
+          abort_superseded_pr_patches: data.ProjectRef.abort_superseded_pr_patches || false,
+          github_checks_enabled: data.ProjectRef.github_checks_enabled || false,
           notify_on_failure: $scope.projectRef.notify_on_failure,
+          artifact_retention: $scope.projectRef.artifact_retention || [],
+          priority_policy: $scope.projectRef.priority_policy || {},
           force_repotracker_run: false,
           delete_aliases: [],
           delete_subscriptions: [],
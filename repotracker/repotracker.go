@@ -330,10 +330,18 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 			}
 		}
 
+		// the version's trace follows it through its tasks' execution
+		_, span := util.GetTracer().Start(ctx, "create version", map[string]string{
+			"project": ref.Identifier,
+			"version": v.Id,
+		})
+		v.TraceParent = span.TraceParent()
+
 		// We rebind newestVersion each iteration, so the last binding will be the newest version
 		err = errors.Wrapf(createVersionItems(v, ref, project),
 			"Error creating version items for %s in project %s",
 			v.Id, ref.Identifier)
+		span.End(err)
 		if err != nil {
 			grip.Error(message.WrapError(err, message.Fields{
 				"runner":  RunnerName,
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// CreateDebugSession requests an interactive debug session into a running
+// task.
+func (c *communicatorImpl) CreateDebugSession(ctx context.Context, taskID string) (*model.APIDebugSession, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("tasks/%s/debug", taskID),
+	}
+	return c.debugSessionRequest(ctx, info, nil, "creating debug session")
+}
+
+// GetDebugSessionOutput returns a debug session along with the output its
+// shell has written after the given sequence number.
+func (c *communicatorImpl) GetDebugSessionOutput(ctx context.Context, sessionID string, after int) (*model.APIDebugSession, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("debug/%s?after=%d", sessionID, after),
+	}
+	return c.debugSessionRequest(ctx, info, nil, "fetching debug session output")
+}
+
+// SendDebugSessionInput queues input for a debug session's shell.
+func (c *communicatorImpl) SendDebugSessionInput(ctx context.Context, sessionID, data string) error {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("debug/%s/input", sessionID),
+	}
+	body := struct {
+		Data string `json:"data"`
+	}{Data: data}
+
+	resp, err := c.request(ctx, info, body)
+	if err != nil {
+		return errors.Wrapf(err, "problem sending input to debug session '%s'", sessionID)
+	}
+	defer resp.Body.Close()
+
+	return errors.Wrap(readDebugSessionError(resp), "problem sending debug session input")
+}
+
+// CloseDebugSession ends a debug session.
+func (c *communicatorImpl) CloseDebugSession(ctx context.Context, sessionID string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    fmt.Sprintf("debug/%s", sessionID),
+	}
+	_, err := c.debugSessionRequest(ctx, info, nil, "closing debug session")
+	return err
+}
+
+func (c *communicatorImpl) debugSessionRequest(ctx context.Context, info requestInfo, body interface{}, action string) (*model.APIDebugSession, error) {
+	resp, err := c.request(ctx, info, body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem %s", action)
+	}
+	defer resp.Body.Close()
+
+	if err = readDebugSessionError(resp); err != nil {
+		return nil, errors.Wrapf(err, "problem %s", action)
+	}
+
+	session := &model.APIDebugSession{}
+	if err = util.ReadJSONInto(resp.Body, session); err != nil {
+		return nil, errors.Wrap(err, "problem reading debug session from response")
+	}
+	return session, nil
+}
+
+func readDebugSessionError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	restErr := gimlet.ErrorResponse{}
+	if err := util.ReadJSONInto(resp.Body, &restErr); err != nil || restErr.Message == "" {
+		return errors.Errorf("expected 200 OK, got %s", resp.Status)
+	}
+	return restErr
+}
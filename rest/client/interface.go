@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	patchmodel "github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/perf"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
@@ -92,11 +93,29 @@ type Communicator interface {
 	S3Copy(context.Context, TaskData, *apimodels.S3CopyRequest) error
 	KeyValInc(context.Context, TaskData, *model.KeyVal) error
 
+	// SendCacheEvent reports the outcome of a cache.save or
+	// cache.restore command.
+	SendCacheEvent(context.Context, TaskData, *apimodels.CacheEvent) error
+
+	// GetDebugSession and ExchangeDebugSession let the agent run the
+	// debug sessions that users open into its task.
+	GetDebugSession(context.Context, TaskData) (*apimodels.DebugSession, error)
+	ExchangeDebugSession(context.Context, TaskData, string, *apimodels.DebugExchangeRequest) (*apimodels.DebugExchangeResponse, error)
+
+	// PauseTask, GetTaskPauseState and UnpauseTask let the agent hold a
+	// task whose command failed for inspection.
+	PauseTask(context.Context, TaskData, *apimodels.TaskPauseRequest) (*apimodels.TaskPauseResponse, error)
+	GetTaskPauseState(context.Context, TaskData) (*apimodels.TaskPauseResponse, error)
+	UnpauseTask(context.Context, TaskData) error
+
 	// these are for the taskdata/json plugin that saves perf data
 	PostJSONData(context.Context, TaskData, string, interface{}) error
 	GetJSONData(context.Context, TaskData, string, string, string) ([]byte, error)
 	GetJSONHistory(context.Context, TaskData, bool, string, string) ([]byte, error)
 
+	// SendPerfResults sends the results of the `perf.send` command.
+	SendPerfResults(context.Context, TaskData, []perf.Result) error
+
 	// GenerateTasks posts new tasks for the `generate.tasks` command.
 	GenerateTasks(context.Context, TaskData, []json.RawMessage) error
 
@@ -160,4 +179,27 @@ type Communicator interface {
 	// GetSubscriptions fetches the subscriptions for the user defined
 	// in the local evergreen yaml
 	GetSubscriptions(context.Context) ([]event.Subscription, error)
+
+	// Artifact methods list the files attached by a task, build, or
+	// version (optionally limited to one variant), filtered by a glob on
+	// the file name.
+	GetTaskArtifacts(context.Context, string, string) ([]restmodel.APIArtifact, error)
+	GetBuildArtifacts(context.Context, string, string) ([]restmodel.APIArtifact, error)
+	GetVersionArtifacts(context.Context, string, string, string) ([]restmodel.APIArtifact, error)
+
+	// GetVersionProvenance fetches the provenance document generated when
+	// a version finished.
+	GetVersionProvenance(context.Context, string) (*restmodel.APIProvenance, error)
+
+	// StreamTaskLogs reads a task's logs from the live log stream, calling
+	// the handler with each line. When following, it returns once the task
+	// finishes, resuming where it left off if the connection drops.
+	StreamTaskLogs(context.Context, TaskLogStreamOptions, func(apimodels.LogMessage) error) error
+
+	// Debug session methods open an interactive shell into a running task,
+	// exchange its input and output, and close it.
+	CreateDebugSession(context.Context, string) (*restmodel.APIDebugSession, error)
+	GetDebugSessionOutput(context.Context, string, int) (*restmodel.APIDebugSession, error)
+	SendDebugSessionInput(context.Context, string, string) error
+	CloseDebugSession(context.Context, string) error
 }
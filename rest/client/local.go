@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+// Files and directories written by the LocalCommunicator under its
+// directory.
+const (
+	LocalTestResultsFile = "test_results.json"
+	LocalArtifactsFile   = "artifacts.json"
+	LocalGenerateFile    = "generate_tasks.json"
+	LocalTestLogsDir     = "test_logs"
+	LocalJSONDataDir     = "json"
+	LocalPerfResultsFile = "perf_results.json"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+
+// LocalCommunicator is a Communicator for running a task's commands on a
+// developer's machine, without an API server. Task logs are written to
+// standard output, and the test results, test logs, artifacts, JSON data,
+// perf results and generated tasks that commands send are written under
+// its directory.
+// Other operations behave as the Mock's do.
+type LocalCommunicator struct {
+	*Mock
+
+	dir         string
+	testResults []task.TestResult
+	artifacts   []*artifact.File
+	generated   []json.RawMessage
+	perfResults []perf.Result
+	localMu     sync.Mutex
+}
+
+// NewLocalCommunicator returns a LocalCommunicator that writes into dir,
+// creating it if needed.
+func NewLocalCommunicator(dir string) (*LocalCommunicator, error) {
+	for _, sub := range []string{dir, filepath.Join(dir, LocalTestLogsDir), filepath.Join(dir, LocalJSONDataDir)} {
+		if err := os.MkdirAll(sub, 0755); err != nil {
+			return nil, errors.Wrapf(err, "problem creating directory '%s'", sub)
+		}
+	}
+	return &LocalCommunicator{
+		Mock: NewMock(""),
+		dir:  dir,
+	}, nil
+}
+
+// Dir returns the directory the communicator writes into.
+func (c *LocalCommunicator) Dir() string { return c.dir }
+
+// GetLoggerProducer logs the task's output to standard output.
+func (c *LocalCommunicator) GetLoggerProducer(ctx context.Context, td TaskData) LoggerProducer {
+	sender := send.MakePlainLogger()
+	grip.Warning(sender.SetLevel(send.LevelInfo{Default: level.Info, Threshold: level.Info}))
+	return NewSingleChannelLogHarness(td.ID, sender)
+}
+
+// SendTestResults adds the results to the test results file.
+func (c *LocalCommunicator) SendTestResults(ctx context.Context, td TaskData, results *task.LocalTestResults) error {
+	if results == nil {
+		return nil
+	}
+
+	c.localMu.Lock()
+	defer c.localMu.Unlock()
+
+	c.testResults = append(c.testResults, results.Results...)
+	return errors.WithStack(c.writeJSON(LocalTestResultsFile, c.testResults))
+}
+
+// SendTestLog writes the log to a file in the test logs directory and
+// returns the file's path as the log's ID.
+func (c *LocalCommunicator) SendTestLog(ctx context.Context, td TaskData, log *serviceModel.TestLog) (string, error) {
+	if log == nil {
+		return "", nil
+	}
+
+	c.localMu.Lock()
+	defer c.localMu.Unlock()
+
+	path := c.uniquePath(filepath.Join(c.dir, LocalTestLogsDir, localFileName(log.Name)), ".log")
+	content := strings.Join(log.Lines, "\n") + "\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", errors.Wrapf(err, "problem writing test log '%s'", log.Name)
+	}
+	return path, nil
+}
+
+// AttachFiles adds the files to the artifacts file. The files themselves
+// stay wherever the command that attached them put them.
+func (c *LocalCommunicator) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifact.File) error {
+	c.localMu.Lock()
+	defer c.localMu.Unlock()
+
+	c.artifacts = append(c.artifacts, taskFiles...)
+	return errors.WithStack(c.writeJSON(LocalArtifactsFile, c.artifacts))
+}
+
+// PostJSONData writes the data to a file named for it in the JSON data
+// directory, replacing any earlier data of the same name.
+func (c *LocalCommunicator) PostJSONData(ctx context.Context, td TaskData, path string, data interface{}) error {
+	c.localMu.Lock()
+	defer c.localMu.Unlock()
+
+	return errors.WithStack(c.writeJSON(filepath.Join(LocalJSONDataDir, localFileName(path)+".json"), data))
+}
+
+// SendPerfResults adds the results to the perf results file.
+func (c *LocalCommunicator) SendPerfResults(ctx context.Context, td TaskData, results []perf.Result) error {
+	c.localMu.Lock()
+	defer c.localMu.Unlock()
+
+	c.perfResults = append(c.perfResults, results...)
+	return errors.WithStack(c.writeJSON(LocalPerfResultsFile, c.perfResults))
+}
+
+// GetJSONData returns data that the task itself posted. Data from other
+// tasks is not available locally.
+func (c *LocalCommunicator) GetJSONData(ctx context.Context, td TaskData, taskName, dataName, variantName string) ([]byte, error) {
+	c.localMu.Lock()
+	defer c.localMu.Unlock()
+
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, LocalJSONDataDir, localFileName(dataName)+".json"))
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("JSON data '%s' for task '%s' is not available locally", dataName, taskName)
+	}
+	return data, errors.Wrapf(err, "problem reading JSON data '%s'", dataName)
+}
+
+// GetJSONHistory is not available locally.
+func (c *LocalCommunicator) GetJSONHistory(ctx context.Context, td TaskData, tags bool, taskName, dataName string) ([]byte, error) {
+	return nil, errors.Errorf("the history of JSON data '%s' is not available locally", dataName)
+}
+
+// GenerateTasks writes the generated configuration to a file rather than
+// adding tasks to a version.
+func (c *LocalCommunicator) GenerateTasks(ctx context.Context, td TaskData, jsonBytes []json.RawMessage) error {
+	c.localMu.Lock()
+	defer c.localMu.Unlock()
+
+	c.generated = append(c.generated, jsonBytes...)
+	return errors.WithStack(c.writeJSON(LocalGenerateFile, c.generated))
+}
+
+// CreateHost is not available locally.
+func (c *LocalCommunicator) CreateHost(ctx context.Context, td TaskData, options apimodels.CreateHost) error {
+	return errors.New("hosts cannot be created by tasks run locally")
+}
+
+func (c *LocalCommunicator) writeJSON(name string, data interface{}) error {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "problem encoding %s", name)
+	}
+	return errors.Wrapf(ioutil.WriteFile(filepath.Join(c.dir, name), out, 0644), "problem writing %s", name)
+}
+
+// uniquePath returns base+ext, or a numbered variant of it if that file
+// already exists.
+func (c *LocalCommunicator) uniquePath(base, ext string) string {
+	path := base + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s.%d%s", base, i, ext)
+	}
+}
+
+func localFileName(name string) string {
+	name = unsafeFileChars.ReplaceAllString(name, "_")
+	if name == "" || name == "." || name == ".." {
+		return "unnamed"
+	}
+	return name
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	patchmodel "github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/perf"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
@@ -497,6 +498,137 @@ func (c *communicatorImpl) KeyValInc(ctx context.Context, taskData TaskData, kv
 	return nil
 }
 
+func (c *communicatorImpl) SendCacheEvent(ctx context.Context, taskData TaskData, event *apimodels.CacheEvent) error {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("cache/event")
+	resp, err := c.retryRequest(ctx, info, event)
+	if err != nil {
+		return errors.Wrapf(err, "problem sending cache event for %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// GetDebugSession returns the debug session a user has opened into the
+// task. It is polled, so it does not retry.
+func (c *communicatorImpl) GetDebugSession(ctx context.Context, taskData TaskData) (*apimodels.DebugSession, error) {
+	info := requestInfo{
+		method:   get,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("debug")
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting debug session for %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("problem getting debug session for %s: server returned %d", taskData.ID, resp.StatusCode)
+	}
+
+	session := &apimodels.DebugSession{}
+	if err = util.ReadJSONInto(resp.Body, session); err != nil {
+		return nil, errors.Wrapf(err, "problem reading debug session for %s", taskData.ID)
+	}
+	return session, nil
+}
+
+// ExchangeDebugSession sends a debug session's shell output and returns the
+// input typed since the acknowledged chunk. It is called in a loop, so it
+// does not retry.
+func (c *communicatorImpl) ExchangeDebugSession(ctx context.Context, taskData TaskData, sessionID string, req *apimodels.DebugExchangeRequest) (*apimodels.DebugExchangeResponse, error) {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix(fmt.Sprintf("debug/%s", sessionID))
+	resp, err := c.request(ctx, info, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem exchanging debug session %s", sessionID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("problem exchanging debug session %s: server returned %d", sessionID, resp.StatusCode)
+	}
+
+	out := &apimodels.DebugExchangeResponse{}
+	if err = util.ReadJSONInto(resp.Body, out); err != nil {
+		return nil, errors.Wrapf(err, "problem reading debug session %s", sessionID)
+	}
+	return out, nil
+}
+
+// PauseTask reports that a command of the task failed, and returns whether
+// the server has paused the task for inspection.
+func (c *communicatorImpl) PauseTask(ctx context.Context, taskData TaskData, req *apimodels.TaskPauseRequest) (*apimodels.TaskPauseResponse, error) {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("pause")
+	resp, err := c.retryRequest(ctx, info, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem pausing task %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	out := &apimodels.TaskPauseResponse{}
+	if err = util.ReadJSONInto(resp.Body, out); err != nil {
+		return nil, errors.Wrapf(err, "problem reading pause state of task %s", taskData.ID)
+	}
+	return out, nil
+}
+
+// GetTaskPauseState returns whether the task is still paused. It is polled,
+// so it does not retry.
+func (c *communicatorImpl) GetTaskPauseState(ctx context.Context, taskData TaskData) (*apimodels.TaskPauseResponse, error) {
+	info := requestInfo{
+		method:   get,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("pause")
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting pause state of task %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("problem getting pause state of task %s: server returned %d", taskData.ID, resp.StatusCode)
+	}
+
+	out := &apimodels.TaskPauseResponse{}
+	if err = util.ReadJSONInto(resp.Body, out); err != nil {
+		return nil, errors.Wrapf(err, "problem reading pause state of task %s", taskData.ID)
+	}
+	return out, nil
+}
+
+// UnpauseTask ends the task's pause once it runs out.
+func (c *communicatorImpl) UnpauseTask(ctx context.Context, taskData TaskData) error {
+	info := requestInfo{
+		method:   delete,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("pause")
+	resp, err := c.retryRequest(ctx, info, nil)
+	if err != nil {
+		return errors.Wrapf(err, "problem unpausing task %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
 func (c *communicatorImpl) PostJSONData(ctx context.Context, taskData TaskData, path string, data interface{}) error {
 	info := requestInfo{
 		method:   post,
@@ -513,6 +645,22 @@ func (c *communicatorImpl) PostJSONData(ctx context.Context, taskData TaskData,
 	return nil
 }
 
+func (c *communicatorImpl) SendPerfResults(ctx context.Context, taskData TaskData, results []perf.Result) error {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("perf")
+	resp, err := c.retryRequest(ctx, info, results)
+	if err != nil {
+		return errors.Wrapf(err, "problem sending perf results for %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
 func (c *communicatorImpl) GetJSONData(ctx context.Context, taskData TaskData, taskName, dataName, variantName string) ([]byte, error) {
 	pathParts := []string{"json", "data", taskName, dataName}
 	if variantName != "" {
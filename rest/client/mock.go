@@ -13,10 +13,12 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	serviceModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/debugsession"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	patchmodel "github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/perf"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest/model"
@@ -56,12 +58,40 @@ type Mock struct {
 	HeartbeatShouldSometimesErr bool
 	TaskExecution               int
 	GetSubscriptionsFail        bool
+	StreamedTaskLogs            []apimodels.LogMessage
 
 	AttachedFiles    map[string][]*artifact.File
 	LogID            string
 	LocalTestResults *task.LocalTestResults
 	TestLogs         []*serviceModel.TestLog
 	TestLogCount     int
+	CacheEvents      []apimodels.CacheEvent
+
+	// TestResults accumulates every result sent, whereas
+	// LocalTestResults only holds the last batch. JSONData holds the
+	// data posted under each name, in order, and GeneratedTasks the
+	// configuration sent by generate.tasks. PerfResults accumulates the
+	// results sent by perf.send.
+	TestResults    []task.TestResult
+	JSONData       map[string][]interface{}
+	GeneratedTasks []json.RawMessage
+	PerfResults    []perf.Result
+
+	// DebugSession is returned to the agent until the session is closed,
+	// which the agent or a test can do. The agent reads DebugInput and
+	// its shell output is collected in DebugOutput.
+	DebugSession     *apimodels.DebugSession
+	DebugInput       []apimodels.DebugChunk
+	DebugOutput      []string
+	DebugClosed      bool
+	DebugCloseReason string
+
+	// PauseResponse is returned to the agent when a command fails, and
+	// TaskPaused is the state it polls until a test clears it.
+	PauseResponse *apimodels.TaskPauseResponse
+	PauseRequests []apimodels.TaskPauseRequest
+	TaskPaused    bool
+	Unpaused      bool
 
 	// metrics collection
 	ProcInfo map[string][]*message.ProcessInfo
@@ -93,6 +123,7 @@ func NewMock(serverURL string) *Mock {
 		ProcInfo:      make(map[string][]*message.ProcessInfo),
 		SysInfo:       make(map[string]*message.SystemInfo),
 		AttachedFiles: make(map[string][]*artifact.File),
+		JSONData:      make(map[string][]interface{}),
 		serverURL:     serverURL,
 	}
 }
@@ -373,10 +404,24 @@ func (c *Mock) RevertSettings(ctx context.Context, guid string) error { return n
 // SendResults posts a set of test results for the communicator's task.
 // If results are empty or nil, this operation is a noop.
 func (c *Mock) SendTestResults(ctx context.Context, td TaskData, results *task.LocalTestResults) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.LocalTestResults = results
+	if results != nil {
+		c.TestResults = append(c.TestResults, results.Results...)
+	}
 	return nil
 }
 
+// GetTestResults returns every test result sent to the mock.
+func (c *Mock) GetTestResults() []task.TestResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]task.TestResult{}, c.TestResults...)
+}
+
 // SendFiles attaches task files.
 func (c *Mock) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifact.File) error {
 	c.mu.Lock()
@@ -391,6 +436,9 @@ func (c *Mock) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifa
 // SendTestLog posts a test log for a communicator's task. Is a
 // noop if the test Log is nil.
 func (c *Mock) SendTestLog(ctx context.Context, td TaskData, log *serviceModel.TestLog) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.TestLogs = append(c.TestLogs, log)
 	c.TestLogs[len(c.TestLogs)-1].Id = c.LogID
 	c.TestLogCount += 1
@@ -398,6 +446,22 @@ func (c *Mock) SendTestLog(ctx context.Context, td TaskData, log *serviceModel.T
 	return c.LogID, nil
 }
 
+// GetTestLogs returns the test logs sent to the mock.
+func (c *Mock) GetTestLogs() []*serviceModel.TestLog {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]*serviceModel.TestLog{}, c.TestLogs...)
+}
+
+// GetAttachedFiles returns the files attached to the task.
+func (c *Mock) GetAttachedFiles(taskID string) []*artifact.File {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]*artifact.File{}, c.AttachedFiles[taskID]...)
+}
+
 func (c *Mock) GetManifest(ctx context.Context, td TaskData) (*manifest.Manifest, error) {
 	return &manifest.Manifest{}, nil
 }
@@ -416,10 +480,128 @@ func (c *Mock) KeyValInc(ctx context.Context, td TaskData, kv *serviceModel.KeyV
 	return nil
 }
 
+func (c *Mock) SendCacheEvent(ctx context.Context, td TaskData, event *apimodels.CacheEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.CacheEvents = append(c.CacheEvents, *event)
+	return nil
+}
+
+func (c *Mock) GetDebugSession(ctx context.Context, td TaskData) (*apimodels.DebugSession, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.DebugSession == nil || c.DebugClosed {
+		return &apimodels.DebugSession{}, nil
+	}
+	session := *c.DebugSession
+	return &session, nil
+}
+
+func (c *Mock) ExchangeDebugSession(ctx context.Context, td TaskData, id string, req *apimodels.DebugExchangeRequest) (*apimodels.DebugExchangeResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.DebugOutput = append(c.DebugOutput, req.Output...)
+	if req.Closed {
+		c.DebugClosed = true
+		c.DebugCloseReason = req.Reason
+	}
+
+	resp := &apimodels.DebugExchangeResponse{Closed: c.DebugClosed}
+	for _, chunk := range c.DebugInput {
+		if chunk.Seq > req.InputAck {
+			resp.Input = append(resp.Input, chunk)
+		}
+	}
+	return resp, nil
+}
+
+func (c *Mock) PauseTask(ctx context.Context, td TaskData, req *apimodels.TaskPauseRequest) (*apimodels.TaskPauseResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.PauseRequests = append(c.PauseRequests, *req)
+	if c.PauseResponse == nil {
+		return &apimodels.TaskPauseResponse{}, nil
+	}
+	c.TaskPaused = c.PauseResponse.Paused
+	resp := *c.PauseResponse
+	return &resp, nil
+}
+
+func (c *Mock) GetTaskPauseState(ctx context.Context, td TaskData) (*apimodels.TaskPauseResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.TaskPaused || c.PauseResponse == nil {
+		return &apimodels.TaskPauseResponse{}, nil
+	}
+	resp := *c.PauseResponse
+	return &resp, nil
+}
+
+func (c *Mock) UnpauseTask(ctx context.Context, td TaskData) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.TaskPaused = false
+	c.Unpaused = true
+	return nil
+}
+
+// ClearTaskPause lets the agent finish a task that the mock paused, as a
+// user resuming it would.
+func (c *Mock) ClearTaskPause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.TaskPaused = false
+}
+
+// GetPauseRequests returns the pause requests the agent has sent.
+func (c *Mock) GetPauseRequests() []apimodels.TaskPauseRequest {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]apimodels.TaskPauseRequest{}, c.PauseRequests...)
+}
+
 func (c *Mock) PostJSONData(ctx context.Context, td TaskData, path string, data interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.JSONData[path] = append(c.JSONData[path], data)
+	return nil
+}
+
+// GetPostedJSONData returns the data posted under the name, in the order
+// it was posted.
+func (c *Mock) GetPostedJSONData(name string) []interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]interface{}{}, c.JSONData[name]...)
+}
+
+func (c *Mock) SendPerfResults(ctx context.Context, td TaskData, results []perf.Result) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.PerfResults = append(c.PerfResults, results...)
 	return nil
 }
 
+// GetPerfResults returns the perf results sent, in the order they were
+// sent.
+func (c *Mock) GetPerfResults() []perf.Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]perf.Result{}, c.PerfResults...)
+}
+
 func (c *Mock) GetJSONData(ctx context.Context, td TaskData, tn, dn, vn string) ([]byte, error) {
 	return nil, nil
 }
@@ -516,9 +698,22 @@ func (c *Mock) GenerateTasks(ctx context.Context, td TaskData, jsonBytes []json.
 	if td.Secret != "mock_secret" {
 		return errors.New("mock failed, wrong secret")
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.GeneratedTasks = append(c.GeneratedTasks, jsonBytes...)
 	return nil
 }
 
+// GetGeneratedTasks returns the configuration sent by generate.tasks.
+func (c *Mock) GetGeneratedTasks() []json.RawMessage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]json.RawMessage{}, c.GeneratedTasks...)
+}
+
 func (c *Mock) CreateHost(ctx context.Context, td TaskData, options apimodels.CreateHost) error {
 	if td.ID == "" {
 		return errors.New("no task ID sent to CreateHost")
@@ -554,3 +749,88 @@ func (c *Mock) GetSubscriptions(_ context.Context) ([]event.Subscription, error)
 		},
 	}, nil
 }
+
+func (c *Mock) GetTaskArtifacts(_ context.Context, _, _ string) ([]model.APIArtifact, error) {
+	return nil, nil
+}
+
+func (c *Mock) GetBuildArtifacts(_ context.Context, _, _ string) ([]model.APIArtifact, error) {
+	return nil, nil
+}
+
+func (c *Mock) GetVersionArtifacts(_ context.Context, _, _, _ string) ([]model.APIArtifact, error) {
+	return nil, nil
+}
+
+func (c *Mock) GetVersionProvenance(_ context.Context, _ string) (*model.APIProvenance, error) {
+	return nil, nil
+}
+
+func (c *Mock) StreamTaskLogs(_ context.Context, opts TaskLogStreamOptions, handler func(apimodels.LogMessage) error) error {
+	for i, msg := range c.StreamedTaskLogs {
+		if i < opts.Offset {
+			continue
+		}
+		if err := handler(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The debug session methods act as the user's side of the session the
+// agent sees through GetDebugSession and ExchangeDebugSession.
+func (c *Mock) CreateDebugSession(_ context.Context, taskID string) (*model.APIDebugSession, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.DebugSession = &apimodels.DebugSession{ID: "debug-" + taskID, User: "mock"}
+	c.DebugInput = nil
+	c.DebugOutput = nil
+	c.DebugClosed = false
+	c.DebugCloseReason = ""
+	return c.mockDebugSession(0), nil
+}
+
+func (c *Mock) GetDebugSessionOutput(_ context.Context, _ string, after int) (*model.APIDebugSession, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.mockDebugSession(after), nil
+}
+
+func (c *Mock) SendDebugSessionInput(_ context.Context, _, data string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.DebugClosed {
+		return errors.New("debug session is closed")
+	}
+	c.DebugInput = append(c.DebugInput, apimodels.DebugChunk{Seq: len(c.DebugInput) + 1, Data: data})
+	return nil
+}
+
+func (c *Mock) CloseDebugSession(_ context.Context, _ string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.DebugClosed = true
+	c.DebugCloseReason = "closed by mock"
+	return nil
+}
+
+func (c *Mock) mockDebugSession(after int) *model.APIDebugSession {
+	s := &model.APIDebugSession{Status: model.ToAPIString(debugsession.StatusActive)}
+	if c.DebugSession != nil {
+		s.ID = model.ToAPIString(c.DebugSession.ID)
+		s.User = model.ToAPIString(c.DebugSession.User)
+	}
+	if c.DebugClosed {
+		s.Status = model.ToAPIString(debugsession.StatusClosed)
+		s.CloseReason = model.ToAPIString(c.DebugCloseReason)
+	}
+	for i := after; i < len(c.DebugOutput); i++ {
+		s.Output = append(s.Output, model.APIDebugOutput{Seq: i + 1, Data: c.DebugOutput[i]})
+	}
+	return s
+}
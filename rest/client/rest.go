@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -611,3 +612,93 @@ func (c *communicatorImpl) GetSubscriptions(ctx context.Context) ([]event.Subscr
 
 	return subs, nil
 }
+
+func (c *communicatorImpl) GetTaskArtifacts(ctx context.Context, taskID, name string) ([]model.APIArtifact, error) {
+	return c.getArtifacts(ctx, fmt.Sprintf("tasks/%s/artifacts", taskID), map[string]string{"name": name})
+}
+
+func (c *communicatorImpl) GetBuildArtifacts(ctx context.Context, buildID, name string) ([]model.APIArtifact, error) {
+	return c.getArtifacts(ctx, fmt.Sprintf("builds/%s/artifacts", buildID), map[string]string{"name": name})
+}
+
+func (c *communicatorImpl) GetVersionArtifacts(ctx context.Context, versionID, variant, name string) ([]model.APIArtifact, error) {
+	return c.getArtifacts(ctx, fmt.Sprintf("versions/%s/artifacts", versionID), map[string]string{
+		"name":    name,
+		"variant": variant,
+	})
+}
+
+func (c *communicatorImpl) getArtifacts(ctx context.Context, path string, filters map[string]string) ([]model.APIArtifact, error) {
+	query := url.Values{}
+	for k, v := range filters {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    path,
+	}
+
+	p, err := newPaginatorHelper(&info, c)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	artifacts := []model.APIArtifact{}
+	for p.hasMore() {
+		resp, err := p.getNextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem fetching artifacts from '%s'", path)
+		}
+
+		page := []model.APIArtifact{}
+		err = util.ReadJSONInto(resp.Body, &page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "problem reading artifacts from response")
+		}
+		artifacts = append(artifacts, page...)
+
+		// the pagination links only carry the page's key and limit, so
+		// the filters have to be reapplied to each subsequent page
+		if next := p.getNextPagePath(); next != "" && len(query) > 0 {
+			p.setNextPagePath(next + "&" + query.Encode())
+		}
+	}
+
+	return artifacts, nil
+}
+
+func (c *communicatorImpl) GetVersionProvenance(ctx context.Context, versionID string) (*model.APIProvenance, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("versions/%s/provenance", versionID),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching provenance for version '%s'", versionID)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		restErr := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &restErr); err != nil || restErr.Message == "" {
+			return nil, errors.Errorf("expected 200 OK while fetching provenance, got %s", resp.Status)
+		}
+		return nil, errors.Wrap(restErr, "server returned error while fetching provenance")
+	}
+
+	doc := &model.APIProvenance{}
+	if err = util.ReadJSONInto(resp.Body, doc); err != nil {
+		return nil, errors.Wrap(err, "problem reading provenance from response")
+	}
+	return doc, nil
+}
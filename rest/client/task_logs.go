@@ -0,0 +1,208 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// maxLogEventSize bounds the size of a single server-sent event line.
+const maxLogEventSize = 16 * 1024 * 1024
+
+// TaskLogStreamOptions selects the logs that StreamTaskLogs reads.
+type TaskLogStreamOptions struct {
+	TaskID string
+	// Execution is the task execution to read; nil reads the latest.
+	Execution *int
+	// Type limits the stream to one log channel: apimodels.TaskLogPrefix,
+	// SystemLogPrefix or AgentLogPrefix. Empty reads every channel.
+	Type string
+	// Offset is the number of the first line to read, counting lines of
+	// every type.
+	Offset int
+	// Follow keeps the stream open until the task finishes; otherwise
+	// only the lines written so far are read.
+	Follow bool
+}
+
+// errLogStreamInterrupted reports a stream that closed before the server
+// sent its end event, so it can be resumed.
+var errLogStreamInterrupted = errors.New("task log stream interrupted")
+
+// errLogStreamReconnect reports a stream that the server closed before its
+// write timeout, which is resumed straight away.
+var errLogStreamReconnect = errors.New("task log stream must reconnect")
+
+func (c *communicatorImpl) StreamTaskLogs(ctx context.Context, opts TaskLogStreamOptions, handler func(apimodels.LogMessage) error) error {
+	if opts.TaskID == "" {
+		return errors.New("must specify a task id")
+	}
+
+	next := opts.Offset
+	backoff := c.getBackoff()
+	attempt := 0
+	for {
+		from := next
+		var err error
+		next, err = c.streamTaskLogsOnce(ctx, opts, from, handler)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return errors.WithStack(ctx.Err())
+		}
+		if errors.Cause(err) == errLogStreamReconnect {
+			attempt = 0
+			backoff.Reset()
+			continue
+		}
+		if errors.Cause(err) != errLogStreamInterrupted {
+			return err
+		}
+
+		if next > from {
+			attempt = 0
+			backoff.Reset()
+		}
+		attempt++
+		if attempt > c.maxAttempts {
+			return errors.Wrapf(err, "task log stream failed after %d attempts", c.maxAttempts)
+		}
+
+		wait := backoff.Duration()
+		grip.Debug(message.Fields{
+			"message":   "reconnecting to task log stream",
+			"task_id":   opts.TaskID,
+			"offset":    next,
+			"attempt":   attempt,
+			"wait_secs": wait.Seconds(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.WithStack(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// streamTaskLogsOnce reads a single connection's worth of the stream,
+// starting from the given line, and returns the line to resume from.
+func (c *communicatorImpl) streamTaskLogsOnce(ctx context.Context, opts TaskLogStreamOptions, offset int, handler func(apimodels.LogMessage) error) (int, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	if opts.Execution != nil {
+		query.Set("execution", strconv.Itoa(*opts.Execution))
+	}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+	if !opts.Follow {
+		query.Set("follow", "false")
+	}
+	path := fmt.Sprintf("tasks/%s/logs/stream?%s", opts.TaskID, query.Encode())
+
+	r, err := c.newRequest(string(get), path, "", string(apiVersion2), nil)
+	if err != nil {
+		return offset, errors.Wrap(err, "problem creating request")
+	}
+	r.Header.Set("Accept", "text/event-stream")
+
+	// the stream is long lived, so it can't use the client's request timeout
+	httpClient := util.GetHTTPClient()
+	defer util.PutHTTPClient(httpClient)
+	httpClient.Timeout = 0
+
+	resp, err := httpClient.Do(r.WithContext(ctx))
+	if err != nil {
+		return offset, errors.Wrap(errLogStreamInterrupted, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return offset, errors.Wrapf(errLogStreamInterrupted, "server returned %d (%s)", resp.StatusCode, string(body))
+		}
+		return offset, errors.Errorf("server returned %d (%s)", resp.StatusCode, string(body))
+	}
+
+	return readTaskLogEvents(resp.Body, offset, handler)
+}
+
+// readTaskLogEvents decodes server-sent events, calling the handler for each
+// log line, until the server sends its end event. It returns the number of
+// the line after the last one handled, which is where the stream resumes.
+func readTaskLogEvents(body io.Reader, offset int, handler func(apimodels.LogMessage) error) (int, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxLogEventSize)
+
+	next := offset
+	event := ""
+	id := ""
+	data := []string{}
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			payload := strings.Join(data, "\n")
+			switch event {
+			case "log":
+				msg := apimodels.LogMessage{}
+				if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+					return next, errors.Wrap(err, "problem reading log line")
+				}
+				if err := handler(msg); err != nil {
+					return next, errors.WithStack(err)
+				}
+				if lineNum, err := strconv.Atoi(id); err == nil {
+					next = lineNum + 1
+				} else {
+					next++
+				}
+			case "error":
+				streamErr := map[string]string{}
+				if err := json.Unmarshal([]byte(payload), &streamErr); err != nil {
+					return next, errors.Wrap(err, "problem reading stream error")
+				}
+				return next, errors.Wrap(errLogStreamInterrupted, streamErr["error"])
+			case "end":
+				return next, nil
+			case "reconnect":
+				return next, errLogStreamReconnect
+			}
+			event = ""
+			id = ""
+			data = data[:0]
+		case strings.HasPrefix(line, ":"):
+			// comments keep the connection alive
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return next, errors.Wrap(errLogStreamInterrupted, err.Error())
+	}
+
+	return next, errLogStreamInterrupted
+}
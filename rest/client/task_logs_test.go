@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTaskLogEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	body := strings.Join([]string{
+		": keepalive",
+		"",
+		"id: 0",
+		"event: log",
+		`data: {"t":"T","m":"first"}`,
+		"",
+		"id: 1",
+		"event: log",
+		`data: {"t":"E","m":"second"}`,
+		"",
+		"event: end",
+		"data: {}",
+		"",
+		"id: 2",
+		"event: log",
+		`data: {"t":"T","m":"after end"}`,
+		"",
+	}, "\n")
+
+	msgs := []string{}
+	next, err := readTaskLogEvents(strings.NewReader(body), 0, func(msg apimodels.LogMessage) error {
+		msgs = append(msgs, msg.Message)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(2, next)
+	assert.Equal([]string{"first", "second"}, msgs)
+
+	// a stream that stops without an end event can be resumed
+	next, err = readTaskLogEvents(strings.NewReader("event: log\ndata: {\"m\":\"x\"}\n\n"), 7, func(apimodels.LogMessage) error { return nil })
+	assert.Equal(8, next)
+	assert.Equal(errLogStreamInterrupted, errors.Cause(err))
+
+	// the server asks the client to reconnect before its write timeout
+	next, err = readTaskLogEvents(strings.NewReader("id: 4\nevent: log\ndata: {\"m\":\"x\"}\n\nevent: reconnect\ndata: {}\n\n"), 4, func(apimodels.LogMessage) error { return nil })
+	assert.Equal(5, next)
+	assert.Equal(errLogStreamReconnect, errors.Cause(err))
+
+	next, err = readTaskLogEvents(strings.NewReader("event: error\ndata: {\"error\":\"db down\"}\n\n"), 0, func(apimodels.LogMessage) error { return nil })
+	assert.Equal(0, next)
+	assert.Equal(errLogStreamInterrupted, errors.Cause(err))
+	assert.Contains(err.Error(), "db down")
+}
+
+func TestStreamTaskLogsResumes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	offsets := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		assert.Equal("/rest/v2/tasks/t1/logs/stream", r.URL.Path)
+		assert.Equal("T", r.URL.Query().Get("type"))
+		assert.Equal("", r.URL.Query().Get("follow"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if len(offsets) == 1 {
+			// drop the connection after two lines, the second of which
+			// follows lines of other types
+			fmt.Fprint(w, "id: 3\nevent: log\ndata: {\"m\":\"three\"}\n\nid: 6\nevent: log\ndata: {\"m\":\"six\"}\n\n")
+			return
+		}
+		fmt.Fprint(w, "id: 7\nevent: log\ndata: {\"m\":\"seven\"}\n\nevent: end\ndata: {}\n\n")
+	}))
+	defer server.Close()
+
+	comm := NewCommunicator(server.URL).(*communicatorImpl)
+	comm.SetTimeoutStart(time.Millisecond)
+	comm.SetTimeoutMax(10 * time.Millisecond)
+	defer comm.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msgs := []string{}
+	err := comm.StreamTaskLogs(ctx, TaskLogStreamOptions{
+		TaskID: "t1",
+		Type:   apimodels.TaskLogPrefix,
+		Offset: 3,
+		Follow: true,
+	}, func(msg apimodels.LogMessage) error {
+		msgs = append(msgs, msg.Message)
+		return nil
+	})
+	require.NoError(err)
+	assert.Equal([]string{"three", "six", "seven"}, msgs)
+	assert.Equal([]string{"3", "7"}, offsets)
+}
+
+func TestStreamTaskLogsClientErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	comm := NewCommunicator(server.URL)
+	defer comm.Close()
+
+	err := comm.StreamTaskLogs(context.Background(), TaskLogStreamOptions{TaskID: "missing"}, func(apimodels.LogMessage) error { return nil })
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+
+	assert.Error(t, comm.StreamTaskLogs(context.Background(), TaskLogStreamOptions{}, func(apimodels.LogMessage) error { return nil }))
+}
@@ -0,0 +1,147 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DBArtifactConnector is a struct that implements the Artifact related
+// methods from the Connector through interactions with the backing
+// database.
+type DBArtifactConnector struct{}
+
+// FindArtifactsByTaskId returns the artifacts attached by the given
+// execution of a task. A negative execution selects the task's latest
+// execution.
+func (ac *DBArtifactConnector) FindArtifactsByTaskId(taskId string, execution int) ([]artifact.Entry, error) {
+	t, err := task.FindOneId(taskId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding task '%s'", taskId)
+	}
+	if t == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found", taskId),
+		}
+	}
+	if execution < 0 {
+		execution = t.Execution
+	}
+
+	return findArtifactsForTasks([]task.Task{{Id: t.Id, Execution: execution}})
+}
+
+// FindArtifactsByBuildId returns the artifacts attached by the latest
+// executions of a build's tasks.
+func (ac *DBArtifactConnector) FindArtifactsByBuildId(buildId string) ([]artifact.Entry, error) {
+	tasks, err := task.Find(task.ByBuildId(buildId).WithFields(task.IdKey, task.ExecutionKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding tasks for build '%s'", buildId)
+	}
+	if len(tasks) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no tasks found for build '%s'", buildId),
+		}
+	}
+
+	return findArtifactsForTasks(tasks)
+}
+
+// FindArtifactsByVersionId returns the artifacts attached by the latest
+// executions of a version's tasks, optionally limited to one build
+// variant.
+func (ac *DBArtifactConnector) FindArtifactsByVersionId(versionId, variant string) ([]artifact.Entry, error) {
+	query := bson.M{task.VersionKey: versionId}
+	if variant != "" {
+		query[task.BuildVariantKey] = variant
+	}
+	q := db.Query(query)
+	tasks, err := task.Find(q.WithFields(task.IdKey, task.ExecutionKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding tasks for version '%s'", versionId)
+	}
+	if len(tasks) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no tasks found for version '%s'", versionId),
+		}
+	}
+
+	return findArtifactsForTasks(tasks)
+}
+
+func findArtifactsForTasks(tasks []task.Task) ([]artifact.Entry, error) {
+	ids := make([]artifact.TaskIDAndExecution, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, artifact.TaskIDAndExecution{TaskID: t.Id, Execution: t.Execution})
+	}
+
+	entries, err := artifact.FindAll(artifact.ByTaskIdsAndExecutions(ids))
+	return entries, errors.Wrap(err, "problem finding artifacts")
+}
+
+// MockArtifactConnector stores a cached set of artifacts and tasks that
+// are queried against by the implementations of the Connector
+// interface's Artifact related functions.
+type MockArtifactConnector struct {
+	CachedArtifacts []artifact.Entry
+	CachedTasks     []task.Task
+	StoredError     error
+}
+
+func (mac *MockArtifactConnector) FindArtifactsByTaskId(taskId string, execution int) ([]artifact.Entry, error) {
+	if mac.StoredError != nil {
+		return nil, mac.StoredError
+	}
+
+	entries := []artifact.Entry{}
+	for _, entry := range mac.CachedArtifacts {
+		if entry.TaskId == taskId && (execution < 0 || entry.Execution == execution) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (mac *MockArtifactConnector) FindArtifactsByBuildId(buildId string) ([]artifact.Entry, error) {
+	if mac.StoredError != nil {
+		return nil, mac.StoredError
+	}
+
+	entries := []artifact.Entry{}
+	for _, entry := range mac.CachedArtifacts {
+		if entry.BuildId == buildId {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (mac *MockArtifactConnector) FindArtifactsByVersionId(versionId, variant string) ([]artifact.Entry, error) {
+	if mac.StoredError != nil {
+		return nil, mac.StoredError
+	}
+
+	taskIds := map[string]bool{}
+	for _, t := range mac.CachedTasks {
+		if t.Version == versionId && (variant == "" || t.BuildVariant == variant) {
+			taskIds[t.Id] = true
+		}
+	}
+
+	entries := []artifact.Entry{}
+	for _, entry := range mac.CachedArtifacts {
+		if taskIds[entry.TaskId] {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
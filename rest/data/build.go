@@ -57,6 +57,16 @@ func (bc *DBBuildConnector) RestartBuild(buildId string, user string) error {
 	return model.RestartBuildTasks(buildId, user)
 }
 
+// FindGithubCheckRunProject wraps the service level GithubCheckRunProject
+func (bc *DBBuildConnector) FindGithubCheckRunProject(externalID string) (string, error) {
+	return model.GithubCheckRunProject(externalID)
+}
+
+// RestartFailedGithubCheckTasks wraps the service level RestartFailedGithubCheckTasks
+func (bc *DBBuildConnector) RestartFailedGithubCheckTasks(externalID string, user string) error {
+	return model.RestartFailedGithubCheckTasks(externalID, user)
+}
+
 // MockBuildConnector is a struct that implements the Build related methods
 // from the Connector through interactions with the backing database.
 type MockBuildConnector struct {
@@ -66,6 +76,7 @@ type MockBuildConnector struct {
 	FailOnChangePriority bool
 	FailOnAbort          bool
 	FailOnRestart        bool
+	CachedRestartedCheck map[string]string
 }
 
 // FindBuildById iterates through the CachedBuilds slice to find the build
@@ -127,3 +138,27 @@ func (bc *MockBuildConnector) RestartBuild(buildId string, user string) error {
 	}
 	return nil
 }
+
+// FindGithubCheckRunProject returns the project of the cached build that
+// the check run with the input external ID reports.
+func (bc *MockBuildConnector) FindGithubCheckRunProject(externalID string) (string, error) {
+	for _, b := range bc.CachedBuilds {
+		if model.GithubCheckRunExternalID(b.Id, "") == externalID {
+			return b.Project, nil
+		}
+	}
+	return "", errors.Errorf("check run '%s' not found", externalID)
+}
+
+// RestartFailedGithubCheckTasks sets the value of the input external ID in
+// CachedRestartedCheck to the user.
+func (bc *MockBuildConnector) RestartFailedGithubCheckTasks(externalID string, user string) error {
+	if bc.FailOnRestart {
+		return errors.New("manufactured error")
+	}
+	if bc.CachedRestartedCheck == nil {
+		bc.CachedRestartedCheck = map[string]string{}
+	}
+	bc.CachedRestartedCheck[externalID] = user
+	return nil
+}
@@ -0,0 +1,31 @@
+package data
+
+import (
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/pkg/errors"
+)
+
+// DBBuildCacheConnector is a struct that implements the build cache
+// related methods from the Connector through interactions with the
+// backing database.
+type DBBuildCacheConnector struct{}
+
+// FindBuildCacheStats returns a project's cache counters.
+func (bc *DBBuildCacheConnector) FindBuildCacheStats(project string) (*buildcache.ProjectStats, error) {
+	stats, err := buildcache.FindStats(project)
+	return stats, errors.WithStack(err)
+}
+
+// MockBuildCacheConnector is a struct that implements the build cache
+// related methods from the Connector with in-memory counters.
+type MockBuildCacheConnector struct {
+	CachedBuildCacheStats map[string]buildcache.ProjectStats
+}
+
+func (bc *MockBuildCacheConnector) FindBuildCacheStats(project string) (*buildcache.ProjectStats, error) {
+	stats, ok := bc.CachedBuildCacheStats[project]
+	if !ok {
+		return &buildcache.ProjectStats{Project: project}, nil
+	}
+	return &stats, nil
+}
@@ -0,0 +1,182 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/debugsession"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DBDebugSessionConnector is a struct that implements the debug session
+// related methods from the Connector through interactions with the backing
+// database.
+type DBDebugSessionConnector struct{}
+
+// CreateDebugSession requests a debug session into the running task.
+func (dc *DBDebugSessionConnector) CreateDebugSession(t *task.Task, user string) (*debugsession.Session, error) {
+	s, err := debugsession.Create(t.Id, t.Execution, t.HostId, user)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return s, nil
+}
+
+// FindDebugSessionById returns the debug session with the given id.
+func (dc *DBDebugSessionConnector) FindDebugSessionById(id string) (*debugsession.Session, error) {
+	s, err := debugsession.FindOne(debugsession.ById(id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding debug session '%s'", id)
+	}
+	if s == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("debug session '%s' not found", id),
+		}
+	}
+	return s, nil
+}
+
+// AppendDebugInput queues input for the session's shell and returns its
+// sequence number.
+func (dc *DBDebugSessionConnector) AppendDebugInput(s *debugsession.Session, data string) (int, error) {
+	if !s.IsOpen() {
+		return 0, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("debug session '%s' is closed", s.ID),
+		}
+	}
+	return s.Append(debugsession.StreamInput, data)
+}
+
+// FindDebugOutput returns up to limit chunks of the session's output after
+// the given sequence number.
+func (dc *DBDebugSessionConnector) FindDebugOutput(id string, after, limit int) ([]debugsession.Chunk, error) {
+	chunks, err := debugsession.FindChunks(id, debugsession.StreamOutput, after, limit)
+	return chunks, errors.Wrapf(err, "problem finding output of debug session '%s'", id)
+}
+
+// CloseDebugSession ends the session.
+func (dc *DBDebugSessionConnector) CloseDebugSession(s *debugsession.Session, reason string) error {
+	return errors.WithStack(s.Close(reason))
+}
+
+// MockDebugSessionConnector is a struct that implements the debug session
+// related methods from the Connector with in-memory sessions.
+type MockDebugSessionConnector struct {
+	CachedSessions []debugsession.Session
+	CachedChunks   []debugsession.Chunk
+
+	mu sync.Mutex
+}
+
+func (dc *MockDebugSessionConnector) CreateDebugSession(t *task.Task, user string) (*debugsession.Session, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for _, s := range dc.CachedSessions {
+		if s.TaskID == t.Id && s.Execution == t.Execution && s.IsOpen() {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("task '%s' already has an open debug session '%s'", t.Id, s.ID),
+			}
+		}
+	}
+
+	s := debugsession.Session{
+		ID:        bson.NewObjectId().Hex(),
+		TaskID:    t.Id,
+		Execution: t.Execution,
+		HostID:    t.HostId,
+		User:      user,
+		Status:    debugsession.StatusRequested,
+		CreatedAt: time.Now(),
+	}
+	dc.CachedSessions = append(dc.CachedSessions, s)
+	return &s, nil
+}
+
+func (dc *MockDebugSessionConnector) FindDebugSessionById(id string) (*debugsession.Session, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for _, s := range dc.CachedSessions {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("debug session '%s' not found", id),
+	}
+}
+
+func (dc *MockDebugSessionConnector) AppendDebugInput(s *debugsession.Session, data string) (int, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for i := range dc.CachedSessions {
+		cached := &dc.CachedSessions[i]
+		if cached.ID != s.ID {
+			continue
+		}
+		if !cached.IsOpen() {
+			return 0, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("debug session '%s' is closed", s.ID),
+			}
+		}
+		cached.InputSeq++
+		dc.CachedChunks = append(dc.CachedChunks, debugsession.Chunk{
+			ID:        bson.NewObjectId(),
+			SessionID: s.ID,
+			Stream:    debugsession.StreamInput,
+			Seq:       cached.InputSeq,
+			Data:      data,
+			Timestamp: time.Now(),
+		})
+		*s = *cached
+		return cached.InputSeq, nil
+	}
+	return 0, errors.Errorf("debug session '%s' not found", s.ID)
+}
+
+func (dc *MockDebugSessionConnector) FindDebugOutput(id string, after, limit int) ([]debugsession.Chunk, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	chunks := []debugsession.Chunk{}
+	for _, c := range dc.CachedChunks {
+		if len(chunks) == limit {
+			break
+		}
+		if c.SessionID == id && c.Stream == debugsession.StreamOutput && c.Seq > after {
+			chunks = append(chunks, c)
+		}
+	}
+	return chunks, nil
+}
+
+func (dc *MockDebugSessionConnector) CloseDebugSession(s *debugsession.Session, reason string) error {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for i := range dc.CachedSessions {
+		cached := &dc.CachedSessions[i]
+		if cached.ID == s.ID && cached.IsOpen() {
+			cached.Status = debugsession.StatusClosed
+			cached.ClosedAt = time.Now()
+			cached.CloseReason = reason
+			*s = *cached
+		}
+	}
+	return nil
+}
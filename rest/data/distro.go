@@ -5,11 +5,14 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
 )
 
 // DBDistroConnector is a struct that implements the Distro related methods
@@ -73,6 +76,22 @@ func (tc *DBDistroConnector) ClearTaskQueue(distroId string) error {
 	return model.ClearTaskQueue(distroId)
 }
 
+// FindProjectShares returns the fair-share accounting of the projects that
+// have queued tasks on a distro or have recently used it.
+func (tc *DBDistroConnector) FindProjectShares(distroId string) ([]model.ProjectShare, error) {
+	d, err := distro.FindOne(distro.ById(distroId))
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("distro '%s' not found", distroId),
+			}
+		}
+		return nil, errors.Wrapf(err, "error finding distro with id %s", distroId)
+	}
+	return model.FindProjectShares(d.Id)
+}
+
 // MockDistroConnector is a struct that implements mock versions of
 // Distro-related methods for testing.
 type MockDistroConnector struct {
@@ -126,3 +145,33 @@ func (mdc *MockDistroConnector) FindCostByDistroId(distroId string,
 func (mdc *MockDistroConnector) ClearTaskQueue(distroId string) error {
 	return errors.New("ClearTaskQueue unimplemented for mock")
 }
+
+// FindProjectShares computes the projects' shares from the cached tasks:
+// queued tasks are the undispatched, activated tasks on the distro.
+func (mdc *MockDistroConnector) FindProjectShares(distroId string) ([]model.ProjectShare, error) {
+	for i := range mdc.CachedDistros {
+		d := &mdc.CachedDistros[i]
+		if d.Id != distroId {
+			continue
+		}
+
+		now := time.Now()
+		queued := map[string]int{}
+		finished := []task.Task{}
+		for _, t := range mdc.CachedTasks {
+			if t.DistroId != distroId {
+				continue
+			}
+			if t.Status == evergreen.TaskUndispatched && t.Activated {
+				queued[t.Project]++
+			} else if util.StringSliceContains(evergreen.CompletedStatuses, t.Status) {
+				finished = append(finished, t)
+			}
+		}
+		return model.ComputeProjectShares(d, queued, model.DecayedUsage(finished, now, d.FairShare.UsageHalfLife())), nil
+	}
+	return nil, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("distro '%s' not found", distroId),
+	}
+}
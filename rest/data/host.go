@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -102,6 +103,25 @@ func (hc *DBHostConnector) TerminateHost(ctx context.Context, host *host.Host, u
 	return errors.WithStack(cloud.TerminateSpawnHost(ctx, host, evergreen.GetEnvironment().Settings(), user))
 }
 
+func (hc *DBHostConnector) FindHostsWithHealth(distroID string) ([]host.Host, error) {
+	hosts, err := host.Find(host.WithHealthScores(distroID))
+	return hosts, errors.Wrap(err, "problem finding host health scores")
+}
+
+func (hc *DBHostConnector) QuarantineHost(h *host.Host, user, reason string) error {
+	return errors.WithStack(h.Quarantine(user, reason))
+}
+
+func (hc *DBHostConnector) RestoreHost(h *host.Host, user string) error {
+	if h.Status != evergreen.HostQuarantined {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("host %s is %s, not %s", h.Id, h.Status, evergreen.HostQuarantined),
+		}
+	}
+	return errors.WithStack(h.Restore(user))
+}
+
 // MockHostConnector is a struct that implements the Host related methods
 // from the Connector through interactions with he backing database.
 type MockHostConnector struct {
@@ -238,6 +258,35 @@ func (hc *MockHostConnector) TerminateHost(ctx context.Context, host *host.Host,
 	return errors.New("can't find host")
 }
 
+func (hc *MockHostConnector) FindHostsWithHealth(distroID string) ([]host.Host, error) {
+	hosts := []host.Host{}
+	for _, h := range hc.CachedHosts {
+		if h.Health.UpdatedAt.IsZero() || h.Status == evergreen.HostTerminated {
+			continue
+		}
+		if distroID != "" && h.Distro.Id != distroID {
+			continue
+		}
+		hosts = append(hosts, h)
+	}
+	sort.SliceStable(hosts, func(i, j int) bool { return hosts[i].Health.Score > hosts[j].Health.Score })
+	return hosts, nil
+}
+
+func (hc *MockHostConnector) QuarantineHost(h *host.Host, user, reason string) error {
+	return hc.SetHostStatus(h, evergreen.HostQuarantined, user)
+}
+
+func (hc *MockHostConnector) RestoreHost(h *host.Host, user string) error {
+	if h.Status != evergreen.HostQuarantined {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("host %s is %s, not %s", h.Id, h.Status, evergreen.HostQuarantined),
+		}
+	}
+	return hc.SetHostStatus(h, evergreen.HostRunning, user)
+}
+
 func (dbc *MockConnector) FindHostByIdWithOwner(hostID string, user gimlet.User) (*host.Host, error) {
 	return findHostByIdWithOwner(dbc, hostID, user)
 }
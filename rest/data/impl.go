@@ -15,6 +15,8 @@ type DBConnector struct {
 	DBDistroConnector
 	DBHostConnector
 	DBTestConnector
+	DBArtifactConnector
+	DBProvenanceConnector
 	DBMetricsConnector
 	DBBuildConnector
 	DBVersionConnector
@@ -30,6 +32,10 @@ type DBConnector struct {
 	DBSubscriptionConnector
 	NotificationConnector
 	DBCreateHostConnector
+	DBTaskLogConnector
+	DBDebugSessionConnector
+	DBPerfConnector
+	DBBuildCacheConnector
 }
 
 func (ctx *DBConnector) GetSuperUsers() []string   { return ctx.superUsers }
@@ -50,6 +56,8 @@ type MockConnector struct {
 	MockDistroConnector
 	MockHostConnector
 	MockTestConnector
+	MockArtifactConnector
+	MockProvenanceConnector
 	MockMetricsConnector
 	MockBuildConnector
 	MockVersionConnector
@@ -65,6 +73,10 @@ type MockConnector struct {
 	MockSubscriptionConnector
 	MockNotificationConnector
 	MockCreateHostConnector
+	MockTaskLogConnector
+	MockDebugSessionConnector
+	MockPerfConnector
+	MockBuildCacheConnector
 }
 
 func (ctx *MockConnector) GetSuperUsers() []string   { return ctx.superUsers }
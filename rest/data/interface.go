@@ -8,11 +8,16 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/model/debugsession"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/provenance"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/model/user"
@@ -50,6 +55,8 @@ type Connector interface {
 	SetTaskActivated(string, string, bool) error
 	ResetTask(string, string) error
 	AbortTask(string, string) error
+	// ResumeTask lets the agent finish a task that is paused on failure.
+	ResumeTask(*task.Task, string) error
 
 	// FindTasksByBuildId is a method to find a set of tasks which all have the same
 	// BuildId. It takes the buildId being queried for as its first parameter,
@@ -67,6 +74,12 @@ type Connector interface {
 	AbortBuild(string, string) error
 	// RestartBuild is a method to restart the build matching the same BuildId.
 	RestartBuild(string, string) error
+	// RestartFailedGithubCheckTasks restarts the failed tasks of the Github
+	// check run with the given external ID.
+	RestartFailedGithubCheckTasks(string, string) error
+	// FindGithubCheckRunProject returns the identifier of the project of the
+	// Github check run with the given external ID.
+	FindGithubCheckRunProject(string) (string, error)
 
 	// FindProjects is a method to find projects as ordered by name
 	FindProjects(string, int, int, bool) ([]model.ProjectRef, error)
@@ -85,6 +98,19 @@ type Connector interface {
 	// limit, and sort to provide additional control over the results.
 	FindTestsByTaskId(string, string, string, int, int) ([]testresult.TestResult, error)
 
+	// FindArtifactsByTaskId, FindArtifactsByBuildId and
+	// FindArtifactsByVersionId find the files attached by a task, or by
+	// the latest executions of the tasks in a build or version. A negative
+	// execution selects the task's latest execution, and an empty variant
+	// selects every variant in the version.
+	FindArtifactsByTaskId(string, int) ([]artifact.Entry, error)
+	FindArtifactsByBuildId(string) ([]artifact.Entry, error)
+	FindArtifactsByVersionId(string, string) ([]artifact.Entry, error)
+
+	// FindProvenanceByVersionId finds the provenance document generated
+	// when a version finished.
+	FindProvenanceByVersionId(string) (*provenance.Provenance, error)
+
 	// FindUserById is a method to find a specific user given its ID.
 	FindUserById(string) (gimlet.User, error)
 
@@ -123,6 +149,10 @@ type Connector interface {
 	// ClearTaskQueue deletes all tasks from the task queue for a distro
 	ClearTaskQueue(string) error
 
+	// FindProjectShares returns the fair-share accounting of the projects
+	// that have queued tasks on a distro or have recently used it.
+	FindProjectShares(string) ([]model.ProjectShare, error)
+
 	// FindVersionById returns version given its ID.
 	FindVersionById(string) (*version.Version, error)
 
@@ -149,6 +179,9 @@ type Connector interface {
 	// SetPatchPriority and SetPatchActivated change the status of the input patch
 	SetPatchPriority(string, int64) error
 	SetPatchActivated(string, string, bool) error
+	// SetPatchPauseOnFailure sets whether the patch's failed tasks are held
+	// for inspection.
+	SetPatchPauseOnFailure(string, bool) error
 
 	// GetEvergreenSettings/SetEvergreenSettings retrieves/sets the system-wide settings document
 	GetEvergreenSettings() (*evergreen.Settings, error)
@@ -183,6 +216,14 @@ type Connector interface {
 	// TerminateHost terminates the given host via the cloud provider's API
 	TerminateHost(context.Context, *host.Host, string) error
 
+	// FindHostsWithHealth returns the hosts, optionally of a single
+	// distro, that have health scores, from least to most healthy.
+	FindHostsWithHealth(string) ([]host.Host, error)
+	// QuarantineHost stops tasks from being dispatched to the host.
+	QuarantineHost(*host.Host, string, string) error
+	// RestoreHost returns a quarantined host to service.
+	RestoreHost(*host.Host, string) error
+
 	// FindProjectAliases queries the database to find all aliases.
 	FindProjectAliases(string) ([]model.ProjectAlias, error)
 
@@ -209,4 +250,42 @@ type Connector interface {
 	ListHostsForTask(string) ([]host.Host, error)
 	MakeIntentHost(string, string, string, apimodels.CreateHost) (*host.Host, error)
 	CreateHostsFromTask(*task.Task, user.DBUser, string) error
+
+	// SubscribeTaskLogs attaches to the shared live log stream for a task
+	// execution, starting at the given line. Viewers of the same execution
+	// share one poller.
+	SubscribeTaskLogs(string, int, int) (*TaskLogSubscription, error)
+
+	// CreateDebugSession requests an interactive debug session into a
+	// running task on behalf of a user.
+	CreateDebugSession(*task.Task, string) (*debugsession.Session, error)
+	// FindDebugSessionById returns a debug session, or a not found error.
+	FindDebugSessionById(string) (*debugsession.Session, error)
+	// AppendDebugInput queues input for a debug session's shell.
+	AppendDebugInput(*debugsession.Session, string) (int, error)
+	// FindDebugOutput returns up to a limit of a debug session's output
+	// chunks after a sequence number.
+	FindDebugOutput(string, int, int) ([]debugsession.Chunk, error)
+	// CloseDebugSession ends a debug session.
+	CloseDebugSession(*debugsession.Session, string) error
+
+	// FindChangePoints returns up to a limit of a project's perf change
+	// points, most recent first, optionally filtered by build variant,
+	// task and measurement.
+	FindChangePoints(string, string, string, string, int) ([]perf.ChangePoint, error)
+	// FindChangePointsByVersion returns the perf change points at a
+	// version.
+	FindChangePointsByVersion(string) ([]perf.ChangePoint, error)
+	// FindPerfResults returns up to a limit of the perf results matching
+	// a filter, oldest first.
+	FindPerfResults(perf.ResultsFilter, int) ([]perf.Result, error)
+	// FindPerfResultsByTask returns the perf results of a task execution.
+	FindPerfResultsByTask(string, int) ([]perf.Result, error)
+	// FindRevisionOrder returns the order number of the mainline version
+	// of a project at a revision.
+	FindRevisionOrder(string, string) (int, error)
+
+	// FindBuildCacheStats returns a project's cache hit, miss, save and
+	// eviction counters.
+	FindBuildCacheStats(string) (*buildcache.ProjectStats, error)
 }
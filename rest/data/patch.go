@@ -80,6 +80,16 @@ func (pc *DBPatchConnector) SetPatchActivated(patchId string, user string, activ
 	return model.SetVersionActivation(patchId, activated, user)
 }
 
+// SetPatchPauseOnFailure sets whether the patch's failed tasks are held for
+// inspection. It only affects tasks that fail afterward.
+func (pc *DBPatchConnector) SetPatchPauseOnFailure(patchId string, pause bool) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(p.SetPauseOnFailure(pause))
+}
+
 func (pc *DBPatchConnector) FindPatchesByUser(user string, ts time.Time, limit int) ([]patch.Patch, error) {
 	patches, err := patch.Find(patch.ByUserPaginated(user, ts, limit))
 	if err != nil {
@@ -95,7 +105,7 @@ func (p *DBPatchConnector) AbortPatchesFromPullRequest(event *github.PullRequest
 		return err
 	}
 
-	err = model.AbortPatchesWithGithubPatchData(*event.PullRequest.ClosedAt,
+	_, err = model.AbortPatchesWithGithubPatchData(*event.PullRequest.ClosedAt,
 		owner, repo, *event.Number)
 	if err != nil {
 		return gimlet.ErrorResponse{
@@ -187,6 +197,16 @@ func (pc *MockPatchConnector) SetPatchActivated(patchId string, user string, act
 	return nil
 }
 
+// SetPatchPauseOnFailure sets the pause on failure field on the input patch.
+func (pc *MockPatchConnector) SetPatchPauseOnFailure(patchId string, pause bool) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	p.PauseOnFailure = &pause
+	return nil
+}
+
 // FindPatchesByUser iterates through the cached patches slice to find the correct patches
 func (hp *MockPatchConnector) FindPatchesByUser(user string, ts time.Time, limit int) ([]patch.Patch, error) {
 	patchesToReturn := []patch.Patch{}
@@ -0,0 +1,139 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// DBPerfConnector is a struct that implements the perf change point
+// related methods from the Connector through interactions with the backing
+// database.
+type DBPerfConnector struct{}
+
+// FindChangePoints returns up to limit change points of a project, most
+// recent first, optionally only those of a build variant, task or
+// measurement.
+func (pc *DBPerfConnector) FindChangePoints(project, variant, task, measurement string, limit int) ([]perf.ChangePoint, error) {
+	cps, err := perf.Find(perf.BySeries(project, variant, task, measurement).Limit(limit))
+	return cps, errors.Wrapf(err, "problem finding change points for project '%s'", project)
+}
+
+// FindChangePointsByVersion returns the change points at a version.
+func (pc *DBPerfConnector) FindChangePointsByVersion(versionID string) ([]perf.ChangePoint, error) {
+	cps, err := perf.Find(perf.ByVersion(versionID))
+	return cps, errors.Wrapf(err, "problem finding change points for version '%s'", versionID)
+}
+
+// FindPerfResults returns up to limit of the results matching the filter,
+// oldest first.
+func (pc *DBPerfConnector) FindPerfResults(f perf.ResultsFilter, limit int) ([]perf.Result, error) {
+	results, err := perf.FindResults(perf.ResultsBySeries(f).Limit(limit))
+	return results, errors.Wrapf(err, "problem finding perf results for project '%s'", f.Project)
+}
+
+// FindPerfResultsByTask returns the results of a task execution.
+func (pc *DBPerfConnector) FindPerfResultsByTask(taskID string, execution int) ([]perf.Result, error) {
+	results, err := perf.FindResults(perf.ResultsByTask(taskID, execution))
+	return results, errors.Wrapf(err, "problem finding perf results for task '%s'", taskID)
+}
+
+// FindRevisionOrder returns the order number of the mainline version of a
+// project at a revision.
+func (pc *DBPerfConnector) FindRevisionOrder(project, revision string) (int, error) {
+	v, err := version.FindOne(version.ByProjectIdAndRevision(project, revision).WithFields(version.RevisionOrderNumberKey))
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem finding version of project '%s' at revision '%s'", project, revision)
+	}
+	if v == nil {
+		return 0, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no version of project '%s' at revision '%s'", project, revision),
+		}
+	}
+	return v.RevisionOrderNumber, nil
+}
+
+// MockPerfConnector is a struct that implements the perf related methods
+// from the Connector with in-memory change points and results.
+type MockPerfConnector struct {
+	CachedChangePoints []perf.ChangePoint
+	CachedResults      []perf.Result
+}
+
+func (pc *MockPerfConnector) FindChangePoints(project, variant, task, measurement string, limit int) ([]perf.ChangePoint, error) {
+	out := []perf.ChangePoint{}
+	for _, cp := range pc.CachedChangePoints {
+		if cp.Project != project ||
+			(variant != "" && cp.Variant != variant) ||
+			(task != "" && cp.Task != task) ||
+			(measurement != "" && cp.Measurement != measurement) {
+			continue
+		}
+		out = append(out, cp)
+	}
+	perf.SortByOrder(out)
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (pc *MockPerfConnector) FindChangePointsByVersion(versionID string) ([]perf.ChangePoint, error) {
+	out := []perf.ChangePoint{}
+	for _, cp := range pc.CachedChangePoints {
+		if cp.VersionID == versionID {
+			out = append(out, cp)
+		}
+	}
+	return out, nil
+}
+
+func (pc *MockPerfConnector) FindPerfResults(f perf.ResultsFilter, limit int) ([]perf.Result, error) {
+	out := []perf.Result{}
+	for _, r := range pc.CachedResults {
+		if r.Info.Project != f.Project || r.Info.IsPatch ||
+			(f.Variant != "" && r.Info.Variant != f.Variant) ||
+			(f.TaskName != "" && r.Info.TaskName != f.TaskName) ||
+			(f.Test != "" && r.Test != f.Test) ||
+			(!f.Start.IsZero() && r.CreatedAt.Before(f.Start)) ||
+			(!f.End.IsZero() && !r.CreatedAt.Before(f.End)) ||
+			(f.MinOrder > 0 && r.Info.Order < f.MinOrder) ||
+			(f.MaxOrder > 0 && r.Info.Order > f.MaxOrder) {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Info.Order < out[j].Info.Order })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (pc *MockPerfConnector) FindPerfResultsByTask(taskID string, execution int) ([]perf.Result, error) {
+	out := []perf.Result{}
+	for _, r := range pc.CachedResults {
+		if r.Info.TaskID == taskID && r.Info.Execution == execution {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (pc *MockPerfConnector) FindRevisionOrder(project, revision string) (int, error) {
+	for _, r := range pc.CachedResults {
+		if r.Info.Project == project && r.Info.Revision == revision && !r.Info.IsPatch {
+			return r.Info.Order, nil
+		}
+	}
+	return 0, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no version of project '%s' at revision '%s'", project, revision),
+	}
+}
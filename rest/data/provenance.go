@@ -0,0 +1,49 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/provenance"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// DBProvenanceConnector is a struct that implements the Provenance related
+// methods from the Connector through interactions with the backing
+// database.
+type DBProvenanceConnector struct{}
+
+// FindProvenanceByVersionId returns the provenance document generated
+// when the version finished.
+func (pc *DBProvenanceConnector) FindProvenanceByVersionId(versionId string) (*provenance.Provenance, error) {
+	doc, err := provenance.FindOne(provenance.ById(versionId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding provenance for version '%s'", versionId)
+	}
+	if doc == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no provenance found for version '%s'", versionId),
+		}
+	}
+	return doc, nil
+}
+
+// MockProvenanceConnector is a struct that implements the Provenance
+// related methods from the Connector through an in-memory cache.
+type MockProvenanceConnector struct {
+	CachedProvenance []provenance.Provenance
+}
+
+func (mpc *MockProvenanceConnector) FindProvenanceByVersionId(versionId string) (*provenance.Provenance, error) {
+	for i := range mpc.CachedProvenance {
+		if mpc.CachedProvenance[i].Id == versionId {
+			return &mpc.CachedProvenance[i], nil
+		}
+	}
+	return nil, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no provenance found for version '%s'", versionId),
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
@@ -168,6 +169,22 @@ func (tc *DBTaskConnector) AbortTask(taskId string, user string) error {
 	return serviceModel.AbortTask(taskId, user)
 }
 
+// ResumeTask ends the pause of a task that is held after a failed command,
+// so that the agent runs its post commands and finishes it.
+func (tc *DBTaskConnector) ResumeTask(t *task.Task, user string) error {
+	if t.Status != evergreen.TaskPaused {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("task '%s' is %s, not %s", t.Id, t.Status, evergreen.TaskPaused),
+		}
+	}
+	if err := t.Resume(); err != nil {
+		return errors.WithStack(err)
+	}
+	event.LogTaskResumed(t.Id, t.Execution, user)
+	return nil
+}
+
 // FindCostTaskByProject queries the backing database for tasks of a project
 // that finishes in the given time range.
 func (tc *DBTaskConnector) FindCostTaskByProject(project, taskId string, starttime,
@@ -358,3 +375,21 @@ func (tc *MockTaskConnector) AbortTask(taskId, user string) error {
 	tc.CachedAborted[taskId] = user
 	return nil
 }
+
+func (tc *MockTaskConnector) ResumeTask(t *task.Task, user string) error {
+	if t.Status != evergreen.TaskPaused {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("task '%s' is %s, not %s", t.Id, t.Status, evergreen.TaskPaused),
+		}
+	}
+	for i := range tc.CachedTasks {
+		if tc.CachedTasks[i].Id == t.Id {
+			tc.CachedTasks[i].Status = evergreen.TaskStarted
+			tc.CachedTasks[i].PausedUntil = time.Time{}
+		}
+	}
+	t.Status = evergreen.TaskStarted
+	t.PausedUntil = time.Time{}
+	return nil
+}
@@ -0,0 +1,427 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const taskLogStreamPollInterval = 2 * time.Second
+
+// taskLogSource is where a log stream reads from: fetch returns the log
+// documents inserted after the given document id, seek returns the id of the
+// last document that ends before the given line and the number of lines up
+// to the end of that document, and finished reports whether the task
+// execution will produce no more logs.
+type taskLogSource struct {
+	fetch    func(string, int, bson.ObjectId) ([]model.TaskLog, error)
+	seek     func(string, int, int) (bson.ObjectId, int, error)
+	finished func(string, int) (bool, error)
+}
+
+type taskLogStreamKey struct {
+	taskId    string
+	execution int
+}
+
+// taskLogHub shares one polling loop per task execution between all of the
+// viewers that are streaming its logs.
+type taskLogHub struct {
+	source   taskLogSource
+	interval time.Duration
+
+	mu      sync.Mutex
+	streams map[taskLogStreamKey]*taskLogStream
+}
+
+func newTaskLogHub(source taskLogSource, interval time.Duration) *taskLogHub {
+	return &taskLogHub{
+		source:   source,
+		interval: interval,
+		streams:  map[taskLogStreamKey]*taskLogStream{},
+	}
+}
+
+// taskLogStream buffers the log lines of one task execution and wakes its
+// subscribers whenever new lines arrive. Lines are numbered from the start
+// of the execution's log, and only the lines that some subscriber has yet
+// to read are kept.
+type taskLogStream struct {
+	key    taskLogStreamKey
+	source taskLogSource
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	lines  []apimodels.LogMessage
+	first  int
+	lastId bson.ObjectId
+	done   bool
+	err    error
+	notify chan struct{}
+	subs   map[*TaskLogSubscription]struct{}
+}
+
+// TaskLogSubscription is one viewer's position in a shared task log stream.
+// Close must be called when the viewer goes away.
+type TaskLogSubscription struct {
+	hub    *taskLogHub
+	stream *taskLogStream
+	cursor int
+	closed bool
+}
+
+// subscribe attaches a viewer to the stream for the task execution, starting
+// at the given line. Viewers share the stream unless they start before the
+// lines it still holds, in which case they get a stream of their own. A new
+// stream skips the log documents before the starting line and loads the
+// rest of the logs written so far before subscribe returns.
+func (h *taskLogHub) subscribe(taskId string, execution, from int) (*TaskLogSubscription, error) {
+	key := taskLogStreamKey{taskId: taskId, execution: execution}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream, shared := h.streams[key]
+	if shared {
+		stream.mu.Lock()
+		shared = from >= stream.first
+		stream.mu.Unlock()
+	}
+	if !shared {
+		var err error
+		stream, err = h.startStream(key, from)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem loading logs for task '%s'", taskId)
+		}
+		if _, ok := h.streams[key]; !ok {
+			h.streams[key] = stream
+		}
+	}
+
+	sub := &TaskLogSubscription{hub: h, stream: stream, cursor: from}
+	stream.mu.Lock()
+	stream.subs[sub] = struct{}{}
+	stream.mu.Unlock()
+
+	return sub, nil
+}
+
+func (h *taskLogHub) startStream(key taskLogStreamKey, from int) (*taskLogStream, error) {
+	lastId, first, err := h.source.seek(key.taskId, key.execution, from)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding starting line")
+	}
+
+	stream := &taskLogStream{
+		key:    key,
+		source: h.source,
+		first:  first,
+		lastId: lastId,
+		notify: make(chan struct{}),
+		subs:   map[*TaskLogSubscription]struct{}{},
+	}
+	if err = stream.poll(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream.cancel = cancel
+	go stream.run(ctx, h.interval)
+
+	return stream, nil
+}
+
+func (h *taskLogHub) release(sub *TaskLogSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream := sub.stream
+	stream.mu.Lock()
+	delete(stream.subs, sub)
+	unused := len(stream.subs) == 0
+	stream.trim()
+	stream.mu.Unlock()
+
+	if unused {
+		stream.cancel()
+		if h.streams[stream.key] == stream {
+			delete(h.streams, stream.key)
+		}
+	}
+}
+
+func (h *taskLogHub) numStreams() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.streams)
+}
+
+func (s *taskLogStream) run(ctx context.Context, interval time.Duration) {
+	defer func() {
+		if err := recover(); err != nil {
+			s.finish(errors.Errorf("task log stream panicked: %v", err))
+		}
+	}()
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if err := s.poll(); err != nil {
+				grip.Warning(message.WrapError(err, message.Fields{
+					"message":   "problem polling task logs",
+					"task_id":   s.key.taskId,
+					"execution": s.key.execution,
+				}))
+				s.finish(err)
+				return
+			}
+			if s.isDone() {
+				return
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// poll appends any new log lines to the buffer. The task's status is checked
+// before fetching so that a finished task's final lines are not missed.
+func (s *taskLogStream) poll() error {
+	finished, err := s.source.finished(s.key.taskId, s.key.execution)
+	if err != nil {
+		return errors.Wrap(err, "problem checking task status")
+	}
+
+	s.mu.Lock()
+	lastId := s.lastId
+	s.mu.Unlock()
+
+	logs, err := s.source.fetch(s.key.taskId, s.key.execution, lastId)
+	if err != nil {
+		return errors.Wrap(err, "problem fetching task logs")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range logs {
+		s.lines = append(s.lines, l.Messages...)
+		s.lastId = l.Id
+	}
+	if finished {
+		s.done = true
+	}
+	if len(logs) > 0 || finished {
+		close(s.notify)
+		s.notify = make(chan struct{})
+	}
+
+	return nil
+}
+
+func (s *taskLogStream) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return
+	}
+	s.done = true
+	s.err = err
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+// trim drops the lines that every subscriber has read. The caller must
+// hold the lock.
+func (s *taskLogStream) trim() {
+	if len(s.subs) == 0 {
+		return
+	}
+
+	slowest := -1
+	for sub := range s.subs {
+		if slowest < 0 || sub.cursor < slowest {
+			slowest = sub.cursor
+		}
+	}
+	drop := slowest - s.first
+	if drop <= 0 {
+		return
+	}
+	if drop > len(s.lines) {
+		drop = len(s.lines)
+	}
+	s.lines = s.lines[drop:]
+	s.first += drop
+}
+
+func (s *taskLogStream) isDone() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.done
+}
+
+// Backlog returns the lines this subscription has not yet seen without
+// waiting for new ones, and whether the stream has ended.
+func (s *TaskLogSubscription) Backlog() ([]apimodels.LogMessage, bool, error) {
+	s.stream.mu.Lock()
+	defer s.stream.mu.Unlock()
+
+	end := s.stream.first + len(s.stream.lines)
+	if s.cursor >= end {
+		return nil, s.stream.done, s.stream.err
+	}
+
+	// the lines are copied, since trimming lets appends reuse the buffer
+	lines := make([]apimodels.LogMessage, end-s.cursor)
+	copy(lines, s.stream.lines[s.cursor-s.stream.first:])
+	s.cursor = end
+	s.stream.trim()
+
+	return lines, s.stream.done, s.stream.err
+}
+
+// Next waits until there are lines this subscription has not yet seen, the
+// stream ends or the context is done. Once the stream has ended and every
+// line has been returned, Next reports done.
+func (s *TaskLogSubscription) Next(ctx context.Context) ([]apimodels.LogMessage, bool, error) {
+	for {
+		s.stream.mu.Lock()
+		notify := s.stream.notify
+		s.stream.mu.Unlock()
+
+		lines, done, err := s.Backlog()
+		if len(lines) > 0 || done || err != nil {
+			return lines, done && len(lines) == 0, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// Close detaches the subscription, stopping the stream if it was the last
+// viewer.
+func (s *TaskLogSubscription) Close() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.hub.release(s)
+}
+
+var (
+	dbTaskLogHub     *taskLogHub
+	dbTaskLogHubOnce sync.Once
+)
+
+// DBTaskLogConnector streams task logs from the database. All DBConnectors
+// share one hub so that there is one poller per task no matter how many
+// viewers there are.
+type DBTaskLogConnector struct{}
+
+func (tc *DBTaskLogConnector) SubscribeTaskLogs(taskId string, execution, from int) (*TaskLogSubscription, error) {
+	dbTaskLogHubOnce.Do(func() {
+		dbTaskLogHub = newTaskLogHub(taskLogSource{
+			fetch:    model.FindTaskLogsAfterId,
+			seek:     model.FindTaskLogIdBeforeLine,
+			finished: dbTaskExecutionFinished,
+		}, taskLogStreamPollInterval)
+	})
+
+	return dbTaskLogHub.subscribe(taskId, execution, from)
+}
+
+func dbTaskExecutionFinished(taskId string, execution int) (bool, error) {
+	t, err := task.FindOneIdOldOrNew(taskId, execution)
+	if err != nil {
+		return false, err
+	}
+	if t == nil || t.Execution != execution {
+		return true, nil
+	}
+
+	return evergreen.IsFinishedTaskStatus(t.Status), nil
+}
+
+// MockTaskLogConnector streams the task logs stored in Logs, keyed by task
+// id. A task's stream ends once the task id is marked in Finished.
+type MockTaskLogConnector struct {
+	Logs     map[string][]model.TaskLog
+	Finished map[string]bool
+
+	mu  sync.Mutex
+	hub *taskLogHub
+}
+
+func (tc *MockTaskLogConnector) SubscribeTaskLogs(taskId string, execution, from int) (*TaskLogSubscription, error) {
+	tc.mu.Lock()
+	if tc.hub == nil {
+		tc.hub = newTaskLogHub(taskLogSource{
+			fetch:    tc.fetch,
+			seek:     tc.seek,
+			finished: tc.finished,
+		}, 10*time.Millisecond)
+	}
+	tc.mu.Unlock()
+
+	return tc.hub.subscribe(taskId, execution, from)
+}
+
+func (tc *MockTaskLogConnector) fetch(taskId string, execution int, after bson.ObjectId) ([]model.TaskLog, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	out := []model.TaskLog{}
+	for _, l := range tc.Logs[taskId] {
+		if l.Execution == execution && l.Id > after {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (tc *MockTaskLogConnector) seek(taskId string, execution, line int) (bson.ObjectId, int, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	lastId := bson.ObjectId("")
+	count := 0
+	for _, l := range tc.Logs[taskId] {
+		if l.Execution != execution {
+			continue
+		}
+		if count+len(l.Messages) > line {
+			break
+		}
+		lastId = l.Id
+		count += len(l.Messages)
+	}
+	return lastId, count, nil
+}
+
+func (tc *MockTaskLogConnector) finished(taskId string, execution int) (bool, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return tc.Finished[taskId], nil
+}
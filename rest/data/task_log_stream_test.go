@@ -0,0 +1,240 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type fakeTaskLogSource struct {
+	mu       sync.Mutex
+	logs     []model.TaskLog
+	finished bool
+	fetches  int
+}
+
+func (s *fakeTaskLogSource) add(msgs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := model.TaskLog{Id: bson.NewObjectId()}
+	for _, m := range msgs {
+		l.Messages = append(l.Messages, apimodels.LogMessage{Type: apimodels.TaskLogPrefix, Message: m})
+	}
+	s.logs = append(s.logs, l)
+}
+
+func (s *fakeTaskLogSource) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finished = true
+}
+
+func (s *fakeTaskLogSource) source() taskLogSource {
+	return taskLogSource{
+		fetch: func(_ string, _ int, after bson.ObjectId) ([]model.TaskLog, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			s.fetches++
+			out := []model.TaskLog{}
+			for _, l := range s.logs {
+				if l.Id > after {
+					out = append(out, l)
+				}
+			}
+			return out, nil
+		},
+		seek: func(_ string, _ int, line int) (bson.ObjectId, int, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			lastId := bson.ObjectId("")
+			count := 0
+			for _, l := range s.logs {
+				if count+len(l.Messages) > line {
+					break
+				}
+				lastId = l.Id
+				count += len(l.Messages)
+			}
+			return lastId, count, nil
+		},
+		finished: func(string, int) (bool, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			return s.finished, nil
+		},
+	}
+}
+
+func messages(lines []apimodels.LogMessage) []string {
+	out := []string{}
+	for _, l := range lines {
+		out = append(out, l.Message)
+	}
+	return out
+}
+
+func TestTaskLogHub(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	src := &fakeTaskLogSource{}
+	src.add("one", "two")
+	hub := newTaskLogHub(src.source(), 10*time.Millisecond)
+
+	first, err := hub.subscribe("t1", 0, 0)
+	require.NoError(err)
+	second, err := hub.subscribe("t1", 0, 0)
+	require.NoError(err)
+	assert.Equal(1, hub.numStreams())
+
+	lines, done, err := first.Backlog()
+	assert.NoError(err)
+	assert.False(done)
+	assert.Equal([]string{"one", "two"}, messages(lines))
+
+	src.add("three")
+	lines, done, err = first.Next(ctx)
+	assert.NoError(err)
+	assert.False(done)
+	assert.Equal([]string{"three"}, messages(lines))
+
+	// a second viewer replays from the start of the shared buffer
+	lines, _, err = second.Next(ctx)
+	assert.NoError(err)
+	assert.Equal([]string{"one", "two", "three"}, messages(lines))
+
+	src.add("four")
+	src.finish()
+	lines, done, err = first.Next(ctx)
+	assert.NoError(err)
+	assert.False(done)
+	assert.Equal([]string{"four"}, messages(lines))
+	lines, done, err = first.Next(ctx)
+	assert.NoError(err)
+	assert.True(done)
+	assert.Empty(lines)
+
+	first.Close()
+	first.Close()
+	assert.Equal(1, hub.numStreams())
+	second.Close()
+	assert.Equal(0, hub.numStreams())
+
+	// a stream for a finished task ends as soon as its logs are read
+	third, err := hub.subscribe("t1", 0, 0)
+	require.NoError(err)
+	defer third.Close()
+	lines, done, err = third.Next(ctx)
+	assert.NoError(err)
+	assert.False(done)
+	assert.Len(lines, 4)
+	_, done, err = third.Next(ctx)
+	assert.NoError(err)
+	assert.True(done)
+}
+
+func TestTaskLogHubTrimsAndResumes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	src := &fakeTaskLogSource{}
+	src.add("zero", "one")
+	src.add("two", "three")
+	src.add("four")
+	hub := newTaskLogHub(src.source(), time.Hour)
+
+	// a viewer resuming at line 3 skips the documents before it
+	first, err := hub.subscribe("t1", 0, 3)
+	require.NoError(err)
+	defer first.Close()
+	stream := first.stream
+	stream.mu.Lock()
+	assert.Equal(2, stream.first)
+	assert.Len(stream.lines, 3)
+	stream.mu.Unlock()
+
+	lines, _, err := first.Backlog()
+	require.NoError(err)
+	assert.Equal([]string{"three", "four"}, messages(lines))
+
+	// lines that every viewer has read are dropped
+	stream.mu.Lock()
+	assert.Equal(5, stream.first)
+	assert.Empty(stream.lines)
+	stream.mu.Unlock()
+
+	// a viewer that starts after the stream's buffer shares it
+	second, err := hub.subscribe("t1", 0, 5)
+	require.NoError(err)
+	defer second.Close()
+	assert.True(second.stream == stream)
+
+	// a viewer that starts before it gets a stream of its own
+	third, err := hub.subscribe("t1", 0, 0)
+	require.NoError(err)
+	assert.False(third.stream == stream)
+	assert.Equal(1, hub.numStreams())
+	lines, _, err = third.Backlog()
+	require.NoError(err)
+	assert.Equal([]string{"zero", "one", "two", "three", "four"}, messages(lines))
+	third.Close()
+	assert.Equal(1, hub.numStreams())
+}
+
+func TestTaskLogHubSharesPolling(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	src := &fakeTaskLogSource{}
+	hub := newTaskLogHub(src.source(), time.Hour)
+
+	subs := []*TaskLogSubscription{}
+	for i := 0; i < 5; i++ {
+		sub, err := hub.subscribe("t1", 0, 0)
+		require.NoError(err)
+		subs = append(subs, sub)
+	}
+	other, err := hub.subscribe("t1", 1, 0)
+	require.NoError(err)
+
+	assert.Equal(2, hub.numStreams())
+	src.mu.Lock()
+	assert.Equal(2, src.fetches)
+	src.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+	other.Close()
+	assert.Equal(0, hub.numStreams())
+}
+
+func TestTaskLogSubscriptionNextHonorsContext(t *testing.T) {
+	src := &fakeTaskLogSource{}
+	hub := newTaskLogHub(src.source(), time.Hour)
+	sub, err := hub.subscribe("t1", 0, 0)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	lines, done, err := sub.Next(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.False(t, done)
+	assert.Empty(t, lines)
+}
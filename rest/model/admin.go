@@ -15,10 +15,15 @@ func NewConfigModel() *APIAdminSettings {
 		Alerts:            &APIAlertsConfig{},
 		Amboy:             &APIAmboyConfig{},
 		Api:               &APIapiConfig{},
+		ArtifactRetention: &APIArtifactRetentionConfig{},
+		ArtifactSigning:   &APIArtifactSigningConfig{},
 		AuthConfig:        &APIAuthConfig{},
+		BuildCache:        &APIBuildCacheConfig{},
 		ContainerPools:    &APIContainerPoolsConfig{},
 		Credentials:       map[string]string{},
 		Expansions:        map[string]string{},
+		GithubApp:         &APIGithubAppConfig{},
+		HostHealth:        &APIHostHealthConfig{},
 		HostInit:          &APIHostInitConfig{},
 		Jira:              &APIJiraConfig{},
 		JIRANotifications: &APIJIRANotificationsConfig{},
@@ -32,6 +37,7 @@ func NewConfigModel() *APIAdminSettings {
 		ServiceFlags:      &APIServiceFlags{},
 		Slack:             &APISlackConfig{},
 		Splunk:            &APISplunkConnectionInfo{},
+		Tracer:            &APITracerConfig{},
 		Ui:                &APIUIConfig{},
 	}
 }
@@ -42,15 +48,20 @@ type APIAdminSettings struct {
 	Amboy              *APIAmboyConfig                   `json:"amboy,omitempty"`
 	Api                *APIapiConfig                     `json:"api,omitempty"`
 	ApiUrl             APIString                         `json:"api_url,omitempty"`
+	ArtifactRetention  *APIArtifactRetentionConfig       `json:"artifact_retention,omitempty"`
+	ArtifactSigning    *APIArtifactSigningConfig         `json:"artifact_signing,omitempty"`
 	AuthConfig         *APIAuthConfig                    `json:"auth,omitempty"`
 	Banner             APIString                         `json:"banner,omitempty"`
 	BannerTheme        APIString                         `json:"banner_theme,omitempty"`
+	BuildCache         *APIBuildCacheConfig              `json:"build_cache,omitempty"`
 	ClientBinariesDir  APIString                         `json:"client_binaries_dir,omitempty"`
 	ConfigDir          APIString                         `json:"configdir,omitempty"`
 	Credentials        map[string]string                 `json:"credentials,omitempty"`
 	ContainerPools     *APIContainerPoolsConfig          `json:"container_pools,omitempty"`
 	Expansions         map[string]string                 `json:"expansions,omitempty"`
+	GithubApp          *APIGithubAppConfig               `json:"github_app,omitempty"`
 	GithubPRCreatorOrg APIString                         `json:"github_pr_creator_org,omitempty"`
+	HostHealth         *APIHostHealthConfig              `json:"host_health,omitempty"`
 	HostInit           *APIHostInitConfig                `json:"hostinit,omitempty"`
 	Jira               *APIJiraConfig                    `json:"jira,omitempty"`
 	Keys               map[string]string                 `json:"keys,omitempty"`
@@ -66,6 +77,7 @@ type APIAdminSettings struct {
 	Slack              *APISlackConfig                   `json:"slack,omitempty"`
 	Splunk             *APISplunkConnectionInfo          `json:"splunk,omitempty"`
 	SuperUsers         []string                          `json:"superusers,omitempty"`
+	Tracer             *APITracerConfig                  `json:"tracer,omitempty"`
 	Ui                 *APIUIConfig                      `json:"ui,omitempty"`
 	JIRANotifications  *APIJIRANotificationsConfig       `json:"jira_notifications,omitempty"`
 }
@@ -535,6 +547,122 @@ type APIBanner struct {
 	Theme APIString `json:"theme"`
 }
 
+type APIArtifactRetentionConfig struct {
+	AWSKey    APIString   `json:"aws_key"`
+	AWSSecret APIString   `json:"aws_secret"`
+	Buckets   []APIString `json:"buckets"`
+	Endpoint  APIString   `json:"endpoint"`
+}
+
+func (a *APIArtifactRetentionConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.ArtifactRetentionConfig:
+		a.AWSKey = ToAPIString(v.AWSKey)
+		a.AWSSecret = ToAPIString(v.AWSSecret)
+		a.Buckets = []APIString{}
+		for _, b := range v.Buckets {
+			a.Buckets = append(a.Buckets, ToAPIString(b))
+		}
+		a.Endpoint = ToAPIString(v.Endpoint)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIArtifactRetentionConfig) ToService() (interface{}, error) {
+	config := evergreen.ArtifactRetentionConfig{
+		AWSKey:    FromAPIString(a.AWSKey),
+		AWSSecret: FromAPIString(a.AWSSecret),
+		Endpoint:  FromAPIString(a.Endpoint),
+	}
+	for _, b := range a.Buckets {
+		config.Buckets = append(config.Buckets, FromAPIString(b))
+	}
+	return config, nil
+}
+
+type APIArtifactSigningConfig struct {
+	PrivateKey APIString `json:"private_key"`
+}
+
+func (a *APIArtifactSigningConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.ArtifactSigningConfig:
+		a.PrivateKey = ToAPIString(v.PrivateKey)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIArtifactSigningConfig) ToService() (interface{}, error) {
+	return evergreen.ArtifactSigningConfig{
+		PrivateKey: FromAPIString(a.PrivateKey),
+	}, nil
+}
+
+type APIGithubAppConfig struct {
+	AppID         int64     `json:"app_id"`
+	PrivateKey    APIString `json:"private_key"`
+	WebhookSecret APIString `json:"webhook_secret"`
+}
+
+func (a *APIGithubAppConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.GithubAppConfig:
+		a.AppID = v.AppID
+		a.PrivateKey = ToAPIString(v.PrivateKey)
+		a.WebhookSecret = ToAPIString(v.WebhookSecret)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIGithubAppConfig) ToService() (interface{}, error) {
+	return evergreen.GithubAppConfig{
+		AppID:         a.AppID,
+		PrivateKey:    FromAPIString(a.PrivateKey),
+		WebhookSecret: FromAPIString(a.WebhookSecret),
+	}, nil
+}
+
+type APIBuildCacheConfig struct {
+	Bucket    APIString `json:"bucket"`
+	AWSKey    APIString `json:"aws_key"`
+	AWSSecret APIString `json:"aws_secret"`
+	Endpoint  APIString `json:"endpoint"`
+	MaxSizeMB int       `json:"max_size_mb"`
+	TTLDays   int       `json:"ttl_days"`
+}
+
+func (a *APIBuildCacheConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.BuildCacheConfig:
+		a.Bucket = ToAPIString(v.Bucket)
+		a.AWSKey = ToAPIString(v.AWSKey)
+		a.AWSSecret = ToAPIString(v.AWSSecret)
+		a.Endpoint = ToAPIString(v.Endpoint)
+		a.MaxSizeMB = v.MaxSizeMB
+		a.TTLDays = v.TTLDays
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIBuildCacheConfig) ToService() (interface{}, error) {
+	return evergreen.BuildCacheConfig{
+		Bucket:    FromAPIString(a.Bucket),
+		AWSKey:    FromAPIString(a.AWSKey),
+		AWSSecret: FromAPIString(a.AWSSecret),
+		Endpoint:  FromAPIString(a.Endpoint),
+		MaxSizeMB: a.MaxSizeMB,
+		TTLDays:   a.TTLDays,
+	}, nil
+}
+
 type APIHostInitConfig struct {
 	SSHTimeoutSeconds int64 `json:"ssh_timeout_secs"`
 }
@@ -743,7 +871,9 @@ func (a *APICloudProviders) ToService() (interface{}, error) {
 }
 
 type APIContainerPoolsConfig struct {
-	Pools []APIContainerPool `json:"pools"`
+	Pools         []APIContainerPool     `json:"pools"`
+	Registries    []APIContainerRegistry `json:"registries"`
+	ImageTTLHours int                    `json:"image_ttl_hours"`
 }
 
 func (a *APIContainerPoolsConfig) BuildFromService(h interface{}) error {
@@ -756,6 +886,14 @@ func (a *APIContainerPoolsConfig) BuildFromService(h interface{}) error {
 			}
 			a.Pools = append(a.Pools, APIpool)
 		}
+		for _, registry := range v.Registries {
+			APIregistry := APIContainerRegistry{}
+			if err := APIregistry.BuildFromService(registry); err != nil {
+				return err
+			}
+			a.Registries = append(a.Registries, APIregistry)
+		}
+		a.ImageTTLHours = v.ImageTTLHours
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -766,7 +904,7 @@ func (a *APIContainerPoolsConfig) ToService() (interface{}, error) {
 	if a == nil {
 		return nil, nil
 	}
-	config := evergreen.ContainerPoolsConfig{}
+	config := evergreen.ContainerPoolsConfig{ImageTTLHours: a.ImageTTLHours}
 	for _, p := range a.Pools {
 		i, err := p.ToService()
 		if err != nil {
@@ -775,14 +913,24 @@ func (a *APIContainerPoolsConfig) ToService() (interface{}, error) {
 		pool := i.(evergreen.ContainerPool)
 		config.Pools = append(config.Pools, pool)
 	}
+	for _, r := range a.Registries {
+		i, err := r.ToService()
+		if err != nil {
+			return nil, err
+		}
+		registry := i.(evergreen.ContainerRegistry)
+		config.Registries = append(config.Registries, registry)
+	}
 	return config, nil
 }
 
 type APIContainerPool struct {
 	Distro        APIString `json:"distro"`
 	Id            APIString `json:"id"`
-	MaxContainers int       `json:"max_containers"`
-	Port          uint16    `json:"port"`
+	MaxContainers  int       `json:"max_containers"`
+	Port           uint16    `json:"port"`
+	ParentCPUs     float64   `json:"parent_cpus"`
+	ParentMemoryMB int       `json:"parent_memory_mb"`
 }
 
 func (a *APIContainerPool) BuildFromService(h interface{}) error {
@@ -792,6 +940,8 @@ func (a *APIContainerPool) BuildFromService(h interface{}) error {
 		a.Id = ToAPIString(v.Id)
 		a.MaxContainers = v.MaxContainers
 		a.Port = v.Port
+		a.ParentCPUs = v.ParentResources.CPUs
+		a.ParentMemoryMB = v.ParentResources.MemoryMB
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -804,6 +954,36 @@ func (a *APIContainerPool) ToService() (interface{}, error) {
 		Id:            FromAPIString(a.Id),
 		MaxContainers: a.MaxContainers,
 		Port:          a.Port,
+		ParentResources: evergreen.ContainerResources{
+			CPUs:     a.ParentCPUs,
+			MemoryMB: a.ParentMemoryMB,
+		},
+	}, nil
+}
+
+type APIContainerRegistry struct {
+	Host     APIString `json:"host"`
+	Username APIString `json:"username"`
+	Password APIString `json:"password"`
+}
+
+func (a *APIContainerRegistry) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.ContainerRegistry:
+		a.Host = ToAPIString(v.Host)
+		a.Username = ToAPIString(v.Username)
+		a.Password = ToAPIString(v.Password)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIContainerRegistry) ToService() (interface{}, error) {
+	return evergreen.ContainerRegistry{
+		Host:     FromAPIString(a.Host),
+		Username: FromAPIString(a.Username),
+		Password: FromAPIString(a.Password),
 	}, nil
 }
 
@@ -1012,6 +1192,7 @@ type APIServiceFlags struct {
 	CLIUpdatesDisabled           bool `json:"cli_updates_disabled"`
 	BackgroundStatsDisabled      bool `json:"background_stats_disabled"`
 	TaskLoggingDisabled          bool `json:"task_logging_disabled"`
+	PerfChangePointsDisabled     bool `json:"perf_change_points_disabled"`
 
 	// Notifications Flags
 	EventProcessingDisabled      bool `json:"event_processing_disabled"`
@@ -1131,6 +1312,61 @@ func (a *APISplunkConnectionInfo) ToService() (interface{}, error) {
 	}, nil
 }
 
+type APIHostHealthConfig struct {
+	QuarantineEnabled    bool    `json:"quarantine_enabled"`
+	TerminateQuarantined bool    `json:"terminate_quarantined"`
+	Threshold            float64 `json:"threshold"`
+	WindowMinutes        int     `json:"window_minutes"`
+	MinTasks             int     `json:"min_tasks"`
+}
+
+func (a *APIHostHealthConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.HostHealthConfig:
+		a.QuarantineEnabled = v.QuarantineEnabled
+		a.TerminateQuarantined = v.TerminateQuarantined
+		a.Threshold = v.Threshold
+		a.WindowMinutes = v.WindowMinutes
+		a.MinTasks = v.MinTasks
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIHostHealthConfig) ToService() (interface{}, error) {
+	return evergreen.HostHealthConfig{
+		QuarantineEnabled:    a.QuarantineEnabled,
+		TerminateQuarantined: a.TerminateQuarantined,
+		Threshold:            a.Threshold,
+		WindowMinutes:        a.WindowMinutes,
+		MinTasks:             a.MinTasks,
+	}, nil
+}
+
+type APITracerConfig struct {
+	Enabled           bool      `json:"enabled"`
+	CollectorEndpoint APIString `json:"collector_endpoint"`
+}
+
+func (a *APITracerConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.TracerConfig:
+		a.Enabled = v.Enabled
+		a.CollectorEndpoint = ToAPIString(v.CollectorEndpoint)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APITracerConfig) ToService() (interface{}, error) {
+	return evergreen.TracerConfig{
+		Enabled:           a.Enabled,
+		CollectorEndpoint: FromAPIString(a.CollectorEndpoint),
+	}, nil
+}
+
 type APIUIConfig struct {
 	Url            APIString `json:"url"`
 	HelpUrl        APIString `json:"help_url"`
@@ -1220,6 +1456,7 @@ func (as *APIServiceFlags) BuildFromService(h interface{}) error {
 		as.GithubStatusAPIDisabled = v.GithubStatusAPIDisabled
 		as.BackgroundStatsDisabled = v.BackgroundStatsDisabled
 		as.TaskLoggingDisabled = v.TaskLoggingDisabled
+		as.PerfChangePointsDisabled = v.PerfChangePointsDisabled
 	default:
 		return errors.Errorf("%T is not a supported service flags type", h)
 	}
@@ -1247,6 +1484,7 @@ func (as *APIServiceFlags) ToService() (interface{}, error) {
 		GithubStatusAPIDisabled:      as.GithubStatusAPIDisabled,
 		BackgroundStatsDisabled:      as.BackgroundStatsDisabled,
 		TaskLoggingDisabled:          as.TaskLoggingDisabled,
+		PerfChangePointsDisabled:     as.PerfChangePointsDisabled,
 	}, nil
 }
 
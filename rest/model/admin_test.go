@@ -87,14 +87,28 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Amboy.Name, FromAPIString(apiSettings.Amboy.Name))
 	assert.EqualValues(testSettings.Amboy.LocalStorage, apiSettings.Amboy.LocalStorage)
 	assert.EqualValues(testSettings.Api.HttpListenAddr, FromAPIString(apiSettings.Api.HttpListenAddr))
+	assert.EqualValues(testSettings.ArtifactRetention.AWSKey, FromAPIString(apiSettings.ArtifactRetention.AWSKey))
+	assert.EqualValues(testSettings.ArtifactRetention.Buckets[0], FromAPIString(apiSettings.ArtifactRetention.Buckets[0]))
 	assert.EqualValues(testSettings.AuthConfig.Crowd.Username, FromAPIString(apiSettings.AuthConfig.Crowd.Username))
 	assert.EqualValues(testSettings.AuthConfig.Naive.Users[0].Username, FromAPIString(apiSettings.AuthConfig.Naive.Users[0].Username))
+	assert.EqualValues(testSettings.ArtifactSigning.PrivateKey, FromAPIString(apiSettings.ArtifactSigning.PrivateKey))
+	assert.EqualValues(testSettings.GithubApp.AppID, apiSettings.GithubApp.AppID)
+	assert.EqualValues(testSettings.GithubApp.WebhookSecret, FromAPIString(apiSettings.GithubApp.WebhookSecret))
+	assert.EqualValues(testSettings.BuildCache.Bucket, FromAPIString(apiSettings.BuildCache.Bucket))
+	assert.EqualValues(testSettings.BuildCache.AWSKey, FromAPIString(apiSettings.BuildCache.AWSKey))
+	assert.EqualValues(testSettings.BuildCache.MaxSizeMB, apiSettings.BuildCache.MaxSizeMB)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Distro, FromAPIString(apiSettings.ContainerPools.Pools[0].Distro))
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Id, FromAPIString(apiSettings.ContainerPools.Pools[0].Id))
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].MaxContainers, apiSettings.ContainerPools.Pools[0].MaxContainers)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Port, apiSettings.ContainerPools.Pools[0].Port)
+	assert.EqualValues(testSettings.ContainerPools.Pools[0].ParentResources.CPUs, apiSettings.ContainerPools.Pools[0].ParentCPUs)
+	assert.EqualValues(testSettings.ContainerPools.Pools[0].ParentResources.MemoryMB, apiSettings.ContainerPools.Pools[0].ParentMemoryMB)
+	assert.EqualValues(testSettings.ContainerPools.Registries[0].Host, FromAPIString(apiSettings.ContainerPools.Registries[0].Host))
+	assert.EqualValues(testSettings.ContainerPools.Registries[0].Username, FromAPIString(apiSettings.ContainerPools.Registries[0].Username))
+	assert.EqualValues(testSettings.ContainerPools.ImageTTLHours, apiSettings.ContainerPools.ImageTTLHours)
 	assert.EqualValues(testSettings.AuthConfig.Github.ClientId, FromAPIString(apiSettings.AuthConfig.Github.ClientId))
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(apiSettings.AuthConfig.Github.Users))
+	assert.EqualValues(testSettings.HostHealth.Threshold, apiSettings.HostHealth.Threshold)
 	assert.EqualValues(testSettings.HostInit.SSHTimeoutSeconds, apiSettings.HostInit.SSHTimeoutSeconds)
 	assert.EqualValues(testSettings.Jira.Username, FromAPIString(apiSettings.Jira.Username))
 	assert.EqualValues(testSettings.LoggerConfig.DefaultLevel, FromAPIString(apiSettings.LoggerConfig.DefaultLevel))
@@ -113,6 +127,7 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Slack.Level, FromAPIString(apiSettings.Slack.Level))
 	assert.EqualValues(testSettings.Slack.Options.Channel, FromAPIString(apiSettings.Slack.Options.Channel))
 	assert.EqualValues(testSettings.Splunk.Channel, FromAPIString(apiSettings.Splunk.Channel))
+	assert.EqualValues(testSettings.Tracer.CollectorEndpoint, FromAPIString(apiSettings.Tracer.CollectorEndpoint))
 	assert.EqualValues(testSettings.Ui.HttpListenAddr, FromAPIString(apiSettings.Ui.HttpListenAddr))
 
 	// test converting from the API model back to a DB model
@@ -125,14 +140,22 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Amboy.Name, dbSettings.Amboy.Name)
 	assert.EqualValues(testSettings.Amboy.LocalStorage, dbSettings.Amboy.LocalStorage)
 	assert.EqualValues(testSettings.Api.HttpListenAddr, dbSettings.Api.HttpListenAddr)
+	assert.EqualValues(testSettings.ArtifactRetention.AWSSecret, dbSettings.ArtifactRetention.AWSSecret)
+	assert.EqualValues(testSettings.ArtifactRetention.Buckets, dbSettings.ArtifactRetention.Buckets)
 	assert.EqualValues(testSettings.AuthConfig.Crowd.Username, dbSettings.AuthConfig.Crowd.Username)
 	assert.EqualValues(testSettings.AuthConfig.Naive.Users[0].Username, dbSettings.AuthConfig.Naive.Users[0].Username)
 	assert.EqualValues(testSettings.AuthConfig.Github.ClientId, dbSettings.AuthConfig.Github.ClientId)
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(dbSettings.AuthConfig.Github.Users))
+	assert.EqualValues(testSettings.ArtifactSigning.PrivateKey, dbSettings.ArtifactSigning.PrivateKey)
+	assert.EqualValues(testSettings.GithubApp, dbSettings.GithubApp)
+	assert.EqualValues(testSettings.BuildCache.AWSKey, dbSettings.BuildCache.AWSKey)
+	assert.EqualValues(testSettings.BuildCache.TTLDays, dbSettings.BuildCache.TTLDays)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Distro, dbSettings.ContainerPools.Pools[0].Distro)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Id, dbSettings.ContainerPools.Pools[0].Id)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].MaxContainers, dbSettings.ContainerPools.Pools[0].MaxContainers)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Port, dbSettings.ContainerPools.Pools[0].Port)
+	assert.EqualValues(testSettings.ContainerPools.Registries[0].Password, dbSettings.ContainerPools.Registries[0].Password)
+	assert.EqualValues(testSettings.ContainerPools.ImageTTLHours, dbSettings.ContainerPools.ImageTTLHours)
 	assert.EqualValues(testSettings.HostInit.SSHTimeoutSeconds, dbSettings.HostInit.SSHTimeoutSeconds)
 	assert.EqualValues(testSettings.Jira.Username, dbSettings.Jira.Username)
 	assert.EqualValues(testSettings.LoggerConfig.DefaultLevel, dbSettings.LoggerConfig.DefaultLevel)
@@ -140,6 +163,7 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Notify.SMTP.From, dbSettings.Notify.SMTP.From)
 	assert.EqualValues(testSettings.Notify.SMTP.Port, dbSettings.Notify.SMTP.Port)
 	assert.Equal(len(testSettings.Notify.SMTP.AdminEmail), len(dbSettings.Notify.SMTP.AdminEmail))
+	assert.EqualValues(testSettings.HostHealth.MinTasks, dbSettings.HostHealth.MinTasks)
 	assert.EqualValues(testSettings.Providers.AWS.Id, dbSettings.Providers.AWS.Id)
 	assert.EqualValues(testSettings.Providers.Docker.APIVersion, dbSettings.Providers.Docker.APIVersion)
 	assert.EqualValues(testSettings.Providers.GCE.ClientEmail, dbSettings.Providers.GCE.ClientEmail)
@@ -151,6 +175,7 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Slack.Level, dbSettings.Slack.Level)
 	assert.EqualValues(testSettings.Slack.Options.Channel, dbSettings.Slack.Options.Channel)
 	assert.EqualValues(testSettings.Splunk.Channel, dbSettings.Splunk.Channel)
+	assert.EqualValues(testSettings.Tracer.Enabled, dbSettings.Tracer.Enabled)
 	assert.EqualValues(testSettings.Ui.HttpListenAddr, dbSettings.Ui.HttpListenAddr)
 }
 
@@ -11,6 +11,35 @@ type APIFile struct {
 	Link           APIString `json:"url"`
 	Visibility     APIString `json:"visibility"`
 	IgnoreForFetch bool      `json:"ignore_for_fetch"`
+	Expired        bool      `json:"expired"`
+	SHA256         APIString `json:"sha256"`
+}
+
+// APIArtifact is a single file attached by a task, along with the task
+// that attached it.
+type APIArtifact struct {
+	TaskId          APIString `json:"task_id"`
+	TaskDisplayName APIString `json:"task_name"`
+	BuildId         APIString `json:"build"`
+	Execution       int       `json:"execution"`
+	APIFile
+}
+
+// APIArtifactManifest is the canonical listing of the files attached by a
+// task execution, along with its signature if the server signs
+// artifacts.
+type APIArtifactManifest struct {
+	TaskId    APIString `json:"task_id"`
+	Execution int       `json:"execution"`
+	Manifest  APIString `json:"manifest"`
+	Signature APIString `json:"signature"`
+}
+
+// APIArtifactSigningKey is the public key that verifies the signatures of
+// artifact manifests.
+type APIArtifactSigningKey struct {
+	Algorithm APIString `json:"algorithm"`
+	PublicKey APIString `json:"public_key"`
 }
 
 type APIEntry struct {
@@ -19,6 +48,7 @@ type APIEntry struct {
 	BuildId         APIString `json:"build"`
 	Files           []APIFile `json:"files"`
 	Execution       int       `json:"execution"`
+	Signature       APIString `json:"signature"`
 }
 
 func (f *APIFile) BuildFromService(h interface{}) error {
@@ -28,6 +58,8 @@ func (f *APIFile) BuildFromService(h interface{}) error {
 		f.Link = ToAPIString(v.Link)
 		f.Visibility = ToAPIString(v.Visibility)
 		f.IgnoreForFetch = v.IgnoreForFetch
+		f.Expired = v.Expired
+		f.SHA256 = ToAPIString(v.SHA256)
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -40,6 +72,8 @@ func (f *APIFile) ToService() (interface{}, error) {
 		Link:           FromAPIString(f.Link),
 		Visibility:     FromAPIString(f.Visibility),
 		IgnoreForFetch: f.IgnoreForFetch,
+		Expired:        f.Expired,
+		SHA256:         FromAPIString(f.SHA256),
 	}, nil
 }
 
@@ -51,6 +85,7 @@ func (e *APIEntry) BuildFromService(h interface{}) error {
 		e.TaskDisplayName = ToAPIString(v.TaskDisplayName)
 		e.BuildId = ToAPIString(v.BuildId)
 		e.Execution = v.Execution
+		e.Signature = ToAPIString(v.Signature)
 		for _, file := range v.Files {
 			apiFile := APIFile{}
 			catcher.Add(apiFile.BuildFromService(file))
@@ -68,6 +103,7 @@ func (e *APIEntry) ToService() (interface{}, error) {
 		TaskDisplayName: FromAPIString(e.TaskDisplayName),
 		BuildId:         FromAPIString(e.BuildId),
 		Execution:       e.Execution,
+		Signature:       FromAPIString(e.Signature),
 	}
 	catcher := grip.NewBasicCatcher()
 	for _, apiFile := range e.Files {
@@ -14,14 +14,17 @@ func TestArtifactModels(t *testing.T) {
 		TaskDisplayName: "task",
 		BuildId:         "b1",
 		Execution:       1,
+		Signature:       "sha256=def456",
 		Files: []artifact.File{
 			{
-				Name: "file1",
-				Link: "l1",
+				Name:   "file1",
+				Link:   "l1",
+				SHA256: "abc123",
 			},
 			{
-				Name: "file2",
-				Link: "l2",
+				Name:    "file2",
+				Link:    "l2",
+				Expired: true,
 			},
 		},
 	}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/pkg/errors"
+)
+
+// APIBuildCacheStats counts a project's use of the cache.save and
+// cache.restore commands.
+type APIBuildCacheStats struct {
+	Project      APIString `json:"project"`
+	Hits         int64     `json:"hits"`
+	PartialHits  int64     `json:"partial_hits"`
+	Misses       int64     `json:"misses"`
+	Saves        int64     `json:"saves"`
+	Evictions    int64     `json:"evictions"`
+	BytesEvicted int64     `json:"bytes_evicted"`
+}
+
+func (a *APIBuildCacheStats) BuildFromService(h interface{}) error {
+	var stats *buildcache.ProjectStats
+	switch v := h.(type) {
+	case buildcache.ProjectStats:
+		stats = &v
+	case *buildcache.ProjectStats:
+		stats = v
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+
+	a.Project = ToAPIString(stats.Project)
+	a.Hits = stats.Hits
+	a.PartialHits = stats.PartialHits
+	a.Misses = stats.Misses
+	a.Saves = stats.Saves
+	a.Evictions = stats.Evictions
+	a.BytesEvicted = stats.BytesEvicted
+	return nil
+}
+
+func (a *APIBuildCacheStats) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for build cache stats")
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/debugsession"
+	"github.com/pkg/errors"
+)
+
+// APIDebugSession is an interactive shell opened into a running task, with
+// the output the shell has written since the requested sequence number.
+type APIDebugSession struct {
+	ID          APIString        `json:"id"`
+	TaskID      APIString        `json:"task_id"`
+	Execution   int              `json:"execution"`
+	HostID      APIString        `json:"host_id"`
+	User        APIString        `json:"user"`
+	Status      APIString        `json:"status"`
+	CreatedAt   APITime          `json:"created_at"`
+	StartedAt   APITime          `json:"started_at"`
+	ClosedAt    APITime          `json:"closed_at"`
+	CloseReason APIString        `json:"close_reason"`
+	Output      []APIDebugOutput `json:"output"`
+}
+
+// APIDebugOutput is one numbered piece of a debug session's output.
+type APIDebugOutput struct {
+	Seq  int    `json:"seq"`
+	Data string `json:"data"`
+}
+
+func (a *APIDebugSession) BuildFromService(h interface{}) error {
+	var v *debugsession.Session
+	switch h := h.(type) {
+	case debugsession.Session:
+		v = &h
+	case *debugsession.Session:
+		v = h
+	case []debugsession.Chunk:
+		a.Output = make([]APIDebugOutput, 0, len(h))
+		for _, c := range h {
+			a.Output = append(a.Output, APIDebugOutput{Seq: c.Seq, Data: c.Data})
+		}
+		return nil
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+
+	a.ID = ToAPIString(v.ID)
+	a.TaskID = ToAPIString(v.TaskID)
+	a.Execution = v.Execution
+	a.HostID = ToAPIString(v.HostID)
+	a.User = ToAPIString(v.User)
+	a.Status = ToAPIString(v.Status)
+	a.CreatedAt = NewTime(v.CreatedAt)
+	a.StartedAt = NewTime(v.StartedAt)
+	a.ClosedAt = NewTime(v.ClosedAt)
+	a.CloseReason = ToAPIString(v.CloseReason)
+	return nil
+}
+
+func (a *APIDebugSession) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for debug sessions")
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
@@ -44,3 +45,42 @@ func (apiDistro *APIDistro) BuildFromService(h interface{}) error {
 func (apiDistro *APIDistro) ToService() (interface{}, error) {
 	return nil, errors.Errorf("ToService() is not impelemented for APIDistro")
 }
+
+// APIProjectShare is a project's share of a distro under the fair-share task
+// prioritizer.
+type APIProjectShare struct {
+	Project       APIString `json:"project"`
+	Weight        float64   `json:"weight"`
+	Share         float64   `json:"share"`
+	Usage         float64   `json:"usage"`
+	UsageFraction float64   `json:"usage_fraction"`
+	Deficit       float64   `json:"deficit"`
+	QueuedTasks   int       `json:"queued_tasks"`
+}
+
+// BuildFromService converts from a service level ProjectShare to an
+// APIProjectShare.
+func (apiShare *APIProjectShare) BuildFromService(h interface{}) error {
+	var share model.ProjectShare
+	switch v := h.(type) {
+	case model.ProjectShare:
+		share = v
+	case *model.ProjectShare:
+		share = *v
+	default:
+		return errors.Errorf("incorrect type %T when converting project share", h)
+	}
+	apiShare.Project = ToAPIString(share.Project)
+	apiShare.Weight = share.Weight
+	apiShare.Share = share.Share
+	apiShare.Usage = share.Usage
+	apiShare.UsageFraction = share.UsageFraction
+	apiShare.Deficit = share.Deficit
+	apiShare.QueuedTasks = share.QueuedTasks
+	return nil
+}
+
+// ToService is not implemented for APIProjectShare.
+func (apiShare *APIProjectShare) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APIProjectShare")
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/pkg/errors"
+)
+
+// APIHostHealth is a host's health score and the task outcomes it was
+// computed from.
+type APIHostHealth struct {
+	HostID                APIString `json:"host_id"`
+	Distro                APIString `json:"distro"`
+	Status                APIString `json:"status"`
+	Score                 float64   `json:"score"`
+	Tasks                 int       `json:"tasks"`
+	SystemFailures        int       `json:"system_failures"`
+	SetupFailures         int       `json:"setup_failures"`
+	HeartbeatTimeouts     int       `json:"heartbeat_timeouts"`
+	TestFailures          int       `json:"test_failures"`
+	DistroTestFailureRate float64   `json:"distro_test_failure_rate"`
+	UpdatedAt             APITime   `json:"updated_at"`
+	RestoredAt            APITime   `json:"restored_at"`
+}
+
+func (a *APIHostHealth) BuildFromService(h interface{}) error {
+	var v *host.Host
+	switch h := h.(type) {
+	case host.Host:
+		v = &h
+	case *host.Host:
+		v = h
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+
+	a.HostID = ToAPIString(v.Id)
+	a.Distro = ToAPIString(v.Distro.Id)
+	a.Status = ToAPIString(v.Status)
+	a.Score = v.Health.Score
+	a.Tasks = v.Health.Tasks
+	a.SystemFailures = v.Health.SystemFailures
+	a.SetupFailures = v.Health.SetupFailures
+	a.HeartbeatTimeouts = v.Health.HeartbeatTimeouts
+	a.TestFailures = v.Health.TestFailures
+	a.DistroTestFailureRate = v.Health.DistroTestFailureRate
+	a.UpdatedAt = NewTime(v.Health.UpdatedAt)
+	a.RestoredAt = NewTime(v.Health.RestoredAt)
+	return nil
+}
+
+func (a *APIHostHealth) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for host health")
+}
@@ -24,6 +24,7 @@ type APIPatch struct {
 	VariantsTasks   []variantTask `json:"variants_tasks"`
 	Activated       bool          `json:"activated"`
 	Alias           APIString     `json:"alias,omitempty"`
+	PauseOnFailure  *bool         `json:"pause_on_failure,omitempty"`
 	GithubPatchData githubPatch   `json:"github_patch_data,omitempty"`
 }
 type variantTask struct {
@@ -73,6 +74,7 @@ func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	apiPatch.VariantsTasks = variantTasks
 	apiPatch.Activated = v.Activated
 	apiPatch.Alias = ToAPIString(v.Alias)
+	apiPatch.PauseOnFailure = v.PauseOnFailure
 	apiPatch.GithubPatchData = githubPatch{}
 	return errors.WithStack(apiPatch.GithubPatchData.BuildFromService(v.GithubPatchData))
 }
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/pkg/errors"
+)
+
+// APIChangePoint is a significant change in a measurement of the
+// performance data that a task sends with json.send.
+type APIChangePoint struct {
+	ID            APIString `json:"id"`
+	Project       APIString `json:"project"`
+	BuildVariant  APIString `json:"build_variant"`
+	Task          APIString `json:"task"`
+	Name          APIString `json:"name"`
+	Measurement   APIString `json:"measurement"`
+	VersionID     APIString `json:"version_id"`
+	TaskID        APIString `json:"task_id"`
+	Revision      APIString `json:"revision"`
+	Order         int       `json:"order"`
+	MeanBefore    float64   `json:"mean_before"`
+	MeanAfter     float64   `json:"mean_after"`
+	PercentChange float64   `json:"percent_change"`
+	Direction     APIString `json:"direction"`
+	Probability   float64   `json:"probability"`
+	DetectedAt    APITime   `json:"detected_at"`
+}
+
+func (a *APIChangePoint) BuildFromService(h interface{}) error {
+	var cp *perf.ChangePoint
+	switch v := h.(type) {
+	case perf.ChangePoint:
+		cp = &v
+	case *perf.ChangePoint:
+		cp = v
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+
+	a.ID = ToAPIString(cp.ID)
+	a.Project = ToAPIString(cp.Project)
+	a.BuildVariant = ToAPIString(cp.Variant)
+	a.Task = ToAPIString(cp.Task)
+	a.Name = ToAPIString(cp.Name)
+	a.Measurement = ToAPIString(cp.Measurement)
+	a.VersionID = ToAPIString(cp.VersionID)
+	a.TaskID = ToAPIString(cp.TaskID)
+	a.Revision = ToAPIString(cp.Revision)
+	a.Order = cp.Order
+	a.MeanBefore = cp.MeanBefore
+	a.MeanAfter = cp.MeanAfter
+	a.PercentChange = cp.PercentChange
+	a.Direction = ToAPIString(cp.Direction)
+	a.Probability = cp.Probability
+	a.DetectedAt = NewTime(cp.DetectedAt)
+	return nil
+}
+
+func (a *APIChangePoint) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for change points")
+}
+
+// APIPerfResult is the outcome of a performance test that a task sent with
+// perf.send.
+type APIPerfResult struct {
+	ID           APIString              `json:"id"`
+	Project      APIString              `json:"project"`
+	BuildVariant APIString              `json:"build_variant"`
+	TaskName     APIString              `json:"task_name"`
+	TaskID       APIString              `json:"task_id"`
+	Execution    int                    `json:"execution"`
+	VersionID    APIString              `json:"version_id"`
+	Revision     APIString              `json:"revision"`
+	Order        int                    `json:"order"`
+	IsPatch      bool                   `json:"is_patch"`
+	Test         APIString              `json:"test"`
+	Args         map[string]interface{} `json:"args"`
+	Metrics      []APIPerfMetric        `json:"metrics"`
+	CreatedAt    APITime                `json:"created_at"`
+}
+
+// APIPerfMetric is a measurement of a performance test.
+type APIPerfMetric struct {
+	Name      APIString `json:"name"`
+	Units     APIString `json:"units"`
+	Direction APIString `json:"direction"`
+	Value     float64   `json:"value"`
+	Trials    []float64 `json:"trials"`
+}
+
+func (a *APIPerfResult) BuildFromService(h interface{}) error {
+	var r *perf.Result
+	switch v := h.(type) {
+	case perf.Result:
+		r = &v
+	case *perf.Result:
+		r = v
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+
+	a.ID = ToAPIString(r.ID)
+	a.Project = ToAPIString(r.Info.Project)
+	a.BuildVariant = ToAPIString(r.Info.Variant)
+	a.TaskName = ToAPIString(r.Info.TaskName)
+	a.TaskID = ToAPIString(r.Info.TaskID)
+	a.Execution = r.Info.Execution
+	a.VersionID = ToAPIString(r.Info.VersionID)
+	a.Revision = ToAPIString(r.Info.Revision)
+	a.Order = r.Info.Order
+	a.IsPatch = r.Info.IsPatch
+	a.Test = ToAPIString(r.Test)
+	a.Args = r.Args
+	a.Metrics = make([]APIPerfMetric, 0, len(r.Metrics))
+	for _, m := range r.Metrics {
+		a.Metrics = append(a.Metrics, APIPerfMetric{
+			Name:      ToAPIString(m.Name),
+			Units:     ToAPIString(m.Units),
+			Direction: ToAPIString(m.Direction),
+			Value:     m.Value,
+			Trials:    m.Trials,
+		})
+	}
+	a.CreatedAt = NewTime(r.CreatedAt)
+	return nil
+}
+
+func (a *APIPerfResult) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for perf results")
+}
@@ -22,6 +22,9 @@ type APIProject struct {
 	Admins             []APIString `json:"admins"`
 	TracksPushEvents   bool        `json:"tracks_push_events"`
 	PRTestingEnabled   bool        `json:"pr_testing_enabled"`
+	DebugSessions      bool        `json:"debug_sessions_enabled"`
+	AbortSuperseded    bool        `json:"abort_superseded_pr_patches"`
+	GithubChecks       bool        `json:"github_checks_enabled"`
 }
 
 func (apiProject *APIProject) BuildFromService(p interface{}) error {
@@ -41,6 +44,9 @@ func (apiProject *APIProject) BuildFromService(p interface{}) error {
 	apiProject.Tracked = v.Tracked
 	apiProject.TracksPushEvents = v.TracksPushEvents
 	apiProject.PRTestingEnabled = v.PRTestingEnabled
+	apiProject.DebugSessions = v.DebugSessionsEnabled
+	apiProject.AbortSuperseded = v.AbortSupersededPRPatches
+	apiProject.GithubChecks = v.GithubChecksEnabled
 	apiProject.DeactivatePrevious = v.DeactivatePrevious
 
 	admins := []APIString{}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/provenance"
+	"github.com/pkg/errors"
+)
+
+// APIProvenance is the provenance document of a version. It is served in
+// the in-toto statement format that it is stored in, so that it can be
+// checked with standard supply chain tooling.
+type APIProvenance struct {
+	VersionId APIString `json:"version_id"`
+	provenance.Provenance
+}
+
+// BuildFromService converts from a provenance.Provenance to an APIProvenance.
+func (p *APIProvenance) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case provenance.Provenance:
+		p.VersionId = ToAPIString(v.Id)
+		p.Provenance = v
+	case *provenance.Provenance:
+		p.VersionId = ToAPIString(v.Id)
+		p.Provenance = *v
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+// ToService returns a provenance.Provenance from an APIProvenance.
+func (p *APIProvenance) ToService() (interface{}, error) {
+	doc := p.Provenance
+	doc.Id = FromAPIString(p.VersionId)
+	return doc, nil
+}
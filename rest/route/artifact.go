@@ -0,0 +1,351 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	artifactsByTask    = "task_id"
+	artifactsByBuild   = "build_id"
+	artifactsByVersion = "version_id"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/artifacts
+// GET /rest/v2/builds/{build_id}/artifacts
+// GET /rest/v2/versions/{version_id}/artifacts
+
+// artifactsGetHandler lists the files attached by a task, or by every
+// task in a build or version, optionally filtered by a glob on the file
+// name.
+type artifactsGetHandler struct {
+	resource  string
+	id        string
+	execution int
+	variant   string
+	name      string
+	key       string
+	limit     int
+	sc        data.Connector
+}
+
+func makeFetchArtifactsForTask(sc data.Connector) gimlet.RouteHandler {
+	return &artifactsGetHandler{resource: artifactsByTask, sc: sc}
+}
+
+func makeFetchArtifactsForBuild(sc data.Connector) gimlet.RouteHandler {
+	return &artifactsGetHandler{resource: artifactsByBuild, sc: sc}
+}
+
+func makeFetchArtifactsForVersion(sc data.Connector) gimlet.RouteHandler {
+	return &artifactsGetHandler{resource: artifactsByVersion, sc: sc}
+}
+
+func (h *artifactsGetHandler) Factory() gimlet.RouteHandler {
+	return &artifactsGetHandler{
+		resource: h.resource,
+		sc:       h.sc,
+	}
+}
+
+func (h *artifactsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)[h.resource]
+	if h.id == "" {
+		return gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("%s must be specified", h.resource),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	var err error
+	vals := r.URL.Query()
+
+	h.execution = -1
+	if execution := vals.Get("execution"); execution != "" && h.resource == artifactsByTask {
+		h.execution, err = strconv.Atoi(execution)
+		if err != nil || h.execution < 0 {
+			return gimlet.ErrorResponse{
+				Message:    "Invalid execution",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	if h.resource == artifactsByVersion {
+		h.variant = vals.Get("variant")
+	}
+
+	h.name = vals.Get("name")
+	if _, err = path.Match(h.name, ""); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("invalid name pattern '%s'", h.name),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	h.key = vals.Get("start_at")
+
+	h.limit, err = getLimit(vals)
+	return errors.WithStack(err)
+}
+
+func (h *artifactsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	var (
+		entries []artifact.Entry
+		err     error
+	)
+	switch h.resource {
+	case artifactsByTask:
+		entries, err = h.sc.FindArtifactsByTaskId(h.id, h.execution)
+	case artifactsByBuild:
+		entries, err = h.sc.FindArtifactsByBuildId(h.id)
+	case artifactsByVersion:
+		entries, err = h.sc.FindArtifactsByVersionId(h.id, h.variant)
+	}
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	artifacts, err := h.filter(entries, gimlet.GetUser(ctx) != nil)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	start := 0
+	if h.key != "" {
+		start = -1
+		for i := range artifacts {
+			if artifacts[i].key() == h.key {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("artifact '%s' not found", h.key),
+				StatusCode: http.StatusNotFound,
+			})
+		}
+	}
+	artifacts = artifacts[start:]
+
+	resp := gimlet.NewResponseBuilder()
+	if err = resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	if len(artifacts) > h.limit {
+		err = resp.SetPages(&gimlet.ResponsePages{
+			Next: &gimlet.Page{
+				Relation:        "next",
+				LimitQueryParam: "limit",
+				KeyQueryParam:   "start_at",
+				BaseURL:         h.sc.GetURL(),
+				Key:             artifacts[h.limit].key(),
+				Limit:           h.limit,
+			},
+		})
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err,
+				"problem paginating response"))
+		}
+		artifacts = artifacts[:h.limit]
+	}
+
+	for i := range artifacts {
+		if err = resp.AddData(&artifacts[i].artifact); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+	}
+
+	return resp
+}
+
+// keyedArtifact is a file along with its position among the files of its
+// task execution, which identifies it even when a task attaches several
+// files with the same name.
+type keyedArtifact struct {
+	artifact model.APIArtifact
+	index    int
+}
+
+func (a keyedArtifact) key() string {
+	return fmt.Sprintf("%s:%d:%s:%d", model.FromAPIString(a.artifact.TaskId),
+		a.artifact.Execution, model.FromAPIString(a.artifact.Name), a.index)
+}
+
+func (a keyedArtifact) less(other keyedArtifact) bool {
+	taskID, otherTaskID := model.FromAPIString(a.artifact.TaskId), model.FromAPIString(other.artifact.TaskId)
+	if taskID != otherTaskID {
+		return taskID < otherTaskID
+	}
+	if a.artifact.Execution != other.artifact.Execution {
+		return a.artifact.Execution < other.artifact.Execution
+	}
+	name, otherName := model.FromAPIString(a.artifact.Name), model.FromAPIString(other.artifact.Name)
+	if name != otherName {
+		return name < otherName
+	}
+	return a.index < other.index
+}
+
+// filter flattens the entries into a stable ordering of the files that
+// match the name pattern and that the UI would show the user.
+func (h *artifactsGetHandler) filter(entries []artifact.Entry, loggedIn bool) ([]keyedArtifact, error) {
+	artifacts := []keyedArtifact{}
+	for _, entry := range entries {
+		for i, file := range entry.Files {
+			if !file.IsVisible(loggedIn) {
+				continue
+			}
+			if h.name != "" {
+				if match, _ := path.Match(h.name, file.Name); !match {
+					continue
+				}
+			}
+
+			a := model.APIArtifact{
+				TaskId:          model.ToAPIString(entry.TaskId),
+				TaskDisplayName: model.ToAPIString(entry.TaskDisplayName),
+				BuildId:         model.ToAPIString(entry.BuildId),
+				Execution:       entry.Execution,
+			}
+			if err := a.APIFile.BuildFromService(file); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			artifacts = append(artifacts, keyedArtifact{artifact: a, index: i})
+		}
+	}
+
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		return artifacts[i].less(artifacts[j])
+	})
+
+	return artifacts, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/artifacts/manifest
+
+// artifactManifestGetHandler returns the manifest of every file attached
+// by a task execution and its signature, so that downloaded artifacts can
+// be checked against the checksums recorded when they were uploaded.
+type artifactManifestGetHandler struct {
+	taskId    string
+	execution int
+	sc        data.Connector
+}
+
+func makeFetchArtifactManifest(sc data.Connector) gimlet.RouteHandler {
+	return &artifactManifestGetHandler{sc: sc}
+}
+
+func (h *artifactManifestGetHandler) Factory() gimlet.RouteHandler {
+	return &artifactManifestGetHandler{sc: h.sc}
+}
+
+func (h *artifactManifestGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskId = gimlet.GetVars(r)["task_id"]
+	if h.taskId == "" {
+		return gimlet.ErrorResponse{
+			Message:    "task_id must be specified",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.execution = -1
+	if execution := r.URL.Query().Get("execution"); execution != "" {
+		var err error
+		h.execution, err = strconv.Atoi(execution)
+		if err != nil || h.execution < 0 {
+			return gimlet.ErrorResponse{
+				Message:    "Invalid execution",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *artifactManifestGetHandler) Run(ctx context.Context) gimlet.Responder {
+	entries, err := h.sc.FindArtifactsByTaskId(h.taskId, h.execution)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if len(entries) == 0 {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("no artifacts found for task '%s'", h.taskId),
+			StatusCode: http.StatusNotFound,
+		})
+	}
+
+	latest := entries[0]
+	for _, entry := range entries[1:] {
+		if entry.Execution > latest.Execution {
+			latest = entry
+		}
+	}
+
+	return gimlet.NewJSONResponse(&model.APIArtifactManifest{
+		TaskId:    model.ToAPIString(latest.TaskId),
+		Execution: latest.Execution,
+		Manifest:  model.ToAPIString(string(latest.Manifest())),
+		Signature: model.ToAPIString(latest.Signature),
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/artifacts/signing_key
+
+// artifactSigningKeyGetHandler publishes the public key that verifies the
+// signatures of artifact manifests.
+type artifactSigningKeyGetHandler struct {
+	sc data.Connector
+}
+
+func makeFetchArtifactSigningKey(sc data.Connector) gimlet.RouteHandler {
+	return &artifactSigningKeyGetHandler{sc: sc}
+}
+
+func (h *artifactSigningKeyGetHandler) Factory() gimlet.RouteHandler {
+	return &artifactSigningKeyGetHandler{sc: h.sc}
+}
+
+func (h *artifactSigningKeyGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *artifactSigningKeyGetHandler) Run(ctx context.Context) gimlet.Responder {
+	settings, err := h.sc.GetEvergreenSettings()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	key, err := settings.ArtifactSigning.PublicKey()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem reading artifact signing key"))
+	}
+	if key == "" {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			Message:    "artifacts are not signed",
+			StatusCode: http.StatusNotFound,
+		})
+	}
+
+	return gimlet.NewJSONResponse(&model.APIArtifactSigningKey{
+		Algorithm: model.ToAPIString("ed25519"),
+		PublicKey: model.ToAPIString(key),
+	})
+}
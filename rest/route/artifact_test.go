@@ -0,0 +1,200 @@
+package route
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ed25519"
+)
+
+type ArtifactsSuite struct {
+	sc *data.MockConnector
+	suite.Suite
+}
+
+func TestArtifactsSuite(t *testing.T) {
+	suite.Run(t, new(ArtifactsSuite))
+}
+
+func (s *ArtifactsSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		URL: "https://example.net/test",
+		MockArtifactConnector: data.MockArtifactConnector{
+			CachedTasks: []task.Task{
+				{Id: "t1", Version: "v1", BuildVariant: "linux"},
+				{Id: "t2", Version: "v1", BuildVariant: "windows"},
+			},
+			CachedArtifacts: []artifact.Entry{
+				{TaskId: "t1", BuildId: "b1", TaskDisplayName: "compile", Files: []artifact.File{
+					{Name: "binaries.tgz", Link: "http://example.com/t1/binaries.tgz"},
+					{Name: "logs.tgz", Link: "http://example.com/t1/logs.tgz"},
+					{Name: "hidden.tgz", Link: "http://example.com/t1/hidden.tgz", Visibility: artifact.None},
+					{Name: "private.tgz", Link: "http://example.com/t1/private.tgz", Visibility: artifact.Private},
+				}},
+				{TaskId: "t2", BuildId: "b2", TaskDisplayName: "compile", Files: []artifact.File{
+					{Name: "binaries.zip", Link: "http://example.com/t2/binaries.zip"},
+				}},
+			},
+		},
+	}
+}
+
+func (s *ArtifactsSuite) run(rh gimlet.RouteHandler) ([]model.APIArtifact, gimlet.Responder) {
+	return s.runAs(context.Background(), rh)
+}
+
+func (s *ArtifactsSuite) runAs(ctx context.Context, rh gimlet.RouteHandler) ([]model.APIArtifact, gimlet.Responder) {
+	resp := rh.Run(ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+
+	out := []model.APIArtifact{}
+	data, ok := resp.Data().([]interface{})
+	s.Require().True(ok)
+	for _, d := range data {
+		a, ok := d.(*model.APIArtifact)
+		s.Require().True(ok)
+		out = append(out, *a)
+	}
+	return out, resp
+}
+
+func (s *ArtifactsSuite) TestTaskArtifactsHideInvisibleFiles() {
+	rh := makeFetchArtifactsForTask(s.sc).(*artifactsGetHandler)
+	rh.id, rh.execution, rh.limit = "t1", -1, 10
+
+	artifacts, resp := s.run(rh)
+	s.Nil(resp.Pages())
+	s.Require().Len(artifacts, 2)
+	s.Equal("binaries.tgz", model.FromAPIString(artifacts[0].Name))
+	s.Equal("logs.tgz", model.FromAPIString(artifacts[1].Name))
+	s.Equal("t1", model.FromAPIString(artifacts[0].TaskId))
+
+	// private files are only shown to users who are logged in
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user"})
+	artifacts, _ = s.runAs(ctx, rh)
+	s.Require().Len(artifacts, 3)
+	s.Equal("private.tgz", model.FromAPIString(artifacts[2].Name))
+}
+
+func (s *ArtifactsSuite) TestNamePatternAndVariantFilter() {
+	rh := makeFetchArtifactsForVersion(s.sc).(*artifactsGetHandler)
+	rh.id, rh.name, rh.limit = "v1", "binaries.*", 10
+
+	artifacts, _ := s.run(rh)
+	s.Len(artifacts, 2)
+
+	rh.variant = "windows"
+	artifacts, _ = s.run(rh)
+	s.Require().Len(artifacts, 1)
+	s.Equal("http://example.com/t2/binaries.zip", model.FromAPIString(artifacts[0].Link))
+}
+
+func (s *ArtifactsSuite) TestPagination() {
+	rh := makeFetchArtifactsForVersion(s.sc).(*artifactsGetHandler)
+	rh.id, rh.limit = "v1", 2
+
+	artifacts, resp := s.run(rh)
+	s.Len(artifacts, 2)
+	s.Require().NotNil(resp.Pages())
+	s.Require().NotNil(resp.Pages().Next)
+	next := resp.Pages().Next.Key
+	s.Equal("t2:0:binaries.zip:0", next)
+
+	rh.key = next
+	artifacts, resp = s.run(rh)
+	s.Nil(resp.Pages())
+	s.Require().Len(artifacts, 1)
+	s.Equal("binaries.zip", model.FromAPIString(artifacts[0].Name))
+
+	rh.key = "missing"
+	s.Equal(http.StatusNotFound, rh.Run(context.Background()).Status())
+}
+
+func (s *ArtifactsSuite) TestPaginationWithDuplicateNames() {
+	s.sc.CachedArtifacts = []artifact.Entry{
+		{TaskId: "t1", BuildId: "b1", Files: []artifact.File{
+			{Name: "log", Link: "http://example.com/t1/a.log"},
+			{Name: "log", Link: "http://example.com/t1/b.log"},
+			{Name: "log", Link: "http://example.com/t1/c.log"},
+		}},
+	}
+	rh := makeFetchArtifactsForTask(s.sc).(*artifactsGetHandler)
+	rh.id, rh.execution, rh.limit = "t1", -1, 1
+
+	links := []string{}
+	for {
+		artifacts, resp := s.run(rh)
+		s.Require().Len(artifacts, 1)
+		links = append(links, model.FromAPIString(artifacts[0].Link))
+		if resp.Pages() == nil {
+			break
+		}
+		s.Require().True(len(links) < 3)
+		rh.key = resp.Pages().Next.Key
+	}
+	s.Equal([]string{"http://example.com/t1/a.log", "http://example.com/t1/b.log", "http://example.com/t1/c.log"}, links)
+}
+
+func (s *ArtifactsSuite) TestBuildArtifacts() {
+	rh := makeFetchArtifactsForBuild(s.sc).(*artifactsGetHandler)
+	rh.id, rh.limit = "b2", 10
+
+	artifacts, _ := s.run(rh)
+	s.Require().Len(artifacts, 1)
+	s.Equal("b2", model.FromAPIString(artifacts[0].BuildId))
+}
+
+func (s *ArtifactsSuite) TestManifest() {
+	s.sc.CachedArtifacts = append(s.sc.CachedArtifacts, artifact.Entry{
+		TaskId: "t2", Execution: 1, Signature: "ed25519=abc", Files: []artifact.File{
+			{Name: "binaries.zip", Link: "http://example.com/t2/binaries.zip", SHA256: "1234"},
+		},
+	})
+
+	rh := makeFetchArtifactManifest(s.sc).(*artifactManifestGetHandler)
+	rh.taskId, rh.execution = "t2", -1
+	resp := rh.Run(context.Background())
+	s.Require().Equal(http.StatusOK, resp.Status())
+	manifest, ok := resp.Data().(*model.APIArtifactManifest)
+	s.Require().True(ok)
+	s.Equal(1, manifest.Execution)
+	s.Equal("task t2 execution 1\n1234  binaries.zip  http://example.com/t2/binaries.zip\n", model.FromAPIString(manifest.Manifest))
+	s.Equal("ed25519=abc", model.FromAPIString(manifest.Signature))
+
+	rh.execution = 0
+	resp = rh.Run(context.Background())
+	s.Require().Equal(http.StatusOK, resp.Status())
+	manifest, ok = resp.Data().(*model.APIArtifactManifest)
+	s.Require().True(ok)
+	s.Equal(0, manifest.Execution)
+	s.Empty(model.FromAPIString(manifest.Signature))
+
+	rh.taskId = "missing"
+	s.Equal(http.StatusNotFound, rh.Run(context.Background()).Status())
+}
+
+func (s *ArtifactsSuite) TestSigningKey() {
+	seed := make([]byte, ed25519.SeedSize)
+	s.sc.MockAdminConnector.MockSettings = &evergreen.Settings{}
+	rh := makeFetchArtifactSigningKey(s.sc)
+	s.Equal(http.StatusNotFound, rh.Run(context.Background()).Status())
+
+	s.sc.MockAdminConnector.MockSettings.ArtifactSigning.PrivateKey = base64.StdEncoding.EncodeToString(seed)
+	resp := rh.Run(context.Background())
+	s.Require().Equal(http.StatusOK, resp.Status())
+	key, ok := resp.Data().(*model.APIArtifactSigningKey)
+	s.Require().True(ok)
+	s.Equal("ed25519", model.FromAPIString(key.Algorithm))
+	public := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	s.Equal(base64.StdEncoding.EncodeToString(public), model.FromAPIString(key.PublicKey))
+}
@@ -0,0 +1,54 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/build_cache/stats
+
+// buildCacheStatsHandler returns a project's counts of cache hits,
+// misses, saves and evictions.
+type buildCacheStatsHandler struct {
+	project string
+	sc      data.Connector
+}
+
+func makeFetchBuildCacheStats(sc data.Connector) gimlet.RouteHandler {
+	return &buildCacheStatsHandler{sc: sc}
+}
+
+func (h *buildCacheStatsHandler) Factory() gimlet.RouteHandler {
+	return &buildCacheStatsHandler{sc: h.sc}
+}
+
+func (h *buildCacheStatsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+	if h.project == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "project_id must be specified",
+		}
+	}
+	return nil
+}
+
+func (h *buildCacheStatsHandler) Run(ctx context.Context) gimlet.Responder {
+	stats, err := h.sc.FindBuildCacheStats(h.project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	out := &model.APIBuildCacheStats{}
+	if err = out.BuildFromService(stats); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(out)
+}
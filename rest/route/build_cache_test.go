@@ -0,0 +1,47 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCacheStatsRoute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockBuildCacheConnector: data.MockBuildCacheConnector{
+			CachedBuildCacheStats: map[string]buildcache.ProjectStats{
+				"p": {Project: "p", Hits: 3, PartialHits: 1, Misses: 2, Saves: 2, Evictions: 1, BytesEvicted: 1024},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	h := makeFetchBuildCacheStats(sc).(*buildCacheStatsHandler)
+	h.project = "p"
+	resp := h.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	stats, ok := resp.Data().(*model.APIBuildCacheStats)
+	require.True(ok)
+	assert.Equal("p", model.FromAPIString(stats.Project))
+	assert.EqualValues(3, stats.Hits)
+	assert.EqualValues(1, stats.PartialHits)
+	assert.EqualValues(2, stats.Misses)
+	assert.EqualValues(1024, stats.BytesEvicted)
+
+	h.project = "unused"
+	resp = h.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	stats, ok = resp.Data().(*model.APIBuildCacheStats)
+	require.True(ok)
+	assert.Equal("unused", model.FromAPIString(stats.Project))
+	assert.Zero(stats.Hits)
+}
@@ -0,0 +1,273 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/debugsession"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// maxDebugOutputChunks bounds the output returned by one request.
+const maxDebugOutputChunks = 100
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/tasks/{task_id}/debug
+
+// debugSessionCreateHandler requests an interactive shell into a running
+// task. Only project admins and superusers may open one, and only into tasks
+// of projects that allow debug sessions. The agent starts the shell the next
+// time it checks in.
+type debugSessionCreateHandler struct {
+	taskID string
+	sc     data.Connector
+}
+
+func makeCreateDebugSession(sc data.Connector) gimlet.RouteHandler {
+	return &debugSessionCreateHandler{sc: sc}
+}
+
+func (h *debugSessionCreateHandler) Factory() gimlet.RouteHandler {
+	return &debugSessionCreateHandler{sc: h.sc}
+}
+
+func (h *debugSessionCreateHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskID = gimlet.GetVars(r)["task_id"]
+	if h.taskID == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a task",
+		}
+	}
+	return nil
+}
+
+func (h *debugSessionCreateHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	t, err := h.sc.FindTaskById(h.taskID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if t.Status != evergreen.TaskStarted && t.Status != evergreen.TaskDispatched && t.Status != evergreen.TaskPaused {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("task '%s' is %s, not running", t.Id, t.Status),
+		})
+	}
+
+	ref, err := h.sc.FindProjectByBranch(t.Project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if ref == nil || !ref.DebugSessionsEnabled {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project '%s' does not allow debug sessions", t.Project),
+		})
+	}
+	if !util.StringSliceContains(ref.Admins, u.Username()) && !auth.IsSuperUser(h.sc.GetSuperUsers(), u) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("only admins of project '%s' may debug its tasks", t.Project),
+		})
+	}
+
+	s, err := h.sc.CreateDebugSession(t, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem creating debug session"))
+	}
+
+	out := &model.APIDebugSession{}
+	if err = out.BuildFromService(s); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(out)
+}
+
+// findOwnDebugSession returns the session if it belongs to the user or the
+// user is a superuser.
+func findOwnDebugSession(sc data.Connector, id string, u gimlet.User) (*debugsession.Session, error) {
+	s, err := sc.FindDebugSessionById(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.User != u.Username() && !auth.IsSuperUser(sc.GetSuperUsers(), u) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("debug session '%s' belongs to another user", id),
+		}
+	}
+	return s, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/debug/{session_id}
+
+// debugSessionGetHandler returns a debug session and the output its shell
+// has written after the sequence number given by the after parameter.
+type debugSessionGetHandler struct {
+	sessionID string
+	after     int
+	sc        data.Connector
+}
+
+func makeFetchDebugSession(sc data.Connector) gimlet.RouteHandler {
+	return &debugSessionGetHandler{sc: sc}
+}
+
+func (h *debugSessionGetHandler) Factory() gimlet.RouteHandler {
+	return &debugSessionGetHandler{sc: h.sc}
+}
+
+func (h *debugSessionGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.sessionID = gimlet.GetVars(r)["session_id"]
+	if after := r.URL.Query().Get("after"); after != "" {
+		var err error
+		h.after, err = strconv.Atoi(after)
+		if err != nil || h.after < 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid value '%s' for after", after),
+			}
+		}
+	}
+	return nil
+}
+
+func (h *debugSessionGetHandler) Run(ctx context.Context) gimlet.Responder {
+	s, err := findOwnDebugSession(h.sc, h.sessionID, MustHaveUser(ctx))
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	chunks, err := h.sc.FindDebugOutput(s.ID, h.after, maxDebugOutputChunks)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	out := &model.APIDebugSession{}
+	if err = out.BuildFromService(s); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	if err = out.BuildFromService(chunks); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(out)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/debug/{session_id}/input
+
+// debugSessionInputHandler queues input for a debug session's shell. The
+// data is written to the shell's stdin as is, so a command must end with a
+// newline.
+type debugSessionInputHandler struct {
+	sessionID string
+	data      string
+	sc        data.Connector
+}
+
+func makeSendDebugInput(sc data.Connector) gimlet.RouteHandler {
+	return &debugSessionInputHandler{sc: sc}
+}
+
+func (h *debugSessionInputHandler) Factory() gimlet.RouteHandler {
+	return &debugSessionInputHandler{sc: h.sc}
+}
+
+func (h *debugSessionInputHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.sessionID = gimlet.GetVars(r)["session_id"]
+
+	body := struct {
+		Data string `json:"data"`
+	}{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &body); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid request body: %s", err.Error()),
+		}
+	}
+	if body.Data == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "no input given",
+		}
+	}
+	if len(body.Data) > debugsession.MaxChunkSize {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("input may be at most %d bytes", debugsession.MaxChunkSize),
+		}
+	}
+	h.data = body.Data
+	return nil
+}
+
+func (h *debugSessionInputHandler) Run(ctx context.Context) gimlet.Responder {
+	s, err := findOwnDebugSession(h.sc, h.sessionID, MustHaveUser(ctx))
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	seq, err := h.sc.AppendDebugInput(s, h.data)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem sending input"))
+	}
+	return gimlet.NewJSONResponse(struct {
+		Seq int `json:"seq"`
+	}{Seq: seq})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/debug/{session_id}
+
+// debugSessionCloseHandler closes a debug session. The agent stops the
+// shell the next time it checks in.
+type debugSessionCloseHandler struct {
+	sessionID string
+	sc        data.Connector
+}
+
+func makeCloseDebugSession(sc data.Connector) gimlet.RouteHandler {
+	return &debugSessionCloseHandler{sc: sc}
+}
+
+func (h *debugSessionCloseHandler) Factory() gimlet.RouteHandler {
+	return &debugSessionCloseHandler{sc: h.sc}
+}
+
+func (h *debugSessionCloseHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.sessionID = gimlet.GetVars(r)["session_id"]
+	return nil
+}
+
+func (h *debugSessionCloseHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	s, err := findOwnDebugSession(h.sc, h.sessionID, u)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	if err = h.sc.CloseDebugSession(s, fmt.Sprintf("closed by %s", u.Username())); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	out := &model.APIDebugSession{}
+	if err = out.BuildFromService(s); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(out)
+}
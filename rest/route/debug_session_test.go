@@ -0,0 +1,86 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/debugsession"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugSessionRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{
+				{Id: "running", Project: "proj", HostId: "h1", Status: evergreen.TaskStarted},
+				{Id: "done", Project: "proj", Status: evergreen.TaskSucceeded},
+				{Id: "paused", Project: "proj", HostId: "h1", Status: evergreen.TaskPaused},
+				{Id: "other", Project: "locked", Status: evergreen.TaskStarted},
+			},
+		},
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*serviceModel.ProjectRef{
+				"proj":   {Identifier: "proj", Admins: []string{"admin"}, DebugSessionsEnabled: true},
+				"locked": {Identifier: "locked", Admins: []string{"admin"}},
+			},
+		},
+	}
+	sc.SetSuperUsers([]string{"root"})
+	admin := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+	stranger := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "stranger"})
+
+	create := func(ctx context.Context, taskID string) gimlet.Responder {
+		h := makeCreateDebugSession(sc).(*debugSessionCreateHandler)
+		h.taskID = taskID
+		return h.Run(ctx)
+	}
+	assert.Equal(http.StatusBadRequest, create(admin, "done").Status())
+	assert.Equal(http.StatusBadRequest, create(admin, "other").Status())
+	assert.Equal(http.StatusUnauthorized, create(stranger, "running").Status())
+
+	resp := create(admin, "running")
+	require.Equal(http.StatusOK, resp.Status())
+	session := resp.Data().(*model.APIDebugSession)
+	id := model.FromAPIString(session.ID)
+	assert.Equal("admin", model.FromAPIString(session.User))
+	assert.Equal("h1", model.FromAPIString(session.HostID))
+	assert.Equal(debugsession.StatusRequested, model.FromAPIString(session.Status))
+	assert.Equal(http.StatusBadRequest, create(admin, "running").Status(), "a task has one open session")
+	assert.Equal(http.StatusOK, create(admin, "paused").Status(), "a task paused on failure can be debugged")
+
+	input := &debugSessionInputHandler{sc: sc, sessionID: id, data: "ls\n"}
+	assert.Equal(http.StatusUnauthorized, input.Run(stranger).Status())
+	assert.Equal(http.StatusOK, input.Run(admin).Status())
+	require.Len(sc.MockDebugSessionConnector.CachedChunks, 1)
+	assert.Equal("ls\n", sc.MockDebugSessionConnector.CachedChunks[0].Data)
+
+	sc.MockDebugSessionConnector.CachedChunks = append(sc.MockDebugSessionConnector.CachedChunks,
+		debugsession.Chunk{SessionID: id, Stream: debugsession.StreamOutput, Seq: 1, Data: "a"},
+		debugsession.Chunk{SessionID: id, Stream: debugsession.StreamOutput, Seq: 2, Data: "b"})
+	get := &debugSessionGetHandler{sc: sc, sessionID: id, after: 1}
+	resp = get.Run(admin)
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Equal([]model.APIDebugOutput{{Seq: 2, Data: "b"}}, resp.Data().(*model.APIDebugSession).Output)
+	assert.Equal(http.StatusNotFound, (&debugSessionGetHandler{sc: sc, sessionID: "missing"}).Run(admin).Status())
+
+	closer := &debugSessionCloseHandler{sc: sc, sessionID: id}
+	assert.Equal(http.StatusUnauthorized, closer.Run(stranger).Status())
+	resp = closer.Run(admin)
+	require.Equal(http.StatusOK, resp.Status())
+	session = resp.Data().(*model.APIDebugSession)
+	assert.Equal(debugsession.StatusClosed, model.FromAPIString(session.Status))
+	assert.Equal("closed by admin", model.FromAPIString(session.CloseReason))
+	assert.Equal(http.StatusBadRequest, input.Run(admin).Status())
+}
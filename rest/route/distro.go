@@ -55,3 +55,52 @@ func (dgh *distroGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return resp
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/fair_share
+
+// distroFairShareHandler returns each project's share of a distro, its
+// recent usage, and how far that usage falls short of its share.
+type distroFairShareHandler struct {
+	distroID string
+	sc       data.Connector
+}
+
+func makeDistroFairShareRoute(sc data.Connector) gimlet.RouteHandler {
+	return &distroFairShareHandler{sc: sc}
+}
+
+func (h *distroFairShareHandler) Factory() gimlet.RouteHandler {
+	return &distroFairShareHandler{sc: h.sc}
+}
+
+func (h *distroFairShareHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	if h.distroID == "" {
+		return errors.New("distro id must not be empty")
+	}
+	return nil
+}
+
+func (h *distroFairShareHandler) Run(ctx context.Context) gimlet.Responder {
+	shares, err := h.sc.FindProjectShares(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	if err = resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	for i := range shares {
+		out := &model.APIProjectShare{}
+		if err = out.BuildFromService(&shares[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		if err = resp.AddData(out); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+	}
+	return resp
+}
@@ -0,0 +1,66 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistroFairShareRoute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockDistroConnector: data.MockDistroConnector{
+			CachedDistros: []distro.Distro{
+				{
+					Id:              "d1",
+					TaskPrioritizer: distro.TaskPrioritizerFairShare,
+					FairShare: distro.FairShareSettings{
+						Weights: []distro.ProjectWeight{{Project: "a", Weight: 2}},
+					},
+				},
+			},
+			CachedTasks: []task.Task{
+				{Id: "t1", DistroId: "d1", Project: "a", Status: evergreen.TaskUndispatched, Activated: true},
+				{Id: "t2", DistroId: "d1", Project: "b", Status: evergreen.TaskUndispatched, Activated: true},
+				{Id: "t3", DistroId: "d1", Project: "b", Status: evergreen.TaskSucceeded, TimeTaken: time.Hour, FinishTime: time.Now()},
+				{Id: "t4", DistroId: "d2", Project: "c", Status: evergreen.TaskUndispatched, Activated: true},
+			},
+		},
+	}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user"})
+
+	handler := makeDistroFairShareRoute(sc).(*distroFairShareHandler)
+	handler.distroID = "d1"
+	resp := handler.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	shares, ok := resp.Data().([]interface{})
+	require.True(ok)
+	require.Len(shares, 2)
+
+	a := shares[0].(*model.APIProjectShare)
+	assert.Equal("a", model.FromAPIString(a.Project))
+	assert.InDelta(2.0/3, a.Share, 0.0001)
+	assert.Equal(1, a.QueuedTasks)
+
+	b := shares[1].(*model.APIProjectShare)
+	assert.Equal("b", model.FromAPIString(b.Project))
+	assert.Equal(1.0, b.UsageFraction)
+
+	handler = makeDistroFairShareRoute(sc).(*distroFairShareHandler)
+	handler.distroID = "d2"
+	resp = handler.Run(ctx)
+	assert.Equal(http.StatusNotFound, resp.Status())
+}
@@ -0,0 +1,163 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/google/go-github/github"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	githubEventCheckRun         = "check_run"
+	githubActionRerequested     = "rerequested"
+	githubActionRequestedAction = "requested_action"
+)
+
+// githubCheckRunEvent is the part of a check_run webhook that the hook
+// uses, since the github client doesn't support the checks API.
+type githubCheckRunEvent struct {
+	Action   string `json:"action"`
+	CheckRun struct {
+		ExternalID string `json:"external_id"`
+	} `json:"check_run"`
+	RequestedAction *struct {
+		Identifier string `json:"identifier"`
+	} `json:"requested_action"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// githubAppHookApi receives the webhooks of the Github App that reports
+// check runs. Github sends the events of an app's check runs to the app's
+// webhook, rather than to the repository's.
+type githubAppHookApi struct {
+	secret []byte
+
+	event     *githubCheckRunEvent
+	eventType string
+	msgID     string
+	sc        data.Connector
+}
+
+func makeGithubAppHooksRoute(sc data.Connector, secret []byte) gimlet.RouteHandler {
+	return &githubAppHookApi{
+		sc:     sc,
+		secret: secret,
+	}
+}
+
+func (gh *githubAppHookApi) Factory() gimlet.RouteHandler {
+	return &githubAppHookApi{
+		secret: gh.secret,
+		sc:     gh.sc,
+	}
+}
+
+func (gh *githubAppHookApi) Parse(ctx context.Context, r *http.Request) error {
+	gh.eventType = r.Header.Get("X-Github-Event")
+	gh.msgID = r.Header.Get("X-Github-Delivery")
+
+	if len(gh.secret) == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "github app webhooks are not configured and therefore disabled",
+		}
+	}
+
+	body, err := github.ValidatePayload(r, gh.secret)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"source":  "github app hook",
+			"message": "rejecting github app webhook",
+			"msg_id":  gh.msgID,
+			"event":   gh.eventType,
+		}))
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "failed to read request body",
+		}
+	}
+
+	// the app also receives events, such as installations, that aren't
+	// acted on
+	if gh.eventType != githubEventCheckRun {
+		return nil
+	}
+
+	gh.event = &githubCheckRunEvent{}
+	if err = json.Unmarshal(body, gh.event); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (gh *githubAppHookApi) Run(ctx context.Context) gimlet.Responder {
+	if gh.event == nil {
+		return gimlet.NewJSONResponse(struct{}{})
+	}
+
+	rerunFailed := gh.event.Action == githubActionRerequested ||
+		(gh.event.Action == githubActionRequestedAction && gh.event.RequestedAction != nil &&
+			gh.event.RequestedAction.Identifier == model.GithubCheckRunRerunFailed)
+	if !rerunFailed {
+		return gimlet.NewJSONResponse(struct{}{})
+	}
+
+	projectID, err := gh.sc.FindGithubCheckRunProject(gh.event.CheckRun.ExternalID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem finding project of check run"))
+	}
+	ref, err := gh.sc.FindProjectByBranch(projectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding project '%s'", projectID))
+	}
+	if ref == nil || !ref.GithubChecksEnabled {
+		grip.Info(message.Fields{
+			"source":      "github app hook",
+			"msg_id":      gh.msgID,
+			"event":       gh.eventType,
+			"action":      gh.event.Action,
+			"external_id": gh.event.CheckRun.ExternalID,
+			"project":     projectID,
+			"message":     "ignoring check run event, github checks are disabled for the project",
+		})
+		return gimlet.NewJSONResponse(struct{}{})
+	}
+
+	grip.Info(message.Fields{
+		"source":      "github app hook",
+		"msg_id":      gh.msgID,
+		"event":       gh.eventType,
+		"action":      gh.event.Action,
+		"external_id": gh.event.CheckRun.ExternalID,
+		"project":     projectID,
+		"sender":      gh.event.Sender.Login,
+		"message":     "restarting failed tasks of check run",
+	})
+
+	if err = gh.sc.RestartFailedGithubCheckTasks(gh.event.CheckRun.ExternalID, model.GithubCheckRunCaller); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"source":      "github app hook",
+			"msg_id":      gh.msgID,
+			"event":       gh.eventType,
+			"action":      gh.event.Action,
+			"external_id": gh.event.CheckRun.ExternalID,
+			"message":     "failed to restart failed tasks of check run",
+		}))
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
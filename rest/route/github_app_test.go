@@ -0,0 +1,88 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGithubAppHookCheckRunRerunFailed(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	secret := []byte("app secret")
+	sc := &data.MockConnector{
+		MockBuildConnector: data.MockBuildConnector{
+			CachedBuilds: []build.Build{{Id: "b", Project: "proj"}, {Id: "unchecked", Project: "other"}},
+			CachedProjects: map[string]*model.ProjectRef{
+				"proj":  {Identifier: "proj", GithubChecksEnabled: true},
+				"other": {Identifier: "other"},
+			},
+		},
+	}
+	h, ok := makeGithubAppHooksRoute(sc, secret).Factory().(*githubAppHookApi)
+	require.True(ok)
+
+	body := []byte(`{
+		"action": "requested_action",
+		"check_run": {"id": 4, "external_id": "build:b"},
+		"requested_action": {"identifier": "rerun_failed"},
+		"sender": {"login": "octocat"}
+	}`)
+	req, err := makeRequest("1", body, secret)
+	require.NoError(err)
+	req.Header.Set("X-Github-Event", "check_run")
+
+	require.NoError(h.Parse(ctx, req))
+	resp := h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Equal(model.GithubCheckRunCaller, sc.MockBuildConnector.CachedRestartedCheck["build:b"])
+
+	// the repository's secret doesn't sign the app's webhooks
+	req, err = makeRequest("2", body, []byte("repo secret"))
+	require.NoError(err)
+	req.Header.Set("X-Github-Event", "check_run")
+	assert.Error(h.Parse(ctx, req))
+
+	// other events restart nothing
+	h, ok = h.Factory().(*githubAppHookApi)
+	require.True(ok)
+	req, err = makeRequest("3", []byte(`{"action": "created"}`), secret)
+	require.NoError(err)
+	req.Header.Set("X-Github-Event", "installation")
+	require.NoError(h.Parse(ctx, req))
+	assert.Nil(h.event)
+	assert.Equal(http.StatusOK, h.Run(ctx).Status())
+
+	h.event = &githubCheckRunEvent{Action: "created"}
+	assert.Equal(http.StatusOK, h.Run(ctx).Status())
+	assert.Len(sc.MockBuildConnector.CachedRestartedCheck, 1)
+
+	// projects without github checks ignore the app's check runs
+	h.event = &githubCheckRunEvent{Action: "rerequested"}
+	h.event.CheckRun.ExternalID = "build:unchecked"
+	assert.Equal(http.StatusOK, h.Run(ctx).Status())
+	assert.Len(sc.MockBuildConnector.CachedRestartedCheck, 1)
+
+	h.event.CheckRun.ExternalID = "build:missing"
+	assert.NotEqual(http.StatusOK, h.Run(ctx).Status())
+	assert.Len(sc.MockBuildConnector.CachedRestartedCheck, 1)
+
+	sc.MockBuildConnector.FailOnRestart = true
+	h.event.CheckRun.ExternalID = "build:b"
+	assert.NotEqual(http.StatusOK, h.Run(ctx).Status())
+
+	// without a secret the app's webhooks are disabled
+	h, ok = makeGithubAppHooksRoute(sc, nil).Factory().(*githubAppHookApi)
+	require.True(ok)
+	req, err = makeRequest("4", body, secret)
+	require.NoError(err)
+	assert.Error(h.Parse(ctx, req))
+}
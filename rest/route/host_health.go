@@ -0,0 +1,206 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/host_health
+
+// hostHealthListHandler returns the health scores of hosts, from least to
+// most healthy, optionally filtered by distro.
+type hostHealthListHandler struct {
+	distroID string
+	sc       data.Connector
+}
+
+func makeFetchHostHealth(sc data.Connector) gimlet.RouteHandler {
+	return &hostHealthListHandler{sc: sc}
+}
+
+func (h *hostHealthListHandler) Factory() gimlet.RouteHandler {
+	return &hostHealthListHandler{sc: h.sc}
+}
+
+func (h *hostHealthListHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = r.URL.Query().Get("distro")
+	return nil
+}
+
+func (h *hostHealthListHandler) Run(ctx context.Context) gimlet.Responder {
+	hosts, err := h.sc.FindHostsWithHealth(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	if err = resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	for i := range hosts {
+		out := &model.APIHostHealth{}
+		if err = out.BuildFromService(&hosts[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		if err = resp.AddData(out); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+	}
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/hosts/{host_id}/health
+
+type hostHealthGetHandler struct {
+	hostID string
+	sc     data.Connector
+}
+
+func makeFetchHostHealthByID(sc data.Connector) gimlet.RouteHandler {
+	return &hostHealthGetHandler{sc: sc}
+}
+
+func (h *hostHealthGetHandler) Factory() gimlet.RouteHandler {
+	return &hostHealthGetHandler{sc: h.sc}
+}
+
+func (h *hostHealthGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	return err
+}
+
+func (h *hostHealthGetHandler) Run(ctx context.Context) gimlet.Responder {
+	foundHost, err := h.sc.FindHostById(h.hostID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	out := &model.APIHostHealth{}
+	if err = out.BuildFromService(foundHost); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(out)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hosts/{host_id}/quarantine
+
+type hostQuarantineHandler struct {
+	hostID string
+	reason string
+	sc     data.Connector
+}
+
+func makeQuarantineHost(sc data.Connector) gimlet.RouteHandler {
+	return &hostQuarantineHandler{sc: sc}
+}
+
+func (h *hostQuarantineHandler) Factory() gimlet.RouteHandler {
+	return &hostQuarantineHandler{sc: h.sc}
+}
+
+func (h *hostQuarantineHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	if err != nil {
+		return err
+	}
+
+	body := struct {
+		Reason string `json:"reason"`
+	}{}
+	if r.Body != nil && r.ContentLength != 0 {
+		if err = util.ReadJSONInto(util.NewRequestReader(r), &body); err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid request body: %s", err.Error()),
+			}
+		}
+	}
+	h.reason = body.Reason
+	return nil
+}
+
+func (h *hostQuarantineHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	foundHost, err := h.sc.FindHostById(h.hostID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if foundHost.Status == evergreen.HostTerminated {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("host %s is already terminated", foundHost.Id),
+		})
+	}
+
+	reason := h.reason
+	if reason == "" {
+		reason = fmt.Sprintf("quarantined by %s", u.Id)
+	}
+	if err = h.sc.QuarantineHost(foundHost, u.Id, reason); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	out := &model.APIHostHealth{}
+	if err = out.BuildFromService(foundHost); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(out)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hosts/{host_id}/restore
+
+type hostRestoreHandler struct {
+	hostID string
+	sc     data.Connector
+}
+
+func makeRestoreHost(sc data.Connector) gimlet.RouteHandler {
+	return &hostRestoreHandler{sc: sc}
+}
+
+func (h *hostRestoreHandler) Factory() gimlet.RouteHandler {
+	return &hostRestoreHandler{sc: h.sc}
+}
+
+func (h *hostRestoreHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	return err
+}
+
+func (h *hostRestoreHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	foundHost, err := h.sc.FindHostById(h.hostID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if err = h.sc.RestoreHost(foundHost, u.Id); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	out := &model.APIHostHealth{}
+	if err = out.BuildFromService(foundHost); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(out)
+}
@@ -0,0 +1,69 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostHealthRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Now()
+	sc := &data.MockConnector{
+		MockHostConnector: data.MockHostConnector{
+			CachedHosts: []host.Host{
+				{Id: "h1", Status: evergreen.HostRunning, Distro: distro.Distro{Id: "d1"}, Health: host.HealthStats{Score: 0.1, Tasks: 10, UpdatedAt: now}},
+				{Id: "h2", Status: evergreen.HostRunning, Distro: distro.Distro{Id: "d1"}, Health: host.HealthStats{Score: 0.7, Tasks: 10, SystemFailures: 7, UpdatedAt: now}},
+				{Id: "h3", Status: evergreen.HostRunning, Distro: distro.Distro{Id: "d2"}, Health: host.HealthStats{Score: 0.2, UpdatedAt: now}},
+				{Id: "h4", Status: evergreen.HostRunning, Distro: distro.Distro{Id: "d1"}},
+			},
+		},
+	}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	list := makeFetchHostHealth(sc).(*hostHealthListHandler)
+	list.distroID = "d1"
+	resp := list.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	scores, ok := resp.Data().([]interface{})
+	require.True(ok)
+	require.Len(scores, 2)
+	assert.Equal("h2", model.FromAPIString(scores[0].(*model.APIHostHealth).HostID))
+	assert.Equal(7, scores[0].(*model.APIHostHealth).SystemFailures)
+
+	get := makeFetchHostHealthByID(sc).(*hostHealthGetHandler)
+	get.hostID = "h3"
+	resp = get.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Equal(0.2, resp.Data().(*model.APIHostHealth).Score)
+	get.hostID = "missing"
+	assert.Equal(http.StatusNotFound, get.Run(ctx).Status())
+
+	restore := makeRestoreHost(sc).(*hostRestoreHandler)
+	restore.hostID = "h2"
+	assert.Equal(http.StatusBadRequest, restore.Run(ctx).Status())
+
+	quarantine := makeQuarantineHost(sc).(*hostQuarantineHandler)
+	quarantine.hostID = "h2"
+	resp = quarantine.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Equal(evergreen.HostQuarantined, model.FromAPIString(resp.Data().(*model.APIHostHealth).Status))
+	assert.Equal(evergreen.HostQuarantined, sc.MockHostConnector.CachedHosts[1].Status)
+
+	resp = restore.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Equal(evergreen.HostRunning, sc.MockHostConnector.CachedHosts[1].Status)
+}
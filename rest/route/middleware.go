@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 )
 
@@ -110,3 +111,12 @@ func validPriority(priority int64, user gimlet.User, sc data.Connector) bool {
 	}
 	return true
 }
+
+// isProjectAdmin returns whether the user is an admin of the project or a
+// superuser.
+func isProjectAdmin(ref *model.ProjectRef, user gimlet.User, sc data.Connector) bool {
+	if user == nil {
+		return false
+	}
+	return util.StringSliceContains(ref.Admins, user.Username()) || auth.IsSuperUser(sc.GetSuperUsers(), user)
+}
@@ -21,8 +21,9 @@ import (
 // PATCH /rest/v2/patches/{patch_id}
 
 type patchChangeStatusHandler struct {
-	Activated *bool  `json:"activated"`
-	Priority  *int64 `json:"priority"`
+	Activated      *bool  `json:"activated"`
+	Priority       *int64 `json:"priority"`
+	PauseOnFailure *bool  `json:"pause_on_failure"`
 
 	patchId string
 	sc      data.Connector
@@ -49,9 +50,9 @@ func (p *patchChangeStatusHandler) Parse(ctx context.Context, r *http.Request) e
 		return errors.Wrap(err, "Argument read error")
 	}
 
-	if p.Activated == nil && p.Priority == nil {
+	if p.Activated == nil && p.Priority == nil && p.PauseOnFailure == nil {
 		return gimlet.ErrorResponse{
-			Message:    "Must set 'activated' or 'priority'",
+			Message:    "Must set 'activated', 'priority' or 'pause_on_failure'",
 			StatusCode: http.StatusBadRequest,
 		}
 	}
@@ -79,6 +80,11 @@ func (p *patchChangeStatusHandler) Run(ctx context.Context) gimlet.Responder {
 			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
 		}
 	}
+	if p.PauseOnFailure != nil {
+		if err := p.sc.SetPatchPauseOnFailure(p.patchId, *p.PauseOnFailure); err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+		}
+	}
 	foundPatch, err := p.sc.FindPatchById(p.patchId)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
@@ -0,0 +1,279 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultChangePointLimit = 100
+	defaultPerfResultLimit  = 1000
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/perf/change_points
+
+// projectChangePointsHandler returns a project's perf change points, most
+// recent first, optionally filtered by build variant, task and
+// measurement.
+type projectChangePointsHandler struct {
+	project     string
+	variant     string
+	task        string
+	measurement string
+	limit       int
+	sc          data.Connector
+}
+
+func makeFetchProjectChangePoints(sc data.Connector) gimlet.RouteHandler {
+	return &projectChangePointsHandler{sc: sc}
+}
+
+func (h *projectChangePointsHandler) Factory() gimlet.RouteHandler {
+	return &projectChangePointsHandler{sc: h.sc}
+}
+
+func (h *projectChangePointsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+	query := r.URL.Query()
+	h.variant = query.Get("build_variant")
+	h.task = query.Get("task")
+	h.measurement = query.Get("measurement")
+
+	h.limit = defaultChangePointLimit
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		h.limit, err = strconv.Atoi(limit)
+		if err != nil || h.limit <= 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid limit",
+			}
+		}
+	}
+	return nil
+}
+
+func (h *projectChangePointsHandler) Run(ctx context.Context) gimlet.Responder {
+	cps, err := h.sc.FindChangePoints(h.project, h.variant, h.task, h.measurement, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	return changePointsResponse(cps)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/perf/change_points
+
+type versionChangePointsHandler struct {
+	versionID string
+	sc        data.Connector
+}
+
+func makeFetchVersionChangePoints(sc data.Connector) gimlet.RouteHandler {
+	return &versionChangePointsHandler{sc: sc}
+}
+
+func (h *versionChangePointsHandler) Factory() gimlet.RouteHandler {
+	return &versionChangePointsHandler{sc: h.sc}
+}
+
+func (h *versionChangePointsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionID = gimlet.GetVars(r)["version_id"]
+	return nil
+}
+
+func (h *versionChangePointsHandler) Run(ctx context.Context) gimlet.Responder {
+	cps, err := h.sc.FindChangePointsByVersion(h.versionID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	return changePointsResponse(cps)
+}
+
+func changePointsResponse(cps []perf.ChangePoint) gimlet.Responder {
+	resp := gimlet.NewResponseBuilder()
+	if err := resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	for i := range cps {
+		out := &model.APIChangePoint{}
+		if err := out.BuildFromService(&cps[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		if err := resp.AddData(out); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+	}
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/perf/results
+
+// projectPerfResultsHandler returns the perf results of a project's
+// mainline versions, oldest first, optionally filtered by build variant,
+// task and test, by the time the results were sent, and by a range of
+// revisions.
+type projectPerfResultsHandler struct {
+	filter       perf.ResultsFilter
+	fromRevision string
+	toRevision   string
+	limit        int
+	sc           data.Connector
+}
+
+func makeFetchProjectPerfResults(sc data.Connector) gimlet.RouteHandler {
+	return &projectPerfResultsHandler{sc: sc}
+}
+
+func (h *projectPerfResultsHandler) Factory() gimlet.RouteHandler {
+	return &projectPerfResultsHandler{sc: h.sc}
+}
+
+func (h *projectPerfResultsHandler) Parse(ctx context.Context, r *http.Request) error {
+	query := r.URL.Query()
+	h.filter = perf.ResultsFilter{
+		Project:  gimlet.GetVars(r)["project_id"],
+		Variant:  query.Get("build_variant"),
+		TaskName: query.Get("task"),
+		Test:     query.Get("test"),
+	}
+	h.fromRevision = query.Get("from_revision")
+	h.toRevision = query.Get("to_revision")
+
+	var err error
+	for key, t := range map[string]*time.Time{"start": &h.filter.Start, "end": &h.filter.End} {
+		value := query.Get(key)
+		if value == "" {
+			continue
+		}
+		if *t, err = time.Parse(time.RFC3339, value); err != nil {
+			return gimlet.ErrorResponse{
+				Message: fmt.Sprintf("problem parsing %s time from '%s' (%s). Time must be given in the following format: %s",
+					key, value, err.Error(), time.RFC3339),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	if !h.filter.Start.IsZero() && !h.filter.End.IsZero() && !h.filter.Start.Before(h.filter.End) {
+		return gimlet.ErrorResponse{
+			Message:    "start time must be before end time",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.limit = defaultPerfResultLimit
+	if limit := query.Get("limit"); limit != "" {
+		h.limit, err = strconv.Atoi(limit)
+		if err != nil || h.limit <= 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid limit",
+			}
+		}
+	}
+	return nil
+}
+
+func (h *projectPerfResultsHandler) Run(ctx context.Context) gimlet.Responder {
+	var err error
+	if h.fromRevision != "" {
+		if h.filter.MinOrder, err = h.sc.FindRevisionOrder(h.filter.Project, h.fromRevision); err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem finding from_revision"))
+		}
+	}
+	if h.toRevision != "" {
+		if h.filter.MaxOrder, err = h.sc.FindRevisionOrder(h.filter.Project, h.toRevision); err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem finding to_revision"))
+		}
+	}
+
+	results, err := h.sc.FindPerfResults(h.filter, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	return perfResultsResponse(results)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/perf/results
+
+// taskPerfResultsHandler returns the perf results of a task's latest
+// execution, or of the execution given.
+type taskPerfResultsHandler struct {
+	taskID    string
+	execution int
+	sc        data.Connector
+}
+
+func makeFetchTaskPerfResults(sc data.Connector) gimlet.RouteHandler {
+	return &taskPerfResultsHandler{sc: sc}
+}
+
+func (h *taskPerfResultsHandler) Factory() gimlet.RouteHandler {
+	return &taskPerfResultsHandler{sc: h.sc}
+}
+
+func (h *taskPerfResultsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskID = gimlet.GetVars(r)["task_id"]
+
+	h.execution = -1
+	if execution := r.URL.Query().Get("execution"); execution != "" {
+		var err error
+		h.execution, err = strconv.Atoi(execution)
+		if err != nil || h.execution < 0 {
+			return gimlet.ErrorResponse{
+				Message:    "Invalid execution",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	return nil
+}
+
+func (h *taskPerfResultsHandler) Run(ctx context.Context) gimlet.Responder {
+	if h.execution < 0 {
+		t, err := h.sc.FindTaskById(h.taskID)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+		}
+		h.execution = t.Execution
+	}
+
+	results, err := h.sc.FindPerfResultsByTask(h.taskID, h.execution)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	return perfResultsResponse(results)
+}
+
+func perfResultsResponse(results []perf.Result) gimlet.Responder {
+	resp := gimlet.NewResponseBuilder()
+	if err := resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	for i := range results {
+		out := &model.APIPerfResult{}
+		if err := out.BuildFromService(&results[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		if err := resp.AddData(out); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+	}
+	return resp
+}
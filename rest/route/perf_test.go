@@ -0,0 +1,167 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangePointRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockPerfConnector: data.MockPerfConnector{
+			CachedChangePoints: []perf.ChangePoint{
+				{ID: "cp1", Project: "p", Variant: "linux", Task: "bench", Measurement: "ops", VersionID: "v1", Order: 1, PercentChange: -20},
+				{ID: "cp2", Project: "p", Variant: "linux", Task: "bench", Measurement: "latency", VersionID: "v2", Order: 2},
+				{ID: "cp3", Project: "p", Variant: "windows", Task: "bench", Measurement: "ops", VersionID: "v2", Order: 2},
+				{ID: "cp4", Project: "other", Variant: "linux", Task: "bench", Measurement: "ops", VersionID: "v3", Order: 3},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	list := makeFetchProjectChangePoints(sc).(*projectChangePointsHandler)
+	list.project = "p"
+	list.limit = defaultChangePointLimit
+	resp := list.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	cps, ok := resp.Data().([]interface{})
+	require.True(ok)
+	require.Len(cps, 3)
+	assert.Equal(2, cps[0].(*model.APIChangePoint).Order, "most recent first")
+
+	list.variant = "linux"
+	list.measurement = "ops"
+	resp = list.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	require.Len(resp.Data(), 1)
+	cp := resp.Data().([]interface{})[0].(*model.APIChangePoint)
+	assert.Equal("cp1", model.FromAPIString(cp.ID))
+	assert.Equal("linux", model.FromAPIString(cp.BuildVariant))
+	assert.Equal(-20.0, cp.PercentChange)
+
+	byVersion := makeFetchVersionChangePoints(sc).(*versionChangePointsHandler)
+	byVersion.versionID = "v2"
+	resp = byVersion.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Len(resp.Data(), 2)
+}
+
+func TestChangePointLimitParsing(t *testing.T) {
+	for limit, ok := range map[string]bool{"": true, "5": true, "0": false, "-1": false, "many": false} {
+		h := makeFetchProjectChangePoints(&data.MockConnector{}).(*projectChangePointsHandler)
+		r, err := http.NewRequest(http.MethodGet, "/projects/p/perf/change_points?limit="+limit, nil)
+		require.NoError(t, err)
+		err = h.Parse(context.Background(), r)
+		if ok {
+			assert.NoError(t, err, limit)
+		} else {
+			assert.Error(t, err, limit)
+		}
+	}
+}
+
+func TestPerfResultRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Now()
+	metrics := []perf.Metric{{Name: "ops_per_sec", Units: "ops/sec", Direction: perf.DirectionHigher, Value: 100}}
+	sc := &data.MockConnector{
+		MockPerfConnector: data.MockPerfConnector{
+			CachedResults: []perf.Result{
+				{ID: "r1", Test: "insert", Metrics: metrics, CreatedAt: now.Add(-2 * time.Hour),
+					Info: perf.ResultInfo{Project: "p", Variant: "linux", TaskName: "bench", TaskID: "t1", Revision: "aaa", Order: 1}},
+				{ID: "r2", Test: "insert", Metrics: metrics, CreatedAt: now.Add(-time.Hour),
+					Info: perf.ResultInfo{Project: "p", Variant: "linux", TaskName: "bench", TaskID: "t2", Revision: "bbb", Order: 2}},
+				{ID: "r3", Test: "update", Metrics: metrics, CreatedAt: now,
+					Info: perf.ResultInfo{Project: "p", Variant: "linux", TaskName: "bench", TaskID: "t3", Revision: "ccc", Order: 3}},
+				{ID: "r4", Test: "insert", Metrics: metrics, CreatedAt: now,
+					Info: perf.ResultInfo{Project: "p", Variant: "linux", TaskName: "bench", TaskID: "t4", Revision: "ccc", Order: 3, IsPatch: true}},
+				{ID: "r5", Test: "insert", Metrics: metrics, CreatedAt: now,
+					Info: perf.ResultInfo{Project: "p", Variant: "linux", TaskName: "bench", TaskID: "t3", Execution: 1, Revision: "ccc", Order: 3}},
+			},
+		},
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{{Id: "t3", Execution: 1}},
+		},
+	}
+	ctx := context.Background()
+
+	list := makeFetchProjectPerfResults(sc).(*projectPerfResultsHandler)
+	list.filter = perf.ResultsFilter{Project: "p"}
+	list.limit = defaultPerfResultLimit
+	resp := list.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	results, ok := resp.Data().([]interface{})
+	require.True(ok)
+	require.Len(results, 4, "patch results are excluded")
+	assert.Equal(1, results[0].(*model.APIPerfResult).Order, "oldest first")
+
+	list.filter.Test = "insert"
+	list.fromRevision = "bbb"
+	resp = list.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	results = resp.Data().([]interface{})
+	require.Len(results, 2)
+	assert.Equal("r2", model.FromAPIString(results[0].(*model.APIPerfResult).ID))
+
+	list.fromRevision = ""
+	list.filter.Start = now.Add(-90 * time.Minute)
+	list.filter.End = now.Add(-30 * time.Minute)
+	resp = list.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	require.Len(resp.Data(), 1)
+
+	list.toRevision = "unknown"
+	resp = list.Run(ctx)
+	assert.Equal(http.StatusNotFound, resp.Status())
+
+	byTask := makeFetchTaskPerfResults(sc).(*taskPerfResultsHandler)
+	byTask.taskID = "t3"
+	byTask.execution = -1
+	resp = byTask.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	require.Len(resp.Data(), 1)
+	result := resp.Data().([]interface{})[0].(*model.APIPerfResult)
+	assert.Equal("r5", model.FromAPIString(result.ID))
+	require.Len(result.Metrics, 1)
+	assert.Equal("ops/sec", model.FromAPIString(result.Metrics[0].Units))
+
+	byTask.execution = 0
+	resp = byTask.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	require.Len(resp.Data(), 1)
+	assert.Equal("update", model.FromAPIString(resp.Data().([]interface{})[0].(*model.APIPerfResult).Test))
+}
+
+func TestPerfResultParsing(t *testing.T) {
+	for query, ok := range map[string]bool{
+		"":                     true,
+		"test=insert&limit=10": true,
+		"start=2018-01-01T00:00:00Z&end=2018-02-01T00:00:00Z": true,
+		"start=yesterday": false,
+		"start=2018-02-01T00:00:00Z&end=2018-01-01T00:00:00Z": false,
+		"limit=0": false,
+	} {
+		h := makeFetchProjectPerfResults(&data.MockConnector{}).(*projectPerfResultsHandler)
+		r, err := http.NewRequest(http.MethodGet, "/projects/p/perf/results?"+query, nil)
+		require.NoError(t, err)
+		err = h.Parse(context.Background(), r)
+		if ok {
+			assert.NoError(t, err, query)
+		} else {
+			assert.Error(t, err, query)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/provenance
+
+// provenanceGetHandler returns the provenance document generated when a
+// version finished. The provenance of a private project's versions, which
+// includes their patch diffs, is only returned to the project's admins.
+type provenanceGetHandler struct {
+	versionId string
+	sc        data.Connector
+}
+
+func makeFetchVersionProvenance(sc data.Connector) gimlet.RouteHandler {
+	return &provenanceGetHandler{sc: sc}
+}
+
+func (h *provenanceGetHandler) Factory() gimlet.RouteHandler {
+	return &provenanceGetHandler{sc: h.sc}
+}
+
+func (h *provenanceGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionId = gimlet.GetVars(r)["version_id"]
+	if h.versionId == "" {
+		return gimlet.ErrorResponse{
+			Message:    "version_id must be specified",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+func (h *provenanceGetHandler) Run(ctx context.Context) gimlet.Responder {
+	doc, err := h.sc.FindProvenanceByVersionId(h.versionId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	project := doc.Predicate.BuildConfig.Project
+	ref, err := h.sc.FindProjectByBranch(project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if ref == nil || (ref.Private && !isProjectAdmin(ref, gimlet.GetUser(ctx), h.sc)) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("provenance for version '%s' not found", h.versionId),
+		})
+	}
+
+	out := &model.APIProvenance{}
+	if err = out.BuildFromService(doc); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(out)
+}
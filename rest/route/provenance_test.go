@@ -0,0 +1,74 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/provenance"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenanceGetHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockProvenanceConnector: data.MockProvenanceConnector{
+			CachedProvenance: []provenance.Provenance{
+				{
+					Id:            "v1",
+					Type:          provenance.StatementType,
+					PredicateType: provenance.PredicateType,
+					Subject: []provenance.Subject{
+						{Name: "https://example.com/binaries.tgz", Digest: map[string]string{"sha256": "abc"}},
+					},
+					Predicate: provenance.Predicate{
+						BuildConfig: provenance.BuildConfig{Project: "proj"},
+					},
+				},
+				{
+					Id: "private_v1",
+					Predicate: provenance.Predicate{
+						BuildConfig: provenance.BuildConfig{Project: "private"},
+					},
+				},
+			},
+		},
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*serviceModel.ProjectRef{
+				"proj":    {Identifier: "proj"},
+				"private": {Identifier: "private", Private: true, Admins: []string{"admin"}},
+			},
+		},
+	}
+	sc.SetSuperUsers([]string{"root"})
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "stranger"})
+
+	rh := makeFetchVersionProvenance(sc).(*provenanceGetHandler)
+	rh.versionId = "v1"
+	resp := rh.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	doc, ok := resp.Data().(*model.APIProvenance)
+	require.True(ok)
+	assert.Equal("v1", model.FromAPIString(doc.VersionId))
+	assert.Equal(provenance.StatementType, doc.Type)
+	require.Len(doc.Subject, 1)
+	assert.Equal("abc", doc.Subject[0].Digest["sha256"])
+
+	rh.versionId = "v2"
+	assert.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+
+	rh.versionId = "private_v1"
+	assert.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+	for _, name := range []string{"admin", "root"} {
+		resp = rh.Run(gimlet.AttachUser(context.Background(), &user.DBUser{Id: name}))
+		assert.Equal(http.StatusOK, resp.Status(), name)
+	}
+}
@@ -11,7 +11,7 @@ const defaultLimit = 100
 // AttachHandler attaches the api's request handlers to the given mux router.
 // It builds a Connector then attaches each of the main functions for
 // the api to the router.
-func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers []string, githubSecret []byte) {
+func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers []string, githubSecret, githubAppSecret []byte) {
 	sc := &data.DBConnector{}
 
 	sc.SetURL(URL)
@@ -22,12 +22,17 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	checkUser := gimlet.NewRequireAuthHandler()
 	addProject := NewProjectContextMiddleware(sc)
 
+	// every route records a span, as a wrapper so that it runs after
+	// routing and can record the route's variables
+	app.AddWrapper(NewTracingMiddleware())
+
 	// Routes
 	app.AddRoute("/").Version(2).Get().RouteHandler(makePlaceHolderManger(sc))
 	app.AddRoute("/admin").Version(2).Get().RouteHandler(makeLegacyAdminConfig(sc))
 	app.AddRoute("/admin/banner").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchAdminBanner(sc))
 	app.AddRoute("/admin/banner").Version(2).Post().Wrap(superUser).RouteHandler(makeSetAdminBanner(sc))
 	app.AddRoute("/admin/events").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchAdminEvents(sc))
+	app.AddRoute("/admin/host_health").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchHostHealth(sc))
 	app.AddRoute("/admin/restart").Version(2).Post().Wrap(superUser).RouteHandler(makeRestartRoute(sc, queue))
 	app.AddRoute("/admin/revert").Version(2).Post().Wrap(superUser).RouteHandler(makeRevertRouteManager(sc))
 	app.AddRoute("/admin/service_flags").Version(2).Post().Wrap(superUser).RouteHandler(makeSetServiceFlagsRouteManager(sc))
@@ -35,21 +40,31 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	app.AddRoute("/admin/settings").Version(2).Post().Wrap(superUser).RouteHandler(makeSetAdminSettings(sc))
 	app.AddRoute("/admin/task_queue").Version(2).Delete().Wrap(superUser).RouteHandler(makeClearTaskQueueHandler(sc))
 	app.AddRoute("/alias/{name}").Version(2).Get().RouteHandler(makeFetchAliases(sc))
+	app.AddRoute("/artifacts/signing_key").Version(2).Get().RouteHandler(makeFetchArtifactSigningKey(sc))
 	app.AddRoute("/builds/{build_id}").Version(2).Get().RouteHandler(makeGetBuildByID(sc))
 	app.AddRoute("/builds/{build_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangeStatusForBuild(sc))
 	app.AddRoute("/builds/{build_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortBuild(sc))
+	app.AddRoute("/builds/{build_id}/artifacts").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchArtifactsForBuild(sc))
 	app.AddRoute("/builds/{build_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartBuild(sc))
 	app.AddRoute("/builds/{build_id}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTasksByBuild(sc))
 	app.AddRoute("/cost/distro/{distro_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByDistroHandler(sc))
 	app.AddRoute("/cost/project/{project_id}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeTaskCostByProjectRoute(sc))
 	app.AddRoute("/cost/version/{version_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByVersionHandler(sc))
+	app.AddRoute("/debug/{session_id}").Version(2).Delete().Wrap(checkUser).RouteHandler(makeCloseDebugSession(sc))
+	app.AddRoute("/debug/{session_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchDebugSession(sc))
+	app.AddRoute("/debug/{session_id}/input").Version(2).Post().Wrap(checkUser).RouteHandler(makeSendDebugInput(sc))
 	app.AddRoute("/distros").Version(2).Get().Wrap(checkUser).RouteHandler(makeDistroRoute(sc))
+	app.AddRoute("/distros/{distro_id}/fair_share").Version(2).Get().Wrap(checkUser).RouteHandler(makeDistroFairShareRoute(sc))
 	app.AddRoute("/hooks/github").Version(2).Post().RouteHandler(makeGithubHooksRoute(sc, queue, githubSecret))
+	app.AddRoute("/hooks/github/app").Version(2).Post().RouteHandler(makeGithubAppHooksRoute(sc, githubAppSecret))
 	app.AddRoute("/hosts").Version(2).Get().RouteHandler(makeFetchHosts(sc))
 	app.AddRoute("/hosts").Version(2).Post().Wrap(checkUser).RouteHandler(makeSpawnHostCreateRoute(sc))
 	app.AddRoute("/hosts/{host_id}").Version(2).Get().RouteHandler(makeGetHostByID(sc))
 	app.AddRoute("/hosts/{host_id}/change_password").Version(2).Post().Wrap(checkUser).RouteHandler(makeHostChangePassword(sc))
 	app.AddRoute("/hosts/{host_id}/extend_expiration").Version(2).Post().Wrap(checkUser).RouteHandler(makeExtendHostExpiration(sc))
+	app.AddRoute("/hosts/{host_id}/health").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchHostHealthByID(sc))
+	app.AddRoute("/hosts/{host_id}/quarantine").Version(2).Post().Wrap(superUser).RouteHandler(makeQuarantineHost(sc))
+	app.AddRoute("/hosts/{host_id}/restore").Version(2).Post().Wrap(superUser).RouteHandler(makeRestoreHost(sc))
 	app.AddRoute("/hosts/{host_id}/terminate").Version(2).Post().Wrap(checkUser).RouteHandler(makeTerminateHostRoute(sc))
 	app.AddRoute("/hosts/{task_id}/create").Version(2).Post().RouteHandler(makeHostCreateRouteManager(sc))
 	app.AddRoute("/hosts/{task_id}/list").Version(2).Get().RouteHandler(makeHostListRouteManager(sc))
@@ -61,7 +76,10 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	app.AddRoute("/patches/{patch_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortPatch(sc))
 	app.AddRoute("/patches/{patch_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartPatch(sc))
 	app.AddRoute("/projects").Version(2).Get().RouteHandler(makeFetchProjectsRoute(sc))
+	app.AddRoute("/projects/{project_id}/build_cache/stats").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchBuildCacheStats(sc))
 	app.AddRoute("/projects/{project_id}/patches").Version(2).Get().Wrap(checkUser).RouteHandler(makePatchesByProjectRoute(sc))
+	app.AddRoute("/projects/{project_id}/perf/change_points").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchProjectChangePoints(sc))
+	app.AddRoute("/projects/{project_id}/perf/results").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchProjectPerfResults(sc))
 	app.AddRoute("/projects/{project_id}/recent_versions").Version(2).Get().RouteHandler(makeFetchProjectVersions(sc))
 	app.AddRoute("/projects/{project_id}/revisions/{commit_hash}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeTasksByProjectAndCommitHandler(sc))
 	app.AddRoute("/status/cli_version").Version(2).Get().RouteHandler(makeFetchCLIVersionRoute(sc))
@@ -74,10 +92,16 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	app.AddRoute("/tasks/{task_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeGetTaskRoute(sc))
 	app.AddRoute("/tasks/{task_id}").Version(2).Patch().Wrap(checkUser, addProject).RouteHandler(makeModifyTaskRoute(sc))
 	app.AddRoute("/tasks/{task_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeTaskAbortHandler(sc))
+	app.AddRoute("/tasks/{task_id}/artifacts").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchArtifactsForTask(sc))
+	app.AddRoute("/tasks/{task_id}/artifacts/manifest").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchArtifactManifest(sc))
+	app.AddRoute("/tasks/{task_id}/debug").Version(2).Post().Wrap(checkUser).RouteHandler(makeCreateDebugSession(sc))
 	app.AddRoute("/tasks/{task_id}/generate").Version(2).Post().RouteHandler(makeGenerateTasksHandler(sc))
+	app.AddRoute("/tasks/{task_id}/logs/stream").Version(2).Get().Wrap(checkUser).Handler(makeTaskLogStream(sc))
 	app.AddRoute("/tasks/{task_id}/metrics/process").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskProcessMetrics(sc))
 	app.AddRoute("/tasks/{task_id}/metrics/system").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskSystmMetrics(sc))
+	app.AddRoute("/tasks/{task_id}/perf/results").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskPerfResults(sc))
 	app.AddRoute("/tasks/{task_id}/restart").Version(2).Post().Wrap(addProject, checkUser).RouteHandler(makeTaskRestartHandler(sc))
+	app.AddRoute("/tasks/{task_id}/resume").Version(2).Post().Wrap(checkUser).RouteHandler(makeTaskResumeHandler(sc))
 	app.AddRoute("/tasks/{task_id}/tests").Version(2).Get().Wrap(addProject).RouteHandler(makeFetchTestsForTask(sc))
 	app.AddRoute("/user/settings").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchUserConfig())
 	app.AddRoute("/user/settings").Version(2).Post().Wrap(checkUser).RouteHandler(makeSetUserConfig(sc))
@@ -85,6 +109,9 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	app.AddRoute("/users/{user_id}/patches").Version(2).Get().Wrap(checkUser).RouteHandler(makeUserPatchHandler(sc))
 	app.AddRoute("/versions/{version_id}").Version(2).Get().RouteHandler(makeGetVersionByID(sc))
 	app.AddRoute("/versions/{version_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortVersion(sc))
+	app.AddRoute("/versions/{version_id}/artifacts").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchArtifactsForVersion(sc))
+	app.AddRoute("/versions/{version_id}/perf/change_points").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchVersionChangePoints(sc))
+	app.AddRoute("/versions/{version_id}/provenance").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchVersionProvenance(sc))
 	app.AddRoute("/versions/{version_id}/builds").Version(2).Get().RouteHandler(makeGetVersionBuilds(sc))
 	app.AddRoute("/versions/{version_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartVersion(sc))
 }
@@ -0,0 +1,239 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	taskLogStreamKeepAlive = 15 * time.Second
+	// taskLogStreamMaxDuration must be less than the server's write
+	// timeout, which a handler can't extend, so that a followed stream
+	// asks the client to reconnect before the server closes it.
+	taskLogStreamMaxDuration = 45 * time.Second
+
+	lastEventIdHeader = "Last-Event-ID"
+)
+
+// legacyLogTypes maps the log channel prefixes onto the types written by
+// older agents.
+var legacyLogTypes = map[string]string{
+	apimodels.TaskLogPrefix:   "task",
+	apimodels.SystemLogPrefix: "system",
+	apimodels.AgentLogPrefix:  "agent",
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/logs/stream
+
+// makeTaskLogStream returns a handler that tails a task's logs as
+// server-sent events. Each log line is sent as a "log" event whose id is the
+// line's number in the execution's log, counting lines of every type, so
+// clients can resume with the Last-Event-ID header or the offset parameter
+// without the lines before it being read again. An "end" event is sent once
+// the task has finished and every line has been sent. The execution
+// defaults to the latest, type limits the stream to one log channel (T, S
+// or E), offset is the first line number to send, and follow=false sends the
+// lines written so far and stops. A followed stream sends a "reconnect"
+// event before the server's write timeout closes it, and the client resumes
+// from the last line it received.
+func makeTaskLogStream(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskId := gimlet.GetVars(r)["task_id"]
+		t, err := sc.FindTaskById(taskId)
+		if err != nil {
+			if errResp, ok := errors.Cause(err).(gimlet.ErrorResponse); ok {
+				gimlet.WriteJSONResponse(w, errResp.StatusCode, errResp)
+				return
+			}
+			writeStreamError(w, http.StatusInternalServerError, errors.Wrapf(err, "problem finding task '%s'", taskId).Error())
+			return
+		}
+		if t == nil {
+			writeStreamError(w, http.StatusNotFound, fmt.Sprintf("task '%s' not found", taskId))
+			return
+		}
+
+		vals := r.URL.Query()
+		execution := t.Execution
+		if exec := vals.Get("execution"); exec != "" {
+			execution, err = strconv.Atoi(exec)
+			if err != nil || execution < 0 {
+				writeStreamError(w, http.StatusBadRequest, fmt.Sprintf("invalid execution '%s'", exec))
+				return
+			}
+		}
+
+		offset := 0
+		if off := vals.Get("offset"); off != "" {
+			offset, err = strconv.Atoi(off)
+			if err != nil || offset < 0 {
+				writeStreamError(w, http.StatusBadRequest, fmt.Sprintf("invalid offset '%s'", off))
+				return
+			}
+		}
+		if last := r.Header.Get(lastEventIdHeader); last != "" {
+			var lastId int
+			lastId, err = strconv.Atoi(last)
+			if err == nil && lastId >= 0 {
+				offset = lastId + 1
+			}
+		}
+
+		logType := vals.Get("type")
+		if logType != "" {
+			if _, ok := legacyLogTypes[logType]; !ok {
+				writeStreamError(w, http.StatusBadRequest, fmt.Sprintf("invalid log type '%s'", logType))
+				return
+			}
+		}
+		follow := vals.Get("follow") != "false"
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeStreamError(w, http.StatusInternalServerError, "streaming is not supported by this connection")
+			return
+		}
+
+		sub, err := sc.SubscribeTaskLogs(taskId, execution, offset)
+		if err != nil {
+			writeStreamError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		stream := &taskLogEventWriter{
+			w:       w,
+			logType: logType,
+			line:    offset,
+		}
+		ctx := r.Context()
+		if follow {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, taskLogStreamMaxDuration)
+			defer cancel()
+		}
+		err = stream.run(ctx, sub, follow)
+		switch {
+		case r.Context().Err() != nil:
+			// the client went away
+		case errors.Cause(err) == context.DeadlineExceeded:
+			grip.Warning(stream.writeEvent("reconnect", -1, struct{}{}))
+		case err != nil:
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":   "task log stream ended with an error",
+				"task_id":   taskId,
+				"execution": execution,
+			}))
+			grip.Warning(stream.writeEvent("error", -1, map[string]string{"error": err.Error()}))
+		}
+		flusher.Flush()
+	}
+}
+
+func writeStreamError(w http.ResponseWriter, code int, msg string) {
+	gimlet.WriteJSONResponse(w, code, gimlet.ErrorResponse{
+		StatusCode: code,
+		Message:    msg,
+	})
+}
+
+// taskLogEventWriter writes the lines read from a subscription as events.
+// line is the number of the next line the subscription returns.
+type taskLogEventWriter struct {
+	w       http.ResponseWriter
+	logType string
+	line    int
+}
+
+func (s *taskLogEventWriter) run(ctx context.Context, sub *data.TaskLogSubscription, follow bool) error {
+	flusher := s.w.(http.Flusher)
+
+	if !follow {
+		lines, _, err := sub.Backlog()
+		if err != nil {
+			return err
+		}
+		if err = s.writeLines(lines); err != nil {
+			return err
+		}
+		return s.writeEvent("end", -1, struct{}{})
+	}
+
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, taskLogStreamKeepAlive)
+		lines, done, err := sub.Next(waitCtx)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Cause(err) == context.DeadlineExceeded:
+			if _, err = fmt.Fprint(s.w, ": keepalive\n\n"); err != nil {
+				return errors.WithStack(err)
+			}
+		case err != nil:
+			return err
+		case done:
+			return s.writeEvent("end", -1, struct{}{})
+		default:
+			if err = s.writeLines(lines); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func (s *taskLogEventWriter) writeLines(lines []apimodels.LogMessage) error {
+	for _, l := range lines {
+		line := s.line
+		s.line++
+		if s.logType != "" && l.Type != s.logType && l.Type != legacyLogTypes[s.logType] {
+			continue
+		}
+
+		if err := s.writeEvent("log", line, l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeEvent writes a single server-sent event. Events without a line number
+// are written without an id so they do not move the client's position.
+func (s *taskLogEventWriter) writeEvent(event string, id int, payload interface{}) error {
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "problem marshalling event")
+	}
+
+	buf := &bytes.Buffer{}
+	if id >= 0 {
+		fmt.Fprintf(buf, "id: %d\n", id)
+	}
+	fmt.Fprintf(buf, "event: %s\ndata: %s\n\n", event, out)
+
+	_, err = s.w.Write(buf.Bytes())
+	return errors.WithStack(err)
+}
@@ -0,0 +1,94 @@
+package route
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestTaskLogStream(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{{Id: "t1", Status: evergreen.TaskSucceeded}},
+		},
+		MockTaskLogConnector: data.MockTaskLogConnector{
+			Logs: map[string][]model.TaskLog{
+				"t1": {
+					{Id: bson.NewObjectId(), Messages: []apimodels.LogMessage{
+						{Type: apimodels.TaskLogPrefix, Message: "t-zero"},
+						{Type: apimodels.AgentLogPrefix, Message: "e-zero"},
+					}},
+					{Id: bson.NewObjectId(), Messages: []apimodels.LogMessage{
+						{Type: "task", Message: "t-one"},
+						{Type: apimodels.TaskLogPrefix, Message: "t-two"},
+					}},
+				},
+			},
+			Finished: map[string]bool{"t1": true},
+		},
+	}
+
+	app := gimlet.NewApp()
+	app.AddRoute("/tasks/{task_id}/logs/stream").Version(2).Get().Handler(makeTaskLogStream(sc))
+	require.NoError(app.Resolve())
+	router, err := app.Router()
+	require.NoError(err)
+
+	stream := func(taskId, query, lastEventId string) (int, string) {
+		r, err := http.NewRequest(http.MethodGet, "/v2/tasks/"+taskId+"/logs/stream?"+query, nil)
+		require.NoError(err)
+		if lastEventId != "" {
+			r.Header.Set(lastEventIdHeader, lastEventId)
+		}
+
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, r)
+		body, err := ioutil.ReadAll(rw.Body)
+		require.NoError(err)
+		return rw.Code, string(body)
+	}
+
+	code, body := stream("t1", "", "")
+	require.Equal(http.StatusOK, code)
+	assert.Equal(4, strings.Count(body, "event: log\n"))
+	assert.Contains(body, "id: 0\nevent: log\ndata: {\"t\":\"T\"")
+	assert.True(strings.HasSuffix(body, "event: end\ndata: {}\n\n"))
+
+	// line numbers count lines of every type
+	code, body = stream("t1", "type=T&offset=1", "")
+	require.Equal(http.StatusOK, code)
+	assert.NotContains(body, "t-zero")
+	assert.NotContains(body, "e-zero")
+	assert.Contains(body, "id: 2\nevent: log")
+	assert.Contains(body, "t-one")
+	assert.Contains(body, "id: 3\nevent: log")
+	assert.Contains(body, "t-two")
+
+	code, body = stream("t1", "follow=false&type=T", "2")
+	require.Equal(http.StatusOK, code)
+	assert.Equal(1, strings.Count(body, "event: log\n"))
+	assert.Contains(body, "t-two")
+	assert.Contains(body, "event: end")
+
+	code, _ = stream("t1", "type=X", "")
+	assert.Equal(http.StatusBadRequest, code)
+	code, _ = stream("t1", "offset=-1", "")
+	assert.Equal(http.StatusBadRequest, code)
+	code, _ = stream("missing", "", "")
+	assert.Equal(http.StatusNotFound, code)
+}
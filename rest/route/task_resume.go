@@ -0,0 +1,90 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/tasks/{task_id}/resume
+
+// taskResumeHandler ends the pause of a task that is held for inspection
+// after a failed command. The agent then runs the task's post commands and
+// finishes it. A paused task can also be aborted. The admins of the task's
+// project may resume it, as may the author of a patch task's patch.
+type taskResumeHandler struct {
+	taskId string
+	sc     data.Connector
+}
+
+func makeTaskResumeHandler(sc data.Connector) gimlet.RouteHandler {
+	return &taskResumeHandler{
+		sc: sc,
+	}
+}
+
+func (t *taskResumeHandler) Factory() gimlet.RouteHandler {
+	return &taskResumeHandler{
+		sc: t.sc,
+	}
+}
+
+func (t *taskResumeHandler) Parse(ctx context.Context, r *http.Request) error {
+	t.taskId = gimlet.GetVars(r)["task_id"]
+	if t.taskId == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a task",
+		}
+	}
+	return nil
+}
+
+func (t *taskResumeHandler) Run(ctx context.Context) gimlet.Responder {
+	foundTask, err := t.sc.FindTaskById(t.taskId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	u := MustHaveUser(ctx)
+	ref, err := t.sc.FindProjectByBranch(foundTask.Project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	allowed := ref != nil && isProjectAdmin(ref, u, t.sc)
+	if !allowed && evergreen.IsPatchRequester(foundTask.Requester) && bson.IsObjectIdHex(foundTask.Version) {
+		p, err := t.sc.FindPatchById(foundTask.Version)
+		if err != nil {
+			if apiErr, ok := err.(gimlet.ErrorResponse); !ok || apiErr.StatusCode != http.StatusNotFound {
+				return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+			}
+		}
+		allowed = p != nil && p.Author == u.Id
+	}
+	if !allowed {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("only admins of project '%s' or the patch's author may resume the task", foundTask.Project),
+		})
+	}
+
+	if err = t.sc.ResumeTask(foundTask, u.Id); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Resume error"))
+	}
+
+	taskModel := &model.APITask{}
+	if err = taskModel.BuildFromService(foundTask); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(taskModel)
+}
@@ -0,0 +1,75 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestTaskResumeHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	patchId := bson.NewObjectId()
+	sc := &data.MockConnector{
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{
+				{Id: "paused", Project: "proj", Status: evergreen.TaskPaused, PausedUntil: time.Now().Add(time.Hour)},
+				{Id: "running", Project: "proj", Status: evergreen.TaskStarted},
+				{Id: "patch", Project: "proj", Status: evergreen.TaskPaused, PausedUntil: time.Now().Add(time.Hour),
+					Requester: evergreen.PatchVersionRequester, Version: patchId.Hex()},
+			},
+		},
+		MockPatchConnector: data.MockPatchConnector{
+			CachedPatches: []patch.Patch{{Id: patchId, Author: "author"}},
+		},
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*serviceModel.ProjectRef{
+				"proj": {Identifier: "proj", Admins: []string{"admin"}},
+			},
+		},
+	}
+	sc.SetSuperUsers([]string{"root"})
+	admin := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+	stranger := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "stranger"})
+	author := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "author"})
+
+	resume := func(ctx context.Context, taskID string) gimlet.Responder {
+		h := makeTaskResumeHandler(sc).Factory().(*taskResumeHandler)
+		h.taskId = taskID
+		return h.Run(ctx)
+	}
+
+	assert.Equal(http.StatusUnauthorized, resume(stranger, "paused").Status())
+
+	resp := resume(admin, "paused")
+	require.Equal(http.StatusOK, resp.Status())
+	apiTask := resp.Data().(*model.APITask)
+	assert.Equal(evergreen.TaskStarted, model.FromAPIString(apiTask.Status))
+
+	found, err := sc.FindTaskById("paused")
+	require.NoError(err)
+	assert.Equal(evergreen.TaskStarted, found.Status)
+	assert.True(found.PausedUntil.IsZero())
+
+	assert.Equal(http.StatusBadRequest, resume(admin, "paused").Status(), "a task is only resumed once")
+	assert.Equal(http.StatusBadRequest, resume(admin, "running").Status())
+
+	// the patch's author may resume its tasks, but not the project's others
+	assert.Equal(http.StatusUnauthorized, resume(stranger, "patch").Status())
+	assert.Equal(http.StatusOK, resume(author, "patch").Status())
+	assert.Equal(http.StatusUnauthorized, resume(author, "running").Status())
+}
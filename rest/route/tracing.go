@@ -0,0 +1,84 @@
+package route
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+)
+
+const traceParentHeader = "traceparent"
+
+type tracingMiddleware struct{}
+
+// NewTracingMiddleware returns a wrapper that records a span for each
+// request, continuing the caller's trace if the request has a traceparent
+// header. It must be added as a wrapper, rather than as middleware, so
+// that it runs after routing and the route's variables are set.
+func NewTracingMiddleware() gimlet.Middleware {
+	return &tracingMiddleware{}
+}
+
+// routeTemplate returns the request's path with the route's variables in
+// place of their values, so that requests to the same route share a route.
+func routeTemplate(path string, vars map[string]string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		for k, v := range vars {
+			if v != "" && segment == v {
+				segments[i] = "{" + k + "}"
+				break
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streaming handlers flush through the recorder.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (m *tracingMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	vars := gimlet.GetVars(r)
+	ctx, span := util.GetTracer().StartFromTraceParent(r.Context(), r.Header.Get(traceParentHeader),
+		"HTTP "+r.Method, map[string]string{
+			"http.method": r.Method,
+			"http.route":  routeTemplate(r.URL.Path, vars),
+			"http.target": r.URL.Path,
+		})
+	for k, v := range vars {
+		span.SetAttribute("http.route."+k, v)
+	}
+
+	recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	next(recorder, r.WithContext(ctx))
+
+	span.SetAttribute("http.status_code", strconv.Itoa(recorder.status))
+	if user := gimlet.GetUser(ctx); user != nil {
+		span.SetAttribute("user", user.Username())
+	}
+	var err error
+	if recorder.status >= http.StatusInternalServerError {
+		err = httpStatusError(recorder.status)
+	}
+	span.End(err)
+}
+
+type httpStatusError int
+
+func (e httpStatusError) Error() string { return http.StatusText(int(e)) }
@@ -0,0 +1,84 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	exporter := &util.InMemorySpanExporter{}
+	tracer := util.NewTracer(exporter)
+	defer util.SetTracer(util.SetTracer(tracer))
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	m := NewTracingMiddleware()
+
+	var handlerSpan *util.Span
+	req := httptest.NewRequest(http.MethodGet, "/rest/v2/hosts", nil)
+	req.Header.Set("traceparent", parent)
+	rw := httptest.NewRecorder()
+	m.ServeHTTP(rw, req, func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = util.SpanFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	require.NotNil(handlerSpan)
+	assert.Equal(http.StatusInternalServerError, rw.Code)
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/rest/v2/hosts", nil),
+		func(w http.ResponseWriter, r *http.Request) {})
+
+	require.NoError(tracer.Close(req.Context()))
+	spans := exporter.Spans()
+	require.Len(spans, 2)
+
+	assert.Equal("HTTP GET", spans[0].Name)
+	assert.Equal(handlerSpan.Context().SpanID, spans[0].SpanID)
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceID)
+	assert.Equal("00f067aa0ba902b7", spans[0].ParentSpanID)
+	assert.Equal("/rest/v2/hosts", spans[0].Attributes["http.target"])
+	assert.Equal("/rest/v2/hosts", spans[0].Attributes["http.route"])
+	assert.Equal("500", spans[0].Attributes["http.status_code"])
+	assert.NotEmpty(spans[0].Error)
+
+	assert.Equal("HTTP POST", spans[1].Name)
+	assert.Empty(spans[1].ParentSpanID)
+	assert.Equal("200", spans[1].Attributes["http.status_code"])
+	assert.Empty(spans[1].Error)
+}
+
+func TestTracingMiddlewareRecordsRouteVariables(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	exporter := &util.InMemorySpanExporter{}
+	tracer := util.NewTracer(exporter)
+	defer util.SetTracer(util.SetTracer(tracer))
+
+	app := gimlet.NewApp()
+	app.NoVersions = true
+	app.AddWrapper(NewTracingMiddleware())
+	app.AddRoute("/hosts/{host_id}").Get().Handler(
+		func(w http.ResponseWriter, r *http.Request) {})
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/hosts/h1", nil))
+	assert.Equal(http.StatusOK, rw.Code)
+
+	require.NoError(tracer.Close(context.Background()))
+	spans := exporter.Spans()
+	require.Len(spans, 1)
+	assert.Equal("/hosts/{host_id}", spans[0].Attributes["http.route"])
+	assert.Equal("h1", spans[0].Attributes["http.route.host_id"])
+}
@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+)
+
+// containerPlan is the result of packing new containers onto the parents of
+// a container pool.
+type containerPlan struct {
+	// placements are the resources of the containers placed on each
+	// running parent
+	placements []containerPlacement
+	// numNewParents is the number of parents to start for the containers
+	// that don't fit on running or starting parents
+	numNewParents int
+	// numUnplaceable is the number of containers that request more
+	// resources than any parent has
+	numUnplaceable int
+}
+
+type containerPlacement struct {
+	parentID  string
+	resources evergreen.ContainerResources
+}
+
+// parentBin tracks what a parent has left while containers are packed
+// onto it.
+type parentBin struct {
+	parentID      string
+	numContainers int
+	used          evergreen.ContainerResources
+}
+
+func (b *parentBin) fits(pool *evergreen.ContainerPool, r evergreen.ContainerResources) bool {
+	return b.numContainers < pool.MaxContainers && b.used.Add(r).FitsIn(pool.ParentResources)
+}
+
+// containerRequests returns the resources that the containers for the next
+// newHostsNeeded tasks in the queue request. The first tasks in the queue
+// are assumed to run on the distro's free hosts.
+func containerRequests(queue []model.TaskQueueItem, numFreeHosts, newHostsNeeded int) []evergreen.ContainerResources {
+	if newHostsNeeded <= 0 {
+		return nil
+	}
+	requests := make([]evergreen.ContainerResources, newHostsNeeded)
+	for i := range requests {
+		idx := numFreeHosts + i
+		if idx < len(queue) && queue[idx].ResourceRequests != nil {
+			requests[i] = *queue[idx].ResourceRequests
+		}
+	}
+	return requests
+}
+
+// countFreeHosts returns the number of hosts that are not running a task.
+func countFreeHosts(hosts []host.Host) int {
+	numFree := 0
+	for _, h := range hosts {
+		if h.RunningTask == "" {
+			numFree++
+		}
+	}
+	return numFree
+}
+
+// packContainers places containers onto parents first-fit decreasing by the
+// resources they request, so that large containers are placed while there
+// is still room for them. Parents are tried in the order given, which is
+// longest expected finish time first. Containers that don't fit on a
+// running parent are packed onto the parents that are starting, and then
+// onto new parents.
+func packContainers(pool *evergreen.ContainerPool, parents []containersOnParents, numStartingParents int, requests []evergreen.ContainerResources) containerPlan {
+	plan := containerPlan{}
+
+	sorted := make([]evergreen.ContainerResources, len(requests))
+	copy(sorted, requests)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return resourceShare(sorted[i], pool.ParentResources) > resourceShare(sorted[j], pool.ParentResources)
+	})
+
+	bins := make([]parentBin, 0, len(parents)+numStartingParents)
+	for _, p := range parents {
+		bins = append(bins, parentBin{
+			parentID:      p.parentHost.Id,
+			numContainers: p.numContainers,
+			used:          p.usedResources,
+		})
+	}
+	for i := 0; i < numStartingParents; i++ {
+		bins = append(bins, parentBin{})
+	}
+	numExistingBins := len(bins)
+
+	for _, r := range sorted {
+		if !r.FitsIn(pool.ParentResources) {
+			plan.numUnplaceable++
+			continue
+		}
+
+		placed := false
+		for i := range bins {
+			if bins[i].fits(pool, r) {
+				bins[i].numContainers++
+				bins[i].used = bins[i].used.Add(r)
+				if bins[i].parentID != "" {
+					plan.placements = append(plan.placements, containerPlacement{parentID: bins[i].parentID, resources: r})
+				}
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			bins = append(bins, parentBin{numContainers: 1, used: r})
+		}
+	}
+	plan.numNewParents = len(bins) - numExistingBins
+
+	return plan
+}
+
+// resourceShare returns the larger of the fractions of a parent's CPUs and
+// memory that a container requests.
+func resourceShare(r, capacity evergreen.ContainerResources) float64 {
+	var share float64
+	if capacity.CPUs > 0 {
+		share = r.CPUs / capacity.CPUs
+	}
+	if capacity.MemoryMB > 0 {
+		if memShare := float64(r.MemoryMB) / float64(capacity.MemoryMB); memShare > share {
+			share = memShare
+		}
+	}
+	return share
+}
+
+// intents returns the intent documents for the containers placed on running
+// parents.
+func (p containerPlan) intents(d distro.Distro) []host.Host {
+	intents := make([]host.Host, 0, len(p.placements))
+	for _, placement := range p.placements {
+		intents = append(intents, newContainerIntent(d, placement.parentID, placement.resources))
+	}
+	return intents
+}
+
+// newContainerIntent creates an intent document for a container on a
+// parent, limited to the resources it requests.
+func newContainerIntent(d distro.Distro, parentID string, resources evergreen.ContainerResources) host.Host {
+	hostOptions := cloud.HostOptions{
+		ParentID: parentID,
+		UserName: evergreen.User,
+	}
+	if !resources.IsZero() {
+		hostOptions.ContainerResources = &resources
+	}
+	return *cloud.NewIntent(d, d.GenerateName(), d.Provider, hostOptions)
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := []model.TaskQueueItem{
+		{Id: "t1", ResourceRequests: &evergreen.ContainerResources{CPUs: 1}},
+		{Id: "t2", ResourceRequests: &evergreen.ContainerResources{CPUs: 2}},
+		{Id: "t3"},
+	}
+	assert.Nil(containerRequests(queue, 0, 0))
+	assert.Equal([]evergreen.ContainerResources{{CPUs: 2}, {}, {}}, containerRequests(queue, 1, 3))
+	assert.Equal([]evergreen.ContainerResources{{CPUs: 1}}, containerRequests(queue, 0, 1))
+
+	hosts := []host.Host{{Id: "h1", RunningTask: "t0"}, {Id: "h2"}}
+	assert.Equal([]evergreen.ContainerResources{{CPUs: 2}, {}}, containerRequests(queue, countFreeHosts(hosts), 2))
+}
+
+func TestPackContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := &evergreen.ContainerPool{
+		Id:              "pool",
+		MaxContainers:   4,
+		ParentResources: evergreen.ContainerResources{CPUs: 8, MemoryMB: 8192},
+	}
+	parents := []containersOnParents{
+		{
+			parentHost:    host.Host{Id: "busy"},
+			numContainers: 2,
+			usedResources: evergreen.ContainerResources{CPUs: 6, MemoryMB: 2048},
+		},
+		{
+			parentHost:    host.Host{Id: "idle"},
+			numContainers: 0,
+		},
+	}
+
+	// the large container goes on the idle parent and the small one fills
+	// the busy parent
+	plan := packContainers(pool, parents, 0, []evergreen.ContainerResources{
+		{CPUs: 2, MemoryMB: 1024},
+		{CPUs: 6, MemoryMB: 4096},
+	})
+	assert.Equal(0, plan.numNewParents)
+	assert.Equal(0, plan.numUnplaceable)
+	assert.Equal([]containerPlacement{
+		{parentID: "idle", resources: evergreen.ContainerResources{CPUs: 6, MemoryMB: 4096}},
+		{parentID: "busy", resources: evergreen.ContainerResources{CPUs: 2, MemoryMB: 1024}},
+	}, plan.placements)
+
+	// containers that don't fit need new parents, after starting parents
+	// are filled, and containers larger than a parent are never placed
+	plan = packContainers(pool, parents, 1, []evergreen.ContainerResources{
+		{CPUs: 8}, {CPUs: 8}, {CPUs: 8}, {MemoryMB: 8193},
+	})
+	assert.Equal(1, plan.numNewParents)
+	assert.Equal(1, plan.numUnplaceable)
+	assert.Len(plan.placements, 1)
+
+	// parents without resources left fit no more than max containers
+	plan = packContainers(pool, nil, 0, make([]evergreen.ContainerResources, 9))
+	assert.Equal(3, plan.numNewParents)
+	assert.Empty(plan.placements)
+}
+
+func TestContainerPlanIntents(t *testing.T) {
+	assert := assert.New(t)
+
+	d := distro.Distro{Id: "d", Provider: evergreen.ProviderNameDocker, ContainerPool: "pool"}
+	plan := containerPlan{
+		placements: []containerPlacement{
+			{parentID: "p1", resources: evergreen.ContainerResources{CPUs: 2}},
+			{parentID: "p2"},
+		},
+	}
+	intents := plan.intents(d)
+	assert.Len(intents, 2)
+	assert.Equal("p1", intents[0].ParentID)
+	assert.Equal(&evergreen.ContainerResources{CPUs: 2}, intents[0].ContainerResources)
+	assert.Equal("p2", intents[1].ParentID)
+	assert.Nil(intents[1].ContainerResources)
+	assert.NotNil(containerPlan{}.intents(d))
+}
@@ -31,7 +31,7 @@ func deficitNumNewHostsForDistro(ctx context.Context, hostAllocatorData *HostAll
 
 	numNewHosts := util.Min(
 		// the deficit of available hosts vs. tasks to be run
-		len(hostAllocatorData.taskQueueItems)-len(freeHosts),
+		queueDemand(hostAllocatorData.taskQueueItems)-len(freeHosts),
 		// the maximum number of new hosts we're allowed to spin up
 		distro.PoolSize-len(hostAllocatorData.existingHosts),
 	)
@@ -75,12 +75,6 @@ type ScheduledDistroTasksData struct {
 
 	// all tasks that have been previously accounted for in other distros
 	tasksAccountedFor map[string]bool
-
-	// all distros this task could run on
-	taskRunDistros []string
-
-	// the name of the distro whose task queue items data this represents
-	currentDistroId string
 }
 
 // NewHostsNeeded decides if new hosts are needed for a
@@ -125,20 +119,18 @@ func computeScheduledTasksDuration(scheduledDistroTasksData *ScheduledDistroTask
 	tasksAccountedFor := scheduledDistroTasksData.tasksAccountedFor
 	sharedTasksDuration = make(map[string]float64)
 
-	// compute the total expected duration for tasks in this queue
+	// compute the total expected duration for tasks in this queue, counting
+	// only this distro's share of tasks that are queued on several distros
 	for _, taskQueueItem := range taskQueueItems {
 		if !tasksAccountedFor[taskQueueItem.Id] {
-			scheduledTasksDuration += taskQueueItem.ExpectedDuration.Seconds()
+			scheduledTasksDuration += taskQueueItem.ExpectedDuration.Seconds() * taskQueueItem.DemandShare()
 			tasksAccountedFor[taskQueueItem.Id] = true
 		}
 
-		// if the task can be run on multiple distros - including this one - add
-		// it to the total duration of 'shared tasks' for the distro and all
-		// other distros it can be run on
-		if util.StringSliceContains(scheduledDistroTasksData.taskRunDistros, scheduledDistroTasksData.currentDistroId) {
-			for _, distroId := range scheduledDistroTasksData.taskRunDistros {
-				sharedTasksDuration[distroId] += taskQueueItem.ExpectedDuration.Seconds()
-			}
+		// if the task can be run on multiple distros, add it to the total
+		// duration of 'shared tasks' for all of the distros it can be run on
+		for _, distroId := range taskQueueItem.Distros {
+			sharedTasksDuration[distroId] += taskQueueItem.ExpectedDuration.Seconds()
 		}
 	}
 	return
@@ -146,7 +138,7 @@ func computeScheduledTasksDuration(scheduledDistroTasksData *ScheduledDistroTask
 
 // computeRunningTasksDuration returns the estimated time to completion of all
 // currently running tasks for a given distro given its hosts
-func computeRunningTasksDuration(existingDistroHosts []host.Host) (runningTasksDuration float64, err error) {
+func computeRunningTasksDuration(existingDistroHosts []host.Host, now time.Time) (runningTasksDuration float64, err error) {
 	runningTaskIds := []string{}
 
 	for _, existingDistroHost := range existingDistroHosts {
@@ -180,7 +172,7 @@ func computeRunningTasksDuration(existingDistroHosts []host.Host) (runningTasksD
 				"Unable to find running task with _id %v", runningTaskId)
 		}
 		expectedDuration := runningTask.FetchExpectedDuration()
-		elapsedTime := time.Since(runningTask.StartTime)
+		elapsedTime := now.Sub(runningTask.StartTime)
 		if elapsedTime > expectedDuration {
 			// probably an outlier; or an unknown data point
 			continue
@@ -378,7 +370,6 @@ func durationNumNewHostsForDistro(ctx context.Context, hostAllocatorData *HostAl
 
 	existingDistroHosts := hostAllocatorData.existingHosts
 	taskQueueItems := hostAllocatorData.taskQueueItems
-	taskRunDistros := hostAllocatorData.taskRunDistros
 	distro := hostAllocatorData.distro
 
 	// determine how many free hosts we have
@@ -391,7 +382,7 @@ func durationNumNewHostsForDistro(ctx context.Context, hostAllocatorData *HostAl
 
 	// determine the total remaining running time of all
 	// tasks currently running on the hosts for this distro
-	runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+	runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, hostAllocatorData.currentTime())
 
 	if err != nil {
 		return numNewHosts, err
@@ -401,8 +392,6 @@ func durationNumNewHostsForDistro(ctx context.Context, hostAllocatorData *HostAl
 	scheduledDistroTasksData := &ScheduledDistroTasksData{
 		taskQueueItems:    taskQueueItems,
 		tasksAccountedFor: tasksAccountedFor,
-		taskRunDistros:    taskRunDistros,
-		currentDistroId:   distro.Id,
 	}
 
 	// determine the total expected running time of all scheduled
@@ -419,7 +408,7 @@ func durationNumNewHostsForDistro(ctx context.Context, hostAllocatorData *HostAl
 	// revise the new host estimate based on the cap of the number of new hosts
 	// and the number of free hosts
 	numNewHosts = numNewDistroHosts(distro.PoolSize, len(existingDistroHosts),
-		numFreeHosts, durationBasedNumNewHosts, len(taskQueueItems))
+		numFreeHosts, durationBasedNumNewHosts, queueDemand(taskQueueItems))
 
 	// create an entry for this distro in the scheduling map
 	distroData := DistroScheduleData{
@@ -512,7 +512,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				{Id: hostIds[4], RunningTask: runningTaskIds[2]},
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, time.Now())
 
 			So(err, ShouldBeNil)
 
@@ -550,7 +550,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				So(runningTask.Insert(), ShouldBeNil)
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, time.Now())
 			So(err, ShouldBeNil)
 			// the running task duration should be a total of the remaining
 			// duration of running tasks - 6 in this case
@@ -582,7 +582,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				So(runningTask.Insert(), ShouldBeNil)
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, time.Now())
 			So(err, ShouldBeNil)
 			// only task 1's duration is known, so the others should use the default.
 			expectedDur := remainingDurationTwoSecs + float64((2*10*time.Minute)/time.Second)
@@ -610,7 +610,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				So(runningTask.Insert(), ShouldBeNil)
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, time.Now())
 			So(err, ShouldBeNil)
 			// task 2's duration should be ignored
 			// due to scheduling variables, we allow a 5 second tolerance
@@ -629,7 +629,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				{Id: hostIds[3]},
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, time.Now())
 			So(err, ShouldBeNil)
 			// the running task duration should be a total of the remaining
 			// duration of running tasks
@@ -754,6 +754,30 @@ func TestComputeScheduledTasksDuration(t *testing.T) {
 			So(tasksAccountedFor, ShouldResemble, expectedTasksAccountedFor)
 			So(scheduledTasksDuration, ShouldEqual, expDur.Seconds())
 		})
+
+		Convey("tasks queued on several distros should count a share of their "+
+			"duration and add to the shared duration of each distro", func() {
+			expDur = time.Duration(180) * time.Minute
+			tasksAccountedFor = make(map[string]bool)
+			queueItems = []model.TaskQueueItem{
+				{Id: "t1", ExpectedDuration: expDur},
+				{Id: "t2", ExpectedDuration: expDur, Distros: []string{"d1", "d2"}},
+			}
+
+			scheduledDistroTasksData := &ScheduledDistroTasksData{
+				taskQueueItems:    queueItems,
+				tasksAccountedFor: tasksAccountedFor,
+			}
+
+			scheduledTasksDuration, sharedTasksDuration := computeScheduledTasksDuration(
+				scheduledDistroTasksData)
+
+			So(scheduledTasksDuration, ShouldEqual, 1.5*expDur.Seconds())
+			So(sharedTasksDuration, ShouldResemble, map[string]float64{
+				"d1": expDur.Seconds(),
+				"d2": expDur.Seconds(),
+			})
+		})
 	})
 }
 
@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// fairShareUnknownTaskCost is what a task that has no expected duration yet
+// costs its project's virtual time.
+const fairShareUnknownTaskCost = 10 * time.Minute
+
+// FairShareTaskPrioritizer allocates a distro's queue positions between
+// projects, so that a project that submits many tasks cannot starve the
+// others. Each project's tasks are ordered by the CmpBasedTaskPrioritizer,
+// and the projects then take turns by the distro's project weights and by
+// how much of the distro they have recently used. Tasks whose priority is
+// above evergreen.MaxTaskPriority stay at the front of the queue.
+type FairShareTaskPrioritizer struct {
+	runtimeID string
+	distro    distro.Distro
+	// now is the time that usage decays to. It is zero when scheduling
+	// runs against the current time, and fixed by the simulator.
+	now time.Time
+}
+
+// GetTaskPrioritizer returns the task prioritizer that a distro is
+// configured to use.
+func GetTaskPrioritizer(d distro.Distro, runtimeID string) TaskPrioritizer {
+	switch d.TaskPrioritizer {
+	case distro.TaskPrioritizerFairShare:
+		return &FairShareTaskPrioritizer{runtimeID: runtimeID, distro: d}
+	default:
+		return &CmpBasedTaskPrioritizer{runtimeID: runtimeID}
+	}
+}
+
+func (p *FairShareTaskPrioritizer) PrioritizeTasks(distroId string, tasks []task.Task, versions map[string]version.Version) ([]task.Task, error) {
+	cmp := &CmpBasedTaskPrioritizer{runtimeID: p.runtimeID}
+
+	highPriority := []task.Task{}
+	byProject := map[string][]task.Task{}
+	for _, t := range tasks {
+		if t.Priority > evergreen.MaxTaskPriority {
+			highPriority = append(highPriority, t)
+			continue
+		}
+		byProject[t.Project] = append(byProject[t.Project], t)
+	}
+
+	prioritized, err := cmp.PrioritizeTasks(distroId, highPriority, versions)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem prioritizing high priority tasks")
+	}
+
+	units := map[string][][]task.Task{}
+	queued := map[string]int{}
+	for project, projectTasks := range byProject {
+		projectTasks, err = cmp.PrioritizeTasks(distroId, projectTasks, versions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem prioritizing tasks for project '%s'", project)
+		}
+		units[project] = groupUnits(projectTasks)
+		queued[project] = len(projectTasks)
+	}
+
+	now := p.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	usage, err := model.FindDecayedUsage(&p.distro, now)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	grip.Debug(message.Fields{
+		"message":   "fair-share project shares",
+		"distro":    distroId,
+		"runner":    RunnerName,
+		"instance":  p.runtimeID,
+		"operation": "prioritize tasks",
+		"shares":    model.ComputeProjectShares(&p.distro, queued, usage),
+	})
+
+	return append(prioritized, interleaveByShare(units, usage, p.distro.FairShare.Weight)...), nil
+}
+
+// groupUnits splits a project's ordered tasks into the units that projects
+// take turns with: a task, or a run of tasks in the same task group, which
+// stay together in the queue.
+func groupUnits(tasks []task.Task) [][]task.Task {
+	units := [][]task.Task{}
+	for i, t := range tasks {
+		if i > 0 && t.TaskGroup != "" {
+			prev := tasks[i-1]
+			if prev.TaskGroup == t.TaskGroup && prev.BuildVariant == t.BuildVariant && prev.Version == t.Version {
+				units[len(units)-1] = append(units[len(units)-1], t)
+				continue
+			}
+		}
+		units = append(units, []task.Task{t})
+	}
+	return units
+}
+
+// interleaveByShare merges the projects' units into one queue by stride
+// scheduling. Each project's virtual time starts at its recent usage divided
+// by its weight, the project with the least virtual time takes the next
+// position, and the expected duration of the unit it queues, divided by its
+// weight, is added to its virtual time.
+func interleaveByShare(units map[string][][]task.Task, usage map[string]float64, weight func(string) float64) []task.Task {
+	virtual := map[string]float64{}
+	next := map[string]int{}
+	total := 0
+	for project, projectUnits := range units {
+		virtual[project] = usage[project] / weight(project)
+		for _, unit := range projectUnits {
+			total += len(unit)
+		}
+	}
+
+	queue := make([]task.Task, 0, total)
+	for len(queue) < total {
+		project := ""
+		for candidate, projectUnits := range units {
+			if next[candidate] >= len(projectUnits) {
+				continue
+			}
+			if project == "" || virtual[candidate] < virtual[project] ||
+				(virtual[candidate] == virtual[project] && candidate < project) {
+				project = candidate
+			}
+		}
+
+		unit := units[project][next[project]]
+		next[project]++
+		var cost float64
+		for i := range unit {
+			cost += fairShareTaskCost(&unit[i]).Seconds()
+		}
+		if cost < 1 {
+			cost = 1
+		}
+		virtual[project] += cost / weight(project)
+		queue = append(queue, unit...)
+	}
+	return queue
+}
+
+// fairShareTaskCost returns a task's expected duration, as last cached on
+// the task. The cached value is read rather than refreshed so that
+// prioritizing doesn't write to every task in the queue.
+func fairShareTaskCost(t *task.Task) time.Duration {
+	switch {
+	case t.ExpectedDuration > 0:
+		return t.ExpectedDuration
+	case t.DurationPrediction.Value > 0:
+		return t.DurationPrediction.Value
+	}
+	return fairShareUnknownTaskCost
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTaskPrioritizer(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.IsType(&CmpBasedTaskPrioritizer{}, GetTaskPrioritizer(distro.Distro{}, "id"))
+	assert.IsType(&CmpBasedTaskPrioritizer{}, GetTaskPrioritizer(distro.Distro{TaskPrioritizer: distro.TaskPrioritizerCmp}, "id"))
+	assert.IsType(&FairShareTaskPrioritizer{}, GetTaskPrioritizer(distro.Distro{TaskPrioritizer: distro.TaskPrioritizerFairShare}, "id"))
+}
+
+func TestGroupUnits(t *testing.T) {
+	assert := assert.New(t)
+
+	units := groupUnits([]task.Task{
+		{Id: "t1", TaskGroup: "g", BuildVariant: "bv", Version: "v"},
+		{Id: "t2", TaskGroup: "g", BuildVariant: "bv", Version: "v"},
+		{Id: "t3", TaskGroup: "g", BuildVariant: "bv2", Version: "v"},
+		{Id: "t4"},
+		{Id: "t5"},
+	})
+	assert.Len(units, 4)
+	assert.Len(units[0], 2)
+	assert.Equal("t3", units[1][0].Id)
+	assert.Equal("t4", units[2][0].Id)
+	assert.Equal("t5", units[3][0].Id)
+	assert.Empty(groupUnits(nil))
+}
+
+func TestInterleaveByShare(t *testing.T) {
+	assert := assert.New(t)
+
+	unit := func(id, project string) []task.Task {
+		return []task.Task{{Id: id, Project: project, ExpectedDuration: time.Minute}}
+	}
+	units := map[string][][]task.Task{
+		"a": {unit("a1", "a"), unit("a2", "a"), unit("a3", "a"), unit("a4", "a")},
+		"b": {unit("b1", "b"), unit("b2", "b")},
+	}
+	ids := func(tasks []task.Task) []string {
+		out := []string{}
+		for _, t := range tasks {
+			out = append(out, t.Id)
+		}
+		return out
+	}
+
+	// equal weights and no usage alternate between the projects
+	equal := func(string) float64 { return 1 }
+	assert.Equal([]string{"a1", "b1", "a2", "b2", "a3", "a4"}, ids(interleaveByShare(units, nil, equal)))
+
+	// a project that has recently used the distro goes after the others
+	assert.Equal([]string{"b1", "b2", "a1", "a2", "a3", "a4"},
+		ids(interleaveByShare(units, map[string]float64{"a": 600}, equal)))
+
+	// a project with twice the weight takes twice the positions
+	weighted := func(p string) float64 {
+		if p == "a" {
+			return 2
+		}
+		return 1
+	}
+	assert.Equal([]string{"a1", "b1", "a2", "a3", "b2", "a4"}, ids(interleaveByShare(units, nil, weighted)))
+}
+
+func TestFairShareTaskCost(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(time.Minute, fairShareTaskCost(&task.Task{ExpectedDuration: time.Minute}))
+	predicted := &task.Task{}
+	predicted.DurationPrediction.Value = time.Hour
+	assert.Equal(time.Hour, fairShareTaskCost(predicted))
+	assert.Equal(fairShareUnknownTaskCost, fairShareTaskCost(&task.Task{}))
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
@@ -15,7 +16,6 @@ import (
 //  distros: a map of distro name -> information on that distro (a model.Distro object)
 //  existingDistroHosts: a map of distro name -> currently running hosts on that distro
 //  projectTaskDurations: the expected duration of tasks by project and variant
-//  taskRunDistros: a map of task id -> distros the task is allowed to run on
 // Returns a map of distro name -> how many hosts need to be spun up for that distro.
 type HostAllocator func(context.Context, HostAllocatorData) (int, error)
 
@@ -23,11 +23,21 @@ type HostAllocator func(context.Context, HostAllocatorData) (int, error)
 type HostAllocatorData struct {
 	taskQueueItems   []model.TaskQueueItem
 	existingHosts    []host.Host
-	taskRunDistros   []string
 	distro           distro.Distro
 	freeHostFraction float64
 	usesContainers   bool
 	containerPool    *evergreen.ContainerPool
+	// now is the time that allocation runs at. It is zero when scheduling
+	// runs against the current time, and fixed by the simulator.
+	now time.Time
+}
+
+// currentTime returns the time that allocation runs at.
+func (d *HostAllocatorData) currentTime() time.Time {
+	if d.now.IsZero() {
+		return time.Now()
+	}
+	return d.now
 }
 
 func GetHostAllocator(name string) HostAllocator {
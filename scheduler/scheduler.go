@@ -55,6 +55,8 @@ type newParentsNeededParams struct {
 type containersOnParents struct {
 	parentHost    host.Host
 	numContainers int
+	// usedResources are the resources that the parent's containers request
+	usedResources evergreen.ContainerResources
 }
 
 func (s *distroSchedueler) scheduleDistro(distroId string, runnableTasksForDistro []task.Task, versions map[string]version.Version) distroSchedulerResult {
@@ -137,7 +139,9 @@ func (s *distroSchedueler) scheduleDistro(distroId string, runnableTasksForDistr
 // Call out to the embedded Manager to spawn hosts.  Takes in a map of
 // distro -> number of hosts to spawn for the distro.
 // Returns a map of distro -> hosts spawned, and an error if one occurs.
-func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *evergreen.ContainerPool) ([]host.Host, error) {
+// For container distros, requests are the resources that each new container
+// needs.
+func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *evergreen.ContainerPool, requests []evergreen.ContainerResources) ([]host.Host, error) {
 
 	startTime := time.Now()
 
@@ -158,6 +162,7 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 
 	// if distro is container distro, check if there are enough parent hosts to
 	// support new containers
+	var containerIntents []host.Host
 	if pool != nil {
 		// find all running parents with the specified container pool
 		currentParents, err := host.FindAllRunningParentsByContainerPool(pool.Id)
@@ -177,15 +182,34 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 			return nil, errors.Wrap(err, "could not count uphost parents")
 		}
 
-		// create numParentsNeededParams struct
-		parentsParams := newParentsNeededParams{
-			numUphostParents:      numUphostParents,
-			numContainersNeeded:   newHostsNeeded,
-			numExistingContainers: len(existingContainers),
-			maxContainers:         pool.MaxContainers,
+		var numNewParents int
+		if pool.ParentResources.IsZero() {
+			// create numParentsNeededParams struct
+			parentsParams := newParentsNeededParams{
+				numUphostParents:      numUphostParents,
+				numContainersNeeded:   newHostsNeeded,
+				numExistingContainers: len(existingContainers),
+				maxContainers:         pool.MaxContainers,
+			}
+			// compute number of parents needed
+			numNewParents = numNewParentsNeeded(parentsParams)
+		} else {
+			// pack containers onto parents by the resources they request
+			parents, err := getNumContainersOnParents(d)
+			if err != nil {
+				return nil, errors.Wrap(err, "Could not find number of containers on each parent")
+			}
+			plan := packContainers(pool, parents, numUphostParents-len(currentParents), requests)
+			containerIntents = plan.intents(d)
+			numNewParents = plan.numNewParents
+			grip.WarningWhen(plan.numUnplaceable > 0, message.Fields{
+				"runner":          RunnerName,
+				"distro":          d.Id,
+				"pool":            pool.Id,
+				"num_unplaceable": plan.numUnplaceable,
+				"message":         "containers request more resources than a parent has",
+			})
 		}
-		// compute number of parents needed
-		numNewParents := numNewParentsNeeded(parentsParams)
 
 		// get parent distro from pool
 		parentDistro, err := distro.FindOne(distro.ById(pool.Distro))
@@ -219,9 +243,12 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 
 	// create intent documents for container hosts
 	if d.ContainerPool != "" {
-		containerIntents, err := generateContainerHostIntents(d, newHostsNeeded)
-		if err != nil {
-			return nil, errors.Wrap(err, "error generating container intent hosts")
+		if containerIntents == nil {
+			var err error
+			containerIntents, err = generateContainerHostIntents(d, newHostsNeeded, requests)
+			if err != nil {
+				return nil, errors.Wrap(err, "error generating container intent hosts")
+			}
 		}
 		hostsSpawned = append(hostsSpawned, containerIntents...)
 	} else { // create intent documents for regular hosts
@@ -252,7 +279,7 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 // generateContainerHostIntents generates container intent documents by going
 // through available parents and packing on the parents with longest expected
 // finish time
-func generateContainerHostIntents(d distro.Distro, newContainersNeeded int) ([]host.Host, error) {
+func generateContainerHostIntents(d distro.Distro, newContainersNeeded int, requests []evergreen.ContainerResources) ([]host.Host, error) {
 	parents, err := getNumContainersOnParents(d)
 	if err != nil {
 		err = errors.Wrap(err, "Could not find number of containers on each parent")
@@ -268,11 +295,11 @@ func generateContainerHostIntents(d distro.Distro, newContainersNeeded int) ([]h
 			containersToCreate = newContainersNeeded
 		}
 		for i := 0; i < containersToCreate; i++ {
-			hostOptions := cloud.HostOptions{
-				ParentID: parent.parentHost.Id,
-				UserName: evergreen.User,
+			var resources evergreen.ContainerResources
+			if idx := len(containerHostIntents); idx < len(requests) {
+				resources = requests[idx]
 			}
-			containerHostIntents = append(containerHostIntents, *cloud.NewIntent(d, d.GenerateName(), d.Provider, hostOptions))
+			containerHostIntents = append(containerHostIntents, newContainerIntent(d, parent.parentHost.Id, resources))
 		}
 		newContainersNeeded -= containersToCreate
 		if newContainersNeeded == 0 {
@@ -309,10 +336,17 @@ func getNumContainersOnParents(d distro.Distro) ([]containersOnParents, error) {
 			return nil, errors.Wrapf(err, "Could not find containers for parent %s", parent.Id)
 		}
 		if len(currentContainers) < parent.ContainerPoolSettings.MaxContainers {
+			var used evergreen.ContainerResources
+			for _, c := range currentContainers {
+				if c.ContainerResources != nil {
+					used = used.Add(*c.ContainerResources)
+				}
+			}
 			numContainersOnParents = append(numContainersOnParents,
 				containersOnParents{
 					parentHost:    parent,
 					numContainers: len(currentContainers),
+					usedResources: used,
 				})
 		}
 	}
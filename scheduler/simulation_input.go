@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// maxBSONDocumentSize is the largest document that mongod stores, and so
+// the largest document that a dump can hold.
+const maxBSONDocumentSize = 16 * 1024 * 1024
+
+// SimulationInput is the recorded history that the scheduler simulator
+// replays.
+type SimulationInput struct {
+	Distro distro.Distro
+	// Tasks are all of the recorded tasks. The distro's tasks are replayed,
+	// and the others are history for the task prioritizer.
+	Tasks []task.Task
+	// Hosts are the distro's recorded hosts.
+	Hosts    []host.Host
+	Versions map[string]version.Version
+}
+
+// LoadSimulationInput reads the history of a distro from a directory of
+// collections written by mongodump. The distro and task collections are
+// required; the host and version collections are read if they exist.
+func LoadSimulationInput(dir, distroID string) (*SimulationInput, error) {
+	input := &SimulationInput{Versions: map[string]version.Version{}}
+
+	found := false
+	err := readBSONDump(dumpPath(dir, distro.Collection), func(raw []byte) error {
+		d := distro.Distro{}
+		if err := bson.Unmarshal(raw, &d); err != nil {
+			return errors.Wrap(err, "problem reading distro")
+		}
+		if d.Id == distroID {
+			input.Distro = d
+			found = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !found {
+		return nil, errors.Errorf("distro '%s' is not in the dump", distroID)
+	}
+
+	err = readBSONDump(dumpPath(dir, task.Collection), func(raw []byte) error {
+		t := task.Task{}
+		if err := bson.Unmarshal(raw, &t); err != nil {
+			return errors.Wrap(err, "problem reading task")
+		}
+		input.Tasks = append(input.Tasks, t)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = readOptionalBSONDump(dumpPath(dir, host.Collection), func(raw []byte) error {
+		h := host.Host{}
+		if err := bson.Unmarshal(raw, &h); err != nil {
+			return errors.Wrap(err, "problem reading host")
+		}
+		if h.Distro.Id == distroID {
+			input.Hosts = append(input.Hosts, h)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = readOptionalBSONDump(dumpPath(dir, version.Collection), func(raw []byte) error {
+		v := version.Version{}
+		if err := bson.Unmarshal(raw, &v); err != nil {
+			return errors.Wrap(err, "problem reading version")
+		}
+		input.Versions[v.Id] = v
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return input, nil
+}
+
+func dumpPath(dir, collection string) string {
+	return filepath.Join(dir, collection+".bson")
+}
+
+func readOptionalBSONDump(path string, handle func([]byte) error) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	return readBSONDump(path, handle)
+}
+
+// readBSONDump calls handle with each document in a file written by
+// mongodump, which is a sequence of BSON documents.
+func readBSONDump(path string, handle func([]byte) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening dump '%s'", path)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		header := make([]byte, 4)
+		if _, err = io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrapf(err, "problem reading dump '%s'", path)
+		}
+
+		// a document's length includes its length prefix
+		size := int(int32(binary.LittleEndian.Uint32(header)))
+		if size < 5 || size > maxBSONDocumentSize {
+			return errors.Errorf("dump '%s' has a document of invalid size %d", path, size)
+		}
+		doc := make([]byte, size)
+		copy(doc, header)
+		if _, err = io.ReadFull(reader, doc[4:]); err != nil {
+			return errors.Wrapf(err, "problem reading dump '%s'", path)
+		}
+
+		if err = handle(doc); err != nil {
+			return errors.Wrapf(err, "problem handling document in dump '%s'", path)
+		}
+	}
+}
@@ -0,0 +1,708 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	simulatorRuntimeID = "scheduler-simulator"
+
+	defaultSimulationInterval        = 30 * time.Second
+	defaultSimulationHostStartupTime = 5 * time.Minute
+	defaultSimulationIdleTimeout     = 4 * time.Minute
+	defaultSimulationMaxDrainTime    = 24 * time.Hour
+
+	// simulationInsertBatchSize is how many tasks are inserted into the
+	// database at a time while the simulator loads history.
+	simulationInsertBatchSize = 1000
+)
+
+// simulationCollections are the collections that the simulator writes the
+// replayed tasks to.
+var simulationCollections = []string{task.Collection, model.TaskQueuesCollection}
+
+// SimulationOptions are the scheduler and distro settings that the
+// simulator replays recorded history with.
+type SimulationOptions struct {
+	// HostAllocator is the name of the host allocator to use.
+	HostAllocator string
+	// TaskPrioritizer, if set, overrides the distro's task prioritizer.
+	TaskPrioritizer string
+	// PoolSize, if positive, overrides the distro's pool size.
+	PoolSize         int
+	FreeHostFraction float64
+
+	// Start and End bound the activation times of the tasks to replay. The
+	// zero values include all of the distro's tasks.
+	Start time.Time
+	End   time.Time
+
+	// Interval is how often the scheduler runs.
+	Interval time.Duration
+	// HostStartupTime is how long a new host takes to be ready for tasks.
+	HostStartupTime time.Duration
+	// IdleTimeout is how long an idle host runs before it is terminated.
+	IdleTimeout time.Duration
+	// MaxDrainTime is how long the simulation continues after the last
+	// task is activated before it stops with tasks unfinished.
+	MaxDrainTime time.Duration
+
+	// CostPerHostHour is what running a host for an hour costs.
+	CostPerHostHour float64
+}
+
+// Validate checks the options and sets the defaults of the options that are
+// not set.
+func (o *SimulationOptions) Validate() error {
+	allocators := []string{"duration", "deficit", "utilization"}
+	if o.HostAllocator == "" {
+		o.HostAllocator = "utilization"
+	}
+	if !util.StringSliceContains(allocators, o.HostAllocator) {
+		return errors.Errorf("supported allocators are %s; %s is not supported",
+			allocators, o.HostAllocator)
+	}
+	if !util.StringSliceContains(distro.ValidTaskPrioritizers, o.TaskPrioritizer) {
+		return errors.Errorf("supported task prioritizers are %s; %s is not supported",
+			distro.ValidTaskPrioritizers, o.TaskPrioritizer)
+	}
+	if o.PoolSize < 0 {
+		return errors.New("pool size cannot be negative")
+	}
+	if o.FreeHostFraction < 0 || o.FreeHostFraction > 1 {
+		return errors.New("free host fraction must be between 0 and 1")
+	}
+	if o.CostPerHostHour < 0 {
+		return errors.New("cost per host hour cannot be negative")
+	}
+	if !o.End.IsZero() && o.End.Before(o.Start) {
+		return errors.New("end cannot be before start")
+	}
+	if o.Interval < 0 || o.HostStartupTime < 0 || o.IdleTimeout < 0 || o.MaxDrainTime < 0 {
+		return errors.New("durations cannot be negative")
+	}
+
+	if o.Interval == 0 {
+		o.Interval = defaultSimulationInterval
+	}
+	if o.HostStartupTime == 0 {
+		o.HostStartupTime = defaultSimulationHostStartupTime
+	}
+	if o.IdleTimeout == 0 {
+		o.IdleTimeout = defaultSimulationIdleTimeout
+	}
+	if o.MaxDrainTime == 0 {
+		o.MaxDrainTime = defaultSimulationMaxDrainTime
+	}
+	return nil
+}
+
+// SimulationReport compares the simulated schedule of a distro's recorded
+// tasks with what happened when they were recorded.
+type SimulationReport struct {
+	Distro          string          `json:"distro"`
+	HostAllocator   string          `json:"host_allocator"`
+	TaskPrioritizer string          `json:"task_prioritizer"`
+	PoolSize        int             `json:"pool_size"`
+	Start           time.Time       `json:"start"`
+	End             time.Time       `json:"end"`
+	Simulated       SimulationStats `json:"simulated"`
+	Recorded        SimulationStats `json:"recorded"`
+}
+
+// SimulationStats summarizes how long a distro's tasks waited to start after
+// they were activated, and how many hosts the distro ran for them.
+type SimulationStats struct {
+	Tasks          int     `json:"tasks"`
+	UnstartedTasks int     `json:"unstarted_tasks"`
+	WaitMeanSecs   float64 `json:"wait_mean_secs"`
+	WaitP50Secs    float64 `json:"wait_p50_secs"`
+	WaitP90Secs    float64 `json:"wait_p90_secs"`
+	WaitP99Secs    float64 `json:"wait_p99_secs"`
+	WaitMaxSecs    float64 `json:"wait_max_secs"`
+	HostsStarted   int     `json:"hosts_started"`
+	MaxHosts       int     `json:"max_hosts"`
+	HostHours      float64 `json:"host_hours"`
+	Cost           float64 `json:"cost"`
+}
+
+// Simulate replays a distro's recorded tasks through the task prioritizer
+// and host allocator with the given settings, and reports how long the
+// tasks would have waited and what the distro's hosts would have cost.
+//
+// Tasks arrive when they were activated, wait for their dependencies, and
+// run for as long as they ran when they were recorded. The prioritizer and
+// allocator read tasks from the database, so the simulator writes the
+// recorded tasks to the task and task queue collections. It refuses to run
+// unless they are empty, and empties them when it is done.
+func Simulate(ctx context.Context, input *SimulationInput, opts SimulationOptions) (*SimulationReport, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid simulation options")
+	}
+	if err := checkSimulationCollections(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() {
+		grip.Warning(errors.Wrap(db.ClearCollections(simulationCollections...), "problem clearing simulated tasks"))
+	}()
+
+	d := input.Distro
+	if opts.PoolSize > 0 {
+		d.PoolSize = opts.PoolSize
+	}
+	if opts.TaskPrioritizer != "" {
+		d.TaskPrioritizer = opts.TaskPrioritizer
+	}
+
+	sim, err := newSimulation(d, input, opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = loadSimulationTasks(input.Tasks, sim.byID); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	allocator := GetHostAllocator(opts.HostAllocator)
+	stopAt := sim.tasks[len(sim.tasks)-1].arrival.Add(opts.MaxDrainTime)
+	now := sim.start
+	for {
+		if ctx.Err() != nil {
+			return nil, errors.New("simulation canceled")
+		}
+
+		for _, t := range sim.finishTasks(now) {
+			if err = recordSimulatedFinish(t); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		sim.readyHosts(now)
+		sim.terminateIdleHosts(now)
+		sim.arrive(now)
+
+		if sim.done() || now.After(stopAt) {
+			break
+		}
+
+		prioritizer := GetTaskPrioritizer(d, simulatorRuntimeID)
+		if fairShare, ok := prioritizer.(*FairShareTaskPrioritizer); ok {
+			fairShare.now = now
+		}
+		ds := &distroSchedueler{
+			runtimeID:          simulatorRuntimeID,
+			TaskPrioritizer:    prioritizer,
+			TaskQueuePersister: &DBTaskQueuePersister{},
+		}
+		res := ds.scheduleDistro(d.Id, sim.runnableTasks(), input.Versions)
+		if res.err != nil {
+			return nil, errors.Wrapf(res.err, "problem scheduling distro at %s", now)
+		}
+
+		var newHosts int
+		newHosts, err = allocator(ctx, HostAllocatorData{
+			taskQueueItems:   res.taskQueueItem,
+			existingHosts:    sim.activeHosts(),
+			distro:           d,
+			freeHostFraction: opts.FreeHostFraction,
+			now:              now,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem allocating hosts at %s", now)
+		}
+		sim.addHosts(newHosts, now)
+
+		for _, t := range sim.dispatch(res.taskQueueItem, now) {
+			if err = t.task.MarkStart(now); err != nil {
+				return nil, errors.Wrapf(err, "problem starting task '%s'", t.task.Id)
+			}
+		}
+
+		now = now.Add(opts.Interval)
+	}
+
+	return sim.report(input, now), nil
+}
+
+// checkSimulationCollections returns an error if any of the collections
+// that the simulator writes to hold documents, so that the simulator
+// doesn't overwrite a database that is in use.
+func checkSimulationCollections() error {
+	for _, collection := range simulationCollections {
+		count, err := db.Count(collection, bson.M{})
+		if err != nil {
+			return errors.Wrapf(err, "problem counting documents in '%s'", collection)
+		}
+		if count > 0 {
+			return errors.Errorf("collection '%s' has %d documents; the simulator only runs against an empty database", collection, count)
+		}
+	}
+	return nil
+}
+
+// loadSimulationTasks inserts the recorded tasks into the database,
+// resetting the tasks to replay to before they were dispatched.
+func loadSimulationTasks(tasks []task.Task, replayed map[string]*simTask) error {
+	batch := make([]interface{}, 0, simulationInsertBatchSize)
+	for _, t := range tasks {
+		if _, ok := replayed[t.Id]; ok {
+			t.Status = evergreen.TaskUndispatched
+			t.HostId = ""
+			t.DispatchTime = util.ZeroTime
+			t.StartTime = util.ZeroTime
+			t.FinishTime = util.ZeroTime
+			t.TimeTaken = 0
+		}
+		batch = append(batch, t)
+		if len(batch) == simulationInsertBatchSize {
+			if err := db.InsertMany(task.Collection, batch...); err != nil {
+				return errors.Wrap(err, "problem inserting tasks")
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		return errors.Wrap(db.InsertMany(task.Collection, batch...), "problem inserting tasks")
+	}
+	return nil
+}
+
+// recordSimulatedFinish finishes a task in the database with the status it
+// was recorded with, so that the prioritizer sees it as history.
+func recordSimulatedFinish(t *simTask) error {
+	status := t.status
+	if !util.StringSliceContains(evergreen.CompletedStatuses, status) {
+		status = evergreen.TaskSucceeded
+	}
+	return errors.Wrapf(task.UpdateOne(
+		bson.M{task.IdKey: t.task.Id},
+		bson.M{"$set": bson.M{
+			task.StatusKey:     status,
+			task.FinishTimeKey: t.end,
+			task.TimeTakenKey:  t.runtime,
+		}},
+	), "problem finishing task '%s'", t.task.Id)
+}
+
+// simTask is a recorded task as the simulator replays it.
+type simTask struct {
+	task task.Task
+	// status and recordedStart are the task's recorded status and start
+	// time, since the task's start is replayed
+	status        string
+	recordedStart time.Time
+	arrival       time.Time
+	runtime       time.Duration
+	start         time.Time
+	end           time.Time
+	started       bool
+	finished      bool
+}
+
+// simHost is a host that the simulator runs tasks on.
+type simHost struct {
+	host       host.Host
+	created    time.Time
+	ready      time.Time
+	terminated time.Time
+	idleSince  time.Time
+	running    *simTask
+}
+
+func (h *simHost) isReady() bool {
+	return h.host.Status == evergreen.HostRunning
+}
+
+func (h *simHost) isTerminated() bool {
+	return !h.terminated.IsZero()
+}
+
+// simulation is the state of a distro's hosts and tasks as the simulator
+// replays them.
+type simulation struct {
+	distro distro.Distro
+	opts   SimulationOptions
+	start  time.Time
+
+	// tasks are the tasks to replay, ordered by arrival
+	tasks   []*simTask
+	byID    map[string]*simTask
+	arrived int
+	pending map[string]*simTask
+
+	hosts           []*simHost
+	numHostsStarted int
+}
+
+func newSimulation(d distro.Distro, input *SimulationInput, opts SimulationOptions) (*simulation, error) {
+	sim := &simulation{
+		distro:  d,
+		opts:    opts,
+		byID:    map[string]*simTask{},
+		pending: map[string]*simTask{},
+	}
+
+	for _, t := range input.Tasks {
+		if t.DistroId != d.Id || t.DisplayOnly || util.IsZeroTime(t.ActivatedTime) {
+			continue
+		}
+		if t.ActivatedTime.Before(opts.Start) || (!opts.End.IsZero() && t.ActivatedTime.After(opts.End)) {
+			continue
+		}
+		st := &simTask{
+			task:          t,
+			status:        t.Status,
+			recordedStart: t.StartTime,
+			arrival:       t.ActivatedTime,
+			runtime:       recordedRuntime(t),
+		}
+		sim.tasks = append(sim.tasks, st)
+		sim.byID[t.Id] = st
+	}
+	if len(sim.tasks) == 0 {
+		return nil, errors.Errorf("distro '%s' has no recorded tasks to replay", d.Id)
+	}
+	sort.SliceStable(sim.tasks, func(i, j int) bool {
+		return sim.tasks[i].arrival.Before(sim.tasks[j].arrival)
+	})
+
+	sim.start = opts.Start
+	if sim.start.IsZero() {
+		sim.start = sim.tasks[0].arrival
+	}
+
+	// the distro starts with the hosts that were up when the replay starts
+	for _, h := range input.Hosts {
+		if h.CreationTime.After(sim.start) {
+			continue
+		}
+		if !util.IsZeroTime(h.TerminationTime) && !h.TerminationTime.After(sim.start) {
+			continue
+		}
+		h.Status = evergreen.HostRunning
+		h.RunningTask = ""
+		h.RunningTaskGroup = ""
+		sim.hosts = append(sim.hosts, &simHost{
+			host:      h,
+			created:   sim.start,
+			ready:     sim.start,
+			idleSince: sim.start,
+		})
+	}
+	return sim, nil
+}
+
+// recordedRuntime returns how long a task ran when it was recorded, or its
+// expected duration if it didn't finish.
+func recordedRuntime(t task.Task) time.Duration {
+	if t.TimeTaken > 0 {
+		return t.TimeTaken
+	}
+	if !util.IsZeroTime(t.StartTime) && t.FinishTime.After(t.StartTime) {
+		return t.FinishTime.Sub(t.StartTime)
+	}
+	return t.ExpectedDuration
+}
+
+// finishTasks frees the hosts of the tasks that finish by now, and returns
+// those tasks.
+func (s *simulation) finishTasks(now time.Time) []*simTask {
+	finished := []*simTask{}
+	for _, h := range s.hosts {
+		if h.running == nil || h.running.end.After(now) {
+			continue
+		}
+		h.running.finished = true
+		finished = append(finished, h.running)
+		h.idleSince = h.running.end
+		h.running = nil
+		h.host.RunningTask = ""
+		h.host.RunningTaskGroup = ""
+		h.host.RunningTaskBuildVariant = ""
+		h.host.RunningTaskProject = ""
+		h.host.RunningTaskVersion = ""
+	}
+	return finished
+}
+
+// readyHosts marks the hosts that have finished starting by now as running.
+func (s *simulation) readyHosts(now time.Time) {
+	for _, h := range s.hosts {
+		if !h.isTerminated() && !h.isReady() && !h.ready.After(now) {
+			h.host.Status = evergreen.HostRunning
+			h.idleSince = h.ready
+		}
+	}
+}
+
+// terminateIdleHosts terminates the hosts of an ephemeral distro that have
+// been idle for longer than the idle timeout.
+func (s *simulation) terminateIdleHosts(now time.Time) {
+	if !s.distro.IsEphemeral() {
+		return
+	}
+	for _, h := range s.hosts {
+		if h.isTerminated() || !h.isReady() || h.running != nil {
+			continue
+		}
+		if terminateAt := h.idleSince.Add(s.opts.IdleTimeout); !terminateAt.After(now) {
+			h.terminated = terminateAt
+			h.host.Status = evergreen.HostTerminated
+		}
+	}
+}
+
+// arrive queues the tasks that were activated by now.
+func (s *simulation) arrive(now time.Time) {
+	for ; s.arrived < len(s.tasks) && !s.tasks[s.arrived].arrival.After(now); s.arrived++ {
+		t := s.tasks[s.arrived]
+		s.pending[t.task.Id] = t
+	}
+}
+
+// runnableTasks returns the queued tasks whose replayed dependencies have
+// finished, ordered by arrival. Dependencies that are not replayed are
+// assumed to have finished before the simulation.
+func (s *simulation) runnableTasks() []task.Task {
+	runnable := []task.Task{}
+	for _, t := range s.tasks[:s.arrived] {
+		if _, ok := s.pending[t.task.Id]; !ok {
+			continue
+		}
+		blocked := false
+		for _, dep := range t.task.DependsOn {
+			if depTask, ok := s.byID[dep.TaskId]; ok && !depTask.finished {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			runnable = append(runnable, t.task)
+		}
+	}
+	return runnable
+}
+
+// activeHosts returns the hosts that are starting or running.
+func (s *simulation) activeHosts() []host.Host {
+	hosts := []host.Host{}
+	for _, h := range s.hosts {
+		if !h.isTerminated() {
+			hosts = append(hosts, h.host)
+		}
+	}
+	return hosts
+}
+
+// addHosts starts new hosts, which are ready after the host startup time.
+func (s *simulation) addHosts(n int, now time.Time) {
+	for i := 0; i < n; i++ {
+		s.numHostsStarted++
+		s.hosts = append(s.hosts, &simHost{
+			host: host.Host{
+				Id:           fmt.Sprintf("simulated-host-%d", s.numHostsStarted),
+				Distro:       s.distro,
+				Provider:     s.distro.Provider,
+				Status:       evergreen.HostStarting,
+				CreationTime: now,
+			},
+			created: now,
+			ready:   now.Add(s.opts.HostStartupTime),
+		})
+	}
+}
+
+// dispatch assigns the tasks at the front of the queue to the free hosts,
+// longest idle first, and returns the tasks that start.
+func (s *simulation) dispatch(queue []model.TaskQueueItem, now time.Time) []*simTask {
+	free := []*simHost{}
+	for _, h := range s.hosts {
+		if !h.isTerminated() && h.isReady() && h.running == nil {
+			free = append(free, h)
+		}
+	}
+	sort.SliceStable(free, func(i, j int) bool {
+		return free[i].idleSince.Before(free[j].idleSince)
+	})
+
+	started := []*simTask{}
+	for _, item := range queue {
+		if len(free) == 0 {
+			break
+		}
+		t, ok := s.pending[item.Id]
+		if !ok {
+			continue
+		}
+		delete(s.pending, item.Id)
+
+		h := free[0]
+		free = free[1:]
+		t.started = true
+		t.start = now
+		t.end = now.Add(t.runtime)
+		h.running = t
+		h.host.RunningTask = t.task.Id
+		h.host.RunningTaskGroup = t.task.TaskGroup
+		h.host.RunningTaskBuildVariant = t.task.BuildVariant
+		h.host.RunningTaskProject = t.task.Project
+		h.host.RunningTaskVersion = t.task.Version
+		started = append(started, t)
+	}
+	return started
+}
+
+// done returns whether all of the tasks have arrived and finished.
+func (s *simulation) done() bool {
+	if s.arrived < len(s.tasks) || len(s.pending) > 0 {
+		return false
+	}
+	for _, h := range s.hosts {
+		if h.running != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *simulation) report(input *SimulationInput, end time.Time) *SimulationReport {
+	report := &SimulationReport{
+		Distro:          s.distro.Id,
+		HostAllocator:   s.opts.HostAllocator,
+		TaskPrioritizer: s.distro.TaskPrioritizer,
+		PoolSize:        s.distro.PoolSize,
+		Start:           s.start,
+		End:             end,
+	}
+
+	waits := []time.Duration{}
+	for _, t := range s.tasks {
+		if t.started {
+			waits = append(waits, t.start.Sub(t.arrival))
+		}
+	}
+	report.Simulated.setWaits(waits, len(s.tasks))
+
+	spans := []hostSpan{}
+	for _, h := range s.hosts {
+		spans = append(spans, hostSpan{from: h.created, to: h.terminated})
+	}
+	report.Simulated.setHosts(spans, s.start, end, s.opts.CostPerHostHour)
+	report.Simulated.HostsStarted = s.numHostsStarted
+
+	waits = waits[:0]
+	for _, t := range s.tasks {
+		recorded := t.recordedStart
+		if !util.IsZeroTime(recorded) && !recorded.After(end) {
+			waits = append(waits, recorded.Sub(t.arrival))
+		}
+	}
+	report.Recorded.setWaits(waits, len(s.tasks))
+
+	spans = spans[:0]
+	for _, h := range input.Hosts {
+		to := h.TerminationTime
+		if util.IsZeroTime(to) {
+			to = time.Time{}
+		}
+		spans = append(spans, hostSpan{from: h.CreationTime, to: to})
+		if h.CreationTime.After(s.start) && !h.CreationTime.After(end) {
+			report.Recorded.HostsStarted++
+		}
+	}
+	report.Recorded.setHosts(spans, s.start, end, s.opts.CostPerHostHour)
+
+	return report
+}
+
+// setWaits summarizes how long the tasks that started waited.
+func (s *SimulationStats) setWaits(waits []time.Duration, numTasks int) {
+	s.Tasks = len(waits)
+	s.UnstartedTasks = numTasks - len(waits)
+	if len(waits) == 0 {
+		return
+	}
+
+	sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+	var total time.Duration
+	for _, w := range waits {
+		total += w
+	}
+	s.WaitMeanSecs = (total / time.Duration(len(waits))).Seconds()
+	s.WaitP50Secs = percentile(waits, 50).Seconds()
+	s.WaitP90Secs = percentile(waits, 90).Seconds()
+	s.WaitP99Secs = percentile(waits, 99).Seconds()
+	s.WaitMaxSecs = waits[len(waits)-1].Seconds()
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// hostSpan is when a host was up. A zero end means that the host was never
+// terminated.
+type hostSpan struct {
+	from time.Time
+	to   time.Time
+}
+
+// setHosts sums the time that hosts were up between start and end, and
+// finds the most hosts that were up at once.
+func (s *SimulationStats) setHosts(spans []hostSpan, start, end time.Time, costPerHostHour float64) {
+	type change struct {
+		at    time.Time
+		delta int
+	}
+	changes := []change{}
+	var total time.Duration
+	for _, span := range spans {
+		from, to := span.from, span.to
+		if from.Before(start) {
+			from = start
+		}
+		if to.IsZero() || to.After(end) {
+			to = end
+		}
+		if !to.After(from) {
+			continue
+		}
+		total += to.Sub(from)
+		changes = append(changes, change{at: from, delta: 1}, change{at: to, delta: -1})
+	}
+
+	// hosts that stop at the same time as others start don't overlap
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].at.Equal(changes[j].at) {
+			return changes[i].delta < changes[j].delta
+		}
+		return changes[i].at.Before(changes[j].at)
+	})
+	up := 0
+	for _, c := range changes {
+		up += c.delta
+		if up > s.MaxHosts {
+			s.MaxHosts = up
+		}
+	}
+
+	s.HostHours = total.Hours()
+	s.Cost = s.HostHours * costPerHostHour
+}
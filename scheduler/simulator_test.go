@@ -0,0 +1,204 @@
+package scheduler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func writeBSONDump(t *testing.T, path string, docs ...interface{}) {
+	out := []byte{}
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		require.NoError(t, err)
+		out = append(out, raw...)
+	}
+	require.NoError(t, ioutil.WriteFile(path, out, 0644))
+}
+
+func TestLoadSimulationInput(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "scheduler-sim")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	_, err = LoadSimulationInput(dir, "d1")
+	assert.Error(err)
+
+	writeBSONDump(t, filepath.Join(dir, "distro.bson"), distro.Distro{Id: "d1", PoolSize: 5}, distro.Distro{Id: "d2"})
+	writeBSONDump(t, filepath.Join(dir, "tasks.bson"), task.Task{Id: "t1", DistroId: "d1"}, task.Task{Id: "t2", DistroId: "d2"})
+	writeBSONDump(t, filepath.Join(dir, "hosts.bson"), host.Host{Id: "h1", Distro: distro.Distro{Id: "d1"}}, host.Host{Id: "h2", Distro: distro.Distro{Id: "d2"}})
+
+	input, err := LoadSimulationInput(dir, "d1")
+	require.NoError(err)
+	assert.Equal(5, input.Distro.PoolSize)
+	assert.Len(input.Tasks, 2)
+	require.Len(input.Hosts, 1)
+	assert.Equal("h1", input.Hosts[0].Id)
+	assert.Empty(input.Versions)
+
+	writeBSONDump(t, filepath.Join(dir, "versions.bson"), version.Version{Id: "v1"})
+	input, err = LoadSimulationInput(dir, "d1")
+	require.NoError(err)
+	assert.Contains(input.Versions, "v1")
+
+	_, err = LoadSimulationInput(dir, "d3")
+	assert.Error(err)
+
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "tasks.bson"), []byte{0xff, 0xff, 0xff, 0x7f, 0}, 0644))
+	_, err = LoadSimulationInput(dir, "d1")
+	assert.Error(err)
+}
+
+func TestSimulationOptionsValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := SimulationOptions{}
+	assert.NoError(opts.Validate())
+	assert.Equal("utilization", opts.HostAllocator)
+	assert.Equal(defaultSimulationInterval, opts.Interval)
+	assert.Equal(defaultSimulationIdleTimeout, opts.IdleTimeout)
+
+	for _, opts := range []SimulationOptions{
+		{HostAllocator: "random"},
+		{TaskPrioritizer: "random"},
+		{PoolSize: -1},
+		{FreeHostFraction: 2},
+		{CostPerHostHour: -1},
+		{Start: time.Now(), End: time.Now().Add(-time.Hour)},
+		{Interval: -time.Second},
+	} {
+		assert.Error(opts.Validate())
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	d := distro.Distro{Id: "d", Provider: evergreen.ProviderNameEc2Auto, PoolSize: 10}
+	input := &SimulationInput{
+		Distro: d,
+		Tasks: []task.Task{
+			{Id: "t1", DistroId: "d", ActivatedTime: start, StartTime: start.Add(time.Minute), TimeTaken: 10 * time.Minute, Status: evergreen.TaskFailed},
+			{Id: "t2", DistroId: "d", ActivatedTime: start.Add(time.Minute), DependsOn: []task.Dependency{{TaskId: "t1"}, {TaskId: "other"}}, ExpectedDuration: 5 * time.Minute},
+			{Id: "t3", DistroId: "other", ActivatedTime: start},
+			{Id: "t4", DistroId: "d", ActivatedTime: start, DisplayOnly: true},
+		},
+		Hosts: []host.Host{
+			{Id: "h1", Distro: d, CreationTime: start.Add(-time.Hour)},
+			{Id: "h2", Distro: d, CreationTime: start.Add(-time.Hour), TerminationTime: start.Add(-time.Minute)},
+			{Id: "h3", Distro: d, CreationTime: start.Add(time.Hour)},
+		},
+	}
+	opts := SimulationOptions{}
+	require.NoError(opts.Validate())
+
+	sim, err := newSimulation(d, input, opts)
+	require.NoError(err)
+	require.Len(sim.tasks, 2)
+	assert.Equal(start, sim.start)
+	assert.Equal(10*time.Minute, sim.byID["t1"].runtime)
+	assert.Equal(5*time.Minute, sim.byID["t2"].runtime)
+	require.Len(sim.hosts, 1)
+	assert.Equal("h1", sim.hosts[0].host.Id)
+
+	// only t1 has arrived
+	sim.arrive(start)
+	runnable := sim.runnableTasks()
+	require.Len(runnable, 1)
+	assert.Equal("t1", runnable[0].Id)
+
+	// t2 waits for t1
+	now := start.Add(time.Minute)
+	sim.arrive(now)
+	assert.Len(sim.runnableTasks(), 1)
+
+	sim.addHosts(1, now)
+	assert.Len(sim.activeHosts(), 2)
+
+	started := sim.dispatch([]model.TaskQueueItem{{Id: "t1"}}, now)
+	require.Len(started, 1)
+	assert.Equal(now.Add(10*time.Minute), started[0].end)
+	assert.Equal("t1", sim.hosts[0].host.RunningTask)
+	assert.Empty(sim.runnableTasks())
+	assert.False(sim.done())
+
+	// the new host is ready but is terminated once it is idle too long
+	now = now.Add(opts.HostStartupTime)
+	sim.readyHosts(now)
+	assert.Equal(evergreen.HostRunning, sim.hosts[1].host.Status)
+	now = now.Add(opts.IdleTimeout)
+	sim.terminateIdleHosts(now)
+	assert.True(sim.hosts[1].isTerminated())
+	assert.Len(sim.activeHosts(), 1)
+
+	now = start.Add(11 * time.Minute)
+	finished := sim.finishTasks(now)
+	require.Len(finished, 1)
+	assert.Equal(evergreen.TaskFailed, finished[0].status)
+	assert.Empty(sim.hosts[0].host.RunningTask)
+	runnable = sim.runnableTasks()
+	require.Len(runnable, 1)
+	assert.Equal("t2", runnable[0].Id)
+
+	sim.dispatch([]model.TaskQueueItem{{Id: "t2"}}, now)
+	now = now.Add(5 * time.Minute)
+	sim.finishTasks(now)
+	assert.True(sim.done())
+
+	report := sim.report(input, now)
+	assert.Equal(2, report.Simulated.Tasks)
+	assert.Equal(1, report.Simulated.HostsStarted)
+	assert.Equal(2, report.Simulated.MaxHosts)
+	assert.Equal(60.0, report.Simulated.WaitP50Secs)
+	assert.Equal(600.0, report.Simulated.WaitMaxSecs)
+	assert.Equal(1, report.Recorded.Tasks)
+	assert.Equal(1, report.Recorded.UnstartedTasks)
+	assert.Equal(1, report.Recorded.MaxHosts)
+}
+
+func TestSimulationStats(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := SimulationStats{}
+	waits := []time.Duration{}
+	for i := 10; i > 0; i-- {
+		waits = append(waits, time.Duration(i)*time.Second)
+	}
+	stats.setWaits(waits, 12)
+	assert.Equal(10, stats.Tasks)
+	assert.Equal(2, stats.UnstartedTasks)
+	assert.Equal(5.5, stats.WaitMeanSecs)
+	assert.Equal(5.0, stats.WaitP50Secs)
+	assert.Equal(9.0, stats.WaitP90Secs)
+	assert.Equal(10.0, stats.WaitP99Secs)
+	assert.Equal(10.0, stats.WaitMaxSecs)
+
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	stats.setHosts([]hostSpan{
+		{from: start.Add(-time.Hour), to: start.Add(time.Hour)},
+		{from: start.Add(time.Hour), to: start.Add(2 * time.Hour)},
+		{from: start.Add(90 * time.Minute)},
+		{from: end.Add(time.Hour)},
+	}, start, end, 2)
+	assert.Equal(4.5, stats.HostHours)
+	assert.Equal(9.0, stats.Cost)
+	assert.Equal(2, stats.MaxHosts)
+}
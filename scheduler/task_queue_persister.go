@@ -21,7 +21,7 @@ type DBTaskQueuePersister struct{}
 func (self *DBTaskQueuePersister) PersistTaskQueue(distro string, tasks []task.Task) ([]model.TaskQueueItem, error) {
 	taskQueue := make([]model.TaskQueueItem, 0, len(tasks))
 	for _, t := range tasks {
-		taskQueue = append(taskQueue, model.TaskQueueItem{
+		item := model.TaskQueueItem{
 			Id:                  t.Id,
 			DisplayName:         t.DisplayName,
 			BuildVariant:        t.BuildVariant,
@@ -34,8 +34,12 @@ func (self *DBTaskQueuePersister) PersistTaskQueue(distro string, tasks []task.T
 			Group:               t.TaskGroup,
 			GroupMaxHosts:       t.TaskGroupMaxHosts,
 			Version:             t.Version,
-		})
-
+			ResourceRequests:    t.ResourceRequests,
+		}
+		if distros := t.Distros(); len(distros) > 1 {
+			item.Distros = distros
+		}
+		taskQueue = append(taskQueue, item)
 	}
 
 	queue := model.NewTaskQueue(distro, taskQueue)
@@ -44,7 +44,8 @@ func UtilizationBasedHostAllocator(ctx context.Context, hostAllocatorData HostAl
 			hostAllocatorData.freeHostFraction,
 			hostAllocatorData.usesContainers,
 			hostAllocatorData.containerPool,
-			maxHosts)
+			maxHosts,
+			hostAllocatorData.currentTime())
 
 		if err != nil {
 			return 0, errors.Wrapf(err, "error calculating hosts for distro %s", hostAllocatorData.distro.Id)
@@ -121,7 +122,7 @@ func makeTaskGroupString(group, bv, project, version string) string {
 // and dividing it by the target duration. Request however many hosts are needed to
 // achieve that minus the number of free hosts
 func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model.TaskQueueItem, existingHosts []host.Host,
-	freeHostFraction float64, usesContainers bool, containerPool *evergreen.ContainerPool, maxHosts int) (int, error) {
+	freeHostFraction float64, usesContainers bool, containerPool *evergreen.ContainerPool, maxHosts int, now time.Time) (int, error) {
 
 	if !d.IsEphemeral() {
 		return 0, nil
@@ -149,7 +150,7 @@ func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model
 	scheduledTasksDuration := calcScheduledTasksDuration(newTaskQueue)
 
 	// determine how many free hosts we have that are already up
-	numFreeHosts, err := calcExistingFreeHosts(existingHosts, freeHostFraction, maxDuration)
+	numFreeHosts, err := calcExistingFreeHosts(existingHosts, freeHostFraction, maxDuration, now)
 	if err != nil {
 		return numNewHosts, err
 	}
@@ -158,11 +159,11 @@ func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model
 	numNewHosts = calcNewHostsNeeded(scheduledTasksDuration, maxDuration, numFreeHosts, hostsForLongTasks)
 
 	// calculate the same values for 0 and 1 values of the fraction (just for reporting purposes)
-	freeHostsIfZero, err := calcExistingFreeHosts(existingHosts, 0, maxDuration)
+	freeHostsIfZero, err := calcExistingFreeHosts(existingHosts, 0, maxDuration, now)
 	if err != nil {
 		return numNewHosts, err
 	}
-	freeHostsIfOne, err := calcExistingFreeHosts(existingHosts, 1, maxDuration)
+	freeHostsIfOne, err := calcExistingFreeHosts(existingHosts, 1, maxDuration, now)
 	if err != nil {
 		return numNewHosts, err
 	}
@@ -170,8 +171,8 @@ func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model
 	newHostsIfOne := calcNewHostsNeeded(scheduledTasksDuration, maxDuration, freeHostsIfOne, hostsForLongTasks)
 
 	// don't start more hosts than new tasks. This can happen if the task queue is mostly long tasks
-	if numNewHosts > len(taskQueue) {
-		numNewHosts = len(taskQueue)
+	if demand := queueDemand(taskQueue); numNewHosts > demand {
+		numNewHosts = demand
 	}
 
 	// enforce the max hosts cap
@@ -234,11 +235,21 @@ func calcScheduledTasksDuration(queue []model.TaskQueueItem) time.Duration {
 	var scheduledTasksDuration time.Duration
 
 	for _, taskQueueItem := range queue {
-		scheduledTasksDuration += taskQueueItem.ExpectedDuration
+		scheduledTasksDuration += time.Duration(float64(taskQueueItem.ExpectedDuration) * taskQueueItem.DemandShare())
 	}
 	return scheduledTasksDuration
 }
 
+// queueDemand returns the number of tasks that a queue accounts for, where a
+// task that is queued on several distros counts as a share of a task on each.
+func queueDemand(queue []model.TaskQueueItem) int {
+	demand := 0.0
+	for _, taskQueueItem := range queue {
+		demand += taskQueueItem.DemandShare()
+	}
+	return int(math.Ceil(demand))
+}
+
 // calcNewHostsNeeded returns the number of new hosts needed based
 // on a heuristic that utilizes the total duration scheduled tasks,
 // targeting a maximum average duration for each task on all hosts
@@ -268,7 +279,7 @@ func calcNewHostsNeeded(scheduledDuration, maxDurationPerHost time.Duration, num
 
 // calcExistingFreeHosts returns the number of hosts that are not running a task,
 // plus hosts that will soon be free scaled by some fraction
-func calcExistingFreeHosts(existingHosts []host.Host, freeHostFactor float64, maxDurationPerHost time.Duration) (int, error) {
+func calcExistingFreeHosts(existingHosts []host.Host, freeHostFactor float64, maxDurationPerHost time.Duration, now time.Time) (int, error) {
 	numFreeHosts := 0
 	if freeHostFactor > 1 {
 		return numFreeHosts, errors.New("free host factor cannot be greater than 1")
@@ -280,7 +291,7 @@ func calcExistingFreeHosts(existingHosts []host.Host, freeHostFactor float64, ma
 		}
 	}
 
-	soonToBeFree, err := getSoonToBeFreeHosts(existingHosts, freeHostFactor, maxDurationPerHost)
+	soonToBeFree, err := getSoonToBeFreeHosts(existingHosts, freeHostFactor, maxDurationPerHost, now)
 	if err != nil {
 		return 0, err
 	}
@@ -292,7 +303,7 @@ func calcExistingFreeHosts(existingHosts []host.Host, freeHostFactor float64, ma
 // to be free for some fraction of the next maxDurationPerHost interval
 // the final value is scaled by some fraction representing how confident we are that
 // the hosts will actually be free in the expected amount of time
-func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, maxDurationPerHost time.Duration) (float64, error) {
+func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, maxDurationPerHost time.Duration, now time.Time) (float64, error) {
 	var freeHosts float64
 	runningTaskIds := []string{}
 
@@ -313,7 +324,7 @@ func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, max
 
 	for _, t := range runningTasks {
 		expectedDuration := t.FetchExpectedDuration()
-		elapsedTime := time.Since(t.StartTime)
+		elapsedTime := now.Sub(t.StartTime)
 		timeLeft := expectedDuration - elapsedTime
 
 		// calculate what fraction of the host will be free within the max duration.
@@ -336,16 +347,16 @@ func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, max
 // 3x as long as the max duration doesn't get 3 hosts allocated for it
 func calcHostsForLongTasks(queue []model.TaskQueueItem, maxDurationPerHost time.Duration) ([]model.TaskQueueItem, int) {
 	newQueue := []model.TaskQueueItem{}
-	numRemoved := 0
+	removed := []model.TaskQueueItem{}
 	for _, queueItem := range queue {
 		if queueItem.ExpectedDuration >= maxDurationPerHost {
-			numRemoved++
+			removed = append(removed, queueItem)
 		} else {
 			newQueue = append(newQueue, queueItem)
 		}
 	}
 
-	return newQueue, numRemoved
+	return newQueue, queueDemand(removed)
 }
 
 // isMaxHostsCapacity returns true if the max number of containers are already running
@@ -113,6 +113,28 @@ func (s *UtilizationAllocatorSuite) TestCalcScheduledTasksDuration() {
 		},
 	}
 	s.Equal(140*time.Minute, calcScheduledTasksDuration(queue))
+
+	// a task queued on two distros counts half of its duration
+	queue = append(queue, model.TaskQueueItem{
+		ExpectedDuration: time.Hour,
+		Distros:          []string{"d1", "d2"},
+	})
+	s.Equal(170*time.Minute, calcScheduledTasksDuration(queue))
+}
+
+func (s *UtilizationAllocatorSuite) TestQueueDemand() {
+	s.Equal(0, queueDemand(nil))
+	s.Equal(2, queueDemand([]model.TaskQueueItem{{Id: "t1"}, {Id: "t2"}}))
+
+	shared := []model.TaskQueueItem{
+		{Id: "t1"},
+		{Id: "t2", Distros: []string{"d1", "d2"}},
+		{Id: "t3", Distros: []string{"d1", "d2", "d3"}},
+		{Id: "t4", Distros: []string{"d1", "d2", "d3"}},
+		{Id: "t5", Distros: []string{"d1", "d2", "d3"}},
+	}
+	s.Equal(3, queueDemand(shared))
+	s.Equal(1, queueDemand(shared[1:3]))
 }
 
 func (s *UtilizationAllocatorSuite) TestCalcNewHostsNeeded() {
@@ -172,7 +194,7 @@ func (s *UtilizationAllocatorSuite) TestCalcExistingFreeHosts() {
 	}
 	s.NoError(t3.Insert())
 
-	freeHosts, err := calcExistingFreeHosts([]host.Host{h1, h2, h3, h4, h5}, 1, 30*time.Minute)
+	freeHosts, err := calcExistingFreeHosts([]host.Host{h1, h2, h3, h4, h5}, 1, 30*time.Minute, time.Now())
 	s.NoError(err)
 	s.Equal(3, freeHosts)
 }
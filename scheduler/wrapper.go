@@ -60,9 +60,7 @@ func PlanDistro(ctx context.Context, conf Configuration, s *evergreen.Settings)
 	}
 
 	ds := &distroSchedueler{
-		TaskPrioritizer: &CmpBasedTaskPrioritizer{
-			runtimeID: schedulerInstance,
-		},
+		TaskPrioritizer:    GetTaskPrioritizer(distroSpec, schedulerInstance),
 		TaskQueuePersister: &DBTaskQueuePersister{},
 		runtimeID:          schedulerInstance,
 	}
@@ -133,7 +131,11 @@ func PlanDistro(ctx context.Context, conf Configuration, s *evergreen.Settings)
 	})
 
 	startHostSpawning := time.Now()
-	hostsSpawned, err := spawnHosts(ctx, distroSpec, newHosts, pool)
+	var requests []evergreen.ContainerResources
+	if pool != nil {
+		requests = containerRequests(res.taskQueueItem, countFreeHosts(distroHosts), newHosts)
+	}
+	hostsSpawned, err := spawnHosts(ctx, distroSpec, newHosts, pool, requests)
 	if err != nil {
 		return errors.Wrap(err, "Error spawning new hosts")
 	}
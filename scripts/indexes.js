@@ -140,3 +140,8 @@ db.users.ensureIndex({ "settings.github_user.uid": 1 }, { unique: true })
 
 //======notifications======//
 db.notifications.ensureIndex({ "sent_at": 1 })
+
+//======perf_results======//
+db.perf_results.ensureIndex({ "info.task_id": 1, "info.execution": 1 })
+db.perf_results.ensureIndex({ "info.project": 1, "info.variant": 1, "info.task_name": 1, "test": 1, "info.order": 1 })
+db.perf_results.ensureIndex({ "info.project": 1, "created_at": 1 })
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
@@ -292,9 +293,42 @@ func (as *APIServer) AttachFiles(w http.ResponseWriter, r *http.Request) {
 		gimlet.WriteJSONInternalError(w, message)
 		return
 	}
+
+	if err := signArtifacts(&as.Settings.ArtifactSigning, entry); err != nil {
+		message := fmt.Sprintf("Error signing artifacts for task %v: %v", t.Id, err)
+		grip.Error(message)
+		gimlet.WriteJSONInternalError(w, message)
+		return
+	}
 	gimlet.WriteJSON(w, fmt.Sprintf("Artifact files for task %v successfully attached", t.Id))
 }
 
+// signArtifacts re-signs the manifest of all files attached by the task,
+// if the server has a signing key.
+func signArtifacts(conf *evergreen.ArtifactSigningConfig, entry *artifact.Entry) error {
+	key, err := conf.SigningKey()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if key == nil {
+		return nil
+	}
+
+	stored, err := artifact.FindOne(artifact.ByTaskIdAndExecution(entry.TaskId, entry.Execution))
+	if err != nil {
+		return errors.Wrap(err, "problem finding attached files")
+	}
+	if stored == nil {
+		return errors.New("attached files not found")
+	}
+
+	signature, err := stored.Sign(key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(stored.SetSignature(signature))
+}
+
 // AppendTaskLog appends the received logs to the task's internal logs.
 func (as *APIServer) AppendTaskLog(w http.ResponseWriter, r *http.Request) {
 	if as.GetSettings().ServiceFlags.TaskLoggingDisabled {
@@ -318,6 +352,8 @@ func (as *APIServer) AppendTaskLog(w http.ResponseWriter, r *http.Request) {
 
 	taskLog.TaskId = t.Id
 	taskLog.Execution = t.Execution
+	// log streams count lines by document to resume without reading them
+	taskLog.MessageCount = len(taskLog.Messages)
 
 	if err := taskLog.Insert(); err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
@@ -342,6 +378,9 @@ func (as *APIServer) Heartbeat(w http.ResponseWriter, r *http.Request) {
 	if t.Aborted {
 		grip.Noticef("Sending abort signal for task %s", t.Id)
 		heartbeatResponse.Abort = true
+	} else if t.Status == evergreen.TaskPaused && time.Since(t.PausedUntil) > pauseGracePeriod {
+		grip.Noticef("Sending abort signal for task %s, which has been paused since %s", t.Id, t.PausedUntil)
+		heartbeatResponse.Abort = true
 	}
 
 	if err := t.UpdateHeartbeat(); err != nil {
@@ -576,6 +615,11 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.Route().Version(2).Route("/task/{taskId}/start").Wrap(checkTaskSecret, checkHost).Handler(as.StartTask).Post()
 	app.Route().Version(2).Route("/task/{taskId}/log").Wrap(checkTaskSecret, checkHost).Handler(as.AppendTaskLog).Post()
 	app.Route().Version(2).Route("/task/{taskId}/").Wrap(checkTaskSecret).Handler(as.FetchTask).Get()
+	app.Route().Version(2).Route("/task/{taskId}/debug").Wrap(checkTaskSecret, checkHost).Handler(as.debugSession).Get()
+	app.Route().Version(2).Route("/task/{taskId}/debug/{session_id}").Wrap(checkTaskSecret, checkHost).Handler(as.debugSessionExchange).Post()
+	app.Route().Version(2).Route("/task/{taskId}/pause").Wrap(checkTaskSecret, checkHost).Handler(as.pauseTask).Post()
+	app.Route().Version(2).Route("/task/{taskId}/pause").Wrap(checkTaskSecret, checkHost).Handler(as.taskPauseState).Get()
+	app.Route().Version(2).Route("/task/{taskId}/pause").Wrap(checkTaskSecret, checkHost).Handler(as.unpauseTask).Delete()
 	app.Route().Version(2).Route("/task/{taskId}/fetch_vars").Wrap(checkTaskSecret).Handler(as.FetchProjectVars).Get()
 	app.Route().Version(2).Route("/task/{taskId}/heartbeat").Wrap(checkTaskSecret, checkHost).Handler(as.Heartbeat).Post()
 	app.Route().Version(2).Route("/task/{taskId}/results").Wrap(checkTaskSecret, checkHost).Handler(as.AttachResults).Post()
@@ -591,12 +635,14 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/git/patchfile/{patchfile_id}").Wrap(checkTask).Handler(as.gitServePatchFile).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/git/patch").Wrap(checkTask).Handler(as.gitServePatch).Get()
+	app.Route().Version(2).Prefix("/task/{taskId}").Route("/cache/event").Wrap(checkTask).Handler(as.cacheEvent).Post()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/keyval/inc").Wrap(checkTask).Handler(as.keyValPluginInc).Post()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/manifest/load").Wrap(checkTask).Handler(as.manifestLoadHandler).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/s3Copy/s3Copy").Wrap(checkTask).Handler(as.s3copyPlugin).Post()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/tags/{task_name}/{name}").Wrap(checkTask).Handler(as.getTaskJSONTagsForTask).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/history/{task_name}/{name}").Wrap(checkTask).Handler(as.getTaskJSONTaskHistory).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/data/{name}").Wrap(checkTask).Handler(as.insertTaskJSON).Post()
+	app.Route().Version(2).Prefix("/task/{taskId}").Route("/perf").Wrap(checkTask).Handler(as.insertPerfResults).Post()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/data/{task_name}/{name}").Wrap(checkTask).Handler(as.getTaskJSONByName).Get()
 	app.Route().Version(2).Prefix("/task/{taskId}").Route("/json/data/{task_name}/{name}/{variant}").Wrap(checkTask).Handler(as.getTaskJSONForVariant).Get()
 
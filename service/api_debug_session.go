@@ -0,0 +1,94 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/debugsession"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// maxDebugInputChunks bounds the input sent to the agent in one exchange.
+const maxDebugInputChunks = 100
+
+// debugSession returns the debug session, if any, that a user has opened into
+// the agent's current task.
+func (as *APIServer) debugSession(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	s, err := debugsession.FindOne(debugsession.OpenForTask(t.Id, t.Execution))
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := apimodels.DebugSession{}
+	if s != nil {
+		resp.ID = s.ID
+		resp.User = s.User
+	}
+	gimlet.WriteJSON(w, resp)
+}
+
+// debugSessionExchange records a debug session's shell output and returns
+// the input typed since the agent's last acknowledgment. The first exchange
+// marks the session active.
+func (as *APIServer) debugSessionExchange(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+	id := gimlet.GetVars(r)["session_id"]
+
+	req := &apimodels.DebugExchangeRequest{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), req); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	s, err := debugsession.FindOne(debugsession.ById(id))
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if s == nil || s.TaskID != t.Id || s.Execution != t.Execution {
+		as.LoggedError(w, r, http.StatusNotFound, errors.Errorf("no debug session '%s' for task '%s'", id, t.Id))
+		return
+	}
+
+	if s.Status == debugsession.StatusRequested {
+		if err = s.Start(); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if s.IsOpen() {
+		for _, out := range req.Output {
+			if _, err = s.Append(debugsession.StreamOutput, out); err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+	}
+
+	if req.Closed {
+		if err = s.Close(req.Reason); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		gimlet.WriteJSON(w, apimodels.DebugExchangeResponse{Closed: true})
+		return
+	}
+
+	chunks, err := debugsession.FindChunks(s.ID, debugsession.StreamInput, req.InputAck, maxDebugInputChunks)
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := apimodels.DebugExchangeResponse{Closed: !s.IsOpen()}
+	for _, c := range chunks {
+		resp.Input = append(resp.Input, apimodels.DebugChunk{Seq: c.Seq, Data: c.Data})
+	}
+	gimlet.WriteJSON(w, resp)
+}
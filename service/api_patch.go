@@ -44,6 +44,8 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		Tasks       []string `json:"tasks"`
 		Finalize    bool     `json:"finalize"`
 		Alias       string   `json:"alias"`
+		// PauseOnFailure holds the patch's failed tasks for inspection
+		PauseOnFailure bool `json:"pause_on_failure"`
 	}{}
 	if err := util.ReadJSONInto(util.NewRequestReaderWithSize(r, patch.SizeLimit), &data); err != nil {
 		as.LoggedError(w, r, http.StatusBadRequest, err)
@@ -99,6 +101,12 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		as.LoggedError(w, r, http.StatusInternalServerError, errors.New("patch couldn't be found"))
 		return
 	}
+	if data.PauseOnFailure {
+		if err = patchDoc.SetPauseOnFailure(true); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "can't set pause on failure"))
+			return
+		}
+	}
 
 	gimlet.WriteJSONResponse(w, http.StatusCreated, PatchAPIResponse{Patch: patchDoc})
 }
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// insertPerfResults stores the results that a task sent with perf.send,
+// attributed to the task's current execution.
+func (as *APIServer) insertPerfResults(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	results := []perf.Result{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &results); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	info := perf.ResultInfo{
+		Project:   t.Project,
+		Variant:   t.BuildVariant,
+		TaskName:  t.DisplayName,
+		TaskID:    t.Id,
+		Execution: t.Execution,
+		VersionID: t.Version,
+		Revision:  t.Revision,
+		Order:     t.RevisionOrderNumber,
+		IsPatch:   evergreen.IsPatchRequester(t.Requester),
+	}
+	now := time.Now()
+	for i := range results {
+		if err := results[i].Validate(); err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, errors.Wrap(err, "invalid perf result"))
+			return
+		}
+		results[i].SetInfo(info)
+		results[i].CreatedAt = now
+	}
+
+	for i := range results {
+		if err := results[i].Save(); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	gimlet.WriteJSON(w, "ok")
+}
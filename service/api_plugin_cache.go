@@ -0,0 +1,100 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// cacheEvent records the outcome of a cache.save or cache.restore
+// command: saves create or replace an entry that the eviction job can
+// later remove, skipped saves and restores mark the entry as used, and
+// restores update the project's hit/miss counters.
+func (as *APIServer) cacheEvent(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	event := &apimodels.CacheEvent{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), event); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	catcher := grip.NewSimpleCatcher()
+	conf := as.Settings.BuildCache
+
+	switch event.Operation {
+	case apimodels.CacheOperationSave:
+		if err := buildcache.ValidateKey(event.MatchedKey); err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if event.Exists {
+			catcher.Add(touchCacheEntry(t.Project, event))
+			break
+		}
+
+		// objects outside of the configured bucket are not tracked,
+		// since the eviction job could not delete them anyway
+		if conf.Bucket != "" && event.Bucket == conf.Bucket {
+			entry := &buildcache.Entry{
+				Project:      t.Project,
+				Key:          event.MatchedKey,
+				Bucket:       event.Bucket,
+				RemotePath:   buildcache.RemotePath(t.Project, event.MatchedKey),
+				SizeBytes:    event.SizeBytes,
+				CreatedAt:    now,
+				LastAccessed: now,
+			}
+			catcher.Add(entry.Upsert())
+		}
+		catcher.Add(buildcache.RecordSave(t.Project))
+	case apimodels.CacheOperationRestore:
+		switch event.MatchedKey {
+		case "":
+			catcher.Add(buildcache.RecordMiss(t.Project))
+		case event.Key:
+			catcher.Add(buildcache.RecordHit(t.Project))
+		default:
+			catcher.Add(buildcache.RecordPartialHit(t.Project))
+		}
+
+		if event.MatchedKey != "" {
+			catcher.Add(touchCacheEntry(t.Project, event))
+		}
+	default:
+		as.LoggedError(w, r, http.StatusBadRequest,
+			errors.Errorf("unknown cache operation '%s'", event.Operation))
+		return
+	}
+
+	if catcher.HasErrors() {
+		as.LoggedError(w, r, http.StatusInternalServerError,
+			errors.Wrapf(catcher.Resolve(), "problem recording cache event for task %s", t.Id))
+		return
+	}
+
+	gimlet.WriteJSON(w, struct{}{})
+}
+
+// touchCacheEntry marks the entry that a cache event used as recently
+// used, if the entry is tracked in the bucket that the task used.
+func touchCacheEntry(project string, event *apimodels.CacheEvent) error {
+	entry, err := buildcache.FindOne(db.Query(bson.M{
+		buildcache.IDKey:     buildcache.EntryID(project, event.MatchedKey),
+		buildcache.BucketKey: event.Bucket,
+	}))
+	if err != nil || entry == nil {
+		return err
+	}
+	return entry.Touch(time.Now())
+}
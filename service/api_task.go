@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/debugsession"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -164,6 +166,11 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		as.LoggedError(w, r, http.StatusInternalServerError, message)
 		return
 	}
+	grip.Warning(message.WrapError(debugsession.CloseForTask(t.Id, t.Execution, "task finished"), message.Fields{
+		"message":   "problem closing debug session",
+		"task_id":   t.Id,
+		"execution": t.Execution,
+	}))
 
 	// the task was aborted if it is still in undispatched.
 	// the active state should be inactive.
@@ -194,6 +201,21 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if updates.VersionComplete {
+		grip.Error(message.WrapError(as.queue.Put(units.NewVersionProvenanceJob(t.Version)), message.Fields{
+			"message": "couldn't queue job to generate version provenance",
+			"version": t.Version,
+			"task":    t.Id,
+		}))
+	}
+	if updates.BuildComplete && projectRef.GithubChecksEnabled {
+		grip.Error(message.WrapError(as.queue.Put(units.NewGithubCheckRunsJob(t.BuildId)), message.Fields{
+			"message": "couldn't queue job to create github check runs",
+			"build":   t.BuildId,
+			"task":    t.Id,
+		}))
+	}
+
 	// update the bookkeeping entry for the task
 	err = task.UpdateExpectedDuration(t, t.TimeTaken)
 	if err != nil {
@@ -211,9 +233,24 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// we should disable hosts and prevent them from performing
-	// more work if they appear to be in a bad state
-	// (e.g. encountered 5 consecutive system failures)
-	if event.AllRecentHostEventsMatchStatus(currentHost.Id, consecutiveSystemFailureThreshold, evergreen.TaskSystemFailed) {
+	// more work if they appear to be in a bad state (e.g. ran out of disk
+	// space, or encountered 5 consecutive system failures)
+	if details.OutOfDisk {
+		msg := "host ran out of disk space"
+		err := currentHost.DisablePoisonedHost(msg)
+		job := units.NewDecoHostNotifyJob(evergreen.GetEnvironment(), currentHost, err, msg)
+		grip.Critical(message.WrapError(as.queue.Put(job),
+			message.Fields{
+				"host_id": currentHost.Id,
+				"task_id": t.Id,
+			}))
+
+		if err != nil {
+			gimlet.WriteJSONInternalError(w, err)
+			return
+		}
+		endTaskResp.ShouldExit = true
+	} else if event.AllRecentHostEventsMatchStatus(currentHost.Id, consecutiveSystemFailureThreshold, evergreen.TaskSystemFailed) {
 		msg := "host encountered consecutive system failures"
 		if currentHost.Provider != evergreen.ProviderNameStatic {
 			err := currentHost.DisablePoisonedHost(msg)
@@ -272,6 +309,9 @@ func assignNextAvailableTask(taskQueue *model.TaskQueue, currentHost *host.Host)
 			})
 		}
 	}
+	// A container only runs tasks that fit in the resources it was
+	// started with.
+	spec.Resources = currentHost.ContainerResources
 
 	// This loop does the following:
 	// 1. Find the next task in the queue.
@@ -363,11 +403,41 @@ func assignNextAvailableTask(taskQueue *model.TaskQueue, currentHost *host.Host)
 			continue
 		}
 
+		// A task that can run on several distros is queued on each of them,
+		// so take it off the other queues now that this host has claimed it.
+		otherDistros := []string{}
+		for _, d := range nextTask.Distros() {
+			if d != taskQueue.Distro {
+				otherDistros = append(otherDistros, d)
+			}
+		}
+		grip.Warning(message.WrapError(model.DequeueTaskFromDistros(nextTask.Id, otherDistros), message.Fields{
+			"message": "problem pulling task from the queues of its other distros",
+			"task_id": nextTask.Id,
+			"distros": otherDistros,
+			"host":    currentHost.Id,
+		}))
+
+		recordDispatchSpan(nextTask, currentHost)
 		return nextTask, nil
 	}
 	return nil, nil
 }
 
+// recordDispatchSpan adds a span covering the time a task waited to be
+// dispatched to the task's trace.
+func recordDispatchSpan(t *task.Task, h *host.Host) {
+	_, span := util.GetTracer().StartFromTraceParent(context.Background(), t.TraceParent, "dispatch task", map[string]string{
+		"task":   t.Id,
+		"host":   h.Id,
+		"distro": t.DistroId,
+	})
+	if !util.IsZeroTime(t.ActivatedTime) {
+		span.SetStartTime(t.ActivatedTime)
+	}
+	span.End(nil)
+}
+
 // NextTask retrieves the next task's id given the host name and host secret by retrieving the task queue
 // and popping the next task off the task queue.
 func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+)
+
+// pauseGracePeriod is how long after its pause runs out a task may stay
+// paused before heartbeats tell its agent to abort it.
+const pauseGracePeriod = 5 * time.Minute
+
+// pauseTask decides whether a task whose command failed is held for
+// inspection, and if so marks it paused. Tasks are paused when the project
+// configuration asks for it, unless a patch task's patch says otherwise.
+func (as *APIServer) pauseTask(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	req := &apimodels.TaskPauseRequest{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), req); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	pause := req.Configured
+	if evergreen.IsPatchRequester(t.Requester) {
+		p, err := patch.FindOne(patch.ByVersion(t.Version))
+		if err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if p != nil && p.PauseOnFailure != nil {
+			pause = *p.PauseOnFailure
+		}
+	}
+	if !pause || t.Aborted {
+		gimlet.WriteJSON(w, apimodels.TaskPauseResponse{})
+		return
+	}
+
+	timeout := time.Duration(req.TimeoutSecs) * time.Second
+	if timeout <= 0 {
+		timeout = model.DefaultPauseTimeout
+	} else if timeout > model.MaxPauseTimeout {
+		timeout = model.MaxPauseTimeout
+	}
+
+	until := time.Now().Add(timeout)
+	if err := t.Pause(until); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	event.LogTaskPaused(t.Id, t.Execution, t.HostId, fmt.Sprintf("command '%s' failed", req.FailedCommand), until)
+
+	gimlet.WriteJSON(w, apimodels.TaskPauseResponse{Paused: true, Until: until})
+}
+
+// taskPauseState tells the agent whether its task is still paused. An
+// aborted task is reported as no longer paused so that the agent ends it.
+func (as *APIServer) taskPauseState(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	resp := apimodels.TaskPauseResponse{}
+	if t.Status == evergreen.TaskPaused && !t.Aborted {
+		resp.Paused = true
+		resp.Until = t.PausedUntil
+	}
+	gimlet.WriteJSON(w, resp)
+}
+
+// unpauseTask is called by the agent when a task's pause runs out.
+func (as *APIServer) unpauseTask(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	if t.Status != evergreen.TaskPaused {
+		gimlet.WriteJSON(w, apimodels.TaskPauseResponse{})
+		return
+	}
+	if err := t.Resume(); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	event.LogTaskResumed(t.Id, t.Execution, evergreen.User)
+
+	gimlet.WriteJSON(w, apimodels.TaskPauseResponse{})
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// GetMetricsApp returns an app that serves the metrics published by the
+// stats collectors at /metrics, in the OpenMetrics text format. The
+// metrics are read from the database when scraped, so that every app
+// server serves the metrics of every collector.
+func GetMetricsApp() *gimlet.APIApp {
+	app := gimlet.NewApp()
+	app.NoVersions = true
+	app.AddRoute("/metrics").Handler(serveMetrics).Get()
+
+	return app
+}
+
+func serveMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics, err := model.FindStatsMetrics(time.Now())
+	if err != nil {
+		gimlet.WriteTextInternalError(w, errors.WithStack(err).Error())
+		return
+	}
+	metrics.ServeHTTP(w, r)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsApp(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	require.NoError(db.Clear(model.StatsMetricsCollection))
+	defer func() {
+		assert.NoError(db.Clear(model.StatsMetricsCollection))
+	}()
+
+	now := time.Now()
+	for collector, value := range map[string]float64{"current": 42, "stale": 1} {
+		metrics := util.NewMetricsRegistry()
+		metrics.SetGauge("evergreen_test_metric", "a metric set by a test", map[string]string{"collector": collector}, value)
+		collectedAt := now
+		if collector == "stale" {
+			collectedAt = now.Add(-2 * time.Hour)
+		}
+		require.NoError(model.SaveStatsMetrics(collector, metrics.Families(), collectedAt))
+	}
+
+	handler, err := GetMetricsApp().Handler()
+	require.NoError(err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.Equal(util.OpenMetricsContentType, rw.Header().Get("Content-Type"))
+	assert.Contains(rw.Body.String(), `evergreen_test_metric{collector="current"} 42`+"\n")
+	assert.NotContains(rw.Body.String(), "stale")
+	assert.Contains(rw.Body.String(), "# EOF\n")
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/user"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
@@ -174,24 +175,29 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	responseRef := struct {
-		Identifier         string               `json:"id"`
-		DisplayName        string               `json:"display_name"`
-		RemotePath         string               `json:"remote_path"`
-		BatchTime          int                  `json:"batch_time"`
-		DeactivatePrevious bool                 `json:"deactivate_previous"`
-		Branch             string               `json:"branch_name"`
-		ProjVarsMap        map[string]string    `json:"project_vars"`
-		ProjectAliases     []model.ProjectAlias `json:"project_aliases"`
-		DeleteAliases      []string             `json:"delete_aliases"`
-		PrivateVars        map[string]bool      `json:"private_vars"`
-		Enabled            bool                 `json:"enabled"`
-		Private            bool                 `json:"private"`
-		Owner              string               `json:"owner_name"`
-		Repo               string               `json:"repo_name"`
-		Admins             []string             `json:"admins"`
-		TracksPushEvents   bool                 `json:"tracks_push_events"`
-		PRTestingEnabled   bool                 `json:"pr_testing_enabled"`
-		PatchingDisabled   bool                 `json:"patching_disabled"`
+		Identifier         string                   `json:"id"`
+		DisplayName        string                   `json:"display_name"`
+		RemotePath         string                   `json:"remote_path"`
+		BatchTime          int                      `json:"batch_time"`
+		DeactivatePrevious bool                     `json:"deactivate_previous"`
+		Branch             string                   `json:"branch_name"`
+		ProjVarsMap        map[string]string        `json:"project_vars"`
+		ProjectAliases     []model.ProjectAlias     `json:"project_aliases"`
+		DeleteAliases      []string                 `json:"delete_aliases"`
+		PrivateVars        map[string]bool          `json:"private_vars"`
+		Enabled            bool                     `json:"enabled"`
+		Private            bool                     `json:"private"`
+		Owner              string                   `json:"owner_name"`
+		Repo               string                   `json:"repo_name"`
+		Admins             []string                 `json:"admins"`
+		TracksPushEvents   bool                     `json:"tracks_push_events"`
+		PRTestingEnabled   bool                     `json:"pr_testing_enabled"`
+		AbortSuperseded    bool                     `json:"abort_superseded_pr_patches"`
+		GithubChecks       bool                     `json:"github_checks_enabled"`
+		PatchingDisabled   bool                     `json:"patching_disabled"`
+		DebugSessions      bool                     `json:"debug_sessions_enabled"`
+		ArtifactRetention  []artifact.RetentionRule `json:"artifact_retention"`
+		PriorityPolicy     model.PriorityPolicy     `json:"priority_policy"`
 		AlertConfig        map[string][]struct {
 			Provider string                 `json:"provider"`
 			Settings map[string]interface{} `json:"settings"`
@@ -232,6 +238,14 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 			errs = append(errs, fmt.Sprintf("task regex #%d is invalid", i+1))
 		}
 	}
+	for i, rule := range responseRef.ArtifactRetention {
+		if err = rule.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("artifact retention rule #%d is invalid: %s", i+1, err.Error()))
+		}
+	}
+	if err = responseRef.PriorityPolicy.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("priority policy is invalid: %s", err.Error()))
+	}
 	if len(errs) > 0 {
 		errMsg := ""
 		for _, err := range errs {
@@ -270,8 +284,13 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	projectRef.Identifier = id
 	projectRef.TracksPushEvents = responseRef.TracksPushEvents
 	projectRef.PRTestingEnabled = responseRef.PRTestingEnabled
+	projectRef.AbortSupersededPRPatches = responseRef.AbortSuperseded
+	projectRef.GithubChecksEnabled = responseRef.GithubChecks
 	projectRef.PatchingDisabled = responseRef.PatchingDisabled
+	projectRef.DebugSessionsEnabled = responseRef.DebugSessions
 	projectRef.NotifyOnBuildFailure = responseRef.NotifyOnBuildFailure
+	projectRef.ArtifactRetention = responseRef.ArtifactRetention
+	projectRef.PriorityPolicy = responseRef.PriorityPolicy
 
 	projectVars, err := model.FindOneProjectVars(id)
 	if err != nil {
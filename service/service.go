@@ -50,7 +50,7 @@ func GetRouter(as *APIServer, uis *UIServer) (http.Handler, error) {
 	// need/want to access and construct it separately.
 	rest := GetRESTv1App(as)
 
-	route.AttachHandler(rest, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret))
+	route.AttachHandler(rest, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret), []byte(as.Settings.GithubApp.WebhookSecret))
 
 	// Historically all rest interfaces were available in the API
 	// and UI endpoints. While there were no users of restv1 in
@@ -59,7 +59,7 @@ func GetRouter(as *APIServer, uis *UIServer) (http.Handler, error) {
 	// endpoints.
 	apiRestV2 := gimlet.NewApp()
 	apiRestV2.SetPrefix(evergreen.APIRoutePrefix + "/" + evergreen.RestRoutePrefix)
-	route.AttachHandler(apiRestV2, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret))
+	route.AttachHandler(apiRestV2, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret), []byte(as.Settings.GithubApp.WebhookSecret))
 
 	// in the future the following functions will be above this
 	// point, and we'll just have the app, but during the legacy
@@ -68,8 +68,9 @@ func GetRouter(as *APIServer, uis *UIServer) (http.Handler, error) {
 
 	uiService := uis.GetServiceApp()
 	apiService := as.GetServiceApp()
+	metrics := GetMetricsApp()
 
 	// the order that we merge handlers matters here, and we must
 	// define more specific routes before less specific routes.
-	return gimlet.MergeApplications(app, uiService, rest, apiRestV2, apiService)
+	return gimlet.MergeApplications(app, metrics, uiService, rest, apiRestV2, apiService)
 }
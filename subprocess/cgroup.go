@@ -0,0 +1,98 @@
+package subprocess
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const mebibyte = 1024 * 1024
+
+// ResourceLimits bounds the resources that the processes of a task may
+// use. A zero value means no limit.
+type ResourceLimits struct {
+	MemoryMB     int
+	CPUShares    int
+	MaxProcesses int
+	DiskWriteMB  int
+}
+
+// IsZero reports whether no limit is set.
+func (l ResourceLimits) IsZero() bool {
+	return l == ResourceLimits{}
+}
+
+// ResourceUsage is the peak resource usage of the processes that a
+// command started.
+type ResourceUsage struct {
+	PeakMemoryBytes int64
+	PeakProcesses   int64
+	WrittenBytes    int64
+}
+
+func (u ResourceUsage) String() string {
+	return fmt.Sprintf("peak memory %.1f MB, peak processes %d, disk writes %.1f MB",
+		float64(u.PeakMemoryBytes)/mebibyte, u.PeakProcesses, float64(u.WrittenBytes)/mebibyte)
+}
+
+// ResourceLimitError describes a task that exceeded one of its resource
+// limits.
+type ResourceLimitError struct {
+	Command  string
+	Resource string
+	Limit    string
+}
+
+func (e *ResourceLimitError) Error() string {
+	return fmt.Sprintf("command '%s' exceeded the task's %s limit of %s", e.Command, e.Resource, e.Limit)
+}
+
+// cpuSharesToWeight converts cgroups v1 cpu shares, which range from 2 to
+// 262144, into a cgroups v2 cpu weight, which ranges from 1 to 10000.
+func cpuSharesToWeight(shares int) int {
+	if shares < 2 {
+		shares = 2
+	}
+	if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// cgroupName makes a key, such as a task id, safe to use as the name of a
+// cgroup directory.
+func cgroupName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// taskCgroups holds the cgroups of running tasks, by task id, so that
+// TrackProcess can place the processes that commands start into them.
+var taskCgroups = struct {
+	sync.RWMutex
+	groups map[string]*TaskCgroup
+}{groups: map[string]*TaskCgroup{}}
+
+func registerTaskCgroup(key string, cg *TaskCgroup) {
+	taskCgroups.Lock()
+	defer taskCgroups.Unlock()
+	taskCgroups.groups[key] = cg
+}
+
+func unregisterTaskCgroup(key string) {
+	taskCgroups.Lock()
+	defer taskCgroups.Unlock()
+	delete(taskCgroups.groups, key)
+}
+
+func getTaskCgroup(key string) *TaskCgroup {
+	taskCgroups.RLock()
+	defer taskCgroups.RUnlock()
+	return taskCgroups.groups[key]
+}
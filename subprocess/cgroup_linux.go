@@ -0,0 +1,430 @@
+package subprocess
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// The agent enforces resource limits with cgroups v2. Each task gets a
+// cgroup that holds its limits, and each command a child cgroup of the
+// task's, so that usage can be attributed to the command that caused it.
+// Because a cgroup that enables controllers for its children may not
+// contain processes itself, the agent first moves itself into a leaf
+// cgroup next to those of its tasks.
+
+var (
+	cgroupFSRoot   = "/sys/fs/cgroup"
+	procSelfCgroup = "/proc/self/cgroup"
+)
+
+const (
+	agentCgroupName    = "evergreen-agent"
+	cgroupPollInterval = time.Second
+)
+
+// TaskCgroup is the cgroup that limits and accounts for the processes
+// started by a task's commands. A nil TaskCgroup does nothing.
+type TaskCgroup struct {
+	key    string
+	path   string
+	limits ResourceLimits
+
+	mu        sync.Mutex
+	commands  int
+	command   *commandCgroup
+	violation *ResourceLimitError
+	// the limit event counters when the task started, since cgroups
+	// of earlier tasks with the same id may be reused
+	oomKills    int64
+	pidsDenials int64
+}
+
+type commandCgroup struct {
+	name  string
+	path  string
+	usage ResourceUsage
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewTaskCgroup creates a cgroup that enforces limits on the processes of
+// the task identified by key, which TrackProcess then places the processes
+// that commands start into.
+func NewTaskCgroup(key string, limits ResourceLimits) (*TaskCgroup, error) {
+	parent, err := delegatedCgroup()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem setting up cgroups")
+	}
+	controllers, err := enableControllers(parent, "cpu", "io", "memory", "pids")
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem enabling controllers in '%s'", parent)
+	}
+	for controller, needed := range map[string]bool{
+		"memory": limits.MemoryMB > 0,
+		"cpu":    limits.CPUShares > 0,
+		"pids":   limits.MaxProcesses > 0,
+		"io":     limits.DiskWriteMB > 0,
+	} {
+		if needed && !controllers[controller] {
+			return nil, errors.Errorf("the %s controller is not available in '%s'", controller, parent)
+		}
+	}
+
+	cg := &TaskCgroup{
+		key:    key,
+		path:   filepath.Join(parent, "task-"+cgroupName(key)),
+		limits: limits,
+	}
+	if err = os.MkdirAll(cg.path, 0755); err != nil {
+		return nil, errors.Wrapf(err, "problem creating cgroup '%s'", cg.path)
+	}
+
+	if limits.MemoryMB > 0 {
+		if err = writeCgroupFile(cg.path, "memory.max", strconv.FormatInt(int64(limits.MemoryMB)*mebibyte, 10)); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		// swapping would let the task exceed its limit unnoticed
+		if _, err = os.Stat(filepath.Join(cg.path, "memory.swap.max")); err == nil {
+			grip.Warning(writeCgroupFile(cg.path, "memory.swap.max", "0"))
+		}
+	}
+	if limits.CPUShares > 0 {
+		if err = writeCgroupFile(cg.path, "cpu.weight", strconv.Itoa(cpuSharesToWeight(limits.CPUShares))); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if limits.MaxProcesses > 0 {
+		if err = writeCgroupFile(cg.path, "pids.max", strconv.Itoa(limits.MaxProcesses)); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if _, err = enableControllers(cg.path, "io", "memory", "pids"); err != nil {
+		return nil, errors.Wrapf(err, "problem enabling controllers in '%s'", cg.path)
+	}
+
+	cg.oomKills = readCgroupKeyedInt(cg.path, "memory.events", "oom_kill")
+	cg.pidsDenials = readCgroupKeyedInt(cg.path, "pids.events", "max")
+
+	registerTaskCgroup(key, cg)
+	return cg, nil
+}
+
+// delegatedCgroup returns the cgroup under which the agent creates the
+// cgroups of its tasks, moving the agent into a leaf cgroup if it is not
+// in one already.
+func delegatedCgroup() (string, error) {
+	data, err := ioutil.ReadFile(procSelfCgroup)
+	if err != nil {
+		return "", errors.Wrap(err, "problem reading the agent's cgroup")
+	}
+
+	var rel string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			rel = strings.TrimPrefix(line, "0::")
+		}
+	}
+	if rel == "" {
+		return "", errors.New("cgroups v2 is not available")
+	}
+
+	current := filepath.Join(cgroupFSRoot, rel)
+	if filepath.Base(current) == agentCgroupName {
+		return filepath.Dir(current), nil
+	}
+
+	leaf := filepath.Join(current, agentCgroupName)
+	if err = os.MkdirAll(leaf, 0755); err != nil {
+		return "", errors.Wrapf(err, "problem creating cgroup '%s'", leaf)
+	}
+	if err = writeCgroupFile(leaf, "cgroup.procs", strconv.Itoa(os.Getpid())); err != nil {
+		return "", errors.Wrap(err, "problem moving the agent into its own cgroup")
+	}
+	return current, nil
+}
+
+// enableControllers enables the available controllers among those given
+// for the children of the cgroup, returning the ones that are enabled.
+func enableControllers(path string, controllers ...string) (map[string]bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, "cgroup.controllers"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	available := map[string]bool{}
+	for _, c := range strings.Fields(string(data)) {
+		available[c] = true
+	}
+
+	enabled := map[string]bool{}
+	for _, c := range controllers {
+		if !available[c] {
+			continue
+		}
+		if err = writeCgroupFile(path, "cgroup.subtree_control", "+"+c); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		enabled[c] = true
+	}
+	return enabled, nil
+}
+
+// StartCommand creates the cgroup of a command, which processes started
+// until EndCommand is called are placed in.
+func (cg *TaskCgroup) StartCommand(name string) error {
+	if cg == nil {
+		return nil
+	}
+
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	if cg.command != nil {
+		return errors.Errorf("command '%s' is already running", cg.command.name)
+	}
+
+	cg.commands++
+	cmd := &commandCgroup{
+		name: name,
+		path: filepath.Join(cg.path, fmt.Sprintf("cmd-%d", cg.commands)),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if err := os.MkdirAll(cmd.path, 0755); err != nil {
+		return errors.Wrapf(err, "problem creating cgroup '%s'", cmd.path)
+	}
+	cg.command = cmd
+
+	go cg.watch(cmd)
+	return nil
+}
+
+// AddProcess moves a process into the cgroup of the running command.
+// Children that the process started before it was moved are not moved.
+func (cg *TaskCgroup) AddProcess(pid int) error {
+	if cg == nil {
+		return nil
+	}
+
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	if cg.command == nil {
+		return errors.New("no command is running")
+	}
+	return errors.WithStack(writeCgroupFile(cg.command.path, "cgroup.procs", strconv.Itoa(pid)))
+}
+
+// EndCommand stops accounting for the running command, returning its peak
+// usage, and an error if the task exceeded a limit while it ran.
+func (cg *TaskCgroup) EndCommand() (ResourceUsage, error) {
+	if cg == nil {
+		return ResourceUsage{}, nil
+	}
+
+	cg.mu.Lock()
+	cmd := cg.command
+	cg.mu.Unlock()
+	if cmd == nil {
+		return ResourceUsage{}, errors.New("no command is running")
+	}
+
+	close(cmd.stop)
+	<-cmd.done
+
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	cg.sample(cmd)
+	if peak := readCgroupInt(cmd.path, "memory.peak"); peak > cmd.usage.PeakMemoryBytes {
+		cmd.usage.PeakMemoryBytes = peak
+	}
+	cg.command = nil
+
+	if cg.violation != nil && cg.violation.Command == cmd.name {
+		return cmd.usage, cg.violation
+	}
+	return cmd.usage, nil
+}
+
+// Violation returns the first limit that the task exceeded, if any.
+func (cg *TaskCgroup) Violation() error {
+	if cg == nil {
+		return nil
+	}
+
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	if cg.violation == nil {
+		return nil
+	}
+	return cg.violation
+}
+
+// Close stops placing processes in the task's cgroup and removes it.
+// Cgroups that still contain processes, such as those a task group keeps
+// running between tasks, are left in place with their limits.
+func (cg *TaskCgroup) Close() error {
+	if cg == nil {
+		return nil
+	}
+	unregisterTaskCgroup(cg.key)
+
+	cg.mu.Lock()
+	cmd := cg.command
+	cg.mu.Unlock()
+	if cmd != nil {
+		_, _ = cg.EndCommand()
+	}
+
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	catcher := grip.NewBasicCatcher()
+	for i := 1; i <= cg.commands; i++ {
+		catcher.Add(removeCgroup(filepath.Join(cg.path, fmt.Sprintf("cmd-%d", i))))
+	}
+	catcher.Add(removeCgroup(cg.path))
+	return catcher.Resolve()
+}
+
+func (cg *TaskCgroup) watch(cmd *commandCgroup) {
+	defer close(cmd.done)
+
+	ticker := time.NewTicker(cgroupPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cmd.stop:
+			return
+		case <-ticker.C:
+			cg.mu.Lock()
+			cg.sample(cmd)
+			cg.mu.Unlock()
+		}
+	}
+}
+
+// sample updates the command's peak usage and checks the task's limits.
+// The caller must hold the lock.
+func (cg *TaskCgroup) sample(cmd *commandCgroup) {
+	if mem := readCgroupInt(cmd.path, "memory.current"); mem > cmd.usage.PeakMemoryBytes {
+		cmd.usage.PeakMemoryBytes = mem
+	}
+	if procs := readCgroupInt(cmd.path, "pids.current"); procs > cmd.usage.PeakProcesses {
+		cmd.usage.PeakProcesses = procs
+	}
+	cmd.usage.WrittenBytes = readWrittenBytes(cmd.path)
+
+	if cg.violation != nil {
+		return
+	}
+	switch {
+	case cg.limits.MemoryMB > 0 && readCgroupKeyedInt(cg.path, "memory.events", "oom_kill") > cg.oomKills:
+		cg.violation = &ResourceLimitError{
+			Command:  cmd.name,
+			Resource: "memory",
+			Limit:    fmt.Sprintf("%d MB", cg.limits.MemoryMB),
+		}
+	case cg.limits.MaxProcesses > 0 && readCgroupKeyedInt(cg.path, "pids.events", "max") > cg.pidsDenials:
+		cg.violation = &ResourceLimitError{
+			Command:  cmd.name,
+			Resource: "process",
+			Limit:    strconv.Itoa(cg.limits.MaxProcesses),
+		}
+	case cg.limits.DiskWriteMB > 0 && readWrittenBytes(cg.path) > int64(cg.limits.DiskWriteMB)*mebibyte:
+		// cgroups limit the rate of writes, not their total, so the
+		// quota is enforced by stopping the command
+		cg.violation = &ResourceLimitError{
+			Command:  cmd.name,
+			Resource: "disk write",
+			Limit:    fmt.Sprintf("%d MB", cg.limits.DiskWriteMB),
+		}
+		killCgroup(cmd.path)
+	}
+}
+
+func writeCgroupFile(path, name, value string) error {
+	return errors.Wrapf(ioutil.WriteFile(filepath.Join(path, name), []byte(value), 0644),
+		"problem writing '%s' to '%s'", value, filepath.Join(path, name))
+}
+
+// readCgroupInt reads a single value file, returning 0 if the file does
+// not exist, as when a controller is not enabled.
+func readCgroupInt(path, name string) int64 {
+	data, err := ioutil.ReadFile(filepath.Join(path, name))
+	if err != nil {
+		return 0
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// readCgroupKeyedInt reads one value of a flat keyed file such as
+// memory.events.
+func readCgroupKeyedInt(path, name, key string) int64 {
+	data, err := ioutil.ReadFile(filepath.Join(path, name))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			v, _ := strconv.ParseInt(fields[1], 10, 64)
+			return v
+		}
+	}
+	return 0
+}
+
+// readWrittenBytes sums the bytes written to every device in io.stat.
+func readWrittenBytes(path string) int64 {
+	data, err := ioutil.ReadFile(filepath.Join(path, "io.stat"))
+	if err != nil {
+		return 0
+	}
+	var total int64
+	for _, field := range strings.Fields(string(data)) {
+		if strings.HasPrefix(field, "wbytes=") {
+			v, _ := strconv.ParseInt(strings.TrimPrefix(field, "wbytes="), 10, 64)
+			total += v
+		}
+	}
+	return total
+}
+
+// killCgroup kills every process in a cgroup, falling back to signalling
+// them one at a time on kernels without cgroup.kill.
+func killCgroup(path string) {
+	if writeCgroupFile(path, "cgroup.kill", "1") == nil {
+		return
+	}
+	data, err := ioutil.ReadFile(filepath.Join(path, "cgroup.procs"))
+	if err != nil {
+		return
+	}
+	for _, field := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+}
+
+func removeCgroup(path string) error {
+	err := os.Remove(path)
+	if err == nil || os.IsNotExist(err) {
+		return nil
+	}
+	if pathErr, ok := err.(*os.PathError); ok && pathErr.Err == syscall.EBUSY {
+		return nil
+	}
+	return errors.Wrapf(err, "problem removing cgroup '%s'", path)
+}
@@ -0,0 +1,155 @@
+package subprocess
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupFakeCgroupFS points the cgroup code at a directory that mimics a
+// cgroups v2 hierarchy in which the agent runs in /agent.slice.
+func setupFakeCgroupFS(t *testing.T) (string, func()) {
+	root, err := ioutil.TempDir("", "cgroup")
+	require.NoError(t, err)
+
+	oldRoot, oldSelf := cgroupFSRoot, procSelfCgroup
+	cgroupFSRoot = filepath.Join(root, "fs")
+	procSelfCgroup = filepath.Join(root, "self")
+
+	require.NoError(t, ioutil.WriteFile(procSelfCgroup, []byte("0::/agent.slice\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(cgroupFSRoot, "agent.slice"), 0755))
+	writeFakeCgroupFile(t, filepath.Join(cgroupFSRoot, "agent.slice"), "cgroup.controllers", "cpu io memory pids")
+
+	return filepath.Join(cgroupFSRoot, "agent.slice"), func() {
+		cgroupFSRoot, procSelfCgroup = oldRoot, oldSelf
+		os.RemoveAll(root)
+	}
+}
+
+func writeFakeCgroupFile(t *testing.T, dir, name, value string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(value), 0644))
+}
+
+func readFakeCgroupFile(t *testing.T, dir, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return strings.TrimSpace(string(data))
+}
+
+func TestTaskCgroupLimitsAndUsage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	parent, cleanup := setupFakeCgroupFS(t)
+	defer cleanup()
+
+	taskDir := filepath.Join(parent, "task-task_1")
+	writeFakeCgroupFile(t, taskDir, "cgroup.controllers", "io memory pids")
+
+	cg, err := NewTaskCgroup("task/1", ResourceLimits{MemoryMB: 64, CPUShares: 1024, MaxProcesses: 10})
+	require.NoError(err)
+	defer cg.Close()
+
+	assert.Equal(strconv.Itoa(os.Getpid()), readFakeCgroupFile(t, filepath.Join(parent, agentCgroupName), "cgroup.procs"))
+	assert.Equal("67108864", readFakeCgroupFile(t, taskDir, "memory.max"))
+	assert.Equal("39", readFakeCgroupFile(t, taskDir, "cpu.weight"))
+	assert.Equal("10", readFakeCgroupFile(t, taskDir, "pids.max"))
+
+	require.NoError(cg.StartCommand("shell.exec"))
+	assert.Error(cg.StartCommand("shell.exec"))
+	TrackProcess("task/1", 1234, logging.MakeGrip(grip.GetSender()))
+	cmdDir := filepath.Join(taskDir, "cmd-1")
+	assert.Equal("1234", readFakeCgroupFile(t, cmdDir, "cgroup.procs"))
+
+	writeFakeCgroupFile(t, cmdDir, "memory.current", "1000")
+	writeFakeCgroupFile(t, cmdDir, "memory.peak", "5000")
+	writeFakeCgroupFile(t, cmdDir, "pids.current", "3")
+	writeFakeCgroupFile(t, cmdDir, "io.stat", "8:0 rbytes=1 wbytes=2048 rios=1 wios=1\n8:16 rbytes=0 wbytes=1024 rios=0 wios=1\n")
+	usage, err := cg.EndCommand()
+	assert.NoError(err)
+	assert.Equal(ResourceUsage{PeakMemoryBytes: 5000, PeakProcesses: 3, WrittenBytes: 3072}, usage)
+	assert.NoError(cg.Violation())
+
+	_, err = cg.EndCommand()
+	assert.Error(err)
+	assert.Error(cg.AddProcess(1))
+
+	require.NoError(cg.StartCommand("subprocess.exec"))
+	writeFakeCgroupFile(t, taskDir, "memory.events", "low 0\nhigh 0\nmax 4\noom 1\noom_kill 1\n")
+	_, err = cg.EndCommand()
+	require.Error(err)
+	limitErr, ok := err.(*ResourceLimitError)
+	require.True(ok)
+	assert.Equal("subprocess.exec", limitErr.Command)
+	assert.Equal("memory", limitErr.Resource)
+	assert.Equal("command 'subprocess.exec' exceeded the task's memory limit of 64 MB", limitErr.Error())
+	assert.Equal(limitErr, cg.Violation())
+
+	// the fake cgroups contain files, so they cannot be removed as real
+	// cgroups can
+	_ = cg.Close()
+	assert.Nil(getTaskCgroup("task/1"))
+}
+
+func TestTaskCgroupDiskWriteQuota(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	parent, cleanup := setupFakeCgroupFS(t)
+	defer cleanup()
+
+	// the agent was moved into its own cgroup by an earlier task
+	require.NoError(ioutil.WriteFile(procSelfCgroup, []byte("0::/agent.slice/"+agentCgroupName+"\n"), 0644))
+	taskDir := filepath.Join(parent, "task-task")
+	writeFakeCgroupFile(t, taskDir, "cgroup.controllers", "io memory pids")
+
+	cg, err := NewTaskCgroup("task", ResourceLimits{DiskWriteMB: 1})
+	require.NoError(err)
+	defer cg.Close()
+
+	require.NoError(cg.StartCommand("shell.exec"))
+	writeFakeCgroupFile(t, taskDir, "io.stat", "8:0 rbytes=0 wbytes=2097152 rios=0 wios=10\n")
+	_, err = cg.EndCommand()
+	require.Error(err)
+	assert.Contains(err.Error(), "disk write limit of 1 MB")
+	assert.Equal("1", readFakeCgroupFile(t, filepath.Join(taskDir, "cmd-1"), "cgroup.kill"))
+}
+
+func TestTaskCgroupRequiresControllers(t *testing.T) {
+	parent, cleanup := setupFakeCgroupFS(t)
+	defer cleanup()
+
+	writeFakeCgroupFile(t, parent, "cgroup.controllers", "cpu pids")
+	_, err := NewTaskCgroup("task", ResourceLimits{MemoryMB: 64})
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(procSelfCgroup, []byte("1:name=systemd:/user.slice\n"), 0644))
+	_, err = NewTaskCgroup("task", ResourceLimits{MaxProcesses: 10})
+	assert.Error(t, err)
+}
+
+func TestCgroupHelpers(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(1, cpuSharesToWeight(2))
+	assert.Equal(1, cpuSharesToWeight(0))
+	assert.Equal(39, cpuSharesToWeight(1024))
+	assert.Equal(10000, cpuSharesToWeight(262144))
+	assert.Equal("evergreen_task_1.2-x", cgroupName("evergreen/task_1.2-x"))
+	assert.True(ResourceLimits{}.IsZero())
+	assert.False(ResourceLimits{CPUShares: 2}.IsZero())
+
+	var cg *TaskCgroup
+	assert.NoError(cg.StartCommand("shell.exec"))
+	assert.NoError(cg.Violation())
+	assert.NoError(cg.Close())
+}
@@ -0,0 +1,21 @@
+// +build !linux
+
+package subprocess
+
+import "github.com/pkg/errors"
+
+// TaskCgroup limits the processes of a task on Linux. On other platforms
+// resource limits are not enforced and a TaskCgroup does nothing.
+type TaskCgroup struct{}
+
+// NewTaskCgroup returns an error, since resource limits are only enforced
+// on Linux.
+func NewTaskCgroup(key string, limits ResourceLimits) (*TaskCgroup, error) {
+	return nil, errors.New("resource limits are only enforced on linux")
+}
+
+func (cg *TaskCgroup) StartCommand(name string) error     { return nil }
+func (cg *TaskCgroup) AddProcess(pid int) error           { return nil }
+func (cg *TaskCgroup) EndCommand() (ResourceUsage, error) { return ResourceUsage{}, nil }
+func (cg *TaskCgroup) Violation() error                   { return nil }
+func (cg *TaskCgroup) Close() error                       { return nil }
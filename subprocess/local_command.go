@@ -18,6 +18,7 @@ type localCmd struct {
 	Shell            string    `json:"shell"`
 	Environment      []string  `json:"environment"`
 	ScriptMode       bool      `json:"script"`
+	Stdin            io.Reader `json:"-"`
 	Stdout           io.Writer `json:"-"`
 	Stderr           io.Writer `json:"-"`
 	cmd              *exec.Cmd
@@ -34,6 +35,17 @@ func NewLocalCommand(cmdString, workingDir, shell string, env []string, scriptMo
 	}
 }
 
+// NewInteractiveShell returns a command that runs a shell reading its
+// commands from stdin until stdin is closed.
+func NewInteractiveShell(workingDir, shell string, env []string, stdin io.Reader) Command {
+	return &localCmd{
+		WorkingDirectory: workingDir,
+		Shell:            shell,
+		Environment:      env,
+		Stdin:            stdin,
+	}
+}
+
 func (lc *localCmd) Run(ctx context.Context) error {
 	err := lc.Start(ctx)
 	if err != nil {
@@ -87,7 +99,10 @@ func (lc *localCmd) Start(ctx context.Context) error {
 	}
 
 	var cmd *exec.Cmd
-	if lc.ScriptMode {
+	if lc.Stdin != nil {
+		cmd = exec.CommandContext(ctx, lc.Shell)
+		cmd.Stdin = lc.Stdin
+	} else if lc.ScriptMode {
 		cmd = exec.CommandContext(ctx, lc.Shell)
 		cmd.Stdin = strings.NewReader(lc.CmdString)
 	} else {
@@ -1,15 +1,21 @@
 package subprocess
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLocalCommands(t *testing.T) {
@@ -150,3 +156,36 @@ func TestLocalScript(t *testing.T) {
 
 	})
 }
+
+func TestInteractiveShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell command test doesn't make sense on windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "interactive-shell")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stdin, input := io.Pipe()
+	out := &bytes.Buffer{}
+	cmd := NewInteractiveShell(dir, "", []string{"GREETING=hello"}, stdin)
+	require.NoError(t, cmd.SetOutput(OutputOptions{Output: out, SendErrorToOutput: true}))
+	require.NoError(t, cmd.Start(ctx))
+
+	_, err = io.WriteString(input, "echo $GREETING\npwd\n")
+	require.NoError(t, err)
+	require.NoError(t, input.Close())
+	require.NoError(t, cmd.Wait())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "hello", lines[0])
+	reported, err := filepath.EvalSymlinks(lines[1])
+	require.NoError(t, err)
+	expected, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	assert.Equal(t, expected, reported)
+}
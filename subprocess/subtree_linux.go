@@ -10,8 +10,13 @@ import (
 )
 
 func TrackProcess(key string, pid int, logger grip.Journaler) {
-	// trackProcess is a noop on linux, because we detect all the processes to be killed in
-	// cleanup() and we don't need to do any special bookkeeping up-front.
+	// we detect all the processes to be killed in cleanup(), so the only bookkeeping
+	// up-front is placing the process in its task's cgroup, if the task has resource limits.
+	if cg := getTaskCgroup(key); cg != nil {
+		if err := cg.AddProcess(pid); err != nil {
+			logger.Warningf("could not enforce resource limits on process %d: %v", pid, err)
+		}
+	}
 }
 
 // getEnv returns a slice of environment variables for the given pid, in the form
@@ -78,8 +78,28 @@ func MockConfig() *evergreen.Settings {
 				Organization: "ghorg",
 			},
 		},
-		Banner:            "banner",
-		BannerTheme:       "important",
+		ArtifactRetention: evergreen.ArtifactRetentionConfig{
+			AWSKey:    "retention_key",
+			AWSSecret: "retention_secret",
+			Buckets:   []string{"retention_bucket"},
+		},
+		ArtifactSigning: evergreen.ArtifactSigningConfig{
+			PrivateKey: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+		},
+		GithubApp: evergreen.GithubAppConfig{
+			AppID:         1234,
+			PrivateKey:    "app_key",
+			WebhookSecret: "app_secret",
+		},
+		Banner:      "banner",
+		BannerTheme: "important",
+		BuildCache: evergreen.BuildCacheConfig{
+			Bucket:    "cache_bucket",
+			AWSKey:    "cache_key",
+			AWSSecret: "cache_secret",
+			MaxSizeMB: 1024,
+			TTLDays:   7,
+		},
 		ClientBinariesDir: "bin_dir",
 		ConfigDir:         "cfg_dir",
 		ContainerPools: evergreen.ContainerPoolsConfig{
@@ -89,12 +109,26 @@ func MockConfig() *evergreen.Settings {
 					Id:            "test-pool-1",
 					MaxContainers: 100,
 					Port:          9999,
+					ParentResources: evergreen.ContainerResources{
+						CPUs:     16,
+						MemoryMB: 64 * 1024,
+					},
 				},
 			},
+			Registries: []evergreen.ContainerRegistry{
+				{Host: "registry.example.com", Username: "user", Password: "pass"},
+			},
+			ImageTTLHours: 48,
 		},
 		Credentials:        map[string]string{"k1": "v1"},
 		Expansions:         map[string]string{"k2": "v2"},
 		GithubPRCreatorOrg: "org",
+		HostHealth: evergreen.HostHealthConfig{
+			QuarantineEnabled: true,
+			Threshold:         0.5,
+			WindowMinutes:     1440,
+			MinTasks:          5,
+		},
 		HostInit: evergreen.HostInitConfig{
 			SSHTimeoutSeconds: 10,
 		},
@@ -189,6 +223,10 @@ func MockConfig() *evergreen.Settings {
 			Channel:   "channel",
 		},
 		SuperUsers: []string{"user"},
+		Tracer: evergreen.TracerConfig{
+			Enabled:           true,
+			CollectorEndpoint: "http://localhost:4318",
+		},
 		Ui: evergreen.UIConfig{
 			Url:            "url",
 			HelpUrl:        "helpurl",
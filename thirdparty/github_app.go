@@ -0,0 +1,116 @@
+package thirdparty
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// the apps API is in preview, so it must be requested explicitly
+	githubAcceptApps = "application/vnd.github.machine-man-preview+json"
+
+	// Github rejects app tokens that expire more than ten minutes after
+	// they are issued, and tokens issued in the future, so they are
+	// backdated to allow for clock drift.
+	githubAppTokenTTL       = 9 * time.Minute
+	githubAppTokenClockSkew = time.Minute
+)
+
+// CreateGithubAppInstallationToken returns a token of the installation of a
+// Github App on a repository, which acts as the app on the repository for an
+// hour. The app is identified by its ID and authenticated by its PEM encoded
+// RSA private key.
+func CreateGithubAppInstallationToken(ctx context.Context, appID int64, privateKey, owner, repo string) (string, error) {
+	jwt, err := makeGithubAppJWT(appID, privateKey, time.Now())
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	return createGithubAppInstallationToken(ctx, client, "https://api.github.com", jwt, owner, repo)
+}
+
+func createGithubAppInstallationToken(ctx context.Context, client *http.Client, apiBase, jwt, owner, repo string) (string, error) {
+	header := http.Header{
+		"Accept":        {githubAcceptApps},
+		"Authorization": {"Bearer " + jwt},
+	}
+
+	installation := struct {
+		ID int64 `json:"id"`
+	}{}
+	url := fmt.Sprintf("%s/repos/%s/%s/installation", apiBase, owner, repo)
+	if err := doGithubJSONRequest(ctx, client, http.MethodGet, url, header, nil, &installation); err != nil {
+		return "", errors.Wrapf(err, "failed to find the github app's installation on '%s/%s'", owner, repo)
+	}
+
+	token := struct {
+		Token string `json:"token"`
+	}{}
+	url = fmt.Sprintf("%s/app/installations/%d/access_tokens", apiBase, installation.ID)
+	if err := doGithubJSONRequest(ctx, client, http.MethodPost, url, header, nil, &token); err != nil {
+		return "", errors.Wrapf(err, "failed to create a token for installation %d", installation.ID)
+	}
+	if token.Token == "" {
+		return "", errors.Errorf("github returned no token for installation %d", installation.ID)
+	}
+
+	return token.Token, nil
+}
+
+// makeGithubAppJWT returns a JSON web token, signed with RS256, that
+// authenticates a Github App.
+func makeGithubAppJWT(appID int64, privateKey string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return "", errors.New("github app private key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if pkcs8Err != nil {
+			return "", errors.Wrap(err, "failed to parse github app private key")
+		}
+		var ok bool
+		if key, ok = parsed.(*rsa.PrivateKey); !ok {
+			return "", errors.New("github app private key is not an RSA key")
+		}
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal token header")
+	}
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-githubAppTokenClockSkew).Unix(),
+		"exp": now.Add(githubAppTokenTTL).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal token claims")
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign token")
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,80 @@
+package thirdparty
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeGithubAppJWT(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(err)
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	now := time.Now()
+	jwt, err := makeGithubAppJWT(1234, pemKey, now)
+	require.NoError(err)
+	parts := strings.Split(jwt, ".")
+	require.Len(parts, 3)
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(err)
+	claims := map[string]int64{}
+	require.NoError(json.Unmarshal(claimsJSON, &claims))
+	assert.EqualValues(1234, claims["iss"])
+	assert.True(claims["iat"] < now.Unix())
+	assert.True(claims["exp"] <= now.Add(10*time.Minute).Unix())
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	_, err = makeGithubAppJWT(1234, "not a key", now)
+	assert.Error(err)
+}
+
+func TestCreateGithubAppInstallationToken(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(githubAcceptApps, r.Header.Get("Accept"))
+		assert.Equal("Bearer jwt", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/evergreen-ci/evergreen/installation":
+			fmt.Fprint(w, `{"id": 42}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/app/installations/42/access_tokens":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"token": "installation-token"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	token, err := createGithubAppInstallationToken(context.Background(), http.DefaultClient, server.URL, "jwt", "evergreen-ci", "evergreen")
+	require.NoError(err)
+	assert.Equal("installation-token", token)
+
+	_, err = createGithubAppInstallationToken(context.Background(), http.DefaultClient, server.URL, "jwt", "evergreen-ci", "other")
+	assert.Error(err)
+}
@@ -0,0 +1,183 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/PuerkitoBio/rehttp"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// the checks API is in preview, so it must be requested explicitly
+	githubAcceptChecks = "application/vnd.github.antiope-preview+json"
+
+	GithubCheckRunCompleted = "completed"
+
+	GithubCheckConclusionSuccess = "success"
+	GithubCheckConclusionFailure = "failure"
+
+	GithubAnnotationLevelFailure = "failure"
+
+	// GithubCheckRunMaxAnnotations is the most annotations that Github
+	// accepts in one request. Check runs with more are sent in batches.
+	GithubCheckRunMaxAnnotations = 50
+)
+
+// GithubCheckRun is a check run in the Github checks API.
+type GithubCheckRun struct {
+	Name        string                 `json:"name"`
+	HeadSHA     string                 `json:"head_sha,omitempty"`
+	DetailsURL  string                 `json:"details_url,omitempty"`
+	ExternalID  string                 `json:"external_id,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Conclusion  string                 `json:"conclusion,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	Output      *GithubCheckRunOutput  `json:"output,omitempty"`
+	Actions     []GithubCheckRunAction `json:"actions,omitempty"`
+}
+
+// GithubCheckRunOutput is the summary of a check run, in markdown, and the
+// annotations on the lines of code that it concerns.
+type GithubCheckRunOutput struct {
+	Title       string                     `json:"title"`
+	Summary     string                     `json:"summary"`
+	Annotations []GithubCheckRunAnnotation `json:"annotations,omitempty"`
+}
+
+// GithubCheckRunAnnotation marks a line of code in a check run.
+type GithubCheckRunAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// GithubCheckRunAction is a button on a check run. Clicking it sends a
+// check_run webhook with the action's identifier.
+type GithubCheckRunAction struct {
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	Identifier  string `json:"identifier"`
+}
+
+// CreateGithubCheckRun creates a check run on a commit. If the check run has
+// more annotations than Github accepts at once, the rest are added to it
+// afterwards. Github only accepts check runs from Github Apps, so the token
+// must be an app installation's token.
+func CreateGithubCheckRun(ctx context.Context, token, owner, repo string, run GithubCheckRun) error {
+	all := rehttp.RetryAll(rehttp.RetryMaxRetries(NumGithubRetries-1),
+		rehttp.RetryAny(rehttp.RetryTemporaryErr(), rehttp.RetryStatusInterval(http.StatusInternalServerError, 600)))
+	client, err := util.GetRetryableOauth2HTTPClient(token, all, util.RehttpDelay(GithubSleepTimeSecs, NumGithubRetries))
+	if err != nil {
+		return errors.Wrap(err, "error getting http client")
+	}
+	defer util.PutHTTPClient(client)
+
+	return createGithubCheckRun(ctx, client, "https://api.github.com", owner, repo, run)
+}
+
+func createGithubCheckRun(ctx context.Context, client *http.Client, apiBase, owner, repo string, run GithubCheckRun) error {
+	var rest []GithubCheckRunAnnotation
+	if run.Output != nil && len(run.Output.Annotations) > GithubCheckRunMaxAnnotations {
+		output := *run.Output
+		rest = output.Annotations[GithubCheckRunMaxAnnotations:]
+		output.Annotations = output.Annotations[:GithubCheckRunMaxAnnotations]
+		run.Output = &output
+	}
+
+	created := struct {
+		ID int64 `json:"id"`
+	}{}
+	url := fmt.Sprintf("%s/repos/%s/%s/check-runs", apiBase, owner, repo)
+	if err := doGithubChecksRequest(ctx, client, http.MethodPost, url, run, &created); err != nil {
+		return errors.Wrapf(err, "failed to create check run '%s'", run.Name)
+	}
+
+	url = fmt.Sprintf("%s/%d", url, created.ID)
+	for len(rest) > 0 {
+		batch := rest
+		if len(batch) > GithubCheckRunMaxAnnotations {
+			batch = batch[:GithubCheckRunMaxAnnotations]
+		}
+		rest = rest[len(batch):]
+
+		// annotations in an update are added to the check run's
+		// existing annotations
+		update := GithubCheckRun{
+			Name: run.Name,
+			Output: &GithubCheckRunOutput{
+				Title:       run.Output.Title,
+				Summary:     run.Output.Summary,
+				Annotations: batch,
+			},
+		}
+		if err := doGithubChecksRequest(ctx, client, http.MethodPatch, url, update, nil); err != nil {
+			return errors.Wrapf(err, "failed to add annotations to check run '%s'", run.Name)
+		}
+	}
+
+	return nil
+}
+
+func doGithubChecksRequest(ctx context.Context, client *http.Client, method, url string, data, out interface{}) error {
+	return doGithubJSONRequest(ctx, client, method, url, http.Header{"Accept": {githubAcceptChecks}}, data, out)
+}
+
+// doGithubJSONRequest sends data, if it is not nil, as JSON and unmarshals
+// the response into out, if it is not nil.
+func doGithubJSONRequest(ctx context.Context, client *http.Client, method, url string, header http.Header, data, out interface{}) error {
+	var body io.Reader
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request")
+		}
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create github request")
+	}
+	req = req.WithContext(ctx)
+	for key, values := range header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	if data != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request to github")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ResponseReadError{err.Error()}
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return errors.Errorf("Expected 200 OK or 201 Created, got %d %s: %s",
+			resp.StatusCode, http.StatusText(resp.StatusCode), string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err = json.Unmarshal(respBody, out); err != nil {
+		return APIUnmarshalError{string(respBody), err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGithubCheckRunBatchesAnnotations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	type request struct {
+		method string
+		path   string
+		run    GithubCheckRun
+	}
+	requests := []request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path}
+		if err := json.NewDecoder(r.Body).Decode(&req.run); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(githubAcceptChecks, r.Header.Get("Accept"))
+		requests = append(requests, req)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		fmt.Fprint(w, `{"id": 42}`)
+	}))
+	defer server.Close()
+
+	run := GithubCheckRun{
+		Name:    "variant",
+		HeadSHA: "abcdef",
+		Output:  &GithubCheckRunOutput{Title: "1 failed", Summary: "summary"},
+	}
+	for i := 0; i < 2*GithubCheckRunMaxAnnotations+1; i++ {
+		run.Output.Annotations = append(run.Output.Annotations, GithubCheckRunAnnotation{Path: "file.go", StartLine: i, EndLine: i})
+	}
+
+	require.NoError(createGithubCheckRun(context.Background(), server.Client(), server.URL, "owner", "repo", run))
+	require.Len(requests, 3)
+	assert.Equal(http.MethodPost, requests[0].method)
+	assert.Equal("/repos/owner/repo/check-runs", requests[0].path)
+	assert.Equal("abcdef", requests[0].run.HeadSHA)
+	assert.Len(requests[0].run.Output.Annotations, GithubCheckRunMaxAnnotations)
+	for _, req := range requests[1:] {
+		assert.Equal(http.MethodPatch, req.method)
+		assert.Equal("/repos/owner/repo/check-runs/42", req.path)
+		assert.Equal("summary", req.run.Output.Summary)
+	}
+	assert.Len(requests[1].run.Output.Annotations, GithubCheckRunMaxAnnotations)
+	require.Len(requests[2].run.Output.Annotations, 1)
+	assert.Equal(2*GithubCheckRunMaxAnnotations, requests[2].run.Output.Annotations[0].StartLine)
+
+	// the caller's run is not modified
+	assert.Len(run.Output.Annotations, 2*GithubCheckRunMaxAnnotations+1)
+}
+
+func TestCreateGithubCheckRunError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message": "Validation Failed"}`)
+	}))
+	defer server.Close()
+
+	err := createGithubCheckRun(context.Background(), server.Client(), server.URL, "owner", "repo", GithubCheckRun{Name: "variant"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Validation Failed")
+}
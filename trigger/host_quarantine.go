@@ -0,0 +1,104 @@
+package trigger
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	registry.registerEventHandler(event.ResourceTypeHost, event.EventHostQuarantined, makeHostQuarantineTriggers)
+}
+
+const (
+	triggerQuarantined = "quarantined"
+	selectorDistro     = "distro"
+)
+
+type hostQuarantineTriggers struct {
+	hostBase
+}
+
+func makeHostQuarantineTriggers() eventHandler {
+	t := &hostQuarantineTriggers{}
+	t.triggers = map[string]trigger{
+		triggerQuarantined: t.hostQuarantined,
+	}
+	return t
+}
+
+// Selectors allows subscribing to the quarantine of any host of a distro.
+func (t *hostQuarantineTriggers) Selectors() []event.Selector {
+	return append(t.hostBase.Selectors(), event.Selector{
+		Type: selectorDistro,
+		Data: t.host.Distro.Id,
+	})
+}
+
+func (t *hostQuarantineTriggers) hostQuarantined(sub *event.Subscription) (*notification.Notification, error) {
+	var payload interface{}
+	switch sub.Subscriber.Type {
+	case event.SlackSubscriberType:
+		payload = t.slack()
+	case event.EmailSubscriberType:
+		payload = t.email(sub.Selectors)
+	default:
+		return nil, errors.Errorf("unsupported subscriber type: %s", sub.Subscriber.Type)
+	}
+
+	return notification.New(t.event, sub.Trigger, &sub.Subscriber, payload)
+}
+
+func (t *hostQuarantineTriggers) url() string {
+	return fmt.Sprintf("%s/host/%s", t.uiConfig.Url, url.PathEscape(t.host.Id))
+}
+
+func (t *hostQuarantineTriggers) slack() *notification.SlackPayload {
+	return &notification.SlackPayload{
+		Body: fmt.Sprintf("Host %s has been quarantined", t.host.Id),
+		Attachments: []message.SlackAttachment{{
+			Title:     fmt.Sprintf("Evergreen Host: %s", t.host.Id),
+			TitleLink: t.url(),
+			Color:     evergreenFailColor,
+			Text:      t.data.Logs,
+			Fields: []*message.SlackAttachmentField{
+				{
+					Title: "Distro",
+					Value: t.host.Distro.Id,
+					Short: true,
+				},
+				{
+					Title: "Quarantined By",
+					Value: t.data.User,
+					Short: true,
+				},
+			},
+		}},
+	}
+}
+
+const hostQuarantineEmailTemplate = `<html>
+<head>
+</head>
+<body>
+<p>Hi,</p>
+
+<p>The Evergreen host <a href="%s">%s</a> with distro '%s' was quarantined by %s, and will not run tasks until it is restored.</p>
+<p>Reason: %s</p>
+
+</body>
+</html>
+`
+
+func (t *hostQuarantineTriggers) email(selectors []event.Selector) *message.Email {
+	return &message.Email{
+		Subject:           fmt.Sprintf("Evergreen host %s with distro '%s' has been quarantined", t.host.Id, t.host.Distro.Id),
+		Body:              fmt.Sprintf(hostQuarantineEmailTemplate, t.url(), t.host.Id, t.host.Distro.Id, t.data.User, t.data.Logs),
+		PlainTextContents: false,
+		Headers:           makeHeaders(selectors),
+	}
+}
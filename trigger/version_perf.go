@@ -0,0 +1,192 @@
+package trigger
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionPerfChangePoint, makeVersionPerfTriggers)
+}
+
+const triggerPerfChangePoint = "perf-change-point"
+
+type versionPerfTriggers struct {
+	event        *event.EventLogEntry
+	data         *event.VersionEventData
+	version      *version.Version
+	changePoints []perf.ChangePoint
+	uiConfig     evergreen.UIConfig
+
+	base
+}
+
+func makeVersionPerfTriggers() eventHandler {
+	t := &versionPerfTriggers{}
+	t.base.triggers = map[string]trigger{
+		triggerPerfChangePoint: t.perfChangePoint,
+	}
+	return t
+}
+
+func (t *versionPerfTriggers) Fetch(e *event.EventLogEntry) error {
+	var err error
+	if err = t.uiConfig.Get(); err != nil {
+		return errors.Wrap(err, "Failed to fetch ui config")
+	}
+
+	t.version, err = version.FindOne(version.ById(e.ResourceId))
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch version")
+	}
+	if t.version == nil {
+		return errors.New("couldn't find version")
+	}
+
+	var ok bool
+	t.data, ok = e.Data.(*event.VersionEventData)
+	if !ok {
+		return errors.Errorf("version '%s' contains unexpected data with type '%T'", e.ResourceId, e.Data)
+	}
+	t.event = e
+
+	t.changePoints, err = perf.Find(perf.ByIDs(t.data.ChangePointIDs))
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch change points")
+	}
+
+	return nil
+}
+
+// Selectors allows subscribing to the change points of a project's
+// versions, and of particular build variants and tasks in them.
+func (t *versionPerfTriggers) Selectors() []event.Selector {
+	selectors := MakeVersionSelectors(*t.version)
+	seen := map[event.Selector]bool{}
+	for _, cp := range t.changePoints {
+		for _, s := range []event.Selector{
+			{Type: selectorBuildVariant, Data: cp.Variant},
+			{Type: selectorDisplayName, Data: cp.Task},
+		} {
+			if !seen[s] {
+				seen[s] = true
+				selectors = append(selectors, s)
+			}
+		}
+	}
+	return selectors
+}
+
+// perfChangePoint notifies of the version's regressions in the build
+// variants and tasks the subscription selects that changed by at least the
+// subscription's percentage, if it has one. Improvements are not notified.
+func (t *versionPerfTriggers) perfChangePoint(sub *event.Subscription) (*notification.Notification, error) {
+	minPercent := 0.0
+	if percentString, ok := sub.TriggerData[event.VersionPercentChangeKey]; ok {
+		var err error
+		minPercent, err = strconv.ParseFloat(percentString, 64)
+		if err != nil {
+			return nil, errors.Errorf("subscription %s has an invalid percentage", sub.ID)
+		}
+	}
+
+	changePoints := []perf.ChangePoint{}
+	for _, cp := range t.changePoints {
+		if !cp.IsRegression() || math.Abs(cp.PercentChange) < minPercent || !matchesChangePoint(sub.Selectors, cp) {
+			continue
+		}
+		changePoints = append(changePoints, cp)
+	}
+	if len(changePoints) == 0 {
+		return nil, nil
+	}
+
+	var payload interface{}
+	switch sub.Subscriber.Type {
+	case event.SlackSubscriberType:
+		payload = t.slack(changePoints)
+	case event.EmailSubscriberType:
+		payload = t.email(sub.Selectors, changePoints)
+	default:
+		return nil, errors.Errorf("unsupported subscriber type: %s", sub.Subscriber.Type)
+	}
+
+	return notification.New(t.event, sub.Trigger, &sub.Subscriber, payload)
+}
+
+func matchesChangePoint(selectors []event.Selector, cp perf.ChangePoint) bool {
+	for _, s := range selectors {
+		switch s.Type {
+		case selectorBuildVariant:
+			if s.Data != cp.Variant {
+				return false
+			}
+		case selectorDisplayName:
+			if s.Data != cp.Task {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func describeChangePoint(cp perf.ChangePoint) string {
+	return fmt.Sprintf("%s/%s %s.%s changed %+.1f%% (%.4g to %.4g)",
+		cp.Variant, cp.Task, cp.Name, cp.Measurement, cp.PercentChange, cp.MeanBefore, cp.MeanAfter)
+}
+
+func (t *versionPerfTriggers) slack(changePoints []perf.ChangePoint) *notification.SlackPayload {
+	lines := make([]string, 0, len(changePoints))
+	for _, cp := range changePoints {
+		lines = append(lines, describeChangePoint(cp))
+	}
+
+	return &notification.SlackPayload{
+		Body: fmt.Sprintf("Performance regressed in %s version %s", t.version.Identifier, t.version.Id),
+		Attachments: []message.SlackAttachment{{
+			Title:     fmt.Sprintf("Evergreen Version: %s", t.version.Id),
+			TitleLink: versionLink(t.uiConfig.Url, t.version.Id),
+			Color:     evergreenFailColor,
+			Text:      strings.Join(lines, "\n"),
+		}},
+	}
+}
+
+const versionPerfEmailTemplate = `<html>
+<head>
+</head>
+<body>
+<p>Hi,</p>
+
+<p>Performance regressed in the Evergreen version <a href="%s">%s</a> of project '%s':</p>
+<ul>
+%s</ul>
+
+</body>
+</html>
+`
+
+func (t *versionPerfTriggers) email(selectors []event.Selector, changePoints []perf.ChangePoint) *message.Email {
+	items := ""
+	for _, cp := range changePoints {
+		items += fmt.Sprintf("<li>%s</li>\n", html.EscapeString(describeChangePoint(cp)))
+	}
+
+	return &message.Email{
+		Subject:           fmt.Sprintf("Evergreen: performance regressed in %s version %s", t.version.Identifier, t.version.Id),
+		Body:              fmt.Sprintf(versionPerfEmailTemplate, versionLink(t.uiConfig.Url, t.version.Id), t.version.Id, t.version.Identifier, items),
+		PlainTextContents: false,
+		Headers:           makeHeaders(selectors),
+	}
+}
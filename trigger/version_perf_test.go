@@ -0,0 +1,61 @@
+package trigger
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionPerfChangePointNotifiesRegressions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	trigger := &versionPerfTriggers{
+		event:   &event.EventLogEntry{ID: "e1", ResourceType: event.ResourceTypeVersion, ResourceId: "v1"},
+		version: &version.Version{Id: "v1", Identifier: "proj"},
+		changePoints: []perf.ChangePoint{
+			{Variant: "bv", Task: "t", Measurement: "ops_per_sec", Direction: perf.DirectionHigher, PercentChange: 20},
+			{Variant: "bv", Task: "t", Measurement: "latency", Direction: perf.DirectionLower, PercentChange: -20},
+			{Variant: "bv", Task: "t", Measurement: "inserts", Direction: perf.DirectionHigher, PercentChange: -15},
+			{Variant: "bv", Task: "t", Measurement: "latency_ms", Direction: perf.DirectionLower, PercentChange: 5},
+		},
+	}
+	sub := &event.Subscription{
+		ID:         "s1",
+		Trigger:    triggerPerfChangePoint,
+		Selectors:  []event.Selector{{Type: selectorBuildVariant, Data: "bv"}},
+		Subscriber: event.Subscriber{Type: event.SlackSubscriberType, Target: "#perf"},
+	}
+
+	n, err := trigger.perfChangePoint(sub)
+	require.NoError(err)
+	require.NotNil(n)
+	payload, ok := n.Payload.(*notification.SlackPayload)
+	require.True(ok)
+	require.Len(payload.Attachments, 1)
+	assert.Contains(payload.Attachments[0].Text, "inserts")
+	assert.Contains(payload.Attachments[0].Text, "latency_ms")
+	assert.NotContains(payload.Attachments[0].Text, "ops_per_sec")
+	assert.NotContains(payload.Attachments[0].Text, "latency changed")
+
+	// the percentage applies to the regressions
+	sub.TriggerData = map[string]string{event.VersionPercentChangeKey: "10"}
+	n, err = trigger.perfChangePoint(sub)
+	require.NoError(err)
+	require.NotNil(n)
+	payload = n.Payload.(*notification.SlackPayload)
+	assert.Contains(payload.Attachments[0].Text, "inserts")
+	assert.NotContains(payload.Attachments[0].Text, "latency_ms")
+
+	// improvements alone notify nothing
+	trigger.changePoints = trigger.changePoints[:2]
+	sub.TriggerData = nil
+	n, err = trigger.perfChangePoint(sub)
+	assert.NoError(err)
+	assert.Nil(n)
+}
@@ -0,0 +1,292 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
+	"github.com/goamz/goamz/s3"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	artifactRetentionJobName = "artifact-retention"
+
+	// expired tasks are processed this many at a time, so that neither
+	// the tasks nor the query for their artifacts grow with the age of
+	// the project
+	artifactRetentionBatchSize = 500
+)
+
+func init() {
+	registry.AddJobType(artifactRetentionJobName,
+		func() amboy.Job { return makeArtifactRetentionJob() })
+}
+
+// artifactStore deletes artifact objects, returning the number of bytes
+// that were reclaimed.
+type artifactStore interface {
+	Delete(bucket, path string) (int64, error)
+}
+
+type artifactRetentionJob struct {
+	ProjectID      string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	BytesReclaimed int64  `bson:"bytes_reclaimed" json:"bytes_reclaimed" yaml:"bytes_reclaimed"`
+	FilesExpired   int    `bson:"files_expired" json:"files_expired" yaml:"files_expired"`
+	job.Base       `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	settings *evergreen.Settings
+	store    artifactStore
+}
+
+func makeArtifactRetentionJob() *artifactRetentionJob {
+	j := &artifactRetentionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    artifactRetentionJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewArtifactRetentionJob deletes the attached files of a project's
+// tasks that have expired under the project's retention rules, and
+// marks them as expired.
+func NewArtifactRetentionJob(projectID, id string) amboy.Job {
+	j := makeArtifactRetentionJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", artifactRetentionJobName, projectID, id))
+	return j
+}
+
+func (j *artifactRetentionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.settings == nil {
+		j.settings = evergreen.GetEnvironment().Settings()
+	}
+	if j.settings == nil {
+		j.AddError(errors.New("evergreen settings are not configured"))
+		return
+	}
+	if j.store == nil {
+		j.store = newS3ArtifactStore(j.settings.ArtifactRetention)
+	}
+
+	ref, err := model.FindOneProjectRef(j.ProjectID)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if ref == nil {
+		j.AddError(errors.Errorf("project '%s' not found", j.ProjectID))
+		return
+	}
+
+	now := time.Now()
+	for _, rule := range ref.ArtifactRetention {
+		if ctx.Err() != nil {
+			j.AddError(errors.New("artifact retention canceled"))
+			break
+		}
+		if err = rule.Validate(); err != nil {
+			j.AddError(errors.Wrapf(err, "skipping invalid retention rule for project '%s'", j.ProjectID))
+			continue
+		}
+		j.AddError(j.applyRule(ctx, rule, now))
+	}
+
+	grip.InfoWhen(j.FilesExpired > 0, message.Fields{
+		"job":             j.ID(),
+		"job_type":        artifactRetentionJobName,
+		"project":         j.ProjectID,
+		"files_expired":   j.FilesExpired,
+		"bytes_reclaimed": j.BytesReclaimed,
+	})
+}
+
+func (j *artifactRetentionJob) applyRule(ctx context.Context, rule artifact.RetentionRule, now time.Time) error {
+	keep := map[string]bool{}
+	if rule.KeepGreenVersions > 0 {
+		versions, err := version.Find(version.ByMostRecentSucceeded(j.ProjectID).
+			WithFields(version.IdKey).Limit(rule.KeepGreenVersions))
+		if err != nil {
+			return errors.Wrapf(err, "problem finding green versions for project '%s'", j.ProjectID)
+		}
+		for _, v := range versions {
+			keep[v.Id] = true
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	cutoff := rule.Cutoff(now)
+	lastID := ""
+	for {
+		if ctx.Err() != nil {
+			return errors.New("artifact retention canceled")
+		}
+
+		tasks, err := task.Find(task.ByFinishedBefore(cutoff, j.ProjectID, rule.Requesters(), lastID).
+			WithFields(task.IdKey, task.VersionKey).Limit(artifactRetentionBatchSize))
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem finding expired tasks for project '%s'", j.ProjectID))
+			break
+		}
+		if len(tasks) == 0 {
+			break
+		}
+		lastID = tasks[len(tasks)-1].Id
+
+		taskIds := []string{}
+		for _, t := range tasks {
+			if !keep[t.Version] {
+				taskIds = append(taskIds, t.Id)
+			}
+		}
+		if len(taskIds) > 0 {
+			catcher.Add(j.expireTaskArtifacts(ctx, rule, taskIds))
+		}
+
+		if len(tasks) < artifactRetentionBatchSize {
+			break
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+// expireTaskArtifacts deletes and marks expired the files attached to the
+// given tasks that the rule matches. Only files in the configured buckets
+// are deleted, and a file that other tasks still link to is only marked
+// expired, so that it is deleted once the last of them expires.
+func (j *artifactRetentionJob) expireTaskArtifacts(ctx context.Context, rule artifact.RetentionRule, taskIds []string) error {
+	entries, err := artifact.FindAll(artifact.ByTaskIdsWithUnexpiredFiles(taskIds))
+	if err != nil {
+		return errors.Wrap(err, "problem finding artifacts")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, entry := range entries {
+		for _, file := range entry.Files {
+			if ctx.Err() != nil {
+				return errors.New("artifact retention canceled")
+			}
+			if file.Expired || !rule.MatchesName(file.Name) {
+				continue
+			}
+			bucket, path, ok := parseS3ArtifactLink(file.Link)
+			if !ok || !util.StringSliceContains(j.settings.ArtifactRetention.Buckets, bucket) {
+				continue
+			}
+
+			others, err := artifact.FindAll(artifact.ByOtherReferencesToLink(file.Link, entry.TaskId, entry.Execution).Limit(1))
+			if err != nil {
+				catcher.Add(errors.Wrapf(err, "problem finding other references to artifact '%s'", file.Link))
+				continue
+			}
+			var size int64
+			if len(others) == 0 {
+				size, err = j.store.Delete(bucket, path)
+				if err != nil {
+					catcher.Add(errors.Wrapf(err, "problem deleting artifact '%s'", file.Link))
+					continue
+				}
+			}
+			if err = entry.MarkFileExpired(file.Link); err != nil {
+				catcher.Add(errors.Wrapf(err, "problem marking artifact '%s' expired", file.Link))
+				continue
+			}
+			j.FilesExpired++
+			j.BytesReclaimed += size
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+// parseS3ArtifactLink returns the bucket and key of an artifact link in
+// either the path style written by s3.put or the virtual host style. It
+// returns false for links that do not point at s3.
+func parseS3ArtifactLink(link string) (string, string, bool) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", "", false
+	}
+
+	const s3Host = "s3.amazonaws.com"
+	path := strings.TrimPrefix(u.Path, "/")
+	switch {
+	case u.Host == s3Host:
+		parts := strings.SplitN(path, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", false
+		}
+		return parts[0], parts[1], true
+	case strings.HasSuffix(u.Host, "."+s3Host):
+		if path == "" {
+			return "", "", false
+		}
+		return strings.TrimSuffix(u.Host, "."+s3Host), path, true
+	default:
+		return "", "", false
+	}
+}
+
+type s3ArtifactStore struct {
+	conf evergreen.ArtifactRetentionConfig
+}
+
+func newS3ArtifactStore(conf evergreen.ArtifactRetentionConfig) artifactStore {
+	return &s3ArtifactStore{conf: conf}
+}
+
+func (s *s3ArtifactStore) Delete(bucket, path string) (int64, error) {
+	region := aws.USEast
+	if s.conf.Endpoint != "" {
+		region = aws.Region{
+			Name:       "custom",
+			S3Endpoint: s.conf.Endpoint,
+		}
+	}
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	b := thirdparty.NewS3Session(&aws.Auth{
+		AccessKey: s.conf.AWSKey,
+		SecretKey: s.conf.AWSSecret,
+	}, region, client).Bucket(bucket)
+
+	var size int64
+	resp, err := b.Head(path, nil)
+	if err != nil {
+		// an object that is already gone has nothing left to reclaim
+		if s3err, ok := err.(*s3.Error); ok && s3err.StatusCode == 404 {
+			return 0, nil
+		}
+		return 0, errors.WithStack(err)
+	}
+	size = resp.ContentLength
+	grip.Warning(resp.Body.Close())
+
+	return size, errors.WithStack(b.Del(path))
+}
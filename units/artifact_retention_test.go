@@ -0,0 +1,136 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockArtifactStore struct {
+	deleted []string
+}
+
+func (s *mockArtifactStore) Delete(bucket, path string) (int64, error) {
+	s.deleted = append(s.deleted, bucket+"/"+path)
+	return 100, nil
+}
+
+func TestParseS3ArtifactLink(t *testing.T) {
+	assert := assert.New(t)
+
+	bucket, path, ok := parseS3ArtifactLink("https://s3.amazonaws.com/mciuploads/proj/file.tgz")
+	assert.True(ok)
+	assert.Equal("mciuploads", bucket)
+	assert.Equal("proj/file.tgz", path)
+
+	bucket, path, ok = parseS3ArtifactLink("https://mciuploads.s3.amazonaws.com/proj/file.tgz")
+	assert.True(ok)
+	assert.Equal("mciuploads", bucket)
+	assert.Equal("proj/file.tgz", path)
+
+	for _, link := range []string{
+		"https://example.com/file.tgz",
+		"https://s3.amazonaws.com/mciuploads",
+		"https://mciuploads.s3.amazonaws.com/",
+		"%",
+	} {
+		_, _, ok = parseS3ArtifactLink(link)
+		assert.False(ok, link)
+	}
+}
+
+func TestArtifactRetentionJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(model.ProjectRefCollection, task.Collection,
+		version.Collection, artifact.Collection))
+
+	ref := model.ProjectRef{
+		Identifier: "proj",
+		ArtifactRetention: []artifact.RetentionRule{
+			{Requester: artifact.RetentionRequesterPatch, MaxAgeDays: 7},
+			{Requester: artifact.RetentionRequesterMainline, NamePattern: "*.tgz", MaxAgeDays: 30, KeepGreenVersions: 1},
+		},
+	}
+	require.NoError(ref.Insert())
+
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	for _, v := range []version.Version{
+		{Id: "green-old", Identifier: "proj", Requester: evergreen.RepotrackerVersionRequester, Status: evergreen.VersionSucceeded, RevisionOrderNumber: 1},
+		{Id: "green-new", Identifier: "proj", Requester: evergreen.RepotrackerVersionRequester, Status: evergreen.VersionSucceeded, RevisionOrderNumber: 2},
+	} {
+		require.NoError(v.Insert())
+	}
+	for _, tsk := range []task.Task{
+		{Id: "patch", Project: "proj", Version: "p", Requester: evergreen.PatchVersionRequester, Status: evergreen.TaskFailed, FinishTime: old},
+		{Id: "recent-patch", Project: "proj", Version: "p2", Requester: evergreen.PatchVersionRequester, Status: evergreen.TaskFailed, FinishTime: time.Now()},
+		{Id: "mainline-old", Project: "proj", Version: "green-old", Requester: evergreen.RepotrackerVersionRequester, Status: evergreen.TaskSucceeded, FinishTime: old},
+		{Id: "mainline-kept", Project: "proj", Version: "green-new", Requester: evergreen.RepotrackerVersionRequester, Status: evergreen.TaskSucceeded, FinishTime: old},
+		{Id: "other-project", Project: "other", Version: "o", Requester: evergreen.PatchVersionRequester, Status: evergreen.TaskFailed, FinishTime: old},
+	} {
+		require.NoError(tsk.Insert())
+	}
+	link := func(name string) string { return "https://s3.amazonaws.com/bucket/" + name }
+	for _, entry := range []artifact.Entry{
+		{TaskId: "patch", Files: []artifact.File{
+			{Name: "log.txt", Link: link("patch/log.txt")},
+			{Name: "external", Link: "https://example.com/x"},
+			{Name: "foreign", Link: "https://s3.amazonaws.com/elsewhere/patch/x"},
+			{Name: "shared", Link: link("shared/lib.so")},
+		}},
+		{TaskId: "recent-patch", Files: []artifact.File{{Name: "log.txt", Link: link("recent/log.txt")}, {Name: "shared", Link: link("shared/lib.so")}}},
+		{TaskId: "mainline-old", Files: []artifact.File{{Name: "bin.tgz", Link: link("old/bin.tgz")}, {Name: "log.txt", Link: link("old/log.txt")}}},
+		{TaskId: "mainline-kept", Files: []artifact.File{{Name: "bin.tgz", Link: link("kept/bin.tgz")}}},
+		{TaskId: "other-project", Files: []artifact.File{{Name: "log.txt", Link: link("other/log.txt")}}},
+	} {
+		require.NoError(entry.Upsert())
+	}
+
+	settings := &evergreen.Settings{
+		ArtifactRetention: evergreen.ArtifactRetentionConfig{Buckets: []string{"bucket"}},
+	}
+	store := &mockArtifactStore{}
+	j := NewArtifactRetentionJob("proj", "test").(*artifactRetentionJob)
+	j.settings = settings
+	j.store = store
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+
+	// files outside of the configured buckets are left alone, and files
+	// that unexpired tasks still link to are expired without deleting them
+	assert.Equal([]string{"bucket/patch/log.txt", "bucket/old/bin.tgz"}, store.deleted)
+	assert.Equal(3, j.FilesExpired)
+	assert.EqualValues(200, j.BytesReclaimed)
+
+	entry, err := artifact.FindOne(artifact.ByTaskId("patch"))
+	require.NoError(err)
+	require.Len(entry.Files, 4)
+	assert.True(entry.Files[0].Expired)
+	assert.False(entry.Files[2].Expired)
+	assert.True(entry.Files[3].Expired)
+
+	entry, err = artifact.FindOne(artifact.ByTaskId("mainline-old"))
+	require.NoError(err)
+	assert.True(entry.Files[0].Expired)
+	assert.False(entry.Files[1].Expired)
+
+	// expired files are not deleted twice
+	store.deleted = nil
+	j = NewArtifactRetentionJob("proj", "again").(*artifactRetentionJob)
+	j.settings = settings
+	j.store = store
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	assert.Empty(store.deleted)
+}
@@ -0,0 +1,155 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	buildCacheEvictionJobName = "build-cache-eviction"
+)
+
+func init() {
+	registry.AddJobType(buildCacheEvictionJobName,
+		func() amboy.Job { return makeBuildCacheEvictionJob() })
+}
+
+type buildCacheEvictionJob struct {
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	settings     *evergreen.Settings
+	deleteObject func(bucket, remotePath string) error
+}
+
+func makeBuildCacheEvictionJob() *buildCacheEvictionJob {
+	j := &buildCacheEvictionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    buildCacheEvictionJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewBuildCacheEvictionJob removes a project's cache entries that have
+// not been used within the configured ttl, and then the least recently
+// used entries until the project is within its size limit.
+func NewBuildCacheEvictionJob(projectID, id string) amboy.Job {
+	j := makeBuildCacheEvictionJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", buildCacheEvictionJobName, projectID, id))
+	return j
+}
+
+func (j *buildCacheEvictionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.settings == nil {
+		j.settings = evergreen.GetEnvironment().Settings()
+	}
+	if j.settings == nil {
+		j.AddError(errors.New("evergreen settings are not configured"))
+		return
+	}
+	conf := j.settings.BuildCache
+	if conf.Bucket == "" {
+		j.AddError(errors.New("build cache bucket is not configured"))
+		return
+	}
+
+	if j.deleteObject == nil {
+		j.deleteObject = j.deleteS3Object
+	}
+
+	entries, err := buildcache.Find(buildcache.ByProject(j.ProjectID))
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	ttl := time.Duration(conf.TTLDays) * 24 * time.Hour
+	maxSize := int64(conf.MaxSizeMB) * 1024 * 1024
+	evict := buildcache.EvictionCandidates(entries, time.Now(), ttl, maxSize)
+
+	var bytesEvicted int64
+	for _, entry := range evict {
+		if ctx.Err() != nil {
+			j.AddError(errors.New("build cache eviction canceled"))
+			break
+		}
+
+		// the object's location is derived from the entry's key
+		// rather than trusted, so that only cache objects are deleted
+		if err = buildcache.ValidateKey(entry.Key); err != nil {
+			j.AddError(errors.Wrapf(err, "not deleting cache entry '%s'", entry.ID))
+			continue
+		}
+		// the server's credentials only cover the configured bucket
+		if entry.Bucket != conf.Bucket {
+			j.AddError(errors.Errorf("not deleting cache entry '%s' outside of bucket '%s'",
+				entry.ID, conf.Bucket))
+			continue
+		}
+		remotePath := buildcache.RemotePath(entry.Project, entry.Key)
+		if err = j.deleteObject(entry.Bucket, remotePath); err != nil {
+			j.AddError(errors.Wrapf(err, "problem deleting cache object '%s/%s'",
+				entry.Bucket, remotePath))
+			continue
+		}
+		if err = entry.Remove(); err != nil {
+			j.AddError(err)
+			continue
+		}
+		j.AddError(buildcache.RecordEviction(j.ProjectID, entry.SizeBytes))
+		bytesEvicted += entry.SizeBytes
+	}
+
+	grip.InfoWhen(len(evict) > 0, message.Fields{
+		"job":           j.ID(),
+		"job_type":      buildCacheEvictionJobName,
+		"project":       j.ProjectID,
+		"entries":       len(entries),
+		"evicted":       len(evict),
+		"bytes_evicted": bytesEvicted,
+	})
+}
+
+func (j *buildCacheEvictionJob) deleteS3Object(bucket, remotePath string) error {
+	conf := j.settings.BuildCache
+
+	region := aws.USEast
+	if conf.Endpoint != "" {
+		region = aws.Region{
+			Name:       "custom",
+			S3Endpoint: conf.Endpoint,
+		}
+	}
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	session := thirdparty.NewS3Session(&aws.Auth{
+		AccessKey: conf.AWSKey,
+		SecretKey: conf.AWSSecret,
+	}, region, client)
+
+	return errors.WithStack(session.Bucket(bucket).Del(remotePath))
+}
@@ -0,0 +1,62 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/buildcache"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCacheEvictionJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(buildcache.Collection, buildcache.StatsCollection))
+
+	now := time.Now()
+	for _, e := range []buildcache.Entry{
+		{Project: "proj", Key: "expired", Bucket: "cache-bucket", RemotePath: "expired.tgz", SizeBytes: 1, LastAccessed: now.Add(-10 * 24 * time.Hour)},
+		{Project: "proj", Key: "lru", Bucket: "cache-bucket", RemotePath: "../../elsewhere", SizeBytes: 1024 * 1024, LastAccessed: now.Add(-2 * time.Hour)},
+		{Project: "proj", Key: "recent", Bucket: "cache-bucket", RemotePath: "recent.tgz", SizeBytes: 1024 * 1024, LastAccessed: now.Add(-time.Hour)},
+		{Project: "other", Key: "expired", Bucket: "cache-bucket", RemotePath: "other.tgz", SizeBytes: 1, LastAccessed: now.Add(-10 * 24 * time.Hour)},
+	} {
+		entry := e
+		require.NoError(entry.Upsert())
+	}
+
+	deleted := []string{}
+	j := NewBuildCacheEvictionJob("proj", "test").(*buildCacheEvictionJob)
+	j.settings = &evergreen.Settings{
+		BuildCache: evergreen.BuildCacheConfig{Bucket: "cache-bucket", MaxSizeMB: 1, TTLDays: 7},
+	}
+	j.deleteObject = func(bucket, remotePath string) error {
+		assert.Equal("cache-bucket", bucket)
+		deleted = append(deleted, remotePath)
+		return nil
+	}
+
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	assert.True(j.Status().Completed)
+	assert.Equal([]string{"build-cache/proj/expired.tgz", "build-cache/proj/lru.tgz"}, deleted)
+
+	remaining, err := buildcache.Find(buildcache.ByProject("proj"))
+	require.NoError(err)
+	require.Len(remaining, 1)
+	assert.Equal("recent", remaining[0].Key)
+
+	other, err := buildcache.Find(buildcache.ByProject("other"))
+	require.NoError(err)
+	assert.Len(other, 1)
+
+	stats, err := buildcache.FindStats("proj")
+	require.NoError(err)
+	assert.EqualValues(2, stats.Evictions)
+	assert.EqualValues(1024*1024+1, stats.BytesEvicted)
+}
@@ -3,6 +3,7 @@ package units
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
@@ -145,4 +146,5 @@ func (j *buildingContainerImageJob) Run(ctx context.Context) {
 		j.AddError(errors.Wrapf(err, "error upserting parent %s", j.parent.Id))
 		return
 	}
+	j.AddError(j.parent.SetContainerImageUsed(j.ImageURL, time.Now()))
 }
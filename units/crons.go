@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/buildcache"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/version"
@@ -644,6 +645,121 @@ func PopulateBackgroundStatsJobs(env evergreen.Environment, part int) amboy.Queu
 	}
 }
 
+func PopulateBuildCacheEvictionJobs(part int) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := buildcache.FindProjects()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(part).Format(tsFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, project := range projects {
+			catcher.Add(queue.Put(NewBuildCacheEvictionJob(project, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulateArtifactRetentionJobs(part int) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := model.FindProjectRefsWithArtifactRetention()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(part).Format(tsFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, project := range projects {
+			catcher.Add(queue.Put(NewArtifactRetentionJob(project.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulatePriorityPolicyJobs(part int) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if flags.SchedulerDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "scheduler is disabled",
+				"impact":  "priority policies are not applied",
+				"mode":    "degraded",
+			})
+			return nil
+		}
+
+		projects, err := model.FindProjectRefsWithPriorityPolicy()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(part).Format(tsFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, project := range projects {
+			catcher.Add(queue.Put(NewPriorityPolicyJob(project.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulateHostHealthJobs(env evergreen.Environment, part int) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		distros, err := distro.Find(distro.ByActive())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(part).Format(tsFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, d := range distros {
+			catcher.Add(queue.Put(NewHostHealthJob(env, d.Id, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+// PopulatePerfChangePointJobs detects perf change points for the
+// mainline versions that finished in the last hour. Versions are seen by
+// more than one run, which only stores change points that are new.
+func PopulatePerfChangePointJobs(part int) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if flags.PerfChangePointsDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "perf change point detection is disabled",
+				"impact":  "perf change points are not detected",
+				"mode":    "degraded",
+			})
+			return nil
+		}
+
+		versions, err := version.Find(version.ByMainlineFinishedSince(time.Now().Add(-time.Hour)))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(part).Format(tsFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, v := range versions {
+			catcher.Add(queue.Put(NewPerfChangePointsJob(v.Id, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
 func PopulatePeriodicNotificationJobs(parts int) amboy.QueueOperation {
 	return func(queue amboy.Queue) error {
 		flags, err := evergreen.GetServiceFlags()
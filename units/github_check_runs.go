@@ -0,0 +1,369 @@
+package units
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	githubCheckRunsJobName = "github-check-runs"
+
+	// a check run's summary lists at most this many failed tests
+	githubCheckRunMaxSummaryTests = 100
+	// a check run annotates at most this many failed tests
+	githubCheckRunMaxAnnotations = 250
+)
+
+func init() {
+	registry.AddJobType(githubCheckRunsJobName,
+		func() amboy.Job { return makeGithubCheckRunsJob() })
+}
+
+type githubCheckRunsJob struct {
+	BuildID  string `bson:"build_id" json:"build_id" yaml:"build_id"`
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	settings *evergreen.Settings
+	// installationToken and createCheckRun are replaced in tests
+	installationToken func(ctx context.Context, appID int64, privateKey, owner, repo string) (string, error)
+	createCheckRun    func(ctx context.Context, token, owner, repo string, run thirdparty.GithubCheckRun) error
+}
+
+func makeGithubCheckRunsJob() *githubCheckRunsJob {
+	j := &githubCheckRunsJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    githubCheckRunsJobName,
+				Version: 0,
+			},
+		},
+		installationToken: thirdparty.CreateGithubAppInstallationToken,
+		createCheckRun:    thirdparty.CreateGithubCheckRun,
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewGithubCheckRunsJob reports a finished build as Github check runs, one
+// for the build variant and one for each of its display tasks, if the
+// build's project has Github checks enabled.
+func NewGithubCheckRunsJob(buildID string) amboy.Job {
+	j := makeGithubCheckRunsJob()
+	j.BuildID = buildID
+	j.SetID(fmt.Sprintf("%s.%s.%d", githubCheckRunsJobName, buildID, job.GetNumber()))
+	return j
+}
+
+func (j *githubCheckRunsJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.settings == nil {
+		j.settings = evergreen.GetEnvironment().Settings()
+	}
+	if j.settings == nil {
+		j.AddError(errors.New("evergreen settings are not configured"))
+		return
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if flags.GithubStatusAPIDisabled {
+		grip.Info(message.Fields{
+			"job":      j.ID(),
+			"job_type": githubCheckRunsJobName,
+			"message":  "github status updates are disabled, not creating check runs",
+		})
+		return
+	}
+	if !j.settings.GithubApp.IsConfigured() {
+		grip.Info(message.Fields{
+			"job":      j.ID(),
+			"job_type": githubCheckRunsJobName,
+			"message":  "no github app is configured, not creating check runs",
+		})
+		return
+	}
+
+	b, err := build.FindOne(build.ById(j.BuildID))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding build '%s'", j.BuildID))
+		return
+	}
+	if b == nil {
+		j.AddError(errors.Errorf("build '%s' not found", j.BuildID))
+		return
+	}
+
+	ref, err := model.FindOneProjectRef(b.Project)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding project '%s'", b.Project))
+		return
+	}
+	if ref == nil || !ref.GithubChecksEnabled {
+		return
+	}
+
+	owner, repo, sha, err := githubCheckRunCommit(b, ref)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if sha == "" {
+		// only pull request patches and mainline commits have checks
+		return
+	}
+
+	grouped, err := model.FindGithubCheckRunTasks(b.Id)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	failedTests, err := findFailedTests(b.Id)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	// Github only accepts check runs from apps
+	token, err := j.installationToken(ctx, j.settings.GithubApp.AppID, j.settings.GithubApp.PrivateKey, owner, repo)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	runs := makeGithubCheckRuns(b, grouped, failedTests, sha, j.settings.Ui.Url)
+	for _, run := range runs {
+		if ctx.Err() != nil {
+			j.AddError(errors.New("github check runs canceled"))
+			return
+		}
+		j.AddError(j.createCheckRun(ctx, token, owner, repo, run))
+	}
+
+	grip.Info(message.Fields{
+		"job":        j.ID(),
+		"job_type":   githubCheckRunsJobName,
+		"build":      b.Id,
+		"project":    b.Project,
+		"commit":     sha,
+		"check_runs": len(runs),
+	})
+}
+
+// githubCheckRunCommit returns the repository and commit that a build's
+// check runs are reported on, or an empty commit if the build has none.
+func githubCheckRunCommit(b *build.Build, ref *model.ProjectRef) (string, string, string, error) {
+	switch b.Requester {
+	case evergreen.GithubPRRequester:
+		p, err := patch.FindOne(patch.ByVersion(b.Version))
+		if err != nil {
+			return "", "", "", errors.Wrapf(err, "problem finding patch for version '%s'", b.Version)
+		}
+		if p == nil {
+			return "", "", "", errors.Errorf("patch for version '%s' not found", b.Version)
+		}
+		return p.GithubPatchData.BaseOwner, p.GithubPatchData.BaseRepo, p.GithubPatchData.HeadHash, nil
+
+	case evergreen.RepotrackerVersionRequester:
+		return ref.Owner, ref.Repo, b.Revision, nil
+	}
+
+	return "", "", "", nil
+}
+
+// findFailedTests returns the failed tests of the latest executions of a
+// build's tasks, by task ID.
+func findFailedTests(buildID string) (map[string][]testresult.TestResult, error) {
+	tasks, err := task.Find(task.ByBuildId(buildID).WithFields(task.IdKey, task.ExecutionKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding tasks of build '%s'", buildID)
+	}
+	executions := map[string]int{}
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		executions[t.Id] = t.Execution
+		ids = append(ids, t.Id)
+	}
+
+	results, err := testresult.Find(testresult.ByTaskIDs(ids))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding test results of build '%s'", buildID)
+	}
+	failed := map[string][]testresult.TestResult{}
+	for _, r := range results {
+		if r.Status == evergreen.TestFailedStatus && r.Execution == executions[r.TaskID] {
+			failed[r.TaskID] = append(failed[r.TaskID], r)
+		}
+	}
+
+	return failed, nil
+}
+
+// makeGithubCheckRuns returns the check runs of a finished build: one for
+// the build variant and one for each of its display tasks.
+func makeGithubCheckRuns(b *build.Build, grouped *model.GithubCheckRunTasks,
+	failedTests map[string][]testresult.TestResult, sha, urlBase string) []thirdparty.GithubCheckRun {
+
+	name := b.DisplayName
+	if name == "" {
+		name = b.BuildVariant
+	}
+
+	runs := []thirdparty.GithubCheckRun{
+		makeGithubCheckRun(name, model.GithubCheckRunExternalID(b.Id, ""),
+			fmt.Sprintf("%s/build/%s", urlBase, b.Id), b.Status == evergreen.BuildSucceeded,
+			grouped.Variant, failedTests, urlBase),
+	}
+	for _, dt := range grouped.DisplayTasks {
+		runs = append(runs, makeGithubCheckRun(fmt.Sprintf("%s / %s", name, dt.DisplayName),
+			model.GithubCheckRunExternalID(b.Id, dt.Id),
+			fmt.Sprintf("%s/task/%s/%d", urlBase, dt.Id, dt.Execution), dt.Status == evergreen.TaskSucceeded,
+			grouped.ExecutionTasks[dt.Id], failedTests, urlBase))
+	}
+
+	for i := range runs {
+		runs[i].HeadSHA = sha
+		runs[i].Status = thirdparty.GithubCheckRunCompleted
+		if !b.FinishTime.IsZero() {
+			finished := b.FinishTime
+			runs[i].CompletedAt = &finished
+		}
+	}
+
+	return runs
+}
+
+func makeGithubCheckRun(name, externalID, detailsURL string, succeeded bool, tasks []task.Task,
+	failedTests map[string][]testresult.TestResult, urlBase string) thirdparty.GithubCheckRun {
+
+	run := thirdparty.GithubCheckRun{
+		Name:       name,
+		ExternalID: externalID,
+		DetailsURL: detailsURL,
+		Conclusion: thirdparty.GithubCheckConclusionSuccess,
+	}
+	if !succeeded {
+		run.Conclusion = thirdparty.GithubCheckConclusionFailure
+	}
+
+	failedTasks := []task.Task{}
+	for _, t := range tasks {
+		if t.Status == evergreen.TaskFailed {
+			failedTasks = append(failedTasks, t)
+		}
+	}
+
+	output := &thirdparty.GithubCheckRunOutput{
+		Title: fmt.Sprintf("%d of %d tasks failed", len(failedTasks), len(tasks)),
+	}
+	summary := &bytes.Buffer{}
+	if len(failedTasks) == 0 {
+		summary.WriteString("No tasks failed.\n")
+	} else {
+		summary.WriteString("### Failed tasks\n\n")
+		for _, t := range failedTasks {
+			fmt.Fprintf(summary, "- [%s](%s/task/%s/%d)\n", escapeMarkdown(t.DisplayName), urlBase, t.Id, t.Execution)
+		}
+		run.Actions = []thirdparty.GithubCheckRunAction{{
+			Label:       "Re-run failed",
+			Description: "Restart the failed tasks",
+			Identifier:  model.GithubCheckRunRerunFailed,
+		}}
+	}
+
+	numTests := 0
+	for _, t := range tasks {
+		for _, r := range failedTests[t.Id] {
+			if numTests == 0 {
+				summary.WriteString("\n### Failed tests\n\n| Test | Task |\n| --- | --- |\n")
+			}
+			if numTests < githubCheckRunMaxSummaryTests {
+				fmt.Fprintf(summary, "| [%s](%s) | %s |\n", escapeMarkdown(r.TestFile),
+					githubCheckTestURL(urlBase, &t, r), escapeMarkdown(t.DisplayName))
+			}
+			numTests++
+
+			if r.SourceLine <= 0 || len(output.Annotations) >= githubCheckRunMaxAnnotations {
+				continue
+			}
+			annotationPath, ok := githubAnnotationPath(r.SourceFile)
+			if !ok {
+				continue
+			}
+			msg := r.Message
+			if msg == "" {
+				msg = fmt.Sprintf("%s failed", r.TestFile)
+			}
+			output.Annotations = append(output.Annotations, thirdparty.GithubCheckRunAnnotation{
+				Path:            annotationPath,
+				StartLine:       r.SourceLine,
+				EndLine:         r.SourceLine,
+				AnnotationLevel: thirdparty.GithubAnnotationLevelFailure,
+				Title:           fmt.Sprintf("%s (%s)", r.TestFile, t.DisplayName),
+				Message:         msg,
+			})
+		}
+	}
+	if numTests > githubCheckRunMaxSummaryTests {
+		fmt.Fprintf(summary, "\nand %d more failed tests\n", numTests-githubCheckRunMaxSummaryTests)
+	}
+
+	output.Summary = summary.String()
+	run.Output = output
+	return run
+}
+
+// githubAnnotationPath returns the path, relative to the repository root,
+// of the file that a failure was reported in. Github rejects annotations of
+// files that are not in the repository, so a path that can't be resolved,
+// such as the bare file name that go test reports, is not annotated.
+func githubAnnotationPath(file string) (string, bool) {
+	if file == "" {
+		return "", false
+	}
+	file = path.Clean(strings.Replace(file, "\\", "/", -1))
+	if path.IsAbs(file) || file == ".." || strings.HasPrefix(file, "../") || !strings.Contains(file, "/") {
+		return "", false
+	}
+	return file, true
+}
+
+// githubCheckTestURL links to a test's log, or to its task if it has none.
+func githubCheckTestURL(urlBase string, t *task.Task, r testresult.TestResult) string {
+	switch {
+	case strings.HasPrefix(r.URL, "/"):
+		return urlBase + r.URL
+	case r.URL != "":
+		return r.URL
+	case r.LogID != "":
+		return fmt.Sprintf("%s/test_log/%s", urlBase, r.LogID)
+	}
+	return fmt.Sprintf("%s/task/%s/%d", urlBase, t.Id, t.Execution)
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "[", "\\[", "]", "\\]")
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
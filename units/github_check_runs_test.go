@@ -0,0 +1,177 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestMakeGithubCheckRuns(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	finished := time.Now()
+	b := &build.Build{Id: "b", DisplayName: "Ubuntu", Status: evergreen.BuildFailed, FinishTime: finished}
+	grouped := &model.GithubCheckRunTasks{
+		Variant: []task.Task{
+			{Id: "compile", DisplayName: "compile", Status: evergreen.TaskSucceeded},
+			{Id: "unit", DisplayName: "unit", Status: evergreen.TaskFailed, Execution: 1},
+			{Id: "lint", DisplayName: "lint", Status: evergreen.TaskSucceeded, DisplayOnly: true},
+		},
+		DisplayTasks: []task.Task{
+			{Id: "lint", DisplayName: "lint", Status: evergreen.TaskSucceeded, DisplayOnly: true},
+		},
+		ExecutionTasks: map[string][]task.Task{
+			"lint": {{Id: "lint-go", DisplayName: "lint-go", Status: evergreen.TaskSucceeded}},
+		},
+	}
+	failedTests := map[string][]testresult.TestResult{
+		"unit": {
+			{TestFile: "TestAdd", URL: "/test_log/unit/1/TestAdd", SourceFile: "math/add_test.go", SourceLine: 12, Message: "expected 2"},
+			{TestFile: "TestSub|Neg", LogID: "log"},
+		},
+	}
+
+	runs := makeGithubCheckRuns(b, grouped, failedTests, "abcdef", "https://example.com")
+	require.Len(runs, 2)
+
+	variant := runs[0]
+	assert.Equal("Ubuntu", variant.Name)
+	assert.Equal(model.GithubCheckRunExternalID("b", ""), variant.ExternalID)
+	assert.Equal("https://example.com/build/b", variant.DetailsURL)
+	assert.Equal("abcdef", variant.HeadSHA)
+	assert.Equal(thirdparty.GithubCheckRunCompleted, variant.Status)
+	assert.Equal(thirdparty.GithubCheckConclusionFailure, variant.Conclusion)
+	require.NotNil(variant.CompletedAt)
+	assert.Equal(finished, *variant.CompletedAt)
+	require.Len(variant.Actions, 1)
+	assert.Equal(model.GithubCheckRunRerunFailed, variant.Actions[0].Identifier)
+
+	require.NotNil(variant.Output)
+	assert.Equal("1 of 3 tasks failed", variant.Output.Title)
+	assert.Contains(variant.Output.Summary, "- [unit](https://example.com/task/unit/1)")
+	assert.Contains(variant.Output.Summary, "| [TestAdd](https://example.com/test_log/unit/1/TestAdd) | unit |")
+	assert.Contains(variant.Output.Summary, `| [TestSub\|Neg](https://example.com/test_log/log) | unit |`)
+	assert.Equal([]thirdparty.GithubCheckRunAnnotation{{
+		Path:            "math/add_test.go",
+		StartLine:       12,
+		EndLine:         12,
+		AnnotationLevel: thirdparty.GithubAnnotationLevelFailure,
+		Title:           "TestAdd (unit)",
+		Message:         "expected 2",
+	}}, variant.Output.Annotations)
+
+	display := runs[1]
+	assert.Equal("Ubuntu / lint", display.Name)
+	assert.Equal(model.GithubCheckRunExternalID("b", "lint"), display.ExternalID)
+	assert.Equal("https://example.com/task/lint/0", display.DetailsURL)
+	assert.Equal(thirdparty.GithubCheckConclusionSuccess, display.Conclusion)
+	assert.Empty(display.Actions)
+	assert.Equal("0 of 1 tasks failed", display.Output.Title)
+	assert.Empty(display.Output.Annotations)
+}
+
+func TestGithubCheckRunsJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(model.ProjectRefCollection, build.Collection, task.Collection,
+		testresult.Collection, patch.Collection, evergreen.ConfigCollection))
+
+	ref := model.ProjectRef{Identifier: "proj", Owner: "evergreen-ci", Repo: "evergreen", Enabled: true, GithubChecksEnabled: true}
+	require.NoError(ref.Insert())
+	p := patch.Patch{
+		Id:      bson.NewObjectId(),
+		Version: "v",
+		GithubPatchData: patch.GithubPatch{
+			BaseOwner: "evergreen-ci",
+			BaseRepo:  "evergreen",
+			HeadHash:  "abcdef",
+		},
+	}
+	require.NoError(p.Insert())
+	for _, b := range []build.Build{
+		{Id: "pr", Project: "proj", Version: "v", Requester: evergreen.GithubPRRequester, Status: evergreen.BuildFailed},
+		{Id: "mainline", Project: "proj", Revision: "123456", Requester: evergreen.RepotrackerVersionRequester, Status: evergreen.BuildSucceeded},
+		{Id: "cli", Project: "proj", Requester: evergreen.PatchVersionRequester, Status: evergreen.BuildSucceeded},
+	} {
+		require.NoError(b.Insert())
+	}
+	tsk := task.Task{Id: "t", BuildId: "pr", Status: evergreen.TaskFailed}
+	require.NoError(tsk.Insert())
+	require.NoError(tsk.SetResults([]task.TestResult{
+		{TestFile: "TestAdd", Status: evergreen.TestFailedStatus, SourceFile: "./math/add_test.go", SourceLine: 3},
+		{TestFile: "TestMul", Status: evergreen.TestFailedStatus, SourceFile: "mul_test.go", SourceLine: 5},
+		{TestFile: "TestSub", Status: evergreen.TestSucceededStatus},
+	}))
+
+	settings := testutil.TestConfig()
+	settings.GithubApp = evergreen.GithubAppConfig{AppID: 1234, PrivateKey: "key"}
+
+	for buildID, expected := range map[string]struct {
+		owner, sha string
+		runs       int
+	}{
+		"pr":       {owner: "evergreen-ci", sha: "abcdef", runs: 1},
+		"mainline": {owner: "evergreen-ci", sha: "123456", runs: 1},
+		"cli":      {},
+	} {
+		runs := []thirdparty.GithubCheckRun{}
+		j := NewGithubCheckRunsJob(buildID).(*githubCheckRunsJob)
+		j.settings = settings
+		j.installationToken = func(_ context.Context, appID int64, privateKey, owner, repo string) (string, error) {
+			assert.EqualValues(1234, appID)
+			assert.Equal("key", privateKey)
+			return "installation token", nil
+		}
+		j.createCheckRun = func(_ context.Context, token, owner, repo string, run thirdparty.GithubCheckRun) error {
+			assert.Equal("installation token", token)
+			assert.Equal(expected.owner, owner)
+			assert.Equal("evergreen", repo)
+			runs = append(runs, run)
+			return nil
+		}
+		j.Run(context.Background())
+		assert.NoError(j.Error(), buildID)
+		require.Len(runs, expected.runs, buildID)
+		for _, run := range runs {
+			assert.Equal(expected.sha, run.HeadSHA)
+		}
+		if buildID == "pr" {
+			require.Len(runs[0].Output.Annotations, 1)
+			assert.Equal("math/add_test.go", runs[0].Output.Annotations[0].Path)
+		}
+	}
+}
+
+func TestGithubAnnotationPath(t *testing.T) {
+	assert := assert.New(t)
+
+	for file, expected := range map[string]string{
+		"math/add_test.go":      "math/add_test.go",
+		"./math/add_test.go":    "math/add_test.go",
+		"math\\add_test.go":     "math/add_test.go",
+		"math/../add/add.go":    "add/add.go",
+		"":                      "",
+		"add_test.go":           "",
+		"/src/math/add_test.go": "",
+		"../math/add_test.go":   "",
+	} {
+		resolved, ok := githubAnnotationPath(file)
+		assert.Equal(expected, resolved, file)
+		assert.Equal(expected != "", ok, file)
+	}
+}
@@ -27,6 +27,7 @@ const (
 	githubUpdateTypeNewPatch    = "new-patch"
 	githubUpdateTypeRequestAuth = "request-auth"
 	githubUpdateTypeBadConfig   = "bad-config"
+	githubUpdateTypeSuperseded  = "superseded"
 )
 
 func init() {
@@ -93,6 +94,18 @@ func NewGithubStatusUpdateJobForBadConfig(intentID string) amboy.Job {
 	return job
 }
 
+// NewGithubStatusUpdateJobForSupersededPatch marks a patch's head commit as
+// superseded because a newer push to the pull request aborted the patch
+func NewGithubStatusUpdateJobForSupersededPatch(patchID string) amboy.Job {
+	job := makeGithubStatusUpdateJob()
+	job.FetchID = patchID
+	job.UpdateType = githubUpdateTypeSuperseded
+
+	job.SetID(fmt.Sprintf("%s:%s-%s-%s", githubStatusUpdateJobName, job.UpdateType, patchID, time.Now().String()))
+
+	return job
+}
+
 func (j *githubStatusUpdateJob) preamble() error {
 	if j.env == nil {
 		j.env = evergreen.GetEnvironment()
@@ -171,6 +184,12 @@ func (j *githubStatusUpdateJob) fetch() (*message.GithubStatus, error) {
 		status.Context = "evergreen"
 		status.Description = "patch must be manually authorized"
 		status.State = message.GithubStateFailure
+
+	} else if j.UpdateType == githubUpdateTypeSuperseded {
+		status.URL = fmt.Sprintf("%s/version/%s", j.urlBase, j.FetchID)
+		status.Context = "evergreen"
+		status.Description = "superseded"
+		status.State = message.GithubStateError
 	}
 
 	if patchDoc == nil {
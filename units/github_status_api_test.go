@@ -283,3 +283,24 @@ func (s *githubStatusUpdateSuite) TestWithGithub() {
 	s.Equal("evergreen", *lastStatus.Context)
 	s.Equal(fmt.Sprintf("http://example.com/version/%s", s.patchDoc.Version), *lastStatus.TargetURL)
 }
+
+func (s *githubStatusUpdateSuite) TestForSupersededPatch() {
+	job, ok := NewGithubStatusUpdateJobForSupersededPatch(s.patchDoc.Id.Hex()).(*githubStatusUpdateJob)
+	s.Require().NotNil(job)
+	s.Require().True(ok)
+	s.Require().Equal(githubUpdateTypeSuperseded, job.UpdateType)
+	job.env = s.env
+	job.Run(context.Background())
+	s.False(job.HasErrors())
+
+	status := s.msgToStatus(s.env.InternalSender)
+
+	s.Equal("evergreen-ci", status.Owner)
+	s.Equal("evergreen", status.Repo)
+	s.Equal("776f608b5b12cd27b8d931c8ee4ca0c13f857299", status.Ref)
+
+	s.Equal(fmt.Sprintf("https://example.com/version/%s", s.patchDoc.Version), status.URL)
+	s.Equal("superseded", status.Description)
+	s.Equal("evergreen", status.Context)
+	s.Equal(message.GithubStateError, status.State)
+}
@@ -0,0 +1,162 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const hostHealthJobName = "host-health-scoring"
+
+func init() {
+	registry.AddJobType(hostHealthJobName,
+		func() amboy.Job { return makeHostHealthJob() })
+}
+
+type hostHealthJob struct {
+	DistroID    string `bson:"distro_id" json:"distro_id" yaml:"distro_id"`
+	Scored      int    `bson:"scored" json:"scored" yaml:"scored"`
+	Quarantined int    `bson:"quarantined" json:"quarantined" yaml:"quarantined"`
+	job.Base    `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	env evergreen.Environment
+}
+
+func makeHostHealthJob() *hostHealthJob {
+	j := &hostHealthJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    hostHealthJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewHostHealthJob scores the running hosts of a distro by the outcomes of
+// the tasks they ran recently, and quarantines the hosts whose scores
+// reach the configured threshold.
+func NewHostHealthJob(env evergreen.Environment, distroID, id string) amboy.Job {
+	j := makeHostHealthJob()
+	j.env = env
+	j.DistroID = distroID
+	j.SetID(fmt.Sprintf("%s.%s.%s", hostHealthJobName, distroID, id))
+	return j
+}
+
+func (j *hostHealthJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	conf := j.env.Settings().HostHealth
+	if err := conf.ValidateAndDefault(); err != nil {
+		j.AddError(errors.Wrap(err, "invalid host health configuration"))
+		return
+	}
+
+	hosts, err := host.Find(host.ByDistroId(j.DistroID))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding hosts for distro %s", j.DistroID))
+		return
+	}
+
+	now := time.Now()
+	since := now.Add(-time.Duration(conf.WindowMinutes) * time.Minute)
+	tasks, err := task.Find(db.Query(bson.M{
+		task.DistroIdKey:   j.DistroID,
+		task.FinishTimeKey: bson.M{"$gte": since},
+		task.StatusKey:     bson.M{"$in": []string{evergreen.TaskSucceeded, evergreen.TaskFailed}},
+	}).WithFields(task.HostIdKey, task.StatusKey, task.DetailsKey, task.FinishTimeKey))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding recent tasks for distro %s", j.DistroID))
+		return
+	}
+
+	byHost := map[string][]task.Task{}
+	var otherFailures int
+	for _, t := range tasks {
+		byHost[t.HostId] = append(byHost[t.HostId], t)
+		if t.Status == evergreen.TaskFailed && !host.IsHostFailure(&t) {
+			otherFailures++
+		}
+	}
+	var distroRate float64
+	if len(tasks) > 0 {
+		distroRate = float64(otherFailures) / float64(len(tasks))
+	}
+
+	for i := range hosts {
+		h := &hosts[i]
+		if h.Status != evergreen.HostRunning {
+			continue
+		}
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+
+		stats := host.HealthStats{DistroTestFailureRate: distroRate, UpdatedAt: now}
+		for k := range byHost[h.Id] {
+			t := &byHost[h.Id][k]
+			if t.FinishTime.Before(h.Health.RestoredAt) {
+				continue
+			}
+			stats.AddTask(t)
+		}
+		stats.ComputeScore()
+
+		if err = h.SetHealth(stats); err != nil {
+			j.AddError(err)
+			continue
+		}
+		j.Scored++
+
+		if !conf.QuarantineEnabled || !stats.ExceedsThreshold(conf) {
+			continue
+		}
+		j.AddError(j.quarantine(h, stats))
+	}
+}
+
+func (j *hostHealthJob) quarantine(h *host.Host, stats host.HealthStats) error {
+	reason := stats.Reason()
+	if err := h.Quarantine(evergreen.User, reason); err != nil {
+		return errors.WithStack(err)
+	}
+	j.Quarantined++
+
+	grip.Warning(message.Fields{
+		"message":      "quarantined unhealthy host",
+		"job":          j.ID(),
+		"host":         h.Id,
+		"distro":       h.Distro.Id,
+		"provider":     h.Provider,
+		"score":        stats.Score,
+		"tasks":        stats.Tasks,
+		"will_replace": j.env.Settings().HostHealth.TerminateQuarantined && h.Provider != evergreen.ProviderNameStatic,
+	})
+
+	if !j.env.Settings().HostHealth.TerminateQuarantined || h.Provider == evergreen.ProviderNameStatic {
+		return nil
+	}
+	// decommissioned hosts are terminated once idle, and the allocator
+	// creates hosts to replace them
+	return errors.Wrapf(h.SetDecommissioned(evergreen.User, reason), "problem decommissioning host %s", h.Id)
+}
@@ -0,0 +1,91 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostHealthJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	testConfig := testutil.TestConfig()
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+	require.NoError(db.ClearCollections(host.Collection, task.Collection, event.AllLogCollection))
+
+	testConfig.HostHealth = evergreen.HostHealthConfig{
+		QuarantineEnabled:    true,
+		TerminateQuarantined: true,
+		Threshold:            0.5,
+		MinTasks:             4,
+	}
+	env := &mock.Environment{EvergreenSettings: testConfig}
+
+	d := distro.Distro{Id: "d1"}
+	healthy := host.Host{Id: "healthy", Distro: d, Status: evergreen.HostRunning, StartedBy: evergreen.User, Provider: evergreen.ProviderNameEc2OnDemand}
+	unhealthy := host.Host{Id: "unhealthy", Distro: d, Status: evergreen.HostRunning, StartedBy: evergreen.User, Provider: evergreen.ProviderNameEc2OnDemand}
+	static := host.Host{Id: "static", Distro: d, Status: evergreen.HostRunning, StartedBy: evergreen.User, Provider: evergreen.ProviderNameStatic}
+	for _, h := range []host.Host{healthy, unhealthy, static} {
+		require.NoError(h.Insert())
+	}
+
+	finished := time.Now().Add(-time.Hour)
+	systemFailure := apimodels.TaskEndDetail{Type: evergreen.CommandTypeSystem}
+	for i := 0; i < 4; i++ {
+		for _, tsk := range []task.Task{
+			{HostId: "healthy", Status: evergreen.TaskSucceeded},
+			{HostId: "unhealthy", Status: evergreen.TaskFailed, Details: systemFailure},
+			{HostId: "static", Status: evergreen.TaskFailed, Details: systemFailure},
+		} {
+			tsk.Id = fmt.Sprintf("%s-%d", tsk.HostId, i)
+			tsk.DistroId = "d1"
+			tsk.FinishTime = finished
+			require.NoError(tsk.Insert())
+		}
+	}
+
+	j := NewHostHealthJob(env, "d1", "ts")
+	j.Run(context.Background())
+	require.NoError(j.Error())
+	assert.Equal(3, j.(*hostHealthJob).Scored)
+	assert.Equal(2, j.(*hostHealthJob).Quarantined)
+
+	dbHost, err := host.FindOneId("healthy")
+	require.NoError(err)
+	assert.Equal(evergreen.HostRunning, dbHost.Status)
+	assert.Equal(4, dbHost.Health.Tasks)
+	assert.Zero(dbHost.Health.Score)
+
+	// dynamic hosts are replaced, while static hosts stay quarantined
+	dbHost, err = host.FindOneId("unhealthy")
+	require.NoError(err)
+	assert.Equal(evergreen.HostDecommissioned, dbHost.Status)
+	assert.Equal(1.0, dbHost.Health.Score)
+
+	dbHost, err = host.FindOneId("static")
+	require.NoError(err)
+	assert.Equal(evergreen.HostQuarantined, dbHost.Status)
+
+	// restored hosts are scored only on the tasks they ran afterwards
+	require.NoError(dbHost.Restore("admin"))
+	j = NewHostHealthJob(env, "d1", "ts2")
+	j.Run(context.Background())
+	require.NoError(j.Error())
+	dbHost, err = host.FindOneId("static")
+	require.NoError(err)
+	assert.Equal(evergreen.HostRunning, dbHost.Status)
+	assert.Zero(dbHost.Health.Tasks)
+}
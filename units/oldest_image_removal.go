@@ -3,6 +3,8 @@ package units
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
@@ -94,12 +96,28 @@ func (j *oldestImageRemovalJob) Run(ctx context.Context) {
 		return
 	}
 
+	// remove images that no container has used within the TTL
+	for _, image := range j.expiredImages(time.Now()) {
+		_, err = j.removeImage(ctx, containerMgr, image)
+		j.AddError(err)
+	}
+
 	diskUsage, err := containerMgr.CalculateImageSpaceUsage(ctx, j.host)
 	if err != nil {
 		j.AddError(errors.Wrap(err, "error getting Docker disk usage"))
 	}
 
 	if diskUsage >= maxDiskUsage {
+		// prefer the least recently used image that Evergreen built, which
+		// is rebuilt if a container needs it again
+		for _, image := range j.imagesByLastUse() {
+			removed, err := j.removeImage(ctx, containerMgr, image)
+			if err != nil || removed {
+				j.AddError(err)
+				return
+			}
+		}
+
 		err = containerMgr.RemoveOldestImage(ctx, j.host)
 		if err != nil {
 			j.AddError(errors.Wrapf(err, "error removing least recently used image ID on parent %s from Docker", j.HostID))
@@ -108,3 +126,54 @@ func (j *oldestImageRemovalJob) Run(ctx context.Context) {
 	}
 
 }
+
+// expiredImages returns the images on the parent that were last used before
+// the configured TTL. Images whose use has never been recorded are recorded
+// as used now, so that they expire a TTL from now.
+func (j *oldestImageRemovalJob) expiredImages(now time.Time) []string {
+	ttl := j.settings.ContainerPools.ImageTTL()
+	expired := []string{}
+	for image, ok := range j.host.ContainerImages {
+		if !ok {
+			continue
+		}
+		lastUsed, ok := j.host.ContainerImageLastUsed(image)
+		if !ok {
+			j.AddError(j.host.SetContainerImageUsed(image, now))
+			continue
+		}
+		if now.Sub(lastUsed) > ttl {
+			expired = append(expired, image)
+		}
+	}
+	return expired
+}
+
+// imagesByLastUse returns the images on the parent whose use has been
+// recorded, from least to most recently used.
+func (j *oldestImageRemovalJob) imagesByLastUse() []string {
+	uses := make([]host.ContainerImageUse, len(j.host.ContainerImageUses))
+	copy(uses, j.host.ContainerImageUses)
+	sort.Slice(uses, func(i, k int) bool { return uses[i].LastUsed.Before(uses[k].LastUsed) })
+
+	images := []string{}
+	for _, use := range uses {
+		if j.host.ContainerImages[use.Image] {
+			images = append(images, use.Image)
+		}
+	}
+	return images
+}
+
+// removeImage removes an image from the parent unless a container is
+// running it, and returns whether it was removed.
+func (j *oldestImageRemovalJob) removeImage(ctx context.Context, containerMgr cloud.ContainerManager, image string) (bool, error) {
+	removed, err := containerMgr.RemoveContainerImage(ctx, j.host, image)
+	if err != nil {
+		return false, errors.Wrapf(err, "error removing image '%s' on parent %s from Docker", image, j.HostID)
+	}
+	if !removed {
+		return false, nil
+	}
+	return true, errors.WithStack(j.host.RemoveContainerImage(image))
+}
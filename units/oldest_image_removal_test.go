@@ -3,12 +3,14 @@ package units
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOldestImageJob(t *testing.T) {
@@ -46,3 +48,44 @@ func TestOldestImageJob(t *testing.T) {
 	assert.True(j.Status().Completed)
 
 }
+
+func TestOldestImageJobRemovesExpiredImages(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	testConfig := testutil.TestConfig()
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+
+	require.NoError(db.Clear(host.Collection))
+
+	const (
+		expired   = "http://0.0.0.0:8000/expired.tgz"
+		recent    = "http://0.0.0.0:8000/recent.tgz"
+		untracked = "http://0.0.0.0:8000/untracked.tgz"
+	)
+	now := time.Now()
+	parent := &host.Host{
+		Id:              "parent-1",
+		Status:          evergreen.HostRunning,
+		HasContainers:   true,
+		ContainerImages: map[string]bool{expired: true, recent: true, untracked: true},
+		ContainerImageUses: []host.ContainerImageUse{
+			{Image: expired, LastUsed: now.Add(-testConfig.ContainerPools.ImageTTL() - time.Hour)},
+			{Image: recent, LastUsed: now},
+		},
+	}
+	require.NoError(parent.Insert())
+
+	j, ok := NewOldestImageRemovalJob(parent, evergreen.ProviderNameDockerMock, "job-1").(*oldestImageRemovalJob)
+	require.True(ok)
+	j.settings = testConfig
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+
+	dbParent, err := host.FindOneId("parent-1")
+	require.NoError(err)
+	assert.Equal(map[string]bool{recent: true, untracked: true}, dbParent.ContainerImages)
+	_, ok = dbParent.ContainerImageLastUsed(expired)
+	assert.False(ok)
+	_, ok = dbParent.ContainerImageLastUsed(untracked)
+	assert.True(ok)
+}
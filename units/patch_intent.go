@@ -83,6 +83,8 @@ func (j *patchIntentProcessor) Run(ctx context.Context) {
 	ctx, cancel = context.WithCancel(ctx)
 	defer cancel()
 	defer j.MarkComplete()
+	ctx, span := startJobSpan(ctx, j, map[string]string{"patch": j.PatchID.Hex()})
+	defer endJobSpan(span, j)
 
 	if j.env == nil {
 		j.env = evergreen.GetEnvironment()
@@ -102,6 +104,9 @@ func (j *patchIntentProcessor) Run(ctx context.Context) {
 	}
 
 	patchDoc := j.intent.NewPatch()
+	// the patch's trace, and the trace of its version and tasks, starts
+	// with this job
+	patchDoc.TraceParent = span.TraceParent()
 
 	if err = j.finishPatch(ctx, patchDoc, githubOauthToken); err != nil {
 		j.AddError(err)
@@ -134,10 +139,38 @@ func (j *patchIntentProcessor) Run(ctx context.Context) {
 			"source":             "patch intents",
 		}))
 
-		j.AddError(model.AbortPatchesWithGithubPatchData(patchDoc.CreateTime,
-			patchDoc.GithubPatchData.BaseOwner, patchDoc.GithubPatchData.BaseRepo,
-			patchDoc.GithubPatchData.PRNumber))
+		j.AddError(j.abortSupersededPatches(ctx, patchDoc))
+	}
+}
+
+// abortSupersededPatches aborts the earlier patches of the pull request, if
+// the project asks for it, and marks the head commits of the patches that
+// were unfinished as superseded.
+func (j *patchIntentProcessor) abortSupersededPatches(ctx context.Context, patchDoc *patch.Patch) error {
+	projectRef, err := model.FindOneProjectRef(patchDoc.Project)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding project '%s'", patchDoc.Project)
+	}
+	if projectRef == nil || !projectRef.AbortSupersededPRPatches {
+		return nil
+	}
+
+	canceled, err := model.AbortPatchesWithGithubPatchData(patchDoc.CreateTime,
+		patchDoc.GithubPatchData.BaseOwner, patchDoc.GithubPatchData.BaseRepo,
+		patchDoc.GithubPatchData.PRNumber)
+
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(err)
+	for _, p := range canceled {
+		if p.GithubPatchData.HeadHash == patchDoc.GithubPatchData.HeadHash {
+			continue
+		}
+		update := NewGithubStatusUpdateJobForSupersededPatch(p.Id.Hex())
+		update.Run(ctx)
+		catcher.Add(update.Error())
 	}
+
+	return catcher.Resolve()
 }
 
 func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.Patch, githubOauthToken string) error {
@@ -7,13 +7,16 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/mock"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
@@ -277,6 +280,49 @@ func (s *PatchIntentUnitsSuite) TestProcessGithubPatchIntent() {
 	s.verifyGithubSubscriptions(patchDoc)
 }
 
+func (s *PatchIntentUnitsSuite) TestAbortSupersededPatchesFollowsProjectSetting() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.NoError(db.ClearCollections(task.Collection, build.Collection))
+
+	earlier := patch.Patch{
+		Id:              bson.NewObjectId(),
+		Project:         s.project,
+		Version:         "earlier",
+		Status:          evergreen.PatchStarted,
+		CreateTime:      time.Now().Add(-time.Hour),
+		GithubPatchData: s.githubPatchData,
+	}
+	s.NoError(earlier.Insert())
+	s.NoError((&task.Task{Id: "earlier-task", Version: "earlier", Status: evergreen.TaskStarted, Activated: true}).Insert())
+	latest := &patch.Patch{
+		Id:              bson.NewObjectId(),
+		Project:         s.project,
+		CreateTime:      time.Now(),
+		GithubPatchData: s.githubPatchData,
+	}
+	j := makePatchIntentProcessor()
+
+	// the project leaves earlier patches running
+	s.NoError(j.abortSupersededPatches(ctx, latest))
+	dbTask, err := task.FindOneId("earlier-task")
+	s.NoError(err)
+	s.Require().NotNil(dbTask)
+	s.False(dbTask.Aborted)
+
+	ref, err := model.FindOneProjectRef(s.project)
+	s.NoError(err)
+	s.Require().NotNil(ref)
+	ref.AbortSupersededPRPatches = true
+	s.NoError(ref.Upsert())
+
+	s.NoError(j.abortSupersededPatches(ctx, latest))
+	dbTask, err = task.FindOneId("earlier-task")
+	s.NoError(err)
+	s.Require().NotNil(dbTask)
+	s.True(dbTask.Aborted)
+}
+
 func (s *PatchIntentUnitsSuite) TestFindEvergreenUserForPR() {
 	dbUser := user.DBUser{
 		Id: "testuser",
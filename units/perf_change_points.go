@@ -0,0 +1,167 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	perfChangePointsJobName = "perf-change-point-detection"
+
+	// perfSeriesLimit is the number of mainline versions of a series
+	// that change points are detected in.
+	perfSeriesLimit = 200
+)
+
+func init() {
+	registry.AddJobType(perfChangePointsJobName,
+		func() amboy.Job { return makePerfChangePointsJob() })
+}
+
+type perfChangePointsJob struct {
+	VersionID    string `bson:"version_id" json:"version_id" yaml:"version_id"`
+	Series       int    `bson:"series" json:"series" yaml:"series"`
+	ChangePoints int    `bson:"change_points" json:"change_points" yaml:"change_points"`
+	job.Base     `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makePerfChangePointsJob() *perfChangePointsJob {
+	j := &perfChangePointsJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    perfChangePointsJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewPerfChangePointsJob detects change points in the history of each
+// measurement that the tasks of a mainline version sent with json.send,
+// stores the change points that are new, and logs an event for each
+// version that new change points were found at.
+func NewPerfChangePointsJob(versionID, id string) amboy.Job {
+	j := makePerfChangePointsJob()
+	j.VersionID = versionID
+	j.SetID(fmt.Sprintf("%s.%s.%s", perfChangePointsJobName, versionID, id))
+	return j
+}
+
+func (j *perfChangePointsJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	v, err := version.FindOne(version.ById(j.VersionID))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding version '%s'", j.VersionID))
+		return
+	}
+	if v == nil {
+		j.AddError(errors.Errorf("version '%s' not found", j.VersionID))
+		return
+	}
+
+	docs, err := model.GetTaskJSONForVersion(v.Id)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	found := map[string][]string{}
+	for _, doc := range docs {
+		if ctx.Err() != nil {
+			j.AddError(errors.New("perf change point detection canceled"))
+			break
+		}
+
+		history, err := model.GetTaskJSONSeries(doc.ProjectId, doc.Variant, doc.TaskName, doc.Name, v.RevisionOrderNumber, perfSeriesLimit)
+		if err != nil {
+			j.AddError(err)
+			continue
+		}
+
+		j.Series += len(perf.Measurements(doc.Data))
+		for _, cp := range detectPerfChangePoints(doc, history) {
+			isNew, err := cp.Insert()
+			if err != nil {
+				j.AddError(err)
+				continue
+			}
+			if isNew {
+				found[cp.VersionID] = append(found[cp.VersionID], cp.ID)
+				j.ChangePoints++
+			}
+		}
+	}
+
+	for versionID, ids := range found {
+		event.LogVersionPerfChangePointEvent(versionID, ids)
+	}
+
+	grip.InfoWhen(j.ChangePoints > 0, message.Fields{
+		"job":           j.ID(),
+		"job_type":      perfChangePointsJobName,
+		"version":       j.VersionID,
+		"series":        j.Series,
+		"change_points": j.ChangePoints,
+	})
+}
+
+// detectPerfChangePoints returns the change points in the history of each
+// measurement in the data sent under a name by a task. Each measurement's
+// series has the versions whose data included it.
+func detectPerfChangePoints(latest model.TaskJSON, history []model.TaskJSON) []perf.ChangePoint {
+	measurements := perf.Measurements(latest.Data)
+	names := make([]string, 0, len(measurements))
+	for name := range measurements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	perDoc := make([]map[string]float64, len(history))
+	for i := range history {
+		perDoc[i] = perf.Measurements(history[i].Data)
+	}
+
+	out := []perf.ChangePoint{}
+	for _, name := range names {
+		points := []perf.Point{}
+		for i, doc := range history {
+			value, ok := perDoc[i][name]
+			if !ok {
+				continue
+			}
+			points = append(points, perf.Point{
+				Order:     doc.RevisionOrderNumber,
+				Revision:  doc.Revision,
+				VersionID: doc.VersionId,
+				TaskID:    doc.TaskId,
+				Value:     value,
+			})
+		}
+
+		series := perf.Series{
+			Project:     latest.ProjectId,
+			Variant:     latest.Variant,
+			Task:        latest.TaskName,
+			Name:        latest.Name,
+			Measurement: name,
+		}
+		out = append(out, perf.Detect(series, points, perf.DefaultEDivisiveOptions)...)
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package units
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectPerfChangePoints(t *testing.T) {
+	history := []model.TaskJSON{}
+	for i := 0; i < 20; i++ {
+		data := map[string]interface{}{"ops_per_sec": 1000.0, "latency": 2.0}
+		if i >= 10 {
+			data["ops_per_sec"] = 500.0
+		}
+		if i%2 == 0 {
+			delete(data, "latency")
+		}
+		history = append(history, model.TaskJSON{
+			ProjectId:           "proj",
+			Variant:             "linux",
+			TaskName:            "bench",
+			Name:                "perf",
+			VersionId:           fmt.Sprintf("v%d", i),
+			TaskId:              fmt.Sprintf("t%d", i),
+			RevisionOrderNumber: i + 1,
+			Data:                data,
+		})
+	}
+
+	cps := detectPerfChangePoints(history[len(history)-1], history)
+	require.Len(t, cps, 1)
+	cp := cps[0]
+	assert.Equal(t, "ops_per_sec", cp.Measurement)
+	assert.Equal(t, "v10", cp.VersionID)
+	assert.Equal(t, "t10", cp.TaskID)
+	assert.Equal(t, 11, cp.Order)
+	assert.Equal(t, -50.0, cp.PercentChange)
+	assert.Equal(t, "proj", cp.Project)
+	assert.Equal(t, "linux", cp.Variant)
+	assert.Equal(t, "bench", cp.Task)
+	assert.Equal(t, "perf", cp.Name)
+}
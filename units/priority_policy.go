@@ -0,0 +1,79 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	priorityPolicyJobName = "priority-policy"
+)
+
+func init() {
+	registry.AddJobType(priorityPolicyJobName,
+		func() amboy.Job { return makePriorityPolicyJob() })
+}
+
+type priorityPolicyJob struct {
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	Changed   int    `bson:"changed" json:"changed" yaml:"changed"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makePriorityPolicyJob() *priorityPolicyJob {
+	j := &priorityPolicyJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    priorityPolicyJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewPriorityPolicyJob applies a project's priority policy to its
+// undispatched tasks.
+func NewPriorityPolicyJob(projectID, id string) amboy.Job {
+	j := makePriorityPolicyJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", priorityPolicyJobName, projectID, id))
+	return j
+}
+
+func (j *priorityPolicyJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	tasks, err := task.FindSchedulableForProject(j.ProjectID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding undispatched tasks for project '%s'", j.ProjectID))
+		return
+	}
+	if ctx.Err() != nil {
+		j.AddError(errors.New("priority policy canceled"))
+		return
+	}
+
+	changes, err := model.ApplyPriorityPolicies(tasks, time.Now())
+	j.AddError(err)
+	j.Changed = len(changes)
+
+	grip.InfoWhen(j.Changed > 0, message.Fields{
+		"job":      j.ID(),
+		"job_type": priorityPolicyJobName,
+		"project":  j.ProjectID,
+		"changed":  j.Changed,
+	})
+}
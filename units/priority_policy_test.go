@@ -0,0 +1,56 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityPolicyJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(model.ProjectRefCollection, task.Collection, build.Collection))
+
+	ref := model.ProjectRef{
+		Identifier:     "proj",
+		Branch:         "v4.0",
+		Enabled:        true,
+		PriorityPolicy: model.PriorityPolicy{ReleaseBranchPattern: `^v\d`, ReleaseBranchPriority: 20, StalePatchHours: 1},
+	}
+	require.NoError(ref.Insert())
+
+	b := build.Build{Id: "b"}
+	require.NoError(b.Insert())
+	for _, tsk := range []task.Task{
+		{Id: "mainline", BuildId: "b", Project: "proj", Requester: evergreen.RepotrackerVersionRequester, Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "patch", BuildId: "b", Project: "proj", Requester: evergreen.PatchVersionRequester, ActivatedTime: time.Now().Add(-2 * time.Hour), Activated: true, Status: evergreen.TaskUndispatched},
+		{Id: "other", BuildId: "b", Project: "other", Requester: evergreen.RepotrackerVersionRequester, Activated: true, Status: evergreen.TaskUndispatched},
+	} {
+		require.NoError(tsk.Insert())
+	}
+
+	j := NewPriorityPolicyJob("proj", "id")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	assert.Equal(2, j.(*priorityPolicyJob).Changed)
+
+	dbTask, err := task.FindOneId("mainline")
+	require.NoError(err)
+	assert.EqualValues(20, dbTask.Priority)
+	dbTask, err = task.FindOneId("patch")
+	require.NoError(err)
+	assert.False(dbTask.Activated)
+	dbTask, err = task.FindOneId("other")
+	require.NoError(err)
+	assert.Zero(dbTask.Priority)
+}
@@ -71,6 +71,8 @@ func NewAgentDeployJob(env evergreen.Environment, h host.Host, id string) amboy.
 func (j *agentDeployJob) Run(ctx context.Context) {
 	var err error
 	defer j.MarkComplete()
+	ctx, span := startJobSpan(ctx, j, map[string]string{"host": j.HostID})
+	defer endJobSpan(span, j)
 
 	if j.host == nil {
 		j.host, err = host.FindOneId(j.HostID)
@@ -300,6 +302,9 @@ func (j *agentDeployJob) startAgentOnRemote(ctx context.Context, settings *everg
 		fmt.Sprintf("--working_directory='%s'", hostObj.Distro.WorkDir),
 		"--cleanup",
 	}
+	if settings.Tracer.Enabled {
+		agentCmdParts = append(agentCmdParts, fmt.Sprintf("--trace_collector='%s'", settings.Tracer.CollectorEndpoint))
+	}
 
 	// build the command to run on the remote machine
 	remoteCmd := strings.Join(agentCmdParts, " ")
@@ -76,6 +76,8 @@ func NewHostCreateJob(env evergreen.Environment, h host.Host, id string, Current
 func (j *createHostJob) Run(ctx context.Context) {
 	var err error
 	defer j.MarkComplete()
+	ctx, span := startJobSpan(ctx, j, map[string]string{"host": j.HostID})
+	defer endJobSpan(span, j)
 
 	j.start = time.Now()
 
@@ -267,7 +269,10 @@ func (j *createHostJob) shouldRetryCreateHost(ctx context.Context) bool {
 }
 
 func (j *createHostJob) waitForContainerImageBuild(ctx context.Context) error {
-	imageURL := (*j.host.Distro.ProviderSettings)["image_url"].(string)
+	imageURL, err := cloud.GetContainerImage(&j.host.Distro)
+	if err != nil {
+		return errors.Wrapf(err, "problem getting container image for '%s'", j.host.Id)
+	}
 
 	timer := time.NewTimer(0)
 	defer timer.Stop()
@@ -77,6 +77,8 @@ func NewHostSetupJob(env evergreen.Environment, h host.Host, id string) amboy.Jo
 func (j *setupHostJob) Run(ctx context.Context) {
 	var err error
 	defer j.MarkComplete()
+	ctx, span := startJobSpan(ctx, j, map[string]string{"host": j.HostID})
+	defer endJobSpan(span, j)
 
 	if j.host == nil {
 		j.host, err = host.FindOneId(j.HostID)
@@ -536,9 +538,43 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 		"provision_duration_secs": h.ProvisionTime.Sub(h.CreationTime).Seconds(),
 	})
 
+	if h.HasContainers && h.ContainerPoolSettings != nil {
+		grip.Error(message.WrapError(j.prePullContainerImages(h), message.Fields{
+			"message": "problem pre-pulling container images onto parent",
+			"host":    h.Id,
+			"pool":    h.ContainerPoolSettings.Id,
+			"job":     j.ID(),
+		}))
+	}
+
 	return nil
 }
 
+// prePullContainerImages starts building the images of the distros in a new
+// parent's container pool, so that its first containers don't wait for them.
+func (j *setupHostJob) prePullContainerImages(h *host.Host) error {
+	distros, err := distro.Find(distro.ByContainerPool(h.ContainerPoolSettings.Id))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding distros in container pool '%s'", h.ContainerPoolSettings.Id)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	seen := map[string]bool{}
+	for i := range distros {
+		image, err := cloud.GetContainerImage(&distros[i])
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+		if seen[image] || h.ContainerImages[image] {
+			continue
+		}
+		seen[image] = true
+		catcher.Add(j.env.RemoteQueue().Put(NewBuildingContainerImageJob(j.env, h, image, evergreen.ProviderNameDocker)))
+	}
+	return catcher.Resolve()
+}
+
 // loadClientResult indicates the locations on a target host where the CLI binary and it's config
 // file have been written to.
 type loadClientResult struct {
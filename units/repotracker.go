@@ -59,6 +59,8 @@ func (j *repotrackerJob) Run(ctx context.Context) {
 	ctx, cancel = context.WithCancel(ctx)
 	defer cancel()
 	defer j.MarkComplete()
+	ctx, span := startJobSpan(ctx, j, map[string]string{"project": j.ProjectID})
+	defer endJobSpan(span, j)
 
 	if j.env == nil {
 		j.env = evergreen.GetEnvironment()
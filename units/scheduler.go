@@ -55,6 +55,8 @@ func NewDistroSchedulerJob(env evergreen.Environment, distroID string, ts time.T
 
 func (j *distroSchedulerJob) Run(ctx context.Context) {
 	defer j.MarkComplete()
+	ctx, span := startJobSpan(ctx, j, map[string]string{"distro": j.DistroID})
+	defer endJobSpan(span, j)
 
 	if j.env == nil {
 		j.env = evergreen.GetEnvironment()
@@ -3,9 +3,11 @@ package units
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
 	"github.com/mongodb/amboy/job"
@@ -15,6 +17,7 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/logging"
 	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -83,17 +86,25 @@ func (j *amboyStatsCollector) Run(ctx context.Context) {
 
 	localQueue := j.env.LocalQueue()
 	if !j.ExcludeLocal && (localQueue != nil && localQueue.Started()) {
+		stats := localQueue.Stats()
+		j.AddError(publishLocalAmboyMetrics(stats))
 		j.logger.Info(message.Fields{
 			"message": "amboy local queue stats",
-			"stats":   localQueue.Stats(),
+			"stats":   stats,
 		})
 	}
 
 	remoteQueue := j.env.RemoteQueue()
 	if !j.ExcludeRemote && (remoteQueue != nil && remoteQueue.Started()) {
+		stats := remoteQueue.Stats()
+		collector := amboyStatsCollectorJobName + "-remote"
+		metrics := util.NewMetricsRegistry()
+		now := time.Now()
+		recordAmboyMetrics(metrics, collector, "remote", "", stats, now)
+		j.AddError(publishMetrics(collector, metrics, now))
 		j.logger.Info(message.Fields{
 			"message": "amboy remote queue stats",
-			"stats":   remoteQueue.Stats(),
+			"stats":   stats,
 		})
 
 		if enableExtendedRemoteStats {
@@ -102,6 +113,21 @@ func (j *amboyStatsCollector) Run(ctx context.Context) {
 	}
 }
 
+// publishLocalAmboyMetrics publishes the stats of this app server's local
+// queue, separately from those of other app servers.
+func publishLocalAmboyMetrics(stats amboy.QueueStats) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return errors.Wrap(err, "problem getting hostname")
+	}
+
+	collector := fmt.Sprintf("%s-local-%s", amboyStatsCollectorJobName, hostname)
+	metrics := util.NewMetricsRegistry()
+	now := time.Now()
+	recordAmboyMetrics(metrics, collector, "local", hostname, stats, now)
+	return publishMetrics(collector, metrics, now)
+}
+
 func (j *amboyStatsCollector) collectExtendedRemoteStats(ctx context.Context) error {
 	settings := j.env.Settings()
 
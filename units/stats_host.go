@@ -3,8 +3,10 @@ package units
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
 	"github.com/mongodb/amboy/job"
@@ -57,15 +59,23 @@ func (j *hostStatsCollector) Run(_ context.Context) {
 	if j.logger == nil {
 		j.logger = logging.MakeGrip(grip.GetSender())
 	}
-
-	j.AddError(j.statsByDistro())
-	j.AddError(j.statsByProvider())
+	distros, err := j.statsByDistro()
+	j.AddError(err)
+	providers, err := j.statsByProvider()
+	j.AddError(err)
+
+	if !j.HasErrors() {
+		metrics := util.NewMetricsRegistry()
+		now := time.Now()
+		recordHostMetrics(metrics, distros, providers, now)
+		j.AddError(publishMetrics(hostStatsCollectorJobName, metrics, now))
+	}
 }
 
-func (j *hostStatsCollector) statsByDistro() error {
+func (j *hostStatsCollector) statsByDistro() (host.DistroStats, error) {
 	hosts, err := host.GetStatsByDistro()
 	if err != nil {
-		return errors.Wrap(err, "problem getting stats by distro")
+		return nil, errors.Wrap(err, "problem getting stats by distro")
 	}
 
 	tasks := 0
@@ -94,13 +104,13 @@ func (j *hostStatsCollector) statsByDistro() error {
 		"total":  excess,
 	})
 
-	return nil
+	return hosts, nil
 }
 
-func (j *hostStatsCollector) statsByProvider() error {
+func (j *hostStatsCollector) statsByProvider() (host.ProviderStats, error) {
 	providers, err := host.GetProviderCounts()
 	if err != nil {
-		return errors.Wrap(err, "problem getting stats by provider")
+		return nil, errors.Wrap(err, "problem getting stats by provider")
 	}
 
 	j.logger.Info(message.Fields{
@@ -110,5 +120,5 @@ func (j *hostStatsCollector) statsByProvider() error {
 		"providers": providers,
 	})
 
-	return nil
+	return providers, nil
 }
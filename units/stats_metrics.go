@@ -0,0 +1,202 @@
+package units
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/amboy"
+)
+
+// The stats collectors publish what they log as OpenMetrics, so that the
+// API server's /metrics endpoint can be scraped and alerted on. The
+// collectors run on whichever app server picks them up, so each stores the
+// metrics it collected, and records when it last ran, for every app server
+// to serve.
+
+const metricsPrefix = "evergreen_"
+
+// publishMetrics stores the metrics that a collector recorded, replacing
+// those it published before.
+func publishMetrics(collector string, metrics *util.MetricsRegistry, now time.Time) error {
+	return model.SaveStatsMetrics(collector, metrics.Families(), now)
+}
+
+func recordCollectorRun(metrics *util.MetricsRegistry, collector string, now time.Time) {
+	metrics.SetGauge(metricsPrefix+"stats_collected_timestamp_seconds",
+		"Unix time at which a stats collector last ran on any app server.",
+		map[string]string{"collector": collector}, float64(now.Unix()))
+}
+
+// recordQueueMetrics publishes the length of each distro's task queue and
+// how long its runnable tasks have waited since they were activated.
+func recordQueueMetrics(metrics *util.MetricsRegistry, queues []model.TaskQueue, runnable []task.Task, now time.Time) {
+	lengths := make([]util.MetricSample, 0, len(queues))
+	for i := range queues {
+		lengths = append(lengths, util.MetricSample{
+			Labels: map[string]string{"distro": queues[i].Distro},
+			Value:  float64(queues[i].Length()),
+		})
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"task_queue_length",
+		"Number of tasks in each distro's task queue.", lengths)
+
+	type waits struct {
+		total time.Duration
+		max   time.Duration
+		count int
+	}
+	byDistro := map[string]*waits{}
+	for _, t := range runnable {
+		if util.IsZeroTime(t.ActivatedTime) {
+			continue
+		}
+		w, ok := byDistro[t.DistroId]
+		if !ok {
+			w = &waits{}
+			byDistro[t.DistroId] = w
+		}
+		wait := now.Sub(t.ActivatedTime)
+		w.total += wait
+		w.count++
+		if wait > w.max {
+			w.max = wait
+		}
+	}
+
+	mean := make([]util.MetricSample, 0, len(byDistro))
+	max := make([]util.MetricSample, 0, len(byDistro))
+	for distro, w := range byDistro {
+		labels := map[string]string{"distro": distro}
+		mean = append(mean, util.MetricSample{Labels: labels, Value: (w.total / time.Duration(w.count)).Seconds()})
+		max = append(max, util.MetricSample{Labels: labels, Value: w.max.Seconds()})
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"task_queue_wait_seconds_mean",
+		"Mean time that runnable tasks in each distro have waited since activation.", mean)
+	metrics.SetGaugeFamily(metricsPrefix+"task_queue_wait_seconds_max",
+		"Longest time that a runnable task in each distro has waited since activation.", max)
+	metrics.SetGauge(metricsPrefix+"runnable_tasks",
+		"Number of tasks that are ready to run.", nil, float64(len(runnable)))
+
+	recordCollectorRun(metrics, queueStatsCollectorJobName, now)
+}
+
+// recordHostMetrics publishes host counts by distro, provider and status.
+func recordHostMetrics(metrics *util.MetricsRegistry, distros host.DistroStats, providers host.ProviderStats, now time.Time) {
+	hosts := make([]util.MetricSample, 0, len(distros))
+	running := map[string]int{}
+	for _, s := range distros {
+		hosts = append(hosts, util.MetricSample{
+			Labels: map[string]string{"distro": s.Distro, "provider": s.Provider, "status": s.Status},
+			Value:  float64(s.Count),
+		})
+		running[s.Distro] += s.NumTasks
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"hosts",
+		"Number of hosts by distro, provider and status.", hosts)
+
+	tasks := make([]util.MetricSample, 0, len(running))
+	for distro, count := range running {
+		tasks = append(tasks, util.MetricSample{
+			Labels: map[string]string{"distro": distro},
+			Value:  float64(count),
+		})
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"hosts_running_tasks",
+		"Number of tasks running on each distro's hosts.", tasks)
+
+	byProvider := make([]util.MetricSample, 0, len(providers))
+	for provider, count := range providers.Map() {
+		byProvider = append(byProvider, util.MetricSample{
+			Labels: map[string]string{"provider": provider},
+			Value:  float64(count),
+		})
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"hosts_by_provider",
+		"Number of hosts by provider.", byProvider)
+
+	recordCollectorRun(metrics, hostStatsCollectorJobName, now)
+}
+
+// recordTaskMetrics publishes the outcomes of the tasks that finished, or
+// changed state, within the collector's interval.
+func recordTaskMetrics(metrics *util.MetricsRegistry, counts *task.ResultCounts, interval time.Duration, now time.Time) {
+	byStatus := map[string]int{
+		"inactive":            counts.Inactive,
+		"unstarted":           counts.Unstarted,
+		"started":             counts.Started,
+		"succeeded":           counts.Succeeded,
+		"failed":              counts.Failed,
+		"setup-failed":        counts.SetupFailed,
+		"system-failed":       counts.SystemFailed,
+		"system-unresponsive": counts.SystemUnresponsive,
+		"system-timed-out":    counts.SystemTimedOut,
+		"test-timed-out":      counts.TestTimedOut,
+	}
+	samples := make([]util.MetricSample, 0, len(byStatus))
+	for status, count := range byStatus {
+		samples = append(samples, util.MetricSample{
+			Labels: map[string]string{"status": status},
+			Value:  float64(count),
+		})
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"recent_tasks",
+		"Number of tasks updated within the collection window, by outcome.", samples)
+	metrics.SetGauge(metricsPrefix+"recent_tasks_window_seconds",
+		"Length of the window that recent task counts cover.", nil, interval.Seconds())
+
+	recordCollectorRun(metrics, taskStatsCollectorJobName, now)
+}
+
+// recordAmboyMetrics publishes the job counts of an amboy queue. Local
+// queues belong to a single app server, which hostname identifies.
+func recordAmboyMetrics(metrics *util.MetricsRegistry, collector, queue, hostname string, stats amboy.QueueStats, now time.Time) {
+	samples := []util.MetricSample{}
+	for state, count := range map[string]int{
+		"running":   stats.Running,
+		"pending":   stats.Pending,
+		"blocked":   stats.Blocked,
+		"completed": stats.Completed,
+	} {
+		labels := map[string]string{"queue": queue, "state": state}
+		if hostname != "" {
+			labels["hostname"] = hostname
+		}
+		samples = append(samples, util.MetricSample{Labels: labels, Value: float64(count)})
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"amboy_jobs",
+		"Number of jobs in each amboy queue by state.", samples)
+
+	recordCollectorRun(metrics, collector, now)
+}
+
+// recordNotificationMetrics publishes the number of notifications waiting
+// to be sent and events waiting to be processed.
+func recordNotificationMetrics(metrics *util.MetricsRegistry, stats *notification.NotificationStats, unprocessed int, now time.Time) {
+	metrics.SetGauge(metricsPrefix+"unprocessed_events",
+		"Number of events that have not been processed into notifications.", nil, float64(unprocessed))
+
+	samples := []util.MetricSample{}
+	if stats != nil {
+		for subscriber, count := range map[string]int{
+			"github_pull_request": stats.GithubPullRequest,
+			"jira_issue":          stats.JIRAIssue,
+			"jira_comment":        stats.JIRAComment,
+			"evergreen_webhook":   stats.EvergreenWebhook,
+			"email":               stats.Email,
+			"slack":               stats.Slack,
+		} {
+			samples = append(samples, util.MetricSample{
+				Labels: map[string]string{"type": subscriber},
+				Value:  float64(count),
+			})
+		}
+	}
+	metrics.SetGaugeFamily(metricsPrefix+"unsent_notifications",
+		"Number of notifications waiting to be sent, by subscriber type.", samples)
+
+	recordCollectorRun(metrics, notificationsStatsCollectorJobName, now)
+}
@@ -0,0 +1,102 @@
+package units
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderMetrics(t *testing.T, metrics *util.MetricsRegistry) string {
+	buf := &bytes.Buffer{}
+	_, err := metrics.WriteTo(buf)
+	require.NoError(t, err)
+	return buf.String()
+}
+
+func TestRecordQueueMetrics(t *testing.T) {
+	assert := assert.New(t)
+	metrics := util.NewMetricsRegistry()
+	now := time.Now()
+
+	queues := []model.TaskQueue{
+		{Distro: "d1", Queue: []model.TaskQueueItem{{Id: "t1"}, {Id: "t2"}}},
+		{Distro: "d2"},
+	}
+	runnable := []task.Task{
+		{Id: "t1", DistroId: "d1", ActivatedTime: now.Add(-10 * time.Second)},
+		{Id: "t2", DistroId: "d1", ActivatedTime: now.Add(-30 * time.Second)},
+		{Id: "t3", DistroId: "d2"},
+	}
+	recordQueueMetrics(metrics, queues, runnable, now)
+
+	out := renderMetrics(t, metrics)
+	assert.Contains(out, `evergreen_task_queue_length{distro="d1"} 2`)
+	assert.Contains(out, `evergreen_task_queue_length{distro="d2"} 0`)
+	assert.Contains(out, `evergreen_task_queue_wait_seconds_mean{distro="d1"} 20`)
+	assert.Contains(out, `evergreen_task_queue_wait_seconds_max{distro="d1"} 30`)
+	assert.NotContains(out, `evergreen_task_queue_wait_seconds_max{distro="d2"}`)
+	assert.Contains(out, "evergreen_runnable_tasks 3")
+	assert.Contains(out, `evergreen_stats_collected_timestamp_seconds{collector="queue-stats-collector"}`)
+}
+
+func TestRecordHostMetrics(t *testing.T) {
+	assert := assert.New(t)
+	metrics := util.NewMetricsRegistry()
+
+	distros := host.DistroStats{
+		{Distro: "d1", Provider: "ec2", Status: "running", Count: 3, NumTasks: 2},
+		{Distro: "d1", Provider: "ec2", Status: "provisioning", Count: 1},
+	}
+	providers := host.ProviderStats{{Provider: "ec2", Count: 4}}
+	recordHostMetrics(metrics, distros, providers, time.Now())
+
+	out := renderMetrics(t, metrics)
+	assert.Contains(out, `evergreen_hosts{distro="d1",provider="ec2",status="running"} 3`)
+	assert.Contains(out, `evergreen_hosts{distro="d1",provider="ec2",status="provisioning"} 1`)
+	assert.Contains(out, `evergreen_hosts_running_tasks{distro="d1"} 2`)
+	assert.Contains(out, `evergreen_hosts_by_provider{provider="ec2"} 4`)
+
+	// hosts that go away are no longer reported
+	recordHostMetrics(metrics, host.DistroStats{}, host.ProviderStats{}, time.Now())
+	assert.NotContains(renderMetrics(t, metrics), `distro="d1"`)
+}
+
+func TestRecordTaskAmboyAndNotificationMetrics(t *testing.T) {
+	assert := assert.New(t)
+	metrics := util.NewMetricsRegistry()
+	now := time.Now()
+
+	recordTaskMetrics(metrics, &task.ResultCounts{Succeeded: 5, SystemFailed: 1}, time.Minute, now)
+	recordAmboyMetrics(metrics, "amboy-stats-collector-remote", "remote", "", amboy.QueueStats{Pending: 7, Running: 2}, now)
+	recordNotificationMetrics(metrics, &notification.NotificationStats{Slack: 4}, 9, now)
+
+	out := renderMetrics(t, metrics)
+	assert.Contains(out, `evergreen_recent_tasks{status="succeeded"} 5`)
+	assert.Contains(out, `evergreen_recent_tasks{status="system-failed"} 1`)
+	assert.Contains(out, "evergreen_recent_tasks_window_seconds 60")
+	assert.Contains(out, `evergreen_amboy_jobs{queue="remote",state="pending"} 7`)
+	assert.Contains(out, `evergreen_amboy_jobs{queue="remote",state="running"} 2`)
+	assert.Contains(out, `evergreen_stats_collected_timestamp_seconds{collector="amboy-stats-collector-remote"}`)
+	assert.Contains(out, `evergreen_unsent_notifications{type="slack"} 4`)
+	assert.Contains(out, "evergreen_unprocessed_events 9")
+}
+
+func TestRecordLocalAmboyMetrics(t *testing.T) {
+	assert := assert.New(t)
+	metrics := util.NewMetricsRegistry()
+
+	recordAmboyMetrics(metrics, "amboy-stats-collector-local-app1", "local", "app1", amboy.QueueStats{Blocked: 1}, time.Now())
+
+	out := renderMetrics(t, metrics)
+	assert.Contains(out, `evergreen_amboy_jobs{hostname="app1",queue="local",state="blocked"} 1`)
+	assert.Contains(out, `evergreen_stats_collected_timestamp_seconds{collector="amboy-stats-collector-local-app1"}`)
+}
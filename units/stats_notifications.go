@@ -7,6 +7,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
 	"github.com/mongodb/amboy/job"
@@ -94,6 +95,10 @@ func (j *notificationsStatsCollector) Run(ctx context.Context) {
 	msg["pending_notifications_by_type"] = stats
 
 	if ctx.Err() == nil {
+		metrics := util.NewMetricsRegistry()
+		now := time.Now()
+		recordNotificationMetrics(metrics, stats, nUnprocessed, now)
+		j.AddError(publishMetrics(notificationsStatsCollectorJobName, metrics, now))
 		j.logger.Info(msg)
 	}
 }
@@ -3,9 +3,12 @@ package units
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/scheduler"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
 	"github.com/mongodb/amboy/job"
@@ -64,4 +67,15 @@ func (j *queueStatsCollector) Run(_ context.Context) {
 	grip.Info(message.Fields{
 		"total_queue_length": len(tasks),
 	})
+
+	queues, err := model.FindAllTaskQueues()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error finding task queues"))
+		return
+	}
+
+	metrics := util.NewMetricsRegistry()
+	now := time.Now()
+	recordQueueMetrics(metrics, queues, tasks, now)
+	j.AddError(publishMetrics(queueStatsCollectorJobName, metrics, now))
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
 	"github.com/mongodb/amboy/job"
@@ -58,12 +59,16 @@ func (j *taskStatsCollector) Run(_ context.Context) {
 	if j.logger == nil {
 		j.logger = logging.MakeGrip(grip.GetSender())
 	}
-
 	tasks, err := task.GetRecentTasks(taskStatsCollectorInterval)
 	if err != nil {
 		j.AddError(err)
 		return
 	}
 
-	j.logger.Info(task.GetResultCounts(tasks))
+	counts := task.GetResultCounts(tasks)
+	metrics := util.NewMetricsRegistry()
+	now := time.Now()
+	recordTaskMetrics(metrics, counts, taskStatsCollectorInterval, now)
+	j.AddError(publishMetrics(taskStatsCollectorJobName, metrics, now))
+	j.logger.Info(counts)
 }
@@ -0,0 +1,26 @@
+package units
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/amboy"
+)
+
+// startJobSpan starts a span covering a job's run. The span is the root
+// of a new trace unless ctx already carries a span.
+func startJobSpan(ctx context.Context, j amboy.Job, attrs map[string]string) (context.Context, *util.Span) {
+	spanAttrs := map[string]string{
+		"job.id":   j.ID(),
+		"job.type": j.Type().Name,
+	}
+	for k, v := range attrs {
+		spanAttrs[k] = v
+	}
+	return util.GetTracer().Start(ctx, "job "+j.Type().Name, spanAttrs)
+}
+
+// endJobSpan ends a job's span, recording the job's errors.
+func endJobSpan(span *util.Span, j amboy.Job) {
+	span.End(j.Error())
+}
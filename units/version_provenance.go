@@ -0,0 +1,86 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	versionProvenanceJobName = "version-provenance"
+)
+
+func init() {
+	registry.AddJobType(versionProvenanceJobName,
+		func() amboy.Job { return makeVersionProvenanceJob() })
+}
+
+type versionProvenanceJob struct {
+	VersionID string `bson:"version_id" json:"version_id" yaml:"version_id"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	settings *evergreen.Settings
+}
+
+func makeVersionProvenanceJob() *versionProvenanceJob {
+	j := &versionProvenanceJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    versionProvenanceJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewVersionProvenanceJob generates the provenance document of a
+// finished version, replacing any document from an earlier run.
+func NewVersionProvenanceJob(versionID string) amboy.Job {
+	j := makeVersionProvenanceJob()
+	j.VersionID = versionID
+	j.SetID(fmt.Sprintf("%s.%s.%d", versionProvenanceJobName, versionID, job.GetNumber()))
+	j.SetPriority(-1)
+	return j
+}
+
+func (j *versionProvenanceJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.settings == nil {
+		j.settings = evergreen.GetEnvironment().Settings()
+	}
+	if j.settings == nil {
+		j.AddError(errors.New("evergreen settings are not configured"))
+		return
+	}
+
+	doc, err := model.BuildVersionProvenance(j.VersionID, j.settings.Ui.Url)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem building provenance for version '%s'", j.VersionID))
+		return
+	}
+
+	if err = doc.Upsert(); err != nil {
+		j.AddError(errors.Wrapf(err, "problem saving provenance for version '%s'", j.VersionID))
+		return
+	}
+
+	grip.Info(message.Fields{
+		"job":      j.ID(),
+		"job_type": versionProvenanceJobName,
+		"version":  j.VersionID,
+		"tasks":    len(doc.Predicate.BuildConfig.Tasks),
+		"subjects": len(doc.Subject),
+	})
+}
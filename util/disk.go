@@ -0,0 +1,28 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// DirectorySize returns the total size, in bytes, of the regular files
+// within a directory. Files that disappear while the directory is walked
+// are skipped.
+func DirectorySize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path != dir {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, errors.Wrapf(err, "problem finding size of '%s'", dir)
+}
@@ -0,0 +1,40 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectorySize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "disk")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(os.MkdirAll(filepath.Join(dir, "a", "b"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "one"), make([]byte, 100), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "a", "b", "two"), make([]byte, 50), 0644))
+
+	size, err := DirectorySize(dir)
+	assert.NoError(err)
+	assert.EqualValues(150, size)
+
+	_, err = DirectorySize(filepath.Join(dir, "missing"))
+	assert.Error(err)
+}
+
+func TestDiskFreeBytes(t *testing.T) {
+	free, err := DiskFreeBytes(os.TempDir())
+	assert.NoError(t, err)
+	assert.True(t, free > 0)
+
+	_, err = DiskFreeBytes(filepath.Join(os.TempDir(), "does", "not", "exist"))
+	assert.Error(t, err)
+}
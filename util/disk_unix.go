@@ -0,0 +1,19 @@
+// +build !windows
+
+package util
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// DiskFreeBytes returns the space, in bytes, that is available to
+// unprivileged users on the disk containing path.
+func DiskFreeBytes(path string) (uint64, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, errors.Wrapf(err, "problem finding free space for '%s'", path)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,30 @@
+package util
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+var procGetDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// DiskFreeBytes returns the space, in bytes, that is available to the
+// current user on the disk containing path.
+func DiskFreeBytes(path string) (uint64, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid path '%s'", path)
+	}
+
+	var available, total, free uint64
+	ret, _, err := procGetDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&available)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)))
+	if ret == 0 {
+		return 0, errors.Wrapf(err, "problem finding free space for '%s'", path)
+	}
+	return available, nil
+}
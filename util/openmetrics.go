@@ -0,0 +1,237 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// OpenMetricsContentType is the content type of the text exposition
+	// format served by MetricsRegistry.
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+	metricTypeGauge   = "gauge"
+	metricTypeCounter = "counter"
+)
+
+// MetricSample is a single value of a metric family, identified by its
+// labels.
+type MetricSample struct {
+	Labels map[string]string `bson:"labels,omitempty" json:"labels,omitempty"`
+	Value  float64           `bson:"value" json:"value"`
+}
+
+// MetricFamily is a snapshot of every series of a metric.
+type MetricFamily struct {
+	Name    string         `bson:"name" json:"name"`
+	Help    string         `bson:"help" json:"help"`
+	Kind    string         `bson:"kind" json:"kind"`
+	Samples []MetricSample `bson:"samples" json:"samples"`
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string
+	samples map[string]MetricSample
+}
+
+// MetricsRegistry holds the current value of gauges and counters, and
+// renders them in the OpenMetrics text format for scraping. It is safe
+// for concurrent use.
+type MetricsRegistry struct {
+	mu       sync.RWMutex
+	families map[string]*metricFamily
+}
+
+// NewMetricsRegistry returns an empty registry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{families: map[string]*metricFamily{}}
+}
+
+// SetGauge sets the value of one series of a gauge.
+func (r *MetricsRegistry) SetGauge(name, help string, labels map[string]string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.family(name, help, metricTypeGauge)
+	f.samples[labelKey(labels)] = MetricSample{Labels: labels, Value: value}
+}
+
+// SetGaugeFamily replaces every series of a gauge, so that series that
+// are no longer reported, such as those of a deleted distro, are dropped.
+func (r *MetricsRegistry) SetGaugeFamily(name, help string, samples []MetricSample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.family(name, help, metricTypeGauge)
+	f.samples = make(map[string]MetricSample, len(samples))
+	for _, s := range samples {
+		f.samples[labelKey(s.Labels)] = s
+	}
+}
+
+// AddCounter increments one series of a counter. Counters only go up, so
+// negative deltas are ignored.
+func (r *MetricsRegistry) AddCounter(name, help string, labels map[string]string, delta float64) {
+	if delta < 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.family(name, help, metricTypeCounter)
+	key := labelKey(labels)
+	s, ok := f.samples[key]
+	if !ok {
+		s = MetricSample{Labels: labels}
+	}
+	s.Value += delta
+	f.samples[key] = s
+}
+
+// Families returns a snapshot of every metric family, sorted by name.
+func (r *MetricsRegistry) Families() []MetricFamily {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]MetricFamily, 0, len(r.families))
+	for _, f := range r.families {
+		family := MetricFamily{
+			Name:    f.name,
+			Help:    f.help,
+			Kind:    f.kind,
+			Samples: make([]MetricSample, 0, len(f.samples)),
+		}
+		for _, s := range f.samples {
+			family.Samples = append(family.Samples, s)
+		}
+		sort.Slice(family.Samples, func(i, j int) bool {
+			return labelKey(family.Samples[i].Labels) < labelKey(family.Samples[j].Labels)
+		})
+		out = append(out, family)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// MergeFamily adds the series of a family snapshot to the registry,
+// replacing the values of series that it already has.
+func (r *MetricsRegistry) MergeFamily(family MetricFamily) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.family(family.Name, family.Help, family.Kind)
+	for _, s := range family.Samples {
+		f.samples[labelKey(s.Labels)] = s
+	}
+}
+
+// family returns the named family, creating it if needed. The caller
+// must hold the lock.
+func (r *MetricsRegistry) family(name, help, kind string) *metricFamily {
+	f, ok := r.families[name]
+	if !ok || f.kind != kind {
+		f = &metricFamily{name: name, kind: kind, samples: map[string]MetricSample{}}
+		r.families[name] = f
+	}
+	f.help = help
+	return f
+}
+
+// WriteTo renders every metric family in the OpenMetrics text format.
+func (r *MetricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	buf := &bytes.Buffer{}
+
+	r.mu.RLock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(buf, "# TYPE %s %s\n", f.name, f.kind)
+		if f.help != "" {
+			fmt.Fprintf(buf, "# HELP %s %s\n", f.name, escapeMetricText(f.help, false))
+		}
+
+		keys := make([]string, 0, len(f.samples))
+		for key := range f.samples {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		sampleName := f.name
+		if f.kind == metricTypeCounter {
+			sampleName += "_total"
+		}
+		for _, key := range keys {
+			fmt.Fprintf(buf, "%s%s %s\n", sampleName, key, formatMetricValue(f.samples[key].Value))
+		}
+	}
+	r.mu.RUnlock()
+
+	buf.WriteString("# EOF\n")
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// ServeHTTP serves the registry to scrapers.
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", OpenMetricsContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = r.WriteTo(w)
+}
+
+// labelKey renders labels in their exposition form, sorted by name, which
+// also serves to identify a series within its family.
+func labelKey(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeMetricText(labels[name], true)))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeMetricText(s string, quoted bool) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	if quoted {
+		s = strings.Replace(s, `"`, `\"`, -1)
+	}
+	return s
+}
+
+func formatMetricValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,92 @@
+package util
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsRegistryExposition(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	r := NewMetricsRegistry()
+	r.SetGauge("queue_length", "tasks in the queue", map[string]string{"distro": "ubuntu"}, 3)
+	r.SetGauge("queue_length", "tasks in the queue", map[string]string{"distro": "archlinux"}, 1.5)
+	r.AddCounter("tasks_finished", "tasks finished", map[string]string{"status": "success"}, 2)
+	r.AddCounter("tasks_finished", "tasks finished", map[string]string{"status": "success"}, 1)
+	r.AddCounter("tasks_finished", "tasks finished", map[string]string{"status": "success"}, -5)
+	r.SetGauge("escaped", "a \\ help\nstring", map[string]string{"name": "a \"quoted\"\nname"}, 0)
+
+	buf := &bytes.Buffer{}
+	_, err := r.WriteTo(buf)
+	require.NoError(err)
+	assert.Equal(`# TYPE escaped gauge
+# HELP escaped a \\ help\nstring
+escaped{name="a \"quoted\"\nname"} 0
+# TYPE queue_length gauge
+# HELP queue_length tasks in the queue
+queue_length{distro="archlinux"} 1.5
+queue_length{distro="ubuntu"} 3
+# TYPE tasks_finished counter
+# HELP tasks_finished tasks finished
+tasks_finished_total{status="success"} 3
+# EOF
+`, buf.String())
+}
+
+func TestMetricsRegistryGaugeFamily(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewMetricsRegistry()
+	r.SetGauge("hosts", "", map[string]string{"distro": "old"}, 4)
+	r.SetGaugeFamily("hosts", "", []MetricSample{
+		{Labels: map[string]string{"distro": "new", "status": "running"}, Value: 2},
+	})
+
+	buf := &bytes.Buffer{}
+	_, err := r.WriteTo(buf)
+	assert.NoError(err)
+	assert.NotContains(buf.String(), "old")
+	assert.Contains(buf.String(), `hosts{distro="new",status="running"} 2`)
+}
+
+func TestMetricsRegistryMergeFamilies(t *testing.T) {
+	assert := assert.New(t)
+
+	local := NewMetricsRegistry()
+	local.SetGauge("jobs", "jobs by queue", map[string]string{"queue": "local"}, 1)
+	remote := NewMetricsRegistry()
+	remote.SetGauge("jobs", "jobs by queue", map[string]string{"queue": "remote"}, 2)
+
+	merged := NewMetricsRegistry()
+	for _, r := range []*MetricsRegistry{local, remote} {
+		for _, f := range r.Families() {
+			merged.MergeFamily(f)
+		}
+	}
+
+	families := merged.Families()
+	assert.Len(families, 1)
+	assert.Equal([]MetricSample{
+		{Labels: map[string]string{"queue": "local"}, Value: 1},
+		{Labels: map[string]string{"queue": "remote"}, Value: 2},
+	}, families[0].Samples)
+}
+
+func TestMetricsRegistryHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewMetricsRegistry()
+	r.SetGauge("up", "", nil, 1)
+
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.Equal(OpenMetricsContentType, rw.Header().Get("Content-Type"))
+	assert.Equal("# TYPE up gauge\nup 1\n# EOF\n", rw.Body.String())
+}
@@ -0,0 +1,385 @@
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// Tracing follows the OpenTelemetry model: a trace is a tree of spans
+// that share a trace id, and a trace context is propagated between
+// processes, and stored on documents such as tasks, as a W3C
+// traceparent string.
+
+const (
+	traceParentVersion = "00"
+	tracingBatchSize   = 512
+	tracingBatchWait   = 5 * time.Second
+)
+
+var traceParentRegex = regexp.MustCompile("^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$")
+
+// TraceContext identifies a span within a trace.
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+}
+
+// ParseTraceParent parses a W3C traceparent string.
+func ParseTraceParent(traceParent string) (TraceContext, error) {
+	match := traceParentRegex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(traceParent)))
+	if match == nil {
+		return TraceContext{}, errors.Errorf("'%s' is not a valid traceparent", traceParent)
+	}
+	tc := TraceContext{TraceID: match[1], SpanID: match[2]}
+	if !tc.IsValid() {
+		return TraceContext{}, errors.Errorf("traceparent '%s' has an all zero id", traceParent)
+	}
+	return tc, nil
+}
+
+// IsValid reports whether the context has non-zero trace and span ids.
+func (tc TraceContext) IsValid() bool {
+	return len(tc.TraceID) == 32 && len(tc.SpanID) == 16 &&
+		strings.Trim(tc.TraceID, "0") != "" && strings.Trim(tc.SpanID, "0") != ""
+}
+
+// TraceParent returns the context as a W3C traceparent string, or an
+// empty string for an invalid context.
+func (tc TraceContext) TraceParent() string {
+	if !tc.IsValid() {
+		return ""
+	}
+	return traceParentVersion + "-" + tc.TraceID + "-" + tc.SpanID + "-01"
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// fall back to something unique enough to keep spans distinct
+		return strings.Repeat("0", 2*n-16) + hex.EncodeToString([]byte(time.Now().Format("15040500")))[:16]
+	}
+	return hex.EncodeToString(b)
+}
+
+// SpanData is a finished span, as handed to exporters.
+type SpanData struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	Error        string
+}
+
+// Span is an operation being timed. Spans are safe to use from multiple
+// goroutines, and a nil span ignores all calls.
+type Span struct {
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+	tracer *Tracer
+}
+
+// Context returns the span's trace context, which is the parent context
+// of any spans started from it.
+func (s *Span) Context() TraceContext {
+	if s == nil {
+		return TraceContext{}
+	}
+	return TraceContext{TraceID: s.data.TraceID, SpanID: s.data.SpanID}
+}
+
+// TraceParent returns the span's context as a W3C traceparent string.
+func (s *Span) TraceParent() string { return s.Context().TraceParent() }
+
+// SetStartTime moves the start of the span, for spans that cover an
+// operation that began before the span was created.
+func (s *Span) SetStartTime(start time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Start = start
+}
+
+// SetAttribute records a key-value pair on the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes[key] = value
+}
+
+// End finishes the span, marking it failed if err is not nil, and queues
+// it for export. Only the first call has an effect.
+func (s *Span) End(err error) {
+	s.EndAt(time.Now(), err)
+}
+
+// EndAt finishes the span at the given time.
+func (s *Span) EndAt(end time.Time, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = end
+	if err != nil {
+		s.data.Error = err.Error()
+	}
+	data := s.data
+	data.Attributes = make(map[string]string, len(s.data.Attributes))
+	for k, v := range s.data.Attributes {
+		data.Attributes[k] = v
+	}
+	s.mu.Unlock()
+
+	s.tracer.queue(data)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a context that carries the span, so that spans
+// started from it become its children.
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, s)
+}
+
+// SpanFromContext returns the span carried by the context, if any.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanContextKey{}).(*Span)
+	return s
+}
+
+// SpanExporter sends finished spans to a tracing backend.
+type SpanExporter interface {
+	ExportSpans(context.Context, []SpanData) error
+}
+
+// Tracer starts spans and batches finished spans to its exporter. A
+// tracer without an exporter still creates trace contexts, but discards
+// the spans.
+type Tracer struct {
+	exporter SpanExporter
+
+	mu      sync.Mutex
+	pending []SpanData
+	flush   chan struct{}
+	done    chan struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewTracer returns a tracer that exports spans in batches until it is
+// closed.
+func NewTracer(exporter SpanExporter) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		flush:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if exporter != nil {
+		t.wg.Add(1)
+		go t.exportLoop()
+	}
+	return t
+}
+
+var (
+	globalTracerMu sync.RWMutex
+	globalTracer   = NewTracer(nil)
+)
+
+// GetTracer returns the process wide tracer.
+func GetTracer() *Tracer {
+	globalTracerMu.RLock()
+	defer globalTracerMu.RUnlock()
+	return globalTracer
+}
+
+// SetTracer replaces the process wide tracer, returning the previous one
+// so that the caller may close it.
+func SetTracer(t *Tracer) *Tracer {
+	globalTracerMu.Lock()
+	defer globalTracerMu.Unlock()
+	previous := globalTracer
+	globalTracer = t
+	return previous
+}
+
+// Start begins a span that is a child of the span carried by ctx, or the
+// root of a new trace if there is none.
+func (t *Tracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, *Span) {
+	parent := SpanFromContext(ctx).Context()
+	s := t.newSpan(parent, name, attrs)
+	return ContextWithSpan(ctx, s), s
+}
+
+// StartFromTraceParent begins a span that is a child of the given
+// traceparent, such as one stored on a task. If the traceparent is not
+// valid, the span is a child of the span carried by ctx instead.
+func (t *Tracer) StartFromTraceParent(ctx context.Context, traceParent, name string, attrs map[string]string) (context.Context, *Span) {
+	parent, err := ParseTraceParent(traceParent)
+	if err != nil {
+		return t.Start(ctx, name, attrs)
+	}
+	s := t.newSpan(parent, name, attrs)
+	return ContextWithSpan(ctx, s), s
+}
+
+func (t *Tracer) newSpan(parent TraceContext, name string, attrs map[string]string) *Span {
+	data := SpanData{
+		SpanID:     randomHex(8),
+		Name:       name,
+		Start:      time.Now(),
+		Attributes: make(map[string]string, len(attrs)),
+	}
+	if parent.IsValid() {
+		data.TraceID = parent.TraceID
+		data.ParentSpanID = parent.SpanID
+	} else {
+		data.TraceID = randomHex(16)
+	}
+	for k, v := range attrs {
+		data.Attributes[k] = v
+	}
+
+	return &Span{data: data, tracer: t}
+}
+
+func (t *Tracer) queue(data SpanData) {
+	if t == nil || t.exporter == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.pending = append(t.pending, data)
+	if len(t.pending) >= tracingBatchSize {
+		select {
+		case t.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *Tracer) exportLoop() {
+	defer t.wg.Done()
+
+	timer := time.NewTimer(tracingBatchWait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-timer.C:
+			timer.Reset(tracingBatchWait)
+		case <-t.flush:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), tracingBatchWait)
+		grip.Warning(message.WrapError(t.Flush(ctx), message.Fields{
+			"message": "problem exporting spans",
+		}))
+		cancel()
+	}
+}
+
+// Flush exports all finished spans.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil || t.exporter == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	spans := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+	return errors.Wrapf(t.exporter.ExportSpans(ctx, spans), "problem exporting %d spans", len(spans))
+}
+
+// Close stops the tracer after exporting any remaining spans.
+func (t *Tracer) Close(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.mu.Unlock()
+
+	close(t.done)
+	t.wg.Wait()
+
+	if t.exporter == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	spans := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	return errors.Wrapf(t.exporter.ExportSpans(ctx, spans), "problem exporting %d spans", len(spans))
+}
+
+// InMemorySpanExporter keeps exported spans in memory, for tests.
+type InMemorySpanExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *InMemorySpanExporter) ExportSpans(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Spans returns the spans exported so far.
+func (e *InMemorySpanExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]SpanData, len(e.spans))
+	copy(out, e.spans)
+	return out
+}
+
+// Reset discards the spans exported so far.
+func (e *InMemorySpanExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
@@ -0,0 +1,154 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	assert := assert.New(t)
+
+	tc, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(err)
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.Equal("00f067aa0ba902b7", tc.SpanID)
+	assert.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tc.TraceParent())
+
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473g-00f067aa0ba902b7-01",
+	} {
+		_, err = ParseTraceParent(bad)
+		assert.Error(err, bad)
+	}
+	assert.Equal("", TraceContext{}.TraceParent())
+}
+
+func TestTracerSpans(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	exporter := &InMemorySpanExporter{}
+	tracer := NewTracer(exporter)
+	ctx := context.Background()
+
+	rootCtx, root := tracer.Start(ctx, "root", map[string]string{"version": "v1"})
+	_, child := tracer.Start(rootCtx, "child", nil)
+	child.SetAttribute("command", "shell.exec")
+	child.End(errors.New("exit code 1"))
+	child.End(nil)
+
+	_, remote := tracer.StartFromTraceParent(ctx, root.TraceParent(), "remote", nil)
+	remote.End(nil)
+	_, fallback := tracer.StartFromTraceParent(rootCtx, "not a traceparent", "fallback", nil)
+	fallback.End(nil)
+	root.End(nil)
+
+	assert.Empty(exporter.Spans(), "spans are batched")
+	require.NoError(tracer.Flush(ctx))
+
+	spans := exporter.Spans()
+	require.Len(spans, 4)
+	byName := map[string]SpanData{}
+	for _, s := range spans {
+		assert.Equal(root.Context().TraceID, s.TraceID)
+		assert.False(s.End.Before(s.Start))
+		byName[s.Name] = s
+	}
+	assert.Empty(byName["root"].ParentSpanID)
+	assert.Equal("v1", byName["root"].Attributes["version"])
+	assert.Equal(root.Context().SpanID, byName["child"].ParentSpanID)
+	assert.Equal("shell.exec", byName["child"].Attributes["command"])
+	assert.Equal("exit code 1", byName["child"].Error)
+	assert.Equal(root.Context().SpanID, byName["remote"].ParentSpanID)
+	assert.Equal(root.Context().SpanID, byName["fallback"].ParentSpanID)
+
+	_, late := tracer.Start(ctx, "late", nil)
+	assert.NotEqual(root.Context().TraceID, late.Context().TraceID)
+	late.End(nil)
+	assert.NoError(tracer.Close(ctx))
+	assert.Len(exporter.Spans(), 5, "close exports remaining spans")
+
+	_, closed := tracer.Start(ctx, "closed", nil)
+	closed.End(nil)
+	assert.NoError(tracer.Flush(ctx))
+	assert.Len(exporter.Spans(), 5)
+}
+
+func TestTracerWithoutExporter(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := NewTracer(nil)
+	_, span := tracer.Start(context.Background(), "span", nil)
+	assert.True(span.Context().IsValid(), "spans have ids to propagate")
+	span.End(nil)
+	assert.NoError(tracer.Flush(context.Background()))
+	assert.NoError(tracer.Close(context.Background()))
+
+	var nilSpan *Span
+	nilSpan.SetAttribute("key", "value")
+	nilSpan.End(nil)
+	assert.Equal("", nilSpan.TraceParent())
+}
+
+func TestOTLPSpanExporter(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var (
+		path        string
+		contentType string
+		body        otlpTraceRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(err)
+		assert.NoError(json.Unmarshal(data, &body))
+	}))
+	defer server.Close()
+
+	tracer := NewTracer(NewOTLPSpanExporter(server.URL+"/", "evergreen-test"))
+	ctx, root := tracer.Start(context.Background(), "root", nil)
+	_, child := tracer.Start(ctx, "child", map[string]string{"task": "t1"})
+	child.End(errors.New("failed"))
+	root.End(nil)
+	require.NoError(tracer.Close(context.Background()))
+
+	assert.Equal("/v1/traces", path)
+	assert.Equal("application/json", contentType)
+	require.Len(body.ResourceSpans, 1)
+	assert.Equal("service.name", body.ResourceSpans[0].Resource["attributes"][0].Key)
+	assert.Equal("evergreen-test", body.ResourceSpans[0].Resource["attributes"][0].Value["stringValue"])
+	require.Len(body.ResourceSpans[0].ScopeSpans, 1)
+	spans := body.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(spans, 2)
+	assert.Equal("child", spans[0].Name)
+	assert.Equal(root.Context().TraceID, spans[0].TraceID)
+	assert.Equal(root.Context().SpanID, spans[0].ParentSpanID)
+	assert.Equal(otlpStatusError, spans[0].Status.Code)
+	assert.Equal("failed", spans[0].Status.Message)
+	assert.Equal([]otlpAttribute{{Key: "task", Value: map[string]string{"stringValue": "t1"}}}, spans[0].Attributes)
+	assert.NotEmpty(spans[0].StartTimeUnixNano)
+	assert.Equal("root", spans[1].Name)
+	assert.Empty(spans[1].ParentSpanID)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	assert.Error(NewOTLPSpanExporter(failing.URL, "evergreen-test").ExportSpans(context.Background(), []SpanData{{Name: "span"}}))
+}
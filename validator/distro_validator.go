@@ -21,6 +21,8 @@ var distroSyntaxValidators = []distroValidator{
 	ensureValidExpansions,
 	ensureStaticHostsAreNotSpawnable,
 	ensureValidContainerPool,
+	ensureValidDiskGuard,
+	ensureValidTaskPrioritizer,
 }
 
 // CheckDistro checks if the distro configuration syntax is valid. Returns
@@ -190,3 +192,40 @@ func ensureValidContainerPool(ctx context.Context, d *distro.Distro, s *evergree
 	}
 	return nil
 }
+
+// ensureValidDiskGuard checks that a distro's disk space limits are not
+// negative and that the floor does not exceed the pre-task minimum.
+func ensureValidDiskGuard(ctx context.Context, d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	g := d.DiskGuard
+	if g.MinFreeMB < 0 || g.FreeFloorMB < 0 || g.WorkDirQuotaMB < 0 {
+		return []ValidationError{{Error, "distro disk guard limits cannot be negative"}}
+	}
+	if g.MinFreeMB != 0 && g.FreeFloorMB > g.MinFreeMB {
+		return []ValidationError{{Error, fmt.Sprintf("distro disk guard free space floor (%d MB) cannot exceed the minimum free space (%d MB)", g.FreeFloorMB, g.MinFreeMB)}}
+	}
+	return nil
+}
+
+// ensureValidTaskPrioritizer checks that a distro's task prioritizer exists
+// and that its fair-share weights are positive and name each project once.
+func ensureValidTaskPrioritizer(ctx context.Context, d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	if !util.StringSliceContains(distro.ValidTaskPrioritizers, d.TaskPrioritizer) {
+		return []ValidationError{{Error, fmt.Sprintf("distro task prioritizer '%s' is not one of %v", d.TaskPrioritizer, distro.ValidTaskPrioritizers[1:])}}
+	}
+	if d.FairShare.UsageHalfLifeHours < 0 {
+		return []ValidationError{{Error, "distro fair-share usage half-life cannot be negative"}}
+	}
+
+	errs := []ValidationError{}
+	projects := map[string]bool{}
+	for _, w := range d.FairShare.Weights {
+		if w.Project == "" || w.Weight <= 0 {
+			errs = append(errs, ValidationError{Error, fmt.Sprintf("distro fair-share weight for project '%s' must name a project and be positive", w.Project)})
+		}
+		if projects[w.Project] {
+			errs = append(errs, ValidationError{Error, fmt.Sprintf("distro fair-share weight for project '%s' is defined more than once", w.Project)})
+		}
+		projects[w.Project] = true
+	}
+	return errs
+}
@@ -319,3 +319,49 @@ func TestEnsureValidContainerPool(t *testing.T) {
 	err = ensureValidContainerPool(ctx, d4, conf)
 	assert.Nil(err)
 }
+
+func TestEnsureValidDiskGuard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert := assert.New(t)
+
+	d := &distro.Distro{}
+	assert.Empty(ensureValidDiskGuard(ctx, d, conf))
+
+	d.DiskGuard = distro.DiskGuardSettings{MinFreeMB: 2048, FreeFloorMB: 512, WorkDirQuotaMB: 10240}
+	assert.Empty(ensureValidDiskGuard(ctx, d, conf))
+
+	d.DiskGuard = distro.DiskGuardSettings{WorkDirQuotaMB: -1}
+	assert.Len(ensureValidDiskGuard(ctx, d, conf), 1)
+
+	d.DiskGuard = distro.DiskGuardSettings{MinFreeMB: 512, FreeFloorMB: 2048}
+	assert.Len(ensureValidDiskGuard(ctx, d, conf), 1)
+
+	d.DiskGuard = distro.DiskGuardSettings{FreeFloorMB: 2048}
+	assert.Empty(ensureValidDiskGuard(ctx, d, conf))
+}
+
+func TestEnsureValidTaskPrioritizer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert := assert.New(t)
+
+	d := &distro.Distro{}
+	assert.Empty(ensureValidTaskPrioritizer(ctx, d, conf))
+
+	d.TaskPrioritizer = distro.TaskPrioritizerFairShare
+	d.FairShare = distro.FairShareSettings{
+		Weights:            []distro.ProjectWeight{{Project: "a", Weight: 2}, {Project: "b", Weight: 0.5}},
+		UsageHalfLifeHours: 12,
+	}
+	assert.Empty(ensureValidTaskPrioritizer(ctx, d, conf))
+
+	d.FairShare.Weights = append(d.FairShare.Weights, distro.ProjectWeight{Project: "a", Weight: 1}, distro.ProjectWeight{Project: "c"})
+	assert.Len(ensureValidTaskPrioritizer(ctx, d, conf), 2)
+
+	d.FairShare = distro.FairShareSettings{UsageHalfLifeHours: -1}
+	assert.Len(ensureValidTaskPrioritizer(ctx, d, conf), 1)
+
+	d.TaskPrioritizer = "lottery"
+	assert.Len(ensureValidTaskPrioritizer(ctx, d, conf), 1)
+}
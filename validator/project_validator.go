@@ -58,6 +58,8 @@ var projectSyntaxValidators = []projectValidator{
 	validateTaskGroups,
 	validateGenerateTasks,
 	validateCreateHosts,
+	validateResourceLimits,
+	validateResourceRequests,
 }
 
 // Functions used to validate the semantics of a project configuration file.
@@ -897,6 +899,49 @@ func validateCreateHosts(p *model.Project) []ValidationError {
 	return errs
 }
 
+// validateResourceLimits checks that tasks' resource limits are within the
+// ranges that the agent can enforce.
+func validateResourceLimits(p *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	for _, t := range p.Tasks {
+		limits := t.ResourceLimits
+		if limits == nil {
+			continue
+		}
+		if limits.MemoryMB < 0 || limits.MaxProcesses < 0 || limits.DiskWriteMB < 0 {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("task '%s' resource limits must not be negative", t.Name),
+				Level:   Error,
+			})
+		}
+		if limits.CPUShares != 0 && (limits.CPUShares < 2 || limits.CPUShares > 262144) {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("task '%s' cpu_shares must be between 2 and 262144", t.Name),
+				Level:   Error,
+			})
+		}
+	}
+	return errs
+}
+
+// validateResourceRequests checks that the resources that tasks request from
+// containers are not negative.
+func validateResourceRequests(p *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	for _, t := range p.Tasks {
+		if t.ResourceRequests == nil {
+			continue
+		}
+		if err := t.ResourceRequests.Validate(); err != nil {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("task '%s' resource requests are invalid: %s", t.Name, err.Error()),
+				Level:   Error,
+			})
+		}
+	}
+	return errs
+}
+
 func validateTimesCalledPerTask(p *model.Project, ts map[string]int, commandName string, times int) (errs []ValidationError) {
 	for _, bv := range p.BuildVariants {
 		for _, t := range bv.Tasks {
@@ -1947,3 +1947,76 @@ func TestValidateCreateHosts(t *testing.T) {
 	errs = validateCreateHosts(&p)
 	assert.Len(errs, 1)
 }
+
+func TestValidateResourceLimits(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	yml := `
+  tasks:
+  - name: t_1
+    resource_limits:
+      memory_mb: 2048
+      cpu_shares: 512
+      max_processes: 100
+      disk_write_mb: 1024
+  - name: t_2
+  buildvariants:
+  - name: "bv"
+    tasks:
+    - name: t_1
+    - name: t_2
+  `
+	var p model.Project
+	require.NoError(model.LoadProjectInto([]byte(yml), "id", &p))
+	require.NotNil(p.FindProjectTask("t_1").ResourceLimits)
+	assert.Equal(2048, p.FindProjectTask("t_1").ResourceLimits.MemoryMB)
+	assert.Equal(1024, p.FindProjectTask("t_1").ResourceLimits.DiskWriteMB)
+	assert.Nil(p.FindProjectTask("t_2").ResourceLimits)
+	assert.Len(validateResourceLimits(&p), 0)
+
+	yml = `
+  tasks:
+  - name: t_1
+    resource_limits:
+      memory_mb: -1
+      cpu_shares: 1
+  buildvariants:
+  - name: "bv"
+    tasks:
+    - name: t_1
+  `
+	p = model.Project{}
+	require.NoError(model.LoadProjectInto([]byte(yml), "id", &p))
+	assert.Len(validateResourceLimits(&p), 2)
+}
+
+func TestValidateResourceRequests(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	yml := `
+  tasks:
+  - name: t_1
+    resource_requests:
+      cpus: 1.5
+      memory_mb: 2048
+  - name: t_2
+    resource_requests:
+      cpus: -1
+  - name: t_3
+  buildvariants:
+  - name: "bv"
+    tasks:
+    - name: t_1
+    - name: t_2
+    - name: t_3
+  `
+	var p model.Project
+	require.NoError(model.LoadProjectInto([]byte(yml), "id", &p))
+	require.NotNil(p.FindProjectTask("t_1").ResourceRequests)
+	assert.Equal(1.5, p.FindProjectTask("t_1").ResourceRequests.CPUs)
+	assert.Equal(2048, p.FindProjectTask("t_1").ResourceRequests.MemoryMB)
+	assert.Nil(p.FindProjectTask("t_3").ResourceRequests)
+	assert.Len(validateResourceRequests(&p), 1)
+}